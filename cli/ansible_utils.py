@@ -10,6 +10,7 @@ from pathlib import Path
 from datetime import datetime
 from typing import Dict, Any, Optional, List
 from dataclasses import dataclass, field
+from cli.exceptions import SecretBackendError
 
 
 @dataclass
@@ -198,6 +199,8 @@ class AnsibleManager:
             "apps",
             "monitoring",
             "addon_configs",
+            "addon_deploy_order",
+            "addon_dependencies",
             "vm_config",
             "docker",
         ]:
@@ -238,8 +241,10 @@ class AnsibleManager:
             if not secret_mgr.has_secrets():
                 return {}
 
-            secrets_data = secret_mgr.load_secrets()
+            secrets_data = secret_mgr.load_secrets(resolve=True)
             return secrets_data
+        except SecretBackendError:
+            raise
         except Exception:
             return {}
 
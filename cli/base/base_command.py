@@ -11,6 +11,7 @@ import json
 from rich.console import Console
 from cli.ui_components import show_header
 from cli.logger import DeployLogger
+from cli.exceptions import SecretBackendError
 from cli.utils import get_project_root
 
 
@@ -238,6 +239,18 @@ class BaseCommand(ABC):
                     f"[dim]Logs saved to:[/dim] {self.logger.log_path}\n"
                 )
             raise SystemExit(1)
+        except SecretBackendError as e:
+            self.console.print(
+                f"\n[bold red]✗ Secret reference failed:[/bold red] {e.message}\n"
+            )
+            if e.context:
+                self.console.print(f"[dim]{e.context}[/dim]\n")
+            if self.logger:
+                self.logger.log_error(e.message, context=e.context)
+                self.console.print(
+                    f"[dim]Logs saved to:[/dim] {self.logger.log_path}\n"
+                )
+            raise SystemExit(1)
         except Exception as e:
             # Generic error handling
             error_type = type(e).__name__
@@ -132,6 +132,48 @@ class ProjectCommand(BaseCommand):
         """
         return self.config_service.list_apps(self.project_name)
 
+    def run_hooks(
+        self, event: str, apps: Optional[list] = None, logger=None, **context
+    ) -> bool:
+        """
+        Run lifecycle hooks (app markers + <project>:hooks) for an event.
+
+        Hook output goes to the console and the command's log.
+
+        Args:
+            event: before_deploy, after_deploy, ...
+            apps: Apps the event concerns (None: every app)
+            logger: Command logger
+            **context: Event details (ref=..., addon=...) passed to hooks
+
+        Returns:
+            False if a hook with on_failure: abort failed
+        """
+        from cli.core.hooks import HookFailed
+        from cli.services.hook_service import HookService
+
+        def log(line: str) -> None:
+            if logger:
+                logger.log(line)
+            if not self.json_output and not (logger and self.verbose):
+                self.console.print(f"  {line}", markup=False, highlight=False)
+
+        try:
+            HookService(self.project_root, self.project_name).run(
+                event, apps, log=log, **context
+            )
+        except HookFailed as e:
+            if logger:
+                logger.log_error(str(e))
+            self.print_error(str(e))
+            return False
+        except Exception as e:
+            if logger:
+                logger.log_error(f"{event} hooks not run: {e}")
+            self.print_error(f"{event} hooks not run: {e}")
+            return False
+        return True
+
     def run(self, **kwargs) -> None:
         """
         Run command with project validation.
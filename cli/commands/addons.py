@@ -7,7 +7,7 @@ Heroku-style addon management with named instances and attachments.
 import click
 import subprocess
 from rich.table import Table
-from cli.base import ProjectCommand
+from cli.base import BaseCommand, ProjectCommand
 from cli.secret_manager import SecretManager
 from cli.logger import DeployLogger
 
@@ -15,7 +15,21 @@ from cli.logger import DeployLogger
 class AddonsListCommand(ProjectCommand):
     """List all addon instances for project."""
 
+    def __init__(
+        self,
+        project_name: str,
+        tree: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.tree = tree
+
     def execute(self) -> None:
+        if self.tree:
+            self._show_tree()
+            return
+
         # Read addons from DATABASE (not config)
         from cli.database import get_db_session
         from sqlalchemy import text
@@ -200,6 +214,60 @@ class AddonsListCommand(ProjectCommand):
             f"\n[dim]View details: superdeploy {self.project_name}:addons:info <addon>[/dim]"
         )
 
+    def _show_tree(self) -> None:
+        """Show the instance dependency graph in deploy order."""
+        from cli.core.addon_graph import AddonGraph, AddonGraphError
+        from cli.core.addon_loader import (
+            AddonLoader,
+            AddonNotFoundError,
+            CircularDependencyError,
+        )
+        from cli.ui_components import addon_dependency_tree
+
+        addons_config = self.config_service.get_raw_config(self.project_name).get(
+            "addons", {}
+        )
+
+        try:
+            graph = AddonGraph.from_project_config(
+                AddonLoader(self.project_root / "addons"), addons_config
+            )
+        except (AddonGraphError, AddonNotFoundError, CircularDependencyError) as e:
+            errors = getattr(e, "errors", [str(e)])
+            if self.json_output:
+                self.output_json_error(
+                    "Addon dependencies not satisfied", details={"errors": errors}
+                )
+            self.console.print("[red]✗ Addon dependencies not satisfied:[/red]")
+            for error in errors:
+                self.console.print(f"  [red]•[/red] {error}")
+            raise SystemExit(1)
+
+        if self.json_output:
+            self.output_json({"project": self.project_name, **graph.to_dict()})
+            return
+
+        self.show_header(
+            title="Addons",
+            project=self.project_name,
+            subtitle="Dependency graph",
+        )
+
+        if not graph.nodes:
+            self.console.print("[yellow]No addons configured[/yellow]")
+            return
+
+        self.console.print(addon_dependency_tree(graph))
+
+        for warning in graph.warnings:
+            self.console.print(f"[yellow]⚠[/yellow] [dim]{warning}[/dim]")
+
+        waves = graph.deploy_waves()
+        self.console.print(
+            f"\n[dim]Total: {len(graph.nodes)} addon instances in {len(waves)} "
+            "deploy wave(s); children wait for their parent to be healthy[/dim]"
+        )
+
 
 class AddonsInfoCommand(ProjectCommand):
     """Show detailed info about addon instance."""
@@ -560,6 +628,12 @@ class AddonsAddCommand(ProjectCommand):
         finally:
             db.close()
 
+        # Project-wide hooks (no app is affected until up/attach)
+        if not self.run_hooks(
+            "after_addon_change", [], addon=f"{category}.{self.name}", action="add"
+        ):
+            raise SystemExit(1)
+
 
 class AddonsRemoveCommand(ProjectCommand):
     """Remove addon instance from config.yml."""
@@ -665,6 +739,11 @@ class AddonsRemoveCommand(ProjectCommand):
         finally:
             db.close()
 
+        if not self.run_hooks(
+            "after_addon_change", [], addon=self.addon, action="remove"
+        ):
+            raise SystemExit(1)
+
 
 class AddonsAttachCommand(ProjectCommand):
     """Attach addon to app in config.yml."""
@@ -852,44 +931,51 @@ class AddonsAttachCommand(ProjectCommand):
         logger = DeployLogger(self.project_name, self.console)
 
         try:
-            # Get app config to find which VM it's on
-            app_config = config["apps"][self.app]
-            vm_role = app_config.get("vm", "app")
-
-            # Use Ansible to restart the app's containers
-            ansible_cmd = [
-                "ansible-playbook",
-                str(self.project_root / "shared/ansible/playbooks/app-restart.yml"),
-                "-e",
-                f"project_name={self.project_name}",
-                "-e",
-                f"app_name={self.app}",
-                "-e",
-                f"vm_role={vm_role}",
-                "--limit",
-                f"{self.project_name}_{vm_role}",
-            ]
-
-            result = subprocess.run(ansible_cmd, capture_output=True, text=True)
+            from cli.services.app_env_service import AppEnvService
 
-            if result.returncode == 0:
+            env_service = AppEnvService(self.project_root, self.project_name)
+            pushed, rollout = env_service.apply(self.app)
+
+            if rollout.action == "rebuild":
+                self.console.print(
+                    f"[green]✓[/green] .env pushed to {pushed.vm_name}, "
+                    f"build-time config changed: rebuild of [cyan]{self.app}[/cyan] triggered"
+                )
+                self.console.print(
+                    "\n[bold green]✅ Addon attached; live once the new image deploys[/bold green]"
+                )
+            elif rollout.action == "unchanged":
+                self.console.print(
+                    f"[green]✓[/green] .env on {pushed.vm_name} already up to date, "
+                    f"app [cyan]{self.app}[/cyan] not restarted"
+                )
+            elif rollout.success:
                 self.console.print(
-                    f"[green]✓[/green] App [cyan]{self.app}[/cyan] restarted"
+                    f"[green]✓[/green] .env pushed to {pushed.vm_name}, "
+                    f"app [cyan]{self.app}[/cyan] restarted"
                 )
                 self.console.print(
                     "\n[bold green]✅ Addon attached and live![/bold green]"
                 )
             else:
-                self.console.print("[yellow]⚠[/yellow] App restart failed")
                 self.console.print(
-                    f"\n[dim]Manual restart:[/dim] [cyan]cd ~/code/{self.app} && git push origin production[/cyan]"
+                    f"[yellow]⚠[/yellow] {rollout.failed} did not become healthy, "
+                    "previous .env restored"
+                )
+                self.console.print(
+                    f"\n[dim]Retry:[/dim] [cyan]superdeploy {self.project_name}:config:push -a {self.app}[/cyan]"
                 )
         except Exception as e:
             self.console.print(f"[yellow]⚠[/yellow] Auto-deploy failed: {e}")
             self.console.print(
-                f"\n[dim]Manual restart:[/dim] [cyan]cd ~/code/{self.app} && git push origin production[/cyan]"
+                f"\n[dim]Retry:[/dim] [cyan]superdeploy {self.project_name}:config:push -a {self.app}[/cyan]"
             )
 
+        if not self.run_hooks(
+            "after_addon_change", [self.app], addon=self.addon, action="attach"
+        ):
+            raise SystemExit(1)
+
 
 class AddonsDetachCommand(ProjectCommand):
     """Detach addon from app in config.yml."""
@@ -1031,65 +1117,380 @@ class AddonsDetachCommand(ProjectCommand):
         logger = DeployLogger(self.project_name, self.console)
 
         try:
-            # Reload config after detach
-            config_path = (
-                self.project_root / "projects" / self.project_name / "config.yml"
-            )
-            with open(config_path) as f:
-                config = yaml.safe_load(f)
-
-            # Get app config to find which VM it's on
-            app_config = config["apps"][self.app]
-            vm_role = app_config.get("vm", "app")
-
-            # Use Ansible to restart the app's containers
-            ansible_cmd = [
-                "ansible-playbook",
-                str(self.project_root / "shared/ansible/playbooks/app-restart.yml"),
-                "-e",
-                f"project_name={self.project_name}",
-                "-e",
-                f"app_name={self.app}",
-                "-e",
-                f"vm_role={vm_role}",
-                "--limit",
-                f"{self.project_name}_{vm_role}",
-            ]
-
-            result = subprocess.run(ansible_cmd, capture_output=True, text=True)
+            from cli.services.app_env_service import AppEnvService
 
-            if result.returncode == 0:
+            env_service = AppEnvService(self.project_root, self.project_name)
+            pushed, rollout = env_service.apply(self.app)
+
+            if rollout.action == "rebuild":
+                self.console.print(
+                    f"[green]✓[/green] .env pushed to {pushed.vm_name}, "
+                    f"build-time config changed: rebuild of [cyan]{self.app}[/cyan] triggered"
+                )
+                self.console.print(
+                    "\n[bold green]✅ Addon detached; live once the new image deploys[/bold green]"
+                )
+            elif rollout.action == "unchanged":
                 self.console.print(
-                    f"[green]✓[/green] App [cyan]{self.app}[/cyan] restarted"
+                    f"[green]✓[/green] .env on {pushed.vm_name} already up to date, "
+                    f"app [cyan]{self.app}[/cyan] not restarted"
+                )
+            elif rollout.success:
+                self.console.print(
+                    f"[green]✓[/green] .env pushed to {pushed.vm_name}, "
+                    f"app [cyan]{self.app}[/cyan] restarted"
                 )
                 self.console.print(
                     "\n[bold green]✅ Addon detached and removed![/bold green]"
                 )
             else:
-                self.console.print("[yellow]⚠[/yellow] App restart failed")
                 self.console.print(
-                    f"\n[dim]Manual restart:[/dim] [cyan]cd ~/code/{self.app} && git push origin production[/cyan]"
+                    f"[yellow]⚠[/yellow] {rollout.failed} did not become healthy, "
+                    "previous .env restored"
+                )
+                self.console.print(
+                    f"\n[dim]Retry:[/dim] [cyan]superdeploy {self.project_name}:config:push -a {self.app}[/cyan]"
                 )
         except Exception as e:
             self.console.print(f"[yellow]⚠[/yellow] Auto-restart failed: {e}")
             self.console.print(
-                f"\n[dim]Manual restart:[/dim] [cyan]cd ~/code/{self.app} && git push origin production[/cyan]"
+                f"\n[dim]Retry:[/dim] [cyan]superdeploy {self.project_name}:config:push -a {self.app}[/cyan]"
+            )
+
+        if not self.run_hooks(
+            "after_addon_change", [self.app], addon=self.addon, action="detach"
+        ):
+            raise SystemExit(1)
+
+
+class AddonsTestCommand(BaseCommand):
+    """Render an addon with a fake project and boot it on local Docker."""
+
+    def __init__(
+        self,
+        addon_type: str,
+        plan: str = "standard",
+        instance: str = "primary",
+        version: str = None,
+        keep: bool = False,
+        render_only: bool = False,
+        timeout: int = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.addon_type = addon_type
+        self.plan = plan
+        self.instance = instance
+        self.version = version
+        self.keep = keep
+        self.render_only = render_only
+        self.timeout = timeout
+
+    def execute(self) -> None:
+        from jinja2 import TemplateError
+        from cli.core.addon_loader import AddonNotFoundError, AddonValidationError
+        from cli.services.addon_test_service import AddonTestService
+
+        self.show_header(
+            title="Test Addon",
+            subtitle=f"{self.addon_type} • plan {self.plan}",
+            details={
+                "Instance": self.instance,
+                "Mode": "render only" if self.render_only else "local docker",
+            },
+        )
+        logger = self.init_logger("addons", f"test-{self.addon_type}")
+
+        try:
+            service = AddonTestService(
+                self.project_root,
+                self.addon_type,
+                plan=self.plan,
+                instance_name=self.instance,
+                version=self.version,
+            )
+        except (AddonNotFoundError, AddonValidationError, ValueError) as e:
+            self.exit_with_error(str(e))
+
+        report = {"addon": self.addon_type, "plan": self.plan, "steps": {}}
+
+        if logger:
+            logger.step("Rendering templates")
+        try:
+            rendered = service.render()
+        except (ValueError, TemplateError) as e:
+            service.teardown(keep_files=self.keep)
+            self.exit_with_error(f"Rendering {self.addon_type} failed: {e}")
+        report["output_dir"] = str(rendered.output_dir)
+        report["undefined"] = rendered.undefined
+        if logger:
+            logger.success(f"Rendered to {rendered.output_dir}")
+            for file_name, names in rendered.undefined.items():
+                logger.warning(
+                    f"{file_name}: undefined {', '.join(names)} "
+                    "(Ansible would fail here)"
+                )
+
+        if self.render_only:
+            report["passed"] = True
+            if self.json_output:
+                self.output_json(report)
+            return
+
+        if not service.docker_available():
+            service.teardown(keep_files=self.keep)
+            self.exit_with_error(
+                "docker with the compose plugin is required for addons:test"
+            )
+
+        passed = False
+        try:
+            passed = self._boot_and_check(service, logger, report)
+        finally:
+            if logger:
+                logger.step("Tearing down")
+            errors = service.teardown(keep_files=self.keep)
+            if logger:
+                for error in errors:
+                    logger.warning(error)
+                if not errors:
+                    logger.success("Containers, volumes and network removed")
+                if self.keep:
+                    logger.success(f"Rendered files kept in {rendered.output_dir}")
+
+        report["passed"] = passed
+        if self.json_output:
+            self.output_json(report, exit_code=0 if passed else 1)
+            return
+
+        if passed:
+            self.console.print(
+                f"\n[color(248)]{self.addon_type} passed healthcheck "
+                "and smoke test.[/color(248)]"
+            )
+        else:
+            self.console.print(
+                f"\n[bold red]❌ {self.addon_type} addon test failed[/bold red]"
+            )
+        if logger:
+            self.console.print(f"[dim]Logs saved to:[/dim] {logger.log_path}\n")
+        if not passed:
+            raise SystemExit(1)
+
+    def _boot_and_check(self, service, logger, report: dict) -> bool:
+        """Start containers, wait for health, run the smoke test"""
+        if logger:
+            logger.step("Starting containers")
+        result = service.up()
+        report["steps"]["up"] = result.returncode == 0
+        if result.returncode != 0:
+            self._fail(logger, "docker compose up failed", result.stderr.strip())
+            return False
+        if logger:
+            logger.success(f"Started {service.rendered.container_name}")
+
+        if logger:
+            logger.step("Waiting for healthcheck")
+        health = service.wait_healthy(timeout=self.timeout)
+        report["steps"]["healthcheck"] = health.passed
+        if not health.passed:
+            self._fail(logger, f"Healthcheck failed: {health.output}", service.logs())
+            return False
+        if logger:
+            logger.success(f"Healthy after {health.duration:.1f}s")
+
+        if logger:
+            logger.step("Running smoke test")
+        if not service.smoke_test:
+            report["steps"]["smoke_test"] = None
+            if logger:
+                logger.warning("No smoke_test declared in addon.yml, skipped")
+            return True
+
+        smoke = service.run_smoke_test()
+        report["steps"]["smoke_test"] = smoke.passed
+        if not smoke.passed:
+            self._fail(logger, f"Smoke test failed: {smoke.output}", service.logs())
+            return False
+        if logger:
+            logger.success(
+                service.smoke_test.get("description", "Smoke test passed")
+                + f" ({smoke.duration:.1f}s)"
+            )
+        return True
+
+    def _fail(self, logger, message: str, details: str = "") -> None:
+        if logger:
+            logger.log_error(message)
+            if details:
+                logger.log_output(details)
+                self.console.print(f"[dim]{details}[/dim]")
+
+
+class AddonsCopyCommand(BaseCommand):
+    """Copy a database addon into another, anonymized, via the orchestrator."""
+
+    def __init__(
+        self,
+        source: str,
+        target: str,
+        no_anonymize: bool = False,
+        dry_run: bool = False,
+        yes: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.source = source
+        self.target = target
+        self.no_anonymize = no_anonymize
+        self.dry_run = dry_run
+        self.yes = yes
+
+    def execute(self) -> None:
+        from cli.core.anonymizer import ProfileError
+        from cli.services.addon_copy_service import AddonCopyService, COPYABLE_TYPES
+
+        service = AddonCopyService()
+        try:
+            source = service.resolve(self.source)
+            target = service.resolve(self.target)
+            profile = service.profile(source.project)
+        except (ValueError, ProfileError) as e:
+            self._error(str(e))
+
+        # Safe by default: production data is never overwritten, and data
+        # only leaves a project anonymized unless explicitly asked otherwise
+        if target.environment == "production":
+            self._error(
+                f"{target.ref} belongs to a production project, refusing to "
+                f"overwrite it\nOnly projects with another environment "
+                f"(project.environment in the manifest) can be copy targets"
+            )
+        if source.ref == target.ref:
+            self._error("Source and target are the same addon")
+        if source.type != target.type:
+            self._error(f"Can't copy {source.type} into {target.type}")
+        if source.type not in COPYABLE_TYPES:
+            self._error(
+                f"addons:copy supports {', '.join(COPYABLE_TYPES)}, "
+                f"not {source.type}"
+            )
+        for endpoint in (source, target):
+            missing = service.missing_credentials(endpoint)
+            if missing:
+                self._error(
+                    f"{endpoint.ref} has no {missing} yet\n"
+                    f"Run: superdeploy {endpoint.project}:up"
+                )
+        if not profile and not self.no_anonymize:
+            self._error(
+                f"No app of {source.project} declares an anonymize profile\n"
+                f"Add one (apps.<app>.anonymize in the manifest) or pass "
+                f"--no-anonymize to copy the data as-is"
+            )
+
+        rules = sum(len(columns) for columns in profile.values())
+        if not self.json_output:
+            self.show_header(
+                title="Copy Addon",
+                subtitle=f"{source.ref} → {target.ref}",
+                details={
+                    "Source": f"{source.ref} ({source.environment})",
+                    "Target": f"{target.ref} ({target.environment})",
+                    "Anonymize": f"{rules} column rule(s)" if rules else "no",
+                },
+            )
+            for table, columns in sorted(profile.items()):
+                for column, rule in sorted(columns.items()):
+                    self.console.print(f"  {table}.{column}: [cyan]{rule}[/cyan]")
+            if profile:
+                self.console.print()
+
+        result = {
+            "source": source.to_dict(),
+            "target": target.to_dict(),
+            "profile": profile,
+            "copied": False,
+        }
+        if self.dry_run:
+            if self.json_output:
+                self.output_json(result)
+                return
+            self.print_dim("Dry run - nothing copied")
+            return
+
+        if not self.yes:
+            if self.json_output:
+                self._error("Copying replaces the target's data, pass --yes")
+            self.print_warning(
+                f"All tables of {target.ref} that exist in {source.ref} are replaced"
+            )
+            if not self.confirm("Continue?"):
+                self.print_dim("Cancelled")
+                return
+
+        from cli.constants import DEFAULT_SSH_KEY_PATH, DEFAULT_SSH_USER
+        from cli.core.orchestrator_loader import OrchestratorLoader
+        from cli.models.ssh import SSHConfig
+        from cli.services.ssh_service import SSHService
+
+        try:
+            orchestrator = OrchestratorLoader(self.project_root / "shared").load()
+            orchestrator_ip = orchestrator.get_ip()
+        except FileNotFoundError:
+            orchestrator_ip = None
+        if not orchestrator_ip:
+            self._error(
+                "Orchestrator not deployed (copies run on the orchestrator)\n"
+                "Run: superdeploy orchestrator:up"
             )
 
+        script = service.build_script(source, target, profile)
+        ssh = SSHService(
+            SSHConfig(key_path=DEFAULT_SSH_KEY_PATH, user=DEFAULT_SSH_USER)
+        )
+        self.print_dim(f"Streaming {source.ref} → {target.ref} on the orchestrator")
+        copy = ssh.execute_command(
+            orchestrator_ip, "bash -s", timeout=6 * 3600, input_data=script
+        )
+        if copy.returncode != 0:
+            self._error(
+                f"Copy failed, {target.ref} is unchanged:\n"
+                f"{copy.stderr.strip()[-2000:]}"
+            )
+
+        result["copied"] = True
+        if self.json_output:
+            self.output_json(result)
+            return
+        self.print_success(f"Copied {source.ref} into {target.ref}")
+        summary = [line for line in copy.stderr.splitlines() if "Anonymized" in line]
+        if summary:
+            self.print_dim(summary[-1])
+
+    def _error(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
 
 # Click command wrappers
 @click.command(name="addons:list")
+@click.option("--tree", is_flag=True, help="Show dependency graph and deploy order")
 @click.option("--verbose", "-v", is_flag=True)
 @click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
-def addons_list(project, verbose, json_output):
+def addons_list(project, tree, verbose, json_output):
     """
     List all addon instances
 
     Examples:
         superdeploy cheapa:addons:list
+        superdeploy cheapa:addons:list --tree
         superdeploy cheapa:addons
     """
-    cmd = AddonsListCommand(project, verbose=verbose, json_output=json_output)
+    cmd = AddonsListCommand(project, tree=tree, verbose=verbose, json_output=json_output)
     cmd.run()
 
 
@@ -1196,6 +1597,108 @@ def addons_detach(project, addon, app, verbose, json_output):
     cmd.run()
 
 
+@click.command(name="addons:test")
+@click.argument("addon_type")  # postgres, redis, rabbitmq, etc.
+@click.option("--plan", default="standard", help="Resource plan (default: standard)")
+@click.option("--instance", default="primary", help="Instance name (default: primary)")
+@click.option("--version", help="Image version (default: addon.yml version)")
+@click.option("--timeout", type=int, help="Healthcheck timeout in seconds")
+@click.option("--keep", is_flag=True, help="Keep rendered files after teardown")
+@click.option(
+    "--render-only", is_flag=True, help="Render templates without starting Docker"
+)
+@click.option("--verbose", "-v", is_flag=True)
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def addons_test(
+    addon_type,
+    plan,
+    instance,
+    version,
+    timeout,
+    keep,
+    render_only,
+    verbose,
+    json_output,
+):
+    """
+    Render an addon and boot it on local Docker
+
+    Renders compose.yml.j2, .env and templates/ the way the addon-deployer
+    role does (throwaway project, free local ports, generated secrets), then
+    runs the addon healthcheck and the smoke_test from addon.yml and tears
+    everything down.
+
+    \b
+    Examples:
+      superdeploy addons:test postgres
+      superdeploy addons:test redis --plan small
+      superdeploy addons:test rabbitmq --keep       # Inspect rendered files
+      superdeploy addons:test mongodb --render-only # No Docker needed
+    """
+    cmd = AddonsTestCommand(
+        addon_type,
+        plan=plan,
+        instance=instance,
+        version=version,
+        keep=keep,
+        render_only=render_only,
+        timeout=timeout,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="addons:copy")
+@click.argument("source")  # prod:databases.primary
+@click.argument("target")  # staging:databases.primary
+@click.option(
+    "--no-anonymize",
+    is_flag=True,
+    help="Copy as-is when the source's apps declare no profile",
+)
+@click.option("--dry-run", is_flag=True, help="Show the copy and profile only")
+@click.option("--yes", "-y", is_flag=True, help="Don't ask before replacing data")
+@click.option("--verbose", "-v", is_flag=True)
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def addons_copy(
+    source, target, no_anonymize, dry_run, yes, verbose, json_output, project=None
+):
+    """
+    Copy a database into another project's, anonymized
+
+    The dump streams on the orchestrator (never to this machine) through
+    the anonymization profiles of the source's apps:
+
+    \b
+    apps:
+      api:
+        anonymize:
+          users: {email: fake:email, name: fake, password: hash, phone: null}
+
+    Rules: keep, null, hash, fake[:email|name|first_name|last_name|phone|
+    address|ip|text]. Targets in production projects are refused; the
+    target's tables are replaced in one transaction (all or nothing).
+
+    \b
+    Examples:
+      superdeploy addons:copy cheapa:databases.primary \\
+          cheapa-staging:databases.primary --dry-run
+      superdeploy addons:copy cheapa:databases.primary \\
+          cheapa-staging:databases.primary --yes
+    """
+    cmd = AddonsCopyCommand(
+        source,
+        target,
+        no_anonymize=no_anonymize,
+        dry_run=dry_run,
+        yes=yes,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
 # Alias: addons without subcommand defaults to list
 @click.command(name="addons")
 @click.option("--verbose", "-v", is_flag=True)
@@ -1,7 +1,10 @@
 """SuperDeploy CLI - Backup command"""
 
+import base64
 import click
+import gzip
 import shutil
+import shlex
 import json
 from dataclasses import dataclass
 from datetime import datetime
@@ -45,6 +48,214 @@ class BackupService:
         else:
             return None
 
+    @staticmethod
+    def get_database_restore_command(
+        addon_name: str, addon_config: Dict, project_name: str
+    ) -> Optional[str]:
+        """Build command that loads a dump from stdin (inverse of the dump)."""
+        instance = addon_config.get("instance", "primary")
+        container_name = f"{project_name}_{addon_name}_{instance}"
+
+        if addon_name == "postgres":
+            db_user = addon_config.get("user", f"{project_name}_user")
+            db_name = addon_config.get("database", f"{project_name}_db")
+            return (
+                f"docker exec -i {container_name} "
+                f"psql -q -v ON_ERROR_STOP=1 -U {db_user} {db_name}"
+            )
+        elif addon_name == "mongodb":
+            db_name = addon_config.get("database", f"{project_name}_db")
+            command = f"docker exec -i {container_name} mongorestore --archive --drop"
+            # Dumps of another project's database are renamed into this one
+            source_db = addon_config.get("source_database")
+            if source_db and source_db != db_name:
+                command += f" --nsFrom '{source_db}.*' --nsTo '{db_name}.*'"
+            return command
+        else:
+            return None
+
+
+class ProjectBackupWriter:
+    """
+    Writes what projects:recover needs to rebuild a project: every database
+    addon as a gzipped dump (one file per instance), the image digest each
+    app runs, and manifest.json. Dumps travel base64 encoded over SSH so
+    binary archives (mongodump) survive.
+    """
+
+    EXTENSIONS = {"postgres": "sql.gz", "mongodb": "archive.gz"}
+
+    def __init__(self, project_name: str, ssh_service, state_service):
+        self.project_name = project_name
+        self.ssh_service = ssh_service
+        self.state_service = state_service
+
+    def stateful_addons(self) -> list[Dict]:
+        """Database addons with the credentials `up` generated for them"""
+        from cli.database import get_db_session, Addon, Project, Secret
+
+        db = get_db_session()
+        try:
+            project = (
+                db.query(Project).filter(Project.name == self.project_name).first()
+            )
+            if not project:
+                return []
+            addons = []
+            for addon in (
+                db.query(Addon)
+                .filter(Addon.project_id == project.id, Addon.category == "databases")
+                .order_by(Addon.id)
+            ):
+                prefix = f"{addon.type}.{addon.instance_name}."
+                credentials = {
+                    row.key[len(prefix) :]: row.value
+                    for row in db.query(Secret).filter(
+                        Secret.project_id == project.id,
+                        Secret.source == "addon",
+                        Secret.key.startswith(prefix),
+                    )
+                }
+                addons.append(
+                    {
+                        "addon": f"{addon.category}.{addon.instance_name}",
+                        "type": addon.type,
+                        "vm": addon.vm,
+                        "config": {
+                            "instance": addon.instance_name,
+                            "user": credentials.get("USER")
+                            or f"{self.project_name}_user",
+                            "database": credentials.get("DATABASE")
+                            or f"{self.project_name}_db",
+                        },
+                    }
+                )
+            return addons
+        finally:
+            db.close()
+
+    def dump_addons(self, backup_path: Path) -> list[Dict]:
+        """
+        Dump all database addons into backup_path.
+
+        Returns:
+            Manifest entries ({"addon", "type", "database", "file"})
+
+        Raises:
+            RuntimeError: If an addon can't be dumped
+        """
+        entries = []
+        for addon in self.stateful_addons():
+            command = BackupService.get_database_dump_command(
+                addon["type"], addon["config"], self.project_name
+            )
+            if not command:
+                raise RuntimeError(
+                    f"{addon['addon']}: no backup support for {addon['type']}"
+                )
+
+            vm_ip = self.state_service.get_vm_ip_by_role(addon["vm"], index=0)
+            remote = f"set -o pipefail; {command} | gzip | base64 -w0"
+            result = self.ssh_service.execute_command(
+                vm_ip, f"bash -c {shlex.quote(remote)}", timeout=3600
+            )
+            if result.returncode != 0 or not result.stdout.strip():
+                raise RuntimeError(
+                    f"{addon['addon']}: dump failed: "
+                    f"{(result.stderr or 'no output').strip()[-500:]}"
+                )
+
+            data = base64.b64decode(result.stdout.strip())
+            gzip.decompress(data)  # Truncated transfers fail here
+            file_name = f"{addon['addon']}.{self.EXTENSIONS[addon['type']]}"
+            (backup_path / file_name).write_bytes(data)
+            entries.append(
+                {
+                    "addon": addon["addon"],
+                    "type": addon["type"],
+                    "database": addon["config"]["database"],
+                    "file": file_name,
+                }
+            )
+        return entries
+
+    def record_releases(self) -> Dict[str, Dict]:
+        """
+        Image digest and last releases.json entry of every running app
+        (apps that aren't running are left out).
+        """
+        from cli.database import get_db_session, Project
+
+        db = get_db_session()
+        try:
+            project = (
+                db.query(Project).filter(Project.name == self.project_name).first()
+            )
+            apps = [
+                (app.name, app.vm or "app", sorted(p.name for p in app.processes))
+                for app in (project.apps if project else [])
+            ]
+        finally:
+            db.close()
+
+        releases = {}
+        for app_name, vm_role, processes in apps:
+            if not processes:
+                continue
+            try:
+                vm_ip = self.state_service.get_vm_ip_by_role(vm_role, index=0)
+            except Exception:
+                continue
+            probe = f"""
+set -e
+cd /opt/superdeploy/projects/{self.project_name}/compose
+cid=$(docker compose ps -q {app_name}-{processes[0]} | head -1)
+image=$(docker inspect --format '{{{{.Image}}}}' "$cid")
+digest=$(docker image inspect --format '{{{{index .RepoDigests 0}}}}' "$image")
+release=$(jq -c --arg app {app_name} '.[$app][-1] // {{}}' ../releases.json \
+  2>/dev/null || echo '{{}}')
+jq -cn --arg image "$digest" --argjson release "$release" \
+  '$release + {{image: $image}}'
+"""
+            result = self.ssh_service.execute_command(
+                vm_ip, f"bash -c {shlex.quote(probe)}", timeout=60
+            )
+            try:
+                release = json.loads(result.stdout.strip().splitlines()[-1])
+            except (ValueError, IndexError):
+                continue
+            if result.returncode == 0 and "@sha256:" in release.get("image", ""):
+                releases[app_name] = release
+        return releases
+
+    def write(
+        self, backup_path: Path, kind: str = "manual", extra: Optional[Dict] = None
+    ) -> Dict:
+        """
+        Dump addons, record releases and write manifest.json.
+
+        Args:
+            kind: Recorded in the manifest (manual, final)
+            extra: More manifest fields
+
+        Returns:
+            The manifest
+
+        Raises:
+            RuntimeError: If an addon can't be dumped
+        """
+        backup_path.mkdir(parents=True, exist_ok=True)
+        manifest = {
+            "project": self.project_name,
+            "kind": kind,
+            "backup_date": datetime.now().isoformat(),
+            **(extra or {}),
+            "addons": self.dump_addons(backup_path),
+            "releases": self.record_releases(),
+        }
+        (backup_path / "manifest.json").write_text(json.dumps(manifest, indent=2))
+        return manifest
+
 
 class BackupsCreateCommand(ProjectCommand):
     """Backup project database and configurations."""
@@ -116,17 +327,17 @@ class BackupsCreateCommand(ProjectCommand):
             progress.advance(task3)
             self.console.print("[green]✓[/green] Configs backed up")
 
-            # Step 4: Create backup manifest
+            # Step 4: Addon dumps and app releases (for projects:recover), manifest
             task4 = progress.add_task("[cyan]Creating manifest...", total=1)
             if logger:
-                logger.log("Creating backup manifest")
+                logger.log("Dumping addons, recording releases, creating manifest")
             metadata = BackupMetadata(
                 project=self.project_name,
                 timestamp=timestamp,
                 backup_date=datetime.now().isoformat(),
                 files=["database.sql", "config.yml", "secrets.json", "compose/"],
             )
-            self._save_manifest(backup_path, metadata)
+            self._save_manifest(backup_path, metadata, ssh_service)
             progress.advance(task4)
             self.console.print("[green]✓[/green] Manifest created")
 
@@ -134,6 +345,9 @@ class BackupsCreateCommand(ProjectCommand):
             logger.success(f"Backup completed: {backup_path}")
         self._display_completion_message(backup_path)
 
+        if not self.run_hooks("after_backup", logger=logger, backup=str(backup_path)):
+            raise SystemExit(1)
+
         if not self.verbose:
             self.console.print(f"\n[dim]Logs saved to:[/dim] {logger.log_path}\n")
 
@@ -199,20 +413,21 @@ class BackupsCreateCommand(ProjectCommand):
         except Exception as e:
             self.console.print(f"[yellow]⚠[/yellow] Config backup failed: {e}")
 
-    def _save_manifest(self, backup_path: Path, metadata: BackupMetadata) -> None:
-        """Save backup manifest."""
-        manifest_file = backup_path / "manifest.json"
-        manifest_file.write_text(
-            json.dumps(
-                {
-                    "project": metadata.project,
-                    "timestamp": metadata.timestamp,
-                    "backup_date": metadata.backup_date,
-                    "files": metadata.files,
-                },
-                indent=2,
-            )
+    def _save_manifest(
+        self, backup_path: Path, metadata: BackupMetadata, ssh_service
+    ) -> None:
+        """Save backup manifest (with every addon's dump and the app releases)."""
+        writer = ProjectBackupWriter(
+            self.project_name, ssh_service, self.state_service
         )
+        try:
+            writer.write(
+                backup_path,
+                kind="manual",
+                extra={"timestamp": metadata.timestamp, "files": metadata.files},
+            )
+        except RuntimeError as e:
+            self.exit_with_error(f"Backup incomplete: {e}")
 
     def _display_completion_message(self, backup_path: Path) -> None:
         """Display backup completion message."""
@@ -239,6 +454,8 @@ def backups_create(project, output, verbose, json_output):
     \b
     This command backs up:
     - PostgreSQL database dump
+    - Every database addon (gzipped, one file each) and the image digest
+      each app runs, for projects:recover
     - Project configuration files
     - Environment variables (encrypted)
     - Docker compose files
@@ -0,0 +1,110 @@
+"""SuperDeploy CLI - Project blueprints
+
+Lists the blueprints `init --blueprint` can create a project from: the
+bundled ones in blueprints/ and your own in ~/.superdeploy/blueprints.
+"""
+
+import click
+from rich.table import Table
+from cli.base import BaseCommand
+
+
+class BlueprintsCommand(BaseCommand):
+    """List blueprints, or show one blueprint's parameters."""
+
+    def __init__(
+        self, name: str = None, verbose: bool = False, json_output: bool = False
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.name = name
+
+    def execute(self) -> None:
+        """Execute blueprints command."""
+        from cli.core.blueprint import BlueprintError, find, list_blueprints
+
+        try:
+            if self.name:
+                blueprints = [find(self.name, self.project_root)]
+            else:
+                blueprints = list_blueprints(self.project_root)
+        except BlueprintError as e:
+            if self.json_output:
+                self.output_json_error(str(e))
+            self.exit_with_error(str(e))
+
+        if self.json_output:
+            self.output_json({"blueprints": [b.to_dict() for b in blueprints]})
+            return
+
+        self.show_header(title="Project Blueprints")
+
+        if not blueprints:
+            self.console.print("[dim]No blueprints found[/dim]")
+            return
+
+        if not self.name:
+            table = Table(show_header=True, header_style="bold cyan")
+            table.add_column("Name", style="cyan")
+            table.add_column("Description")
+            table.add_column("Apps")
+            table.add_column("Required")
+            for blueprint in blueprints:
+                info = blueprint.to_dict()
+                table.add_row(
+                    info["name"],
+                    info["description"],
+                    ", ".join(info["apps"]) or "-",
+                    ", ".join(blueprint.required_params()) or "-",
+                )
+            self.console.print(table)
+            self.console.print(
+                "\n[dim]Parameters: superdeploy blueprints <name>[/dim]\n"
+            )
+            return
+
+        info = blueprints[0].to_dict()
+        self.console.print(f"[bold]{info['name']}[/bold]  [dim]{info['source']}[/dim]")
+        if info["description"]:
+            self.console.print(info["description"])
+        self.console.print(f"\nVMs:    {', '.join(info['vms'])}")
+        self.console.print(f"Apps:   {', '.join(info['apps']) or '-'}")
+        self.console.print(f"Addons: {', '.join(info['addons']) or '-'}\n")
+
+        table = Table(show_header=True, header_style="bold cyan")
+        table.add_column("Parameter", style="cyan")
+        table.add_column("Type")
+        table.add_column("Default")
+        table.add_column("Description")
+        for param, spec in info["params"].items():
+            default = "[red]required[/red]" if spec["required"] else spec["default"]
+            table.add_row(param, spec["type"], str(default), spec["description"])
+        self.console.print(table)
+
+        sets = " ".join(
+            f"--set {p}=..." for p, spec in info["params"].items() if spec["required"]
+        )
+        self.console.print(
+            f"\n[dim]superdeploy <project>:init --blueprint {self.name} {sets}[/dim]\n"
+        )
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="blueprints")
+@click.argument("name", required=False)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def blueprints(name, verbose, json_output):
+    """
+    List project blueprints
+
+    \b
+    Examples:
+      superdeploy blueprints                 # All blueprints
+      superdeploy blueprints saas-basic      # Parameters of one blueprint
+    """
+    cmd = BlueprintsCommand(name, verbose=verbose, json_output=json_output)
+    cmd.run()
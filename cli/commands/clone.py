@@ -0,0 +1,426 @@
+"""SuperDeploy CLI - Clone a project (staging copies)
+
+Copies apps, processes, addons, VMs and secrets of a project into a new
+project with its own subnets, rewriting domains by pattern. Addon
+credentials are generated for the clone on its first `up`, and data can be
+seeded from the source's latest backup through an anonymization hook.
+"""
+
+import click
+from pathlib import Path
+from cli.base import BaseCommand
+from cli.commands.manifest import ACTION_STYLES
+
+
+class ProjectsCloneCommand(BaseCommand):
+    """Clone a project, or copy its secrets into another environment."""
+
+    def __init__(
+        self,
+        source: str,
+        target: str,
+        from_env: str = "production",
+        to_env: str = "production",
+        environment: str = "staging",
+        domain_patterns: tuple = (),
+        seed: bool = False,
+        seed_only: bool = False,
+        backup_path: str = None,
+        anonymize: str = None,
+        dry_run: bool = False,
+        yes: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.source = source
+        self.target = target
+        self.from_env = from_env
+        self.to_env = to_env
+        self.environment = environment
+        self.domain_patterns = list(domain_patterns)
+        self.seed = seed or seed_only
+        self.seed_only = seed_only
+        self.backup_path = backup_path
+        self.anonymize = anonymize
+        self.dry_run = dry_run
+        self.yes = yes
+
+    def execute(self) -> None:
+        """Execute projects:clone command."""
+        from cli.core.addon_schema import validate_data
+        from cli.services.clone_service import (
+            ProjectCloneService,
+            parse_domain_patterns,
+        )
+        from cli.services.manifest_service import ManifestService
+
+        try:
+            patterns = parse_domain_patterns(self.domain_patterns)
+        except ValueError as e:
+            self._fail(f"Invalid --domain-pattern: {e}")
+
+        manifests = ManifestService()
+        clones = ProjectCloneService()
+        source_manifest, _ = manifests.export(self.source)
+        if source_manifest is None:
+            self._fail(f"Project '{self.source}' not found")
+        target_exists = manifests.export(self.target)[0] is not None
+
+        if self.source == self.target:
+            self._copy_environment(clones)
+            return
+        if self.seed_only:
+            if not target_exists:
+                self._fail(f"Project '{self.target}' not found")
+            seeded = self._seed(clones)
+            if self.json_output:
+                self.output_json({"project": self.target, "seed": seeded})
+            return
+        if target_exists:
+            self._fail(
+                f"Project '{self.target}' already exists\n"
+                f"Use --seed-only to load data into it"
+            )
+
+        # Subnets are allocated (and persisted) only when the clone is created
+        subnets = None
+        if not self.dry_run:
+            from cli.subnet_allocator import SubnetAllocator
+
+            allocator = SubnetAllocator()
+            subnets = (
+                allocator.get_subnet(self.target),
+                allocator.get_docker_subnet(self.target),
+            )
+
+        manifest, domains = clones.clone_manifest(
+            source_manifest, self.target, patterns, subnets, self.environment
+        )
+        issues = validate_data("manifest", manifest)
+        if issues:
+            self._fail(
+                "Cloned manifest is invalid:\n"
+                + "\n".join(f"  {issue.path}: {issue.message}" for issue in issues)
+            )
+        changes, problems = manifests.plan(manifest)
+        if problems:
+            self._fail(
+                f"'{self.source}' can't be cloned:\n"
+                + "\n".join(f"  • {problem}" for problem in problems)
+            )
+
+        if not self.json_output:
+            self.show_header(
+                title="Clone Project",
+                project=self.target,
+                details={
+                    "Source": self.source,
+                    "Environment": self.environment,
+                    "Secrets": f"{self.from_env} → {self.to_env}",
+                    "Subnets": " / ".join(subnets) if subnets else "allocated on apply",
+                },
+            )
+            self.print_plan(changes, domains)
+
+        if self.dry_run:
+            if self.json_output:
+                self.output_json(self.to_dict(changes, domains, None, applied=False))
+                return
+            self.print_dim("Dry run - nothing cloned")
+            return
+
+        manifests.apply(manifest, changes)
+        copied = clones.copy_secrets(
+            self.source, self.target, self.from_env, self.to_env, domains
+        )
+        (self.project_root / "projects" / self.target).mkdir(
+            parents=True, exist_ok=True
+        )
+
+        if self.json_output:
+            result = self.to_dict(changes, domains, copied, applied=True)
+            if self.seed:
+                result["seed"] = self._seed(clones)
+            self.output_json(result)
+            return
+
+        self.print_success(
+            f"Cloned {self.source} into {self.target}: {len(changes)} change(s), "
+            f"{copied.secrets} secret(s), {copied.files} file(s)"
+        )
+        self.print_copy_result(copied)
+        if self.seed:
+            self._seed(clones)
+            return
+        self.print_next_steps(changes, copied)
+
+    def _copy_environment(self, clones) -> None:
+        """source == target: copy one environment's secrets into another"""
+        if self.from_env == self.to_env:
+            self._fail("Cloning a project into itself needs --from-env != --to-env")
+        if self.seed:
+            self._fail("Environments share the project's database, nothing to seed")
+
+        if self.dry_run:
+            if self.json_output:
+                self.output_json(
+                    {"project": self.source, "applied": False, "copied": None}
+                )
+                return
+            self.print_dim("Dry run - nothing copied")
+            return
+
+        copied = clones.copy_secrets(
+            self.source, self.source, self.from_env, self.to_env
+        )
+        if self.json_output:
+            self.output_json(
+                {
+                    "project": self.source,
+                    "from_env": self.from_env,
+                    "to_env": self.to_env,
+                    "copied": copied.to_dict(),
+                    "applied": True,
+                }
+            )
+            return
+        self.print_success(
+            f"Copied {copied.secrets} secret(s) and {copied.files} file(s) "
+            f"of {self.source} from {self.from_env} to {self.to_env}"
+        )
+        self.print_copy_result(copied)
+
+    def _seed(self, clones) -> dict:
+        """Load the source's latest backup into the clone's database"""
+        from cli.commands.backup import BackupService
+        from cli.services.state_service import StateService
+        from cli.services.vm_service import VMService
+
+        follow_up = (
+            f"superdeploy projects:clone {self.source} {self.target} --seed-only"
+        )
+        if self.anonymize:
+            follow_up += f" --anonymize '{self.anonymize}'"
+
+        state = StateService(self.project_root, self.target)
+        if not state.has_state():
+            if self.json_output:
+                return {"seeded": False, "next": [f"{self.target}:up", follow_up]}
+            self.print_warning(f"{self.target} isn't deployed yet, nothing seeded")
+            self.console.print(
+                f"\n[bold]Next steps:[/bold]\n"
+                f"  1. [cyan]superdeploy {self.target}:up[/cyan]\n"
+                f"  2. [cyan]{follow_up}[/cyan]"
+            )
+            return {"seeded": False}
+
+        if self.backup_path:
+            dump_path = Path(self.backup_path)
+            if dump_path.is_dir():
+                dump_path = dump_path / "database.sql"
+        else:
+            # Where backups:create writes by default
+            dump_path = clones.latest_backup(
+                self.source, Path("./backups") / self.source
+            )
+            if dump_path is None:
+                self._fail(
+                    f"No backup of {self.source} found\n"
+                    f"Run: superdeploy {self.source}:backups:create"
+                )
+        if not dump_path.is_file() or not dump_path.stat().st_size:
+            self._fail(f"No database dump at {dump_path}")
+
+        target = clones.seed_target(self.target)
+        if target is None:
+            self._fail(f"{self.target} has no postgres or mongodb addon to seed")
+        addon_type, addon_config = target
+
+        if not self.anonymize and not self.yes:
+            if self.json_output:
+                self._fail("Seeding without --anonymize needs --yes")
+            self.print_warning(
+                f"{dump_path} is loaded into {self.target} as-is (no --anonymize)"
+            )
+            if not self.confirm("Copy production data into the clone?"):
+                self.print_dim("Seed cancelled")
+                return {"seeded": False}
+
+        dump = dump_path.read_text()
+        if self.anonymize:
+            self.print_dim(f"Anonymizing with: {self.anonymize}")
+            try:
+                dump = clones.anonymize(dump, self.anonymize, self.source, self.target)
+            except RuntimeError as e:
+                self._fail(f"{e}\nNothing was loaded into {self.target}")
+
+        if addon_type == "postgres":
+            dump = clones.retarget_dump(dump, self.source, self.target)
+        else:
+            addon_config["source_database"] = f"{self.source}_db"
+        command = BackupService.get_database_restore_command(
+            addon_type, addon_config, self.target
+        )
+
+        vm_ip = state.get_vm_ip_by_role("core", index=0)
+        ssh_service = VMService(self.project_root, self.target).get_ssh_service()
+        self.print_dim(f"Loading {dump_path} into {self.target} ({addon_type})")
+        result = ssh_service.execute_command(
+            vm_ip, command, timeout=3600, input_data=dump
+        )
+        if result.returncode != 0:
+            self._fail(f"Seeding failed: {result.stderr.strip()[-1000:]}")
+
+        self.print_success(f"Seeded {self.target} from {dump_path}")
+        return {
+            "seeded": True,
+            "from": str(dump_path),
+            "anonymized": bool(self.anonymize),
+        }
+
+    def print_plan(self, changes, domains) -> None:
+        """Planned database changes and domain rewrites"""
+        for change in changes:
+            marker, color = ACTION_STYLES[change.action]
+            self.console.print(f"[{color}]{marker} {change.resource}[/{color}]")
+            for name, (_, new) in change.fields.items():
+                self.console.print(f"    {name}: {new}")
+
+        if domains:
+            self.console.print("\n[bold]Domains:[/bold]")
+            for old, new in domains.items():
+                if new:
+                    self.console.print(f"  {old} → [cyan]{new}[/cyan]")
+                else:
+                    self.console.print(
+                        f"  [yellow]{old} dropped[/yellow] "
+                        f"[dim](no --domain-pattern matches)[/dim]"
+                    )
+        self.console.print()
+
+    def print_copy_result(self, copied) -> None:
+        if copied.rewritten:
+            self.print_dim(f"Domains rewritten in {copied.rewritten} secret value(s)")
+        if copied.regenerated:
+            self.print_dim(
+                f"{copied.regenerated} addon credential(s) not copied, "
+                f"generated on the first up"
+            )
+        for skipped in copied.skipped:
+            self.print_warning(f"Not copied: {skipped}")
+
+    def print_next_steps(self, changes, copied) -> None:
+        from cli.core.manifest import rollout_commands
+
+        steps = [f"{self.target}:up"] + rollout_commands(self.target, changes)
+        if copied.secrets or copied.files:
+            steps.append(f"{self.target}:config:push")
+        self.console.print("\n[bold]Next steps:[/bold]")
+        for i, step in enumerate(dict.fromkeys(steps), 1):
+            self.console.print(f"  {i}. [cyan]superdeploy {step}[/cyan]")
+        self.console.print(
+            f"  [dim]Seed data: superdeploy projects:clone {self.source} "
+            f"{self.target} --seed-only --anonymize <cmd>[/dim]\n"
+        )
+
+    def to_dict(self, changes, domains, copied, applied: bool) -> dict:
+        return {
+            "source": self.source,
+            "project": self.target,
+            "changes": [c.to_dict() for c in changes],
+            "domains": domains,
+            "copied": copied.to_dict() if copied else None,
+            "applied": applied,
+        }
+
+    def _fail(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="projects:clone")
+@click.argument("source")
+@click.argument("target")
+@click.option("--from-env", default="production", help="Secrets environment to copy")
+@click.option("--to-env", default="production", help="Environment to copy into")
+@click.option(
+    "--environment",
+    default="staging",
+    help="The clone's project environment (default: staging)",
+)
+@click.option(
+    "--domain-pattern",
+    "domain_patterns",
+    multiple=True,
+    metavar="FROM=TO",
+    help="Domain rewrite, e.g. '*.acme.com=*.staging.acme.com' (repeatable)",
+)
+@click.option("--seed", is_flag=True, help="Load the source's latest backup")
+@click.option("--seed-only", is_flag=True, help="Only seed an existing clone")
+@click.option("--backup", "backup_path", help="Backup directory or dump to seed from")
+@click.option("--anonymize", help="Command the dump is piped through before loading")
+@click.option("--dry-run", is_flag=True, help="Show what would be cloned")
+@click.option("--yes", "-y", is_flag=True, help="Seed without --anonymize unasked")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def projects_clone(
+    source,
+    target,
+    from_env,
+    to_env,
+    environment,
+    domain_patterns,
+    seed,
+    seed_only,
+    backup_path,
+    anonymize,
+    dry_run,
+    yes,
+    verbose,
+    json_output,
+):
+    """
+    Clone a project (e.g. a staging copy)
+
+    \b
+    Copied:   apps, processes, addons, VMs, secrets and secret files
+    New:      subnets, addon credentials (generated on the first up)
+    Domains:  rewritten by --domain-pattern, dropped if no pattern matches
+
+    --seed loads the source's latest backup (backups:create) into the
+    clone once it is up. --anonymize gets the dump on stdin and prints
+    the anonymized dump; SUPERDEPLOY_SOURCE_PROJECT and
+    SUPERDEPLOY_TARGET_PROJECT are set for it.
+
+    \b
+    Examples:
+      superdeploy projects:clone cheapa cheapa-staging \\
+          --domain-pattern '*.cheapa.io=*.staging.cheapa.io'
+      superdeploy projects:clone cheapa cheapa-staging --seed-only \\
+          --anonymize ./scripts/anonymize.sh
+      superdeploy projects:clone cheapa cheapa --from-env production --to-env staging
+    """
+    cmd = ProjectsCloneCommand(
+        source,
+        target,
+        from_env=from_env,
+        to_env=to_env,
+        environment=environment,
+        domain_patterns=domain_patterns,
+        seed=seed,
+        seed_only=seed_only,
+        backup_path=backup_path,
+        anonymize=anonymize,
+        dry_run=dry_run,
+        yes=yes,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
@@ -19,7 +19,9 @@ class ConfigSetCommand(ProjectCommand):
         app: str = None,
         environment: str = "production",
         deploy: bool = False,
+        apply: bool = False,
         no_sync: bool = False,
+        usage: str = None,
         verbose: bool = False,
         json_output: bool = False,
     ):
@@ -28,7 +30,9 @@ class ConfigSetCommand(ProjectCommand):
         self.app = app
         self.environment = environment
         self.deploy = deploy
+        self.apply = apply
         self.no_sync = no_sync
+        self.usage = usage
 
     def execute(self) -> None:
         """Execute config:set command - Database-backed."""
@@ -41,6 +45,9 @@ class ConfigSetCommand(ProjectCommand):
         except ValueError:
             self.exit_with_error("Invalid format! Use: KEY=VALUE")
 
+        if self.apply and self.deploy:
+            self.exit_with_error("Use either --apply or --deploy, not both")
+
         self.show_header(
             title="Set Configuration",
             subtitle="Heroku-like config management (Database)",
@@ -61,12 +68,14 @@ class ConfigSetCommand(ProjectCommand):
             # Set secret in database
             if self.app:
                 # App-specific secret
-                secret_mgr.set_app_secret(self.app, key, value)
+                secret_mgr.set_app_secret(self.app, key, value, usage=self.usage)
                 location = f"app:{self.app}"
             else:
                 # Shared secret (all apps)
-                secret_mgr.set_shared_secret(key, value)
+                secret_mgr.set_shared_secret(key, value, usage=self.usage)
                 location = "shared"
+            if self.usage:
+                location += f", {self.usage}"
 
             if logger:
                 logger.log(f"✓ Updated {key} in {location}")
@@ -129,8 +138,22 @@ class ConfigSetCommand(ProjectCommand):
             if logger:
                 logger.log("--no-sync flag provided")
 
-        # Step 3: Trigger Deployment
-        if self.deploy:
+        # Step 3: Apply on VMs (no CI run) or trigger deployment
+        if self.apply:
+            if logger:
+                logger.step("[3/4] Applying to VMs")
+            apps = [self.app] if self.app else self.list_apps()
+            applied, failed = apply_app_env(
+                self.project_root, self.project_name, self.environment, apps, logger
+            )
+            if logger:
+                logger.step("[4/4] Summary")
+            if logger:
+                logger.log(f"{applied} app(s) updated, {failed} failed")
+            if failed:
+                raise SystemExit(1)
+
+        elif self.deploy:
             if logger:
                 logger.step("[3/4] Triggering Deployment")
 
@@ -238,14 +261,203 @@ class ConfigSetCommand(ProjectCommand):
         self.console.print(f"  [cyan]{key}[/cyan] = [green]{value}[/green]")
         self.console.print()
 
-        if self.deploy:
+        if self.deploy or self.apply:
             self.console.print(
                 f"[dim]💡 Tip: Check deployment status with 'superdeploy {self.project_name}:status'[/dim]"
             )
         else:
             self.console.print(
-                "[dim]💡 Tip: Run with --deploy to trigger deployment automatically[/dim]"
+                "[dim]💡 Tip: Run with --apply to push .env and restart apps (no CI run)[/dim]"
+            )
+
+
+def apply_app_env(
+    project_root: Path,
+    project_name: str,
+    environment: str,
+    apps: list,
+    logger=None,
+    restart: bool = True,
+    force: bool = False,
+    enforce_contract: bool = True,
+) -> tuple:
+    """
+    Push rendered .env files to the apps' VMs and roll their services.
+
+    Apps whose env violates their marker's config contract are skipped.
+    Apps whose build-time config changed are rebuilt instead of restarted.
+
+    Returns:
+        (applied, failed) counts
+    """
+    from cli.exceptions import ConfigContractError
+    from cli.services.app_env_service import AppEnvService
+
+    service = AppEnvService(project_root, project_name, environment)
+    applied = failed = 0
+
+    for app_name in apps:
+        try:
+            pushed, rollout = service.apply(
+                app_name,
+                restart=restart,
+                force=force,
+                enforce_contract=enforce_contract,
+            )
+        except ConfigContractError as e:
+            if logger:
+                logger.log_error(f"✗ {app_name}: {e.message}", context=e.context)
+            failed += 1
+            continue
+        except Exception as e:
+            if logger:
+                logger.log_error(f"✗ {app_name}: {e}")
+            failed += 1
+            continue
+
+        if not pushed.changed:
+            if logger:
+                logger.log(f"✓ {app_name}: .env unchanged on {pushed.vm_name}")
+            applied += 1
+            continue
+
+        if pushed.env_changed:
+            if logger:
+                logger.log(
+                    f"✓ {app_name}: .env written on {pushed.vm_name} "
+                    f"({pushed.key_count} vars, sha256 {pushed.checksum[:12]})"
+                )
+        if pushed.files_changed:
+            if logger:
+                logger.log(
+                    f"✓ {app_name}: {pushed.file_count} secret file(s) written on "
+                    f"{pushed.vm_name} (tmpfs, read-only mounts)"
+                )
+        if pushed.build_changed and restart:
+            if logger:
+                logger.log(
+                    f"  ⚒ build-time config changed: rebuild triggered "
+                    f"(git push origin {environment})"
+                )
+            applied += 1
+        elif pushed.build_changed:
+            if logger:
+                logger.warning(
+                    f"⚠ {app_name}: build-time config changed, image is stale "
+                    f"(rebuild: superdeploy {project_name}:config:push -a {app_name})"
+                )
+            applied += 1
+        elif rollout.action == "skipped":
+            applied += 1
+        elif rollout.success:
+            restarted = ", ".join(rollout.restarted) or "no services found"
+            if logger:
+                logger.log(f"  ↻ restarted: {restarted}")
+            applied += 1
+        else:
+            if logger:
+                logger.log_error(
+                    f"✗ {app_name}: {rollout.failed} did not become healthy",
+                    context="Previous .env/secret files restored and restarted services recreated",
+                )
+            if logger and rollout.output:
+                logger.log_output(rollout.output)
+            failed += 1
+
+    return applied, failed
+
+
+class ConfigPushCommand(ProjectCommand):
+    """Render app .env files from the database and push them to the VMs."""
+
+    def __init__(
+        self,
+        project_name: str,
+        app: str = None,
+        environment: str = "production",
+        restart: bool = True,
+        force: bool = False,
+        dry_run: bool = False,
+        enforce_contract: bool = True,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.app = app
+        self.environment = environment
+        self.restart = restart
+        self.force = force
+        self.dry_run = dry_run
+        self.enforce_contract = enforce_contract
+
+    def execute(self) -> None:
+        """Execute config:push command."""
+        apps = [self.app] if self.app else self.list_apps()
+        if self.app:
+            self.get_app_config(self.app)
+
+        if self.dry_run:
+            self._show_rendered(apps)
+            return
+
+        self.show_header(
+            title="Push Configuration",
+            subtitle="Render .env from database and apply on VMs",
+            project=self.project_name,
+            details={"Apps": ", ".join(apps), "Environment": self.environment},
+        )
+        self.require_deployment()
+
+        logger = self.init_logger(self.project_name, "config-push")
+        if logger:
+            logger.step("Pushing .env files")
+
+        applied, failed = apply_app_env(
+            self.project_root,
+            self.project_name,
+            self.environment,
+            apps,
+            logger,
+            restart=self.restart,
+            force=self.force,
+            enforce_contract=self.enforce_contract,
+        )
+
+        if self.json_output:
+            self.output_json({"applied": applied, "failed": failed})
+        if failed:
+            if logger:
+                logger.log_error(f"{failed} app(s) failed, {applied} updated")
+            raise SystemExit(1)
+
+        if logger:
+            logger.success(f"{applied} app(s) up to date")
+
+    def _show_rendered(self, apps: list) -> None:
+        """Print the keys each app would receive (values masked)"""
+        from cli.services.app_env_service import AppEnvService
+
+        service = AppEnvService(self.project_root, self.project_name, self.environment)
+        rendered = {app_name: service.build_env(app_name) for app_name in apps}
+
+        if self.json_output:
+            self.output_json(
+                {app_name: sorted(env) for app_name, env in rendered.items()}
             )
+            return
+
+        for app_name, env in rendered.items():
+            table = Table(
+                title=f"{app_name} ({len(env)} vars)",
+                show_header=True,
+                header_style="bold cyan",
+            )
+            table.add_column("Key", style="cyan")
+            table.add_column("Value", style="dim")
+            for key in sorted(env):
+                table.add_row(key, "***")
+            self.console.print(table)
+            self.console.print()
 
 
 class ConfigGetCommand(ProjectCommand):
@@ -279,7 +491,7 @@ class ConfigGetCommand(ProjectCommand):
         # Get value from appropriate location
         if self.app:
             # Get from app-specific secrets
-            app_secrets = secret_mgr.get_app_secrets(self.app)
+            app_secrets = secret_mgr.get_app_secrets(self.app, resolve=False)
             value = app_secrets.get(self.key)
             location = f"secrets.{self.app} (merged with shared)"
         else:
@@ -337,7 +549,7 @@ class ConfigListCommand(ProjectCommand):
         # Get appropriate secrets (DB-based system)
         if self.app:
             # Get merged secrets for app
-            env_vars = secret_mgr.get_app_secrets(self.app)
+            env_vars = secret_mgr.get_app_secrets(self.app, resolve=False)
             scope = f"{self.app} (shared + app-specific)"
         else:
             # Get only shared secrets from DB
@@ -398,6 +610,10 @@ class ConfigListCommand(ProjectCommand):
         table.add_column("Key", style="cyan", no_wrap=True)
         table.add_column("Value", style="green")
 
+        # Build-time keys are tagged next to the name
+        usages = secret_mgr.get_usages(self.app) if self.app else {}
+        usage_labels = {"build": "build only", "both": "build + runtime"}
+
         # Add rows
         for key, value in sorted(filtered.items()):
             # Skip None values
@@ -405,6 +621,9 @@ class ConfigListCommand(ProjectCommand):
                 continue
 
             value_str = str(value)
+            name = key
+            if usages.get(key) in usage_labels:
+                name = f"{key} [magenta]({usage_labels[usages[key]]})[/magenta]"
 
             # Mask sensitive values
             if any(
@@ -412,12 +631,12 @@ class ConfigListCommand(ProjectCommand):
                 for sensitive in ["PASSWORD", "TOKEN", "SECRET", "KEY", "PAT"]
             ):
                 masked_value = "***" + value_str[-4:] if len(value_str) > 4 else "***"
-                table.add_row(key, masked_value)
+                table.add_row(name, masked_value)
             else:
                 display_value = (
                     value_str[:50] + "..." if len(value_str) > 50 else value_str
                 )
-                table.add_row(key, display_value)
+                table.add_row(name, display_value)
 
         self.console.print(table)
         self.console.print()
@@ -889,6 +1108,379 @@ class ConfigShowCommand(ProjectCommand):
         self.console.print()
 
 
+class ConfigRefsCommand(ProjectCommand):
+    """List external secret references and check that they resolve."""
+
+    def __init__(
+        self,
+        project_name: str,
+        environment: str = "production",
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.environment = environment
+
+    def execute(self) -> None:
+        """Execute config:refs command."""
+        from cli.exceptions import SecretBackendError
+        from cli.secret_manager import SecretManager
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        references = secret_mgr.list_references()
+
+        results = {}
+        for name, reference in sorted(references.items()):
+            try:
+                secret_mgr.resolver.resolve(reference)
+                results[name] = {"reference": reference, "ok": True}
+            except SecretBackendError as e:
+                results[name] = {
+                    "reference": reference,
+                    "ok": False,
+                    "error": e.format_message(),
+                }
+        failed = sum(1 for r in results.values() if not r["ok"])
+
+        if self.json_output:
+            self.output_json({"references": results}, exit_code=1 if failed else 0)
+            return
+
+        self.show_header(
+            title="Secret References",
+            project=self.project_name,
+            details={"Environment": self.environment},
+        )
+
+        if not results:
+            self.console.print("[dim]No vault:// or sops:// references[/dim]")
+            return
+
+        table = Table(show_header=True, header_style="bold cyan")
+        table.add_column("Key", style="cyan")
+        table.add_column("Reference", style="dim")
+        table.add_column("Status")
+        for name, result in results.items():
+            status = (
+                "[green]✓ resolved[/green]"
+                if result["ok"]
+                else f"[red]✗ {result['error']}[/red]"
+            )
+            table.add_row(name, result["reference"], status)
+        self.console.print(table)
+
+        if failed:
+            self.console.print(f"\n[red]{failed} reference(s) failed[/red]")
+            raise SystemExit(1)
+
+
+class ConfigRenderCommand(ProjectCommand):
+    """Preview an app's resolved env (templates, aliases, references)."""
+
+    SENSITIVE = ("PASSWORD", "TOKEN", "SECRET", "KEY", "PAT", "DSN")
+
+    def __init__(
+        self,
+        project_name: str,
+        app: str,
+        environment: str = "production",
+        reveal: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.app = app
+        self.environment = environment
+        self.reveal = reveal
+        self._secrets_cache = None
+
+    def execute(self) -> None:
+        """Execute config:render command."""
+        from cli.services.app_env_service import AppEnvService
+
+        self.get_app_config(self.app)
+
+        service = AppEnvService(self.project_root, self.project_name, self.environment)
+        secret_mgr = service.secret_manager
+        env = service.build_env(self.app)
+        templates = service.env_templates(self.app)
+        aliases = secret_mgr.get_aliases(self.app)
+        unresolved = sorted(set(secret_mgr.template_engine.undefined_names))
+
+        def source(key: str) -> str:
+            if key in templates:
+                return "template"
+            if key in aliases:
+                return "alias"
+            return "secret"
+
+        values = {
+            key: env[key] if self.reveal else self._mask(key, env[key])
+            for key in sorted(env)
+        }
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "app": self.app,
+                    "environment": self.environment,
+                    "env": {
+                        key: {"value": value, "source": source(key)}
+                        for key, value in values.items()
+                    },
+                    "unresolved": unresolved,
+                },
+                exit_code=1 if unresolved else 0,
+            )
+            return
+
+        self.show_header(
+            title="Rendered Environment",
+            project=self.project_name,
+            app=self.app,
+            details={
+                "Environment": self.environment,
+                "Values": "revealed" if self.reveal else "masked",
+            },
+        )
+
+        table = Table(show_header=True, header_style="bold cyan")
+        table.add_column("Key", style="cyan", no_wrap=True)
+        table.add_column("Value", style="green")
+        table.add_column("Source", style="dim")
+        for key, value in values.items():
+            display = value if len(value) <= 80 else value[:77] + "..."
+            if "{{" in value:
+                display = f"[yellow]{display}[/yellow]"
+            table.add_row(key, display, source(key))
+        self.console.print(table)
+        self.console.print(f"\n[dim]Total: {len(values)} variables[/dim]")
+
+        if unresolved:
+            self.console.print(
+                f"\n[yellow]⚠ Unresolved placeholders: {', '.join(unresolved)}[/yellow]"
+            )
+            raise SystemExit(1)
+
+    def _mask(self, key: str, value: str) -> str:
+        """Mask whole sensitive values and secrets embedded in others (DSNs)"""
+        if any(marker in key.upper() for marker in self.SENSITIVE):
+            return "***"
+        for secret in self._secret_values():
+            value = value.replace(secret, "***")
+        return value
+
+    def _secret_values(self) -> list:
+        """Sensitive values the env may embed, plus urlencoded/base64 forms"""
+        if self._secrets_cache is not None:
+            return self._secrets_cache
+
+        import base64
+        from urllib.parse import quote
+        from cli.secret_manager import OrgSecretManager, SecretManager
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        found = set()
+
+        def collect(data, key=""):
+            if isinstance(data, dict):
+                for child_key, value in data.items():
+                    collect(value, child_key)
+            elif data and any(marker in key.upper() for marker in self.SENSITIVE):
+                found.add(str(data))
+
+        collect(secret_mgr.load_secrets(resolve=True))
+        collect(
+            {
+                key: data["value"]
+                for key, data in OrgSecretManager(self.environment).list().items()
+            }
+        )
+
+        values = set()
+        for value in found:
+            if len(value) < 4:
+                continue
+            values.add(value)
+            values.add(quote(value, safe=""))
+            values.add(base64.b64encode(value.encode()).decode())
+        # Longest first so a secret containing another is masked whole
+        self._secrets_cache = sorted(values, key=len, reverse=True)
+        return self._secrets_cache
+
+
+class ConfigGrantCommand(ProjectCommand):
+    """Grant (or revoke) another project access to this project's secrets."""
+
+    def __init__(
+        self,
+        project_name: str,
+        consumer: str,
+        key_pattern: str,
+        revoke: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.consumer = consumer
+        self.key_pattern = key_pattern
+        self.revoke = revoke
+
+    def execute(self) -> None:
+        """Execute config:grant / config:revoke command."""
+        from cli.secret_manager import SecretManager
+
+        secret_mgr = SecretManager(self.project_root, self.project_name)
+
+        if self.revoke:
+            removed = secret_mgr.revoke(self.consumer, self.key_pattern)
+            if self.json_output:
+                self.output_json(
+                    {
+                        "consumer": self.consumer,
+                        "pattern": self.key_pattern,
+                        "revoked": removed,
+                    },
+                    exit_code=0 if removed else 1,
+                )
+                return
+            if not removed:
+                self.console.print(
+                    f"[red]✗ No grant '{self.key_pattern}' for {self.consumer}[/red]"
+                )
+                raise SystemExit(1)
+            self.console.print(
+                f"[green]✓ Revoked '{self.key_pattern}' from {self.consumer}[/green]"
+            )
+            self.console.print(
+                "[dim]Existing .env files keep the value until the consumer syncs[/dim]"
+            )
+            return
+
+        try:
+            secret_mgr.grant(self.consumer, self.key_pattern)
+        except ValueError as e:
+            if self.json_output:
+                self.output_json({"error": str(e)}, exit_code=1)
+                return
+            self.console.print(f"[red]✗ {e}[/red]")
+            raise SystemExit(1)
+
+        if self.json_output:
+            self.output_json(
+                {"consumer": self.consumer, "pattern": self.key_pattern, "granted": True}
+            )
+            return
+
+        self.console.print(
+            f"[green]✓ {self.consumer} may reference "
+            f"project.{self.project_name}.{self.key_pattern}[/green]"
+        )
+
+
+class ConfigGrantsCommand(ProjectCommand):
+    """List secret grants and cross-app/org/project dependencies."""
+
+    def __init__(
+        self,
+        project_name: str,
+        environment: str = "production",
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.environment = environment
+
+    def execute(self) -> None:
+        """Execute config:grants command."""
+        from cli.secret_manager import SecretManager
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        grants = secret_mgr.list_grants()
+        consumers = secret_mgr.get_dependencies()
+        consumes = secret_mgr.get_consumed_references()
+
+        if self.json_output:
+            self.output_json(
+                {"grants": grants, "consumers": consumers, "consumes": consumes}
+            )
+            return
+
+        self.show_header(
+            title="Secret Sharing",
+            project=self.project_name,
+            details={"Environment": self.environment},
+        )
+
+        if grants["given"] or grants["received"]:
+            table = Table(title="Grants", show_header=True, header_style="bold cyan")
+            table.add_column("Direction")
+            table.add_column("Project", style="cyan")
+            table.add_column("Pattern", style="dim")
+            for grant in grants["given"]:
+                table.add_row("→ granted to", grant["project"], grant["pattern"])
+            for grant in grants["received"]:
+                table.add_row("← granted by", grant["project"], grant["pattern"])
+            self.console.print(table)
+        else:
+            self.console.print("[dim]No grants[/dim]")
+
+        def status(stale: bool) -> str:
+            return "[yellow]stale[/yellow]" if stale else "[green]in sync[/green]"
+
+        if consumes:
+            table = Table(
+                title="References used by this project",
+                show_header=True,
+                header_style="bold cyan",
+            )
+            table.add_column("App", style="cyan")
+            table.add_column("Key")
+            table.add_column("Source", style="dim")
+            table.add_column("Status")
+            for dep in consumes:
+                table.add_row(
+                    dep["app"] or "shared",
+                    dep["key"],
+                    dep["source_ref"],
+                    status(dep["stale"]),
+                )
+            self.console.print(table)
+
+        if consumers:
+            table = Table(
+                title="Consumers of this project's secrets",
+                show_header=True,
+                header_style="bold cyan",
+            )
+            table.add_column("Project", style="cyan")
+            table.add_column("App")
+            table.add_column("Key")
+            table.add_column("Source", style="dim")
+            table.add_column("Status")
+            for dep in consumers:
+                table.add_row(
+                    dep["project"],
+                    dep["app"] or "shared",
+                    dep["key"],
+                    dep["source_ref"],
+                    status(dep["stale"]),
+                )
+            self.console.print(table)
+
+        if any(d["stale"] for d in consumes + consumers):
+            self.console.print(
+                f"\n[dim]Stale consumers are re-synced by: "
+                f"superdeploy {self.project_name}:vars:sync[/dim]"
+            )
+
+
 # ============================================================================
 # Click Command Wrappers
 # ============================================================================
@@ -911,11 +1503,30 @@ class ConfigShowCommand(ProjectCommand):
 @click.option(
     "--deploy", is_flag=True, help="Auto-deploy after setting config (Heroku-like!)"
 )
+@click.option(
+    "--apply",
+    is_flag=True,
+    help="Push .env to the VMs and rolling-restart apps (no CI run)",
+)
 @click.option("--no-sync", is_flag=True, help="Skip GitHub sync")
+@click.option(
+    "--usage",
+    type=click.Choice(["runtime", "build", "both"]),
+    help="Runtime .env, image build, or both (default: keep, new keys runtime)",
+)
 @click.option("--verbose", "-v", is_flag=True, help="Show all command output")
 @click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
 def config_set(
-    project, key_value, app, environment, deploy, no_sync, verbose, json_output
+    project,
+    key_value,
+    app,
+    environment,
+    deploy,
+    apply,
+    no_sync,
+    usage,
+    verbose,
+    json_output,
 ):
     """
     Set configuration variable (Heroku-like!)
@@ -932,6 +1543,12 @@ def config_set(
 
       # Update for specific app only
       superdeploy cheapa:config:set STRIPE_API_KEY=sk_live_xyz -a api --deploy
+
+      # Update + apply on the VM right away (rolling restart, no CI)
+      superdeploy cheapa:config:set FEATURE_X=on -a api --apply
+
+      # Build-time value: --apply rebuilds the image instead of restarting
+      superdeploy cheapa:config:set NEXT_PUBLIC_API_URL=https://api.x.io -a web --usage both --apply
     """
     cmd = ConfigSetCommand(
         project,
@@ -939,12 +1556,75 @@ def config_set(
         app=app,
         environment=environment,
         deploy=deploy,
+        apply=apply,
         no_sync=no_sync,
+        usage=usage,
         verbose=verbose,
     )
     cmd.run()
 
 
+@click.command(name="config:push")
+@click.option("-a", "--app", help="App name. If not specified, pushes all apps")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--no-restart", is_flag=True, help="Write .env without restarting")
+@click.option("--force", is_flag=True, help="Write and restart even if unchanged")
+@click.option("--dry-run", is_flag=True, help="Show keys that would be written")
+@click.option(
+    "--skip-contract",
+    is_flag=True,
+    help="Push even if the env violates the marker's config contract",
+)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def config_push(
+    project,
+    app,
+    environment,
+    no_restart,
+    force,
+    dry_run,
+    skip_contract,
+    verbose,
+    json_output,
+):
+    """
+    Push app .env files straight from the database
+
+    Renders each app's environment (shared + app secrets, addon aliases,
+    marker env_templates), writes it to the VM atomically and recreates the
+    app's services one by one. Deploy workflows leave an orchestrator-managed
+    .env alone, so GitHub is only needed for build-time values.
+
+    Secret files (files:set) are written to tmpfs on the VM and mounted
+    read-only; a VM that lost them on reboot gets them back here.
+
+    \b
+    Examples:
+      superdeploy cheapa:config:push                 # All apps
+      superdeploy cheapa:config:push -a api          # Single app
+      superdeploy cheapa:config:push -a api --dry-run
+    """
+    cmd = ConfigPushCommand(
+        project,
+        app=app,
+        environment=environment,
+        restart=not no_restart,
+        force=force,
+        dry_run=dry_run,
+        enforce_contract=not skip_contract,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
 @click.command(name="config:get")
 @click.argument("key")
 @click.option(
@@ -1050,3 +1730,150 @@ def config_show(project, mask, verbose, json_output):
         project, mask=mask, verbose=verbose, json_output=json_output
     )
     cmd.run()
+
+
+@click.command(name="config:refs")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def config_refs(project, environment, verbose, json_output):
+    """
+    Check vault:// and sops:// secret references
+
+    Values stored as references are fetched at sync/render time. This
+    resolves each one (values are never printed) and reports failures.
+
+    \b
+    Examples:
+      superdeploy cheapa:config:set STRIPE_KEY='vault://kv/data/api#STRIPE_KEY' -a api
+      superdeploy cheapa:config:set SENTRY_DSN='sops://prod.yaml#sentry.dsn'
+      superdeploy cheapa:config:refs
+    """
+    cmd = ConfigRefsCommand(
+        project, environment=environment, verbose=verbose, json_output=json_output
+    )
+    cmd.run()
+
+
+@click.command(name="config:grant")
+@click.argument("consumer")
+@click.argument("key_pattern")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def config_grant(project, consumer, key_pattern, verbose, json_output):
+    """
+    Allow another project to reference this project's secrets
+
+    KEY_PATTERN is a key inside this project and may use globs:
+    app.<app>.KEY for app secrets, <addon>.<instance>.KEY for addon
+    credentials, KEY for shared secrets.
+
+    \b
+    Examples:
+      superdeploy billing:config:grant cheapa 'app.api.INTERNAL_TOKEN'
+      superdeploy billing:config:grant cheapa 'postgres.primary.*'
+      superdeploy cheapa:config:set BILLING_TOKEN='{{ project.billing.app.api.INTERNAL_TOKEN }}' -a api
+    """
+    cmd = ConfigGrantCommand(
+        project, consumer, key_pattern, verbose=verbose, json_output=json_output
+    )
+    cmd.run()
+
+
+@click.command(name="config:revoke")
+@click.argument("consumer")
+@click.argument("key_pattern")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def config_revoke(project, consumer, key_pattern, verbose, json_output):
+    """
+    Revoke a grant made with config:grant
+
+    \b
+    Example:
+      superdeploy billing:config:revoke cheapa 'app.api.INTERNAL_TOKEN'
+    """
+    cmd = ConfigGrantCommand(
+        project,
+        consumer,
+        key_pattern,
+        revoke=True,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="config:grants")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def config_grants(project, environment, verbose, json_output):
+    """
+    Show grants and cross-app/org/project secret dependencies
+
+    Dependencies are recorded on vars:sync and config:push; a dependency
+    is stale when its source value changed after the consumer's last sync.
+
+    \b
+    Example:
+      superdeploy billing:config:grants
+    """
+    cmd = ConfigGrantsCommand(
+        project, environment=environment, verbose=verbose, json_output=json_output
+    )
+    cmd.run()
+
+
+@click.command(name="config:render")
+@click.option("-a", "--app", required=True, help="App name")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--reveal", is_flag=True, help="Show values unmasked")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def config_render(project, app, environment, reveal, verbose, json_output):
+    """
+    Preview an app's resolved environment
+
+    Renders env_templates and alias targets exactly as vars:sync and
+    config:push would, with secrets masked (also inside DSNs and URLs).
+    Exits 1 if a placeholder can't be resolved.
+
+    \b
+    Template examples (marker env_templates):
+      DATABASE_URL: "postgres://app:{{ postgres.primary.PASSWORD | urlencode }}@{{ postgres.primary.HOST }}/app"
+      GCP_KEY_B64: "{{ GCP_KEY_JSON | b64encode }}"
+      API_URL: "{% if app.api.domain %}{{ app.api.url }}{% else %}http://{{ APP_0_EXTERNAL_IP }}:{{ app.api.port }}{% endif %}"
+
+    \b
+    Examples:
+      superdeploy cheapa:config:render -a api
+      superdeploy cheapa:config:render -a api --reveal
+    """
+    cmd = ConfigRenderCommand(
+        project,
+        app,
+        environment=environment,
+        reveal=reveal,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
@@ -1,14 +1,17 @@
 """Deploy command - Quick local deployment"""
 
 import click
+import os
+import re
 import subprocess
 from pathlib import Path
-from dataclasses import dataclass
-from typing import Optional
+from dataclasses import dataclass, field
+from typing import Dict, Optional, Set
 
 from cli.base import ProjectCommand
 from cli.secret_manager import SecretManager
 from cli.exceptions import DeploymentError
+from cli.services.deploy_queue_service import queued_script
 
 
 @dataclass
@@ -25,6 +28,8 @@ class DeploymentConfig:
     docker_registry: str
     docker_username: Optional[str]
     docker_token: Optional[str]
+    # Keys tagged build/both (plus marker build_env), passed to docker build
+    build_env: Dict[str, str] = field(default_factory=dict)
 
 
 @dataclass
@@ -44,9 +49,43 @@ class DockerImageBuilder:
         self.console = console
         self.verbose = verbose
 
-    def build(self, app_path: Path, image_tag: str, image_latest: str) -> bool:
-        """Build Docker image with given tags."""
+    @staticmethod
+    def dockerfile_args(app_path: Path) -> Set[str]:
+        """ARG names declared in the app's Dockerfile"""
+        dockerfile = app_path / "Dockerfile"
+        if not dockerfile.exists():
+            return set()
+        return set(
+            re.findall(
+                r"^\s*ARG\s+([A-Za-z_][A-Za-z0-9_]*)",
+                dockerfile.read_text(),
+                re.IGNORECASE | re.MULTILINE,
+            )
+        )
+
+    def build(
+        self,
+        app_path: Path,
+        image_tag: str,
+        image_latest: str,
+        build_env: Optional[Dict[str, str]] = None,
+    ) -> bool:
+        """
+        Build Docker image with given tags.
+
+        Build-time config is mounted as BuildKit secrets
+        (RUN --mount=type=secret,id=KEY) and also passed as a build arg
+        for keys the Dockerfile declares with ARG. The image is labelled
+        with the config's fingerprint so config:push can tell when it's stale.
+        """
+        from cli.services.app_env_service import (
+            BUILD_CONFIG_LABEL,
+            build_config_fingerprint,
+        )
+
         self.console.print("\n[bold]📦 Building Docker image...[/bold]")
+        build_env = build_env or {}
+        declared = self.dockerfile_args(app_path)
 
         build_cmd = [
             "docker",
@@ -55,13 +94,28 @@ class DockerImageBuilder:
             image_tag,
             "-t",
             image_latest,
-            str(app_path),
+            "--label",
+            f"{BUILD_CONFIG_LABEL}={build_config_fingerprint(build_env)}",
         ]
+        # Values travel in the environment, never on the command line
+        for key in sorted(build_env):
+            build_cmd += ["--secret", f"id={key},env={key}"]
+            if key in declared:
+                build_cmd += ["--build-arg", key]
+        build_cmd.append(str(app_path))
 
         if self.verbose:
             self.console.print(f"[dim]$ {' '.join(build_cmd)}[/dim]")
 
-        result = subprocess.run(build_cmd, cwd=app_path)
+        result = subprocess.run(
+            build_cmd,
+            cwd=app_path,
+            env={
+                **os.environ,
+                "DOCKER_BUILDKIT": "1",
+                **{key: str(value) for key, value in build_env.items()},
+            },
+        )
         if result.returncode != 0:
             self.console.print("[red]❌ Build failed[/red]")
             return False
@@ -122,14 +176,25 @@ class ApplicationDeployer:
         self.verbose = verbose
 
     def deploy(
-        self, target: VMTarget, project_name: str, app_name: str, domain: Optional[str]
+        self,
+        target: VMTarget,
+        project_name: str,
+        app_name: str,
+        domain: Optional[str],
+        git_sha: str = "",
     ) -> bool:
         """Deploy application to target VM."""
         self.console.print("\n[bold]🚀 Deploying to VM...[/bold]")
         self.console.print(f"Target: {target.ssh_user}@{target.ip}")
 
-        # Build deployment script
-        deploy_script = self._build_deploy_script(project_name, app_name)
+        # Build deployment script, run holding the VM's deploy slot
+        deploy_script = queued_script(
+            self._build_deploy_script(project_name, app_name),
+            project_name,
+            app_name,
+            source="deploy",
+            ref=git_sha,
+        )
 
         # Execute via SSH
         ssh_cmd = [
@@ -152,6 +217,12 @@ class ApplicationDeployer:
             if domain:
                 self.console.print(f"🌐 https://{domain}")
             return True
+        elif result.returncode == 75:
+            self.console.print(
+                "\n[red]❌ Timed out waiting for the deploy queue[/red]\n"
+                f"[dim]See: superdeploy {project_name}:deploys:queue[/dim]"
+            )
+            return False
         else:
             self.console.print("\n[red]❌ Deployment failed[/red]")
             return False
@@ -169,6 +240,14 @@ docker compose up -d {app_name}
 echo "⏳ Waiting for containers to stabilize..."
 sleep 5
 
+# Readiness probes from the marker (processes.<name>.readiness)
+HEALTHCHECK=/opt/superdeploy/bin/healthcheck
+if [ -x "$HEALTHCHECK" ] && ! "$HEALTHCHECK" wait --project {project_name} --app {app_name}; then
+    echo "❌ Deployment failed - replicas not ready"
+    docker compose logs {app_name} --tail 50
+    exit 1
+fi
+
 # Check replica status (no container_name in replicated mode)
 RUNNING_REPLICAS=$(docker compose ps {app_name} --status running --format '{{{{.Name}}}}' 2>/dev/null | wc -l)
 TOTAL_CONTAINERS=$(docker compose ps {app_name} --format '{{{{.Name}}}}' 2>/dev/null | wc -l)
@@ -237,17 +316,22 @@ class DeployCommand(ProjectCommand):
             if logger:
                 logger.success("Configuration loaded")
 
+            # Freeze windows and production approvals (deploys:policy)
+            self._check_deploy_policy(deploy_config.git_sha)
+
             # Display deployment info
             self._display_deployment_info(deploy_config)
 
             # Step 1: Build Docker image
             if self.build_image:
+                self._run_hooks("before_build", deploy_config.git_sha, logger)
                 if logger:
                     logger.step("Building Docker image")
                 if not self.image_builder.build(
                     deploy_config.app_path,
                     deploy_config.image_tag,
                     deploy_config.image_latest,
+                    deploy_config.build_env,
                 ):
                     if logger:
                         logger.log_error("Image build failed")
@@ -276,8 +360,13 @@ class DeployCommand(ProjectCommand):
             if logger:
                 logger.step(f"Deploying to VM ({target_vm.vm_name})")
             domain = self._get_app_domain()
+            self._run_hooks("before_deploy", deploy_config.git_sha, logger)
             if not self.app_deployer.deploy(
-                target_vm, self.project_name, self.app_name, domain
+                target_vm,
+                self.project_name,
+                self.app_name,
+                domain,
+                git_sha=deploy_config.git_sha,
             ):
                 if logger:
                     logger.log_error("Deployment failed")
@@ -286,6 +375,8 @@ class DeployCommand(ProjectCommand):
             if logger:
                 logger.success("Deployment completed successfully")
 
+            self._run_hooks("after_deploy", deploy_config.git_sha, logger)
+
             if not self.verbose:
                 self.console.print(f"\n[dim]Logs saved to:[/dim] {logger.log_path}\n")
 
@@ -308,6 +399,32 @@ class DeployCommand(ProjectCommand):
                 self.console.print(f"[dim]Logs saved to:[/dim] {logger.log_path}\n")
             raise SystemExit(1)
 
+    def _run_hooks(self, event: str, git_sha: str, logger) -> None:
+        """Run the app's hooks for event; raise DeploymentError on abort"""
+        if not self.run_hooks(
+            event, [self.app_name], logger, ref=git_sha, source="deploy"
+        ):
+            live = " (the new version is live)" if event == "after_deploy" else ""
+            raise DeploymentError(f"{event} hook failed{live}")
+
+    def _check_deploy_policy(self, git_sha: str) -> None:
+        """Raise DeploymentError during freezes or without approvals"""
+        from cli.services.deploy_policy_service import (
+            DeployBlockedError,
+            DeployPolicyService,
+        )
+
+        try:
+            decision = DeployPolicyService().check(
+                self.project_name, f"{self.app_name}@{git_sha}"
+            )
+        except DeployBlockedError as e:
+            raise DeploymentError(str(e))
+        if decision["override"]:
+            self.print_warning(
+                f"Deploy policy overridden: {decision['override']['reason']}"
+            )
+
     def _load_deployment_config(self) -> DeploymentConfig:
         """Load and validate deployment configuration."""
         # Load secrets
@@ -361,6 +478,12 @@ class DeployCommand(ProjectCommand):
             ["git", "rev-parse", "HEAD"], cwd=app_path, text=True
         ).strip()[:7]
 
+        from cli.services.app_env_service import AppEnvService
+
+        build_env = AppEnvService(
+            self.project_root, self.project_name
+        ).buildtime_env(self.app_name)
+
         return DeploymentConfig(
             app_name=self.app_name,
             app_path=app_path,
@@ -372,6 +495,7 @@ class DeployCommand(ProjectCommand):
             docker_registry=docker_registry,
             docker_username=shared_secrets.get("DOCKER_USERNAME"),
             docker_token=shared_secrets.get("DOCKER_TOKEN"),
+            build_env=build_env,
         )
 
     def _find_target_vm(self, vm_role: str) -> VMTarget:
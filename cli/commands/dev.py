@@ -0,0 +1,245 @@
+"""SuperDeploy CLI - Local development environment
+
+Runs an app and the addons it attaches on local Docker: addons from their
+templates, the app from its Dockerfile and marker processes, with the env
+var names it has in production and local values.
+"""
+
+import click
+from cli.base import ProjectCommand
+
+
+class DevCommand(ProjectCommand):
+    """Run an app and its addons locally."""
+
+    def __init__(
+        self,
+        project_name: str,
+        app_name: str,
+        app_path: str = None,
+        port: int = None,
+        detach: bool = False,
+        down: bool = False,
+        reset: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.app_name = app_name
+        self.app_path = app_path
+        self.port = port
+        self.detach = detach or json_output
+        self.down = down or reset
+        self.reset = reset
+
+    def execute(self) -> None:
+        """Execute dev command."""
+        from cli.core.addon_loader import AddonNotFoundError, AddonValidationError
+        from cli.services.addon_test_service import AddonTestService
+        from cli.services.dev_service import DevService
+
+        if not AddonTestService.docker_available():
+            self._fail("docker with the compose plugin is required for dev")
+
+        try:
+            service = DevService(
+                self.project_root,
+                self.project_name,
+                self.app_name,
+                app_path=self.app_path,
+                port=self.port,
+            )
+            addons = service.addons()
+        except KeyError as e:
+            self._fail(str(e).strip("'\""))
+        except (
+            FileNotFoundError,
+            ValueError,
+            AddonNotFoundError,
+            AddonValidationError,
+        ) as e:
+            self._fail(str(e))
+
+        if self.down:
+            self._down(service, addons)
+            return
+
+        self.show_header(
+            title="Local Development",
+            project=self.project_name,
+            app=self.app_name,
+            details={
+                "Path": str(service.app_path),
+                "Addons": ", ".join(addon.ref for addon in addons) or "none",
+                "Hot reload": service.source_dir() or "no (image code)",
+            },
+        )
+
+        for addon in addons:
+            self._start_addon(service, addon)
+
+        runtime, buildtime = service.app_env(addons)
+        service.write_compose(runtime, buildtime)
+        violations = service.check_contract(runtime)
+        for violation in violations:
+            self.print_warning(f"{violation} - set it in {service.values_path}")
+        for key in service.unresolved:
+            self.print_warning(f"{key} has an unresolved placeholder locally")
+
+        self._run(
+            service,
+            "--profile",
+            "release",
+            "build",
+            failure=f"Building {self.app_name} failed",
+        )
+        for release in service.release_services():
+            self.print_dim(f"Running {release}")
+            self._run(service, "run", "--rm", release, failure=f"{release} failed")
+
+        if self.json_output:
+            self._run(service, "up", "-d", "--no-build", failure="compose up failed")
+            self.output_json(
+                {
+                    "project": self.project_name,
+                    "app": self.app_name,
+                    "url": service.url,
+                    "addons": [
+                        {
+                            "addon": addon.ref,
+                            "container": addon.rendered.container_name,
+                            "port": addon.host_port,
+                        }
+                        for addon in addons
+                    ],
+                    "compose": str(service.compose_path),
+                    "env": str(service.values_path),
+                    "unresolved": service.unresolved,
+                    "violations": [str(v) for v in violations],
+                }
+            )
+            return
+
+        self._print_summary(service, addons)
+        if self.detach:
+            self._run(service, "up", "-d", "--no-build", failure="compose up failed")
+            self.console.print(
+                f"\n[dim]Stop:[/dim] [cyan]superdeploy {self.project_name}:dev "
+                f"-a {self.app_name} --down[/cyan]\n"
+            )
+            return
+
+        # Foreground: Ctrl+C stops the app, addons keep running for next time
+        try:
+            service.compose("up", "--no-build", capture=False)
+        except KeyboardInterrupt:
+            service.compose("stop")
+
+    def _start_addon(self, service, addon) -> None:
+        """Render, start and wait for one addon"""
+        addon.render(addon.work_dir)
+        service.save_state()
+        result = addon.up()
+        if result.returncode != 0:
+            self._fail(f"Starting {addon.ref} failed\n{result.stderr.strip()}")
+        health = addon.wait_healthy()
+        if not health.passed:
+            self._fail(f"{addon.ref} is not healthy: {health.output}\n{addon.logs()}")
+        self.print_success(f"{addon.ref} on localhost:{addon.host_port}")
+
+    def _run(self, service, *args: str, failure: str) -> None:
+        """Run compose, streaming its output unless in JSON mode"""
+        result = service.compose(*args, capture=self.json_output)
+        if result.returncode != 0:
+            details = f"\n{result.stderr.strip()}" if self.json_output else ""
+            self._fail(f"{failure}{details}")
+
+    def _down(self, service, addons) -> None:
+        errors = service.down(addons, reset=self.reset)
+        if self.json_output:
+            self.output_json(
+                {
+                    "project": self.project_name,
+                    "app": self.app_name,
+                    "stopped": [self.app_name] + [addon.ref for addon in addons],
+                    "reset": self.reset,
+                    "errors": errors,
+                },
+                exit_code=1 if errors else 0,
+            )
+            return
+        for error in errors:
+            self.print_warning(error)
+        if self.reset:
+            self.print_success(
+                f"{self.app_name} and its addons removed with their data"
+            )
+        else:
+            self.print_success(f"{self.app_name} and its addons stopped (data kept)")
+        if errors:
+            raise SystemExit(1)
+
+    def _print_summary(self, service, addons) -> None:
+        if service.url:
+            self.console.print(f"\n[bold]App:[/bold] [cyan]{service.url}[/cyan]")
+        for addon in addons:
+            self.console.print(
+                f"[bold]{addon.ref}:[/bold] localhost:{addon.host_port} "
+                f"[dim]({addon.rendered.env_path})[/dim]"
+            )
+        self.console.print(f"[dim]Local values:[/dim] {service.values_path}")
+
+    def _fail(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command()
+@click.option("-a", "--app", required=True, help="App name (api, dashboard, services)")
+@click.option("--path", "app_path", help="App checkout (default: the app's path)")
+@click.option("--port", type=int, help="Host port for the web process")
+@click.option("--detach", "-d", is_flag=True, help="Run in the background")
+@click.option("--down", is_flag=True, help="Stop the app and its addons")
+@click.option("--reset", is_flag=True, help="Stop and remove addon data and values")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def dev(project, app, app_path, port, detach, down, reset, verbose, json_output):
+    """
+    Run an app and its addons locally with Docker
+
+    \b
+    Addons the app attaches start from their addon templates; the app is
+    built from its Dockerfile and runs its marker (or Procfile) processes.
+    Env var names match production; values are local and never production
+    secrets: addon credentials are generated, other keys land in
+    ./dev/<project>/<app>.env where they can be edited.
+
+    \b
+    The code is mounted for hot reload where the app type supports it
+    (Next.js: next dev; Python: the process command's own reloader).
+
+    \b
+    Examples:
+      superdeploy cheapa:dev -a api
+      superdeploy cheapa:dev -a dashboard --port 3001 -d
+      superdeploy cheapa:dev -a api --down
+      superdeploy cheapa:dev -a api --reset     # fresh databases
+    """
+    cmd = DevCommand(
+        project,
+        app,
+        app_path=app_path,
+        port=port,
+        detach=detach,
+        down=down,
+        reset=reset,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
@@ -8,8 +8,10 @@ import click
 import subprocess
 import time
 import shutil
+from datetime import datetime
 from pathlib import Path
 from dataclasses import dataclass
+from typing import Optional
 
 from cli.base import ProjectCommand
 
@@ -21,6 +23,8 @@ class DownOptions:
     yes: bool = False
     keep_infra: bool = False
     destroy: bool = False
+    skip_final_backup: bool = False
+    skip_hooks: bool = False
 
 
 @dataclass
@@ -512,6 +516,8 @@ class DownCommand(ProjectCommand):
     Destroy project resources.
 
     Features:
+    - Deletion protection check
+    - Final backup of database addons
     - GCP resource cleanup
     - Terraform state cleanup
     - Local files cleanup
@@ -576,6 +582,14 @@ class DownCommand(ProjectCommand):
         # Initialize logger
         logger = self.init_logger(self.project_name, "down")
 
+        # Protected projects/addons must be unprotected first (audited)
+        from cli.services.protection_service import ProtectionService, ProtectedError
+
+        try:
+            ProtectionService().check(self.project_name)
+        except ProtectedError as e:
+            self.exit_with_error(str(e))
+
         # Check if project exists in Terraform state (more reliable than database)
         if not self._check_terraform_state():
             self.console.print(
@@ -599,29 +613,38 @@ class DownCommand(ProjectCommand):
         region = self._load_region_config(logger)
         project_config = self._load_project_config(logger)
 
-        # Execute cleanup in 4 steps
-        total_steps = 4
+        # Execute cleanup in 5 steps
+        total_steps = 5
 
-        # Step 1: Terraform Destroy (destroys all GCP resources from state)
+        # before_down hooks, then Step 1: Final Backup (both abort before
+        # anything is destroyed)
+        self._execute_hooks("before_down", logger)
         if logger:
-            logger.step(f"[1/{total_steps}] Terraform Destroy")
+            logger.step(f"[1/{total_steps}] Final Backup")
         self.console.print("  [dim]✓ Configuration loaded[/dim]")
+        final_backup = self._execute_final_backup(logger)
+        if final_backup:
+            self._execute_hooks("after_backup", logger, backup=final_backup)
+
+        # Step 2: Terraform Destroy (destroys all GCP resources from state)
+        if logger:
+            logger.step(f"[2/{total_steps}] Terraform Destroy")
         self._execute_terraform_cleanup(logger, project_config)
 
-        # Step 2: GCP Manual Cleanup (clean up resources not in Terraform state)
+        # Step 3: GCP Manual Cleanup (clean up resources not in Terraform state)
         if logger:
-            logger.step(f"[2/{total_steps}] GCP Cleanup")
+            logger.step(f"[3/{total_steps}] GCP Cleanup")
         self._execute_gcp_cleanup(logger, region)
 
-        # Step 3: Local Files Cleanup
+        # Step 4: Local Files Cleanup
         if logger:
-            logger.step(f"[3/{total_steps}] Local Files Cleanup")
+            logger.step(f"[4/{total_steps}] Local Files Cleanup")
         self._execute_local_cleanup(logger)
 
-        # Step 4: Database Cleanup
+        # Step 5: Database Cleanup
         if logger:
-            logger.step(f"[4/{total_steps}] Database Cleanup")
-        self._execute_database_cleanup(logger)
+            logger.step(f"[5/{total_steps}] Database Cleanup")
+        self._execute_database_cleanup(logger, final_backup)
 
         if not self.verbose:
             self.console.print("\n[color(248)]Project destroyed.[/color(248)]")
@@ -709,6 +732,61 @@ class DownCommand(ProjectCommand):
             default=False,
         )
 
+    def _execute_hooks(self, event: str, logger, **context) -> None:
+        """Run lifecycle hooks; a failed abort hook stops the shutdown."""
+        if self.options.skip_hooks:
+            self.console.print(
+                f"  [yellow]⚠ {event} hooks skipped (--skip-hooks)[/yellow]"
+            )
+            return
+        if not self.run_hooks(event, logger=logger, **context):
+            self.exit_with_error(
+                f"{event} hook failed, nothing was destroyed\n"
+                f"Fix it, or rerun with --skip-hooks"
+            )
+
+    def _execute_final_backup(self, logger) -> Optional[str]:
+        """Dump every database addon to ./backups before the VMs go away."""
+        if self.options.skip_final_backup:
+            if logger:
+                logger.warning("Final backup skipped (--skip-final-backup)")
+            self.console.print(
+                "  [yellow]⚠ Final backup skipped (--skip-final-backup)[/yellow]"
+            )
+            return None
+
+        from cli.commands.backup import ProjectBackupWriter
+
+        writer = ProjectBackupWriter(
+            self.project_name,
+            self.ensure_vm_service().get_ssh_service(),
+            self.ensure_state_service(),
+        )
+        if not writer.stateful_addons():
+            self.console.print("  [dim]✓ No database addons to back up[/dim]")
+            return None
+
+        timestamp = datetime.now().strftime("%Y%m%d_%H%M%S")
+        backup_path = (
+            Path("./backups")
+            / self.project_name
+            / f"{self.project_name}_final_{timestamp}"
+        )
+        try:
+            entries = writer.write(backup_path, kind="final")["addons"]
+        except Exception as e:
+            self.exit_with_error(
+                f"Final backup failed, nothing was destroyed: {e}\n"
+                f"Fix it, or rerun with --skip-final-backup to destroy without one"
+            )
+
+        if logger:
+            logger.log(f"Final backup: {backup_path} ({len(entries)} addon(s))")
+        self.console.print(
+            f"  [dim]✓ Final backup of {len(entries)} addon(s): {backup_path}[/dim]"
+        )
+        return str(backup_path)
+
     def _execute_gcp_cleanup(self, logger, region: str) -> None:
         """Execute GCP resource cleanup."""
         cleaner = GCPResourceCleaner(self.project_name, self.console)
@@ -735,65 +813,53 @@ class DownCommand(ProjectCommand):
         )
         cleaner.cleanup()
 
-    def _execute_database_cleanup(self, logger) -> None:
-        """Execute database cleanup - optionally destroy entire project from DB."""
+    def _execute_database_cleanup(
+        self, logger, final_backup: Optional[str] = None
+    ) -> None:
+        """Execute database cleanup - optionally soft-delete the project from DB."""
+        # Clear project state (mark VMs as terminated)
+        from cli.sync import clear_project_state
+
+        clear_project_state(self.project_name)
+
         if self.options.destroy:
-            # Complete database destruction
-            from cli.database import SessionLocal, Project, App, Secret, SecretAlias
+            # Soft delete: records stay restorable with projects:undelete
+            from cli.services.protection_service import ProtectedError
+            from cli.services.trash_service import TrashService
 
-            session = SessionLocal()
             try:
-                project = (
-                    session.query(Project).filter_by(name=self.project_name).first()
+                entry = TrashService().delete(
+                    self.project_name, final_backup=final_backup
                 )
-                if project:
-                    # Delete secrets
-                    deleted_secrets = (
-                        session.query(Secret)
-                        .filter_by(project_name=self.project_name)
-                        .delete()
-                    )
-                    # Delete secret aliases
-                    deleted_aliases = (
-                        session.query(SecretAlias)
-                        .filter_by(project_name=self.project_name)
-                        .delete()
-                    )
-                    # Delete apps
-                    apps = session.query(App).filter_by(project_id=project.id).all()
-                    for app in apps:
-                        session.delete(app)
-                    # Delete project
-                    session.delete(project)
-                    session.commit()
-
-                    if logger:
-                        logger.log(
-                            f"[dim]✓ Destroyed project from database ({deleted_secrets} secrets, {deleted_aliases} aliases, {len(apps)} apps)[/dim]"
-                        )
-                    self.console.print(
-                        f"  [dim]✓ Destroyed project from database ({deleted_secrets} secrets, {deleted_aliases} aliases, {len(apps)} apps)[/dim]"
-                    )
-                else:
-                    if logger:
-                        logger.log("[dim]✓ Project not found in database[/dim]")
-                    self.console.print("  [dim]✓ Project not found in database[/dim]")
+            except ProtectedError as e:
+                self.console.print(f"  [yellow]⚠ Database kept: {e}[/yellow]")
+                return
+            except ValueError:
+                if logger:
+                    logger.log("[dim]✓ Project not found in database[/dim]")
+                self.console.print("  [dim]✓ Project not found in database[/dim]")
+                return
             except Exception as e:
-                session.rollback()
                 self.console.print(f"  [yellow]⚠ Database cleanup error: {e}[/yellow]")
-            finally:
-                session.close()
+                return
+
+            records = ", ".join(
+                f"{count} {table}" for table, count in entry["records"].items()
+            )
+            if logger:
+                logger.log(f"Project moved to trash ({records})")
+            self.console.print(
+                f"  [dim]✓ Project deleted from database ({records})[/dim]"
+            )
+            self.console.print(
+                f"  [dim]  Restorable until {entry['purge_after'][:16]} UTC: "
+                f"superdeploy projects:undelete {self.project_name}[/dim]"
+            )
         else:
             # Preserve VMs config - just clear runtime state
             # NOTE: VMs configuration is part of the project definition and should NOT be deleted
             # on teardown. It will be reused on next 'up' command.
             # Only runtime state (Terraform state, GCP resources, local files) is cleaned.
-
-            # Clear project state (mark VMs as terminated)
-            from cli.sync import clear_project_state
-
-            clear_project_state(self.project_name)
-
             if logger:
                 logger.log("✓ Database preserved (VMs config retained)")
             self.console.print(
@@ -812,20 +878,43 @@ class DownCommand(ProjectCommand):
 @click.option(
     "--destroy",
     is_flag=True,
-    help="Completely destroy project from database (secrets, apps, config)",
+    help="Delete project from database (restorable with projects:undelete)",
+)
+@click.option(
+    "--skip-final-backup",
+    is_flag=True,
+    help="Destroy without dumping the database addons first",
+)
+@click.option(
+    "--skip-hooks",
+    is_flag=True,
+    help="Don't run before_down/after_backup hooks",
 )
 @click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
-def down(project, yes, verbose, keep_infra, destroy, json_output):
+def down(
+    project,
+    yes,
+    verbose,
+    keep_infra,
+    destroy,
+    skip_final_backup,
+    skip_hooks,
+    json_output,
+):
     """
     Stop and destroy project resources (like 'heroku apps:destroy')
 
     This command will:
+    - Refuse if the project or an addon has deletion protection
+    - Run before_down hooks (and after_backup after the final backup)
+    - Dump every database addon to ./backups/<project>/ (final backup)
     - Delete all VMs (core, scrape, proxy)
     - Optionally delete VPC network and firewall rules
     - Clean up local state
-    - Optionally destroy project from database (--destroy)
+    - Optionally delete project from database (--destroy, restorable
+      with projects:undelete for 7 days)
 
-    Warning: All data on VMs will be lost.
+    Warning: Data on VMs not covered by the final backup will be lost.
 
     Examples:
         # Destroy project with confirmation
@@ -837,9 +926,18 @@ def down(project, yes, verbose, keep_infra, destroy, json_output):
         # Keep shared infrastructure
         superdeploy cheapa:down --keep-infra
 
-        # Completely destroy project from database
+        # Delete project from database too (soft delete)
         superdeploy receet:down --destroy --yes
+
+        # Region is gone, nothing left to back up
+        superdeploy receet:down --skip-final-backup
     """
-    options = DownOptions(yes=yes, keep_infra=keep_infra, destroy=destroy)
+    options = DownOptions(
+        yes=yes,
+        keep_infra=keep_infra,
+        destroy=destroy,
+        skip_final_backup=skip_final_backup,
+        skip_hooks=skip_hooks,
+    )
     cmd = DownCommand(project, options, verbose=verbose, json_output=json_output)
     cmd.run()
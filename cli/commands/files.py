@@ -0,0 +1,347 @@
+"""SuperDeploy CLI - Secret files
+
+Service-account JSON, TLS client certs and SSH keys stored as files instead
+of env vars. Same scoping as config vars (shared or per app, per
+environment). config:push writes them to tmpfs on the app's VM and mounts
+them read-only into the app's containers at their declared path.
+"""
+
+import click
+from pathlib import Path
+from rich.table import Table
+from cli.base import ProjectCommand
+
+
+class FilesSetCommand(ProjectCommand):
+    """Create or update a secret file."""
+
+    def __init__(
+        self,
+        project_name: str,
+        name: str,
+        source: str = None,
+        reference: str = None,
+        mount_path: str = None,
+        app: str = None,
+        environment: str = "production",
+        apply: bool = True,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.name = name
+        self.source = source
+        self.reference = reference
+        self.mount_path = mount_path
+        self.app = app
+        self.environment = environment
+        self.apply = apply
+
+    def execute(self) -> None:
+        """Execute files:set command."""
+        from cli.commands.config import apply_app_env
+        from cli.secret_manager import SecretManager
+
+        if bool(self.source) == bool(self.reference):
+            self.exit_with_error("Use exactly one of --from or --ref")
+
+        if self.source:
+            path = Path(self.source).expanduser()
+            if not path.is_file():
+                self.exit_with_error(f"File not found: {path}")
+            content = path.read_text()
+        else:
+            content = self.reference
+
+        if self.app:
+            self.get_app_config(self.app)
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        try:
+            secret_mgr.set_file(self.app, self.name, content, self.mount_path)
+        except ValueError as e:
+            self.exit_with_error(str(e))
+
+        scope = f"app:{self.app}" if self.app else "shared"
+        if self.json_output and not self.apply:
+            self.output_json(
+                {"name": self.name, "scope": scope, "environment": self.environment}
+            )
+            return
+
+        self.console.print(
+            f"[green]✓ {self.name} saved ({scope}, {self.environment})[/green]"
+        )
+        if not self.apply:
+            self.console.print(
+                f"[dim]Apply with: superdeploy {self.project_name}:config:push"
+                f"{' -a ' + self.app if self.app else ''}[/dim]"
+            )
+            return
+
+        self.require_deployment()
+        logger = self.init_logger(self.project_name, "files-set")
+        if logger:
+            logger.step("Writing secret files to VMs")
+        apps = [self.app] if self.app else self.list_apps()
+        applied, failed = apply_app_env(
+            self.project_root, self.project_name, self.environment, apps, logger
+        )
+        if failed:
+            if logger:
+                logger.log_error(f"{failed} app(s) failed, {applied} updated")
+            raise SystemExit(1)
+        if logger:
+            logger.success(f"{applied} app(s) up to date")
+
+
+class FilesUnsetCommand(ProjectCommand):
+    """Delete a secret file."""
+
+    def __init__(
+        self,
+        project_name: str,
+        name: str,
+        app: str = None,
+        environment: str = "production",
+        apply: bool = True,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.name = name
+        self.app = app
+        self.environment = environment
+        self.apply = apply
+
+    def execute(self) -> None:
+        """Execute files:unset command."""
+        from cli.commands.config import apply_app_env
+        from cli.secret_manager import SecretManager
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        removed = secret_mgr.delete_file(self.app, self.name)
+
+        if not removed:
+            if self.json_output:
+                self.output_json({"name": self.name, "removed": False}, 1)
+                return
+            self.console.print(f"[red]✗ {self.name} not found[/red]")
+            raise SystemExit(1)
+
+        self.console.print(f"[green]✓ {self.name} removed[/green]")
+        if not self.apply:
+            return
+
+        # Pushing unmounts the file and removes it from the VM
+        self.require_deployment()
+        logger = self.init_logger(self.project_name, "files-unset")
+        if logger:
+            logger.step("Removing secret file from VMs")
+        apps = [self.app] if self.app else self.list_apps()
+        applied, failed = apply_app_env(
+            self.project_root, self.project_name, self.environment, apps, logger
+        )
+        if failed:
+            if logger:
+                logger.log_error(f"{failed} app(s) failed, {applied} updated")
+            raise SystemExit(1)
+        if logger:
+            logger.success(f"{applied} app(s) up to date")
+
+
+class FilesListCommand(ProjectCommand):
+    """List secret files (content is never printed)."""
+
+    def __init__(
+        self,
+        project_name: str,
+        environment: str = "production",
+        app: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.environment = environment
+        self.app = app
+
+    def execute(self) -> None:
+        """Execute files:list command."""
+        from cli.secret_manager import SecretManager
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        files = secret_mgr.list_files()
+        if self.app:
+            files = [f for f in files if f["app"] in (self.app, None)]
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "environment": self.environment,
+                    "files": [
+                        {
+                            **f,
+                            "updated_at": (
+                                f["updated_at"].isoformat() if f["updated_at"] else None
+                            ),
+                        }
+                        for f in files
+                    ],
+                }
+            )
+            return
+
+        self.show_header(
+            title="Secret Files",
+            project=self.project_name,
+            details={"Environment": self.environment},
+        )
+
+        if not files:
+            self.console.print("[dim]No secret files[/dim]")
+            self.console.print(
+                f"[dim]Add one: superdeploy {self.project_name}:files:set "
+                f"gcp.json --from ./gcp.json --mount /app/gcp.json -a api[/dim]"
+            )
+            return
+
+        table = Table(show_header=True, header_style="bold cyan")
+        table.add_column("Name", style="cyan")
+        table.add_column("Scope")
+        table.add_column("Mount path")
+        table.add_column("Content", style="dim")
+        for f in files:
+            content = "reference" if f["reference"] else f"{f['size']} bytes"
+            table.add_row(f["name"], f["app"] or "shared", f["mount_path"], content)
+        self.console.print(table)
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="files:set")
+@click.argument("name")
+@click.option("--from", "source", help="Local file to store")
+@click.option("--ref", "reference", help="vault:// or sops:// reference instead")
+@click.option(
+    "--mount",
+    "mount_path",
+    help="Absolute path inside the containers (required for new files)",
+)
+@click.option("-a", "--app", help="App name (default: shared, mounted into all apps)")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--no-apply", is_flag=True, help="Only store, don't push to the VMs")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def files_set(
+    project,
+    name,
+    source,
+    reference,
+    mount_path,
+    app,
+    environment,
+    no_apply,
+    verbose,
+    json_output,
+):
+    """
+    Store a secret file and mount it into the app's containers
+
+    The file is written to tmpfs on the VM and mounted read-only; the
+    app's services are restarted when it changes.
+
+    \b
+    Examples:
+      superdeploy cheapa:files:set gcp.json --from ./sa.json --mount /app/gcp.json -a api
+      superdeploy cheapa:files:set client.pem --ref vault://kv/tls#cert --mount /certs/client.pem
+    """
+    cmd = FilesSetCommand(
+        project,
+        name,
+        source=source,
+        reference=reference,
+        mount_path=mount_path,
+        app=app,
+        environment=environment,
+        apply=not no_apply,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="files:unset")
+@click.argument("name")
+@click.option("-a", "--app", help="App name (default: shared)")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--no-apply", is_flag=True, help="Only delete, don't push to the VMs")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def files_unset(project, name, app, environment, no_apply, verbose, json_output):
+    """
+    Remove a secret file and unmount it
+
+    \b
+    Example:
+      superdeploy cheapa:files:unset gcp.json -a api
+    """
+    cmd = FilesUnsetCommand(
+        project,
+        name,
+        app=app,
+        environment=environment,
+        apply=not no_apply,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="files:list")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("-a", "--app", help="Only files mounted into this app")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def files_list(project, environment, app, verbose, json_output):
+    """
+    List secret files and where they are mounted
+
+    \b
+    Example:
+      superdeploy cheapa:files:list -a api
+    """
+    cmd = FilesListCommand(
+        project,
+        environment=environment,
+        app=app,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
@@ -5,12 +5,14 @@ Generate deployment files - GitHub Actions workflows with self-hosted runners
 import click
 from pathlib import Path
 from dataclasses import dataclass
+from typing import Optional
 from jinja2 import Template
 
 from cli.base import ProjectCommand
 from cli.secret_manager import SecretManager
 from cli.core.app_type_registry import app_type_registry
 from cli.exceptions import ConfigurationError
+from cli.marker_manager import AppMarker, MarkerManager
 
 
 @dataclass
@@ -25,6 +27,7 @@ class WorkflowConfig:
     repo_org: str
     docker_org: str
     processes: dict  # Process definitions for marker file
+    port: Optional[int] = None  # Replaces $PORT in Procfile commands
 
 
 class WorkflowGenerator:
@@ -109,6 +112,7 @@ class WorkflowGenerator:
             repo_org=config.repo_org,
             docker_org=config.docker_org,
             processes=config.processes,  # For marker file creation
+            port=config.port,
         )
 
 
@@ -119,11 +123,13 @@ class GenerateCommand(ProjectCommand):
         self,
         project_name: str,
         app: str = None,
+        write_marker: bool = False,
         verbose: bool = False,
         json_output: bool = False,
     ):
         super().__init__(project_name, verbose=verbose, json_output=json_output)
         self.app = app
+        self.write_marker = write_marker
 
     def execute(self) -> None:
         """Execute generate command."""
@@ -258,8 +264,29 @@ class GenerateCommand(ProjectCommand):
             # 2. Create superdeploy marker (multi-process mode)
             vm_role = app_config.get("vm", "app")
 
-            # Get processes from config or create default
+            # Marker, or its Procfile/app.json equivalent
+            marker = None
+            heroku = False
+            if app_path and app_path.exists():
+                marker = MarkerManager.load_marker(
+                    app_path,
+                    self.project_name,
+                    app_name,
+                    vm_role,
+                    app_config.get("port"),
+                )
+                heroku = marker is not None and not MarkerManager.has_marker(app_path)
+            if heroku:
+                self.console.print("  [dim]Marker: Procfile/app.json[/dim]")
+            elif marker and self.write_marker:
+                self.console.print("  [dim]Marker exists, not overwritten[/dim]")
+
+            # Get processes from config, the marker or create default
             processes = app_config.get("processes")
+            if not processes and marker and marker.processes:
+                processes = {
+                    name: proc.to_dict() for name, proc in marker.processes.items()
+                }
             if not processes:
                 # Auto-create default process based on app type
                 port = app_config.get("port")
@@ -325,6 +352,7 @@ class GenerateCommand(ProjectCommand):
                 repo_org=config.get("github", {}).get("organization", "GITHUB_ORG"),
                 docker_org=config.get("docker", {}).get("organization", "DOCKER_ORG"),
                 processes=processes,  # For marker file generation in workflow
+                port=app_config.get("port"),
             )
 
             github_workflow = WorkflowGenerator.generate_workflow(workflow_config)
@@ -354,6 +382,11 @@ class GenerateCommand(ProjectCommand):
             workflow_file.write_text(github_workflow)
             self.console.print(f"  [dim]✓ Workflow: {workflow_file}[/dim]")
 
+            if marker and marker.addons:
+                self.attach_addons(app_name, marker, config["addons"], secret_mgr)
+            if heroku:
+                self.apply_app_json(app_name, app_path, app_config, secret_mgr)
+
             # Sync processes to database
             from cli.database import get_db_session
             from sqlalchemy import text
@@ -425,24 +458,104 @@ class GenerateCommand(ProjectCommand):
         self.console.print("  [dim]3. GitHub Actions will automatically deploy![/dim]")
         self.console.print()
 
+    def attach_addons(self, app_name, marker, addons: dict, secret_mgr) -> None:
+        """
+        Create the marker's addon attachments (<AS>_HOST ..., <AS>_URL).
+        Aliases that already exist are left alone.
+        """
+        from cli.core.heroku import attachment_aliases
+
+        existing = secret_mgr.get_aliases(app_name)
+        for attachment in marker.addons:
+            addon_type = attachment["type"]
+            as_var = attachment.get("as") or addon_type.upper()
+            instances = [
+                (category, name)
+                for category, items in addons.items()
+                for name, addon in items.items()
+                if addon["type"] == addon_type
+            ]
+            if not instances:
+                self.console.print(
+                    f"  [yellow]⚠ No {addon_type} addon to attach as {as_var}: "
+                    f"superdeploy {self.project_name}:addons:add {addon_type}"
+                    "[/yellow]"
+                )
+                continue
+            category, name = next(
+                (i for i in instances if i[1] == "primary"), instances[0]
+            )
+
+            aliases = attachment_aliases(addon_type, f"{addon_type}.{name}", as_var)
+            added = 0
+            for alias_key, target in aliases.items():
+                if alias_key not in existing:
+                    secret_mgr.set_alias(app_name, alias_key, target)
+                    added += 1
+            if added:
+                self.console.print(
+                    f"  [dim]✓ Attached {category}.{name} as {as_var} "
+                    f"({added} aliases)[/dim]"
+                )
+
+    def apply_app_json(self, app_name, app_path: Path, app_config, secret_mgr) -> None:
+        """Seed app.json env defaults, report what has no equivalent, write marker"""
+        from cli.core.heroku import build_marker, env_defaults
+
+        current = secret_mgr.get_app_secrets(app_name, resolve=False)
+        seeded = 0
+        for key, value in env_defaults(app_path).items():
+            if key not in current:
+                secret_mgr.set_app_secret(app_name, key, value)
+                seeded += 1
+        if seeded:
+            self.console.print(
+                f"  [dim]✓ Seeded {seeded} app.json env value(s)[/dim]"
+            )
+
+        vm_role = app_config.get("vm", "app")
+        marker, warnings = build_marker(
+            app_path, self.project_name, app_name, vm_role, app_config.get("port")
+        )
+        for warning in warnings:
+            self.console.print(f"  [yellow]⚠ {warning}[/yellow]")
+
+        if self.write_marker:
+            marker_file = MarkerManager.save_marker(
+                app_path, AppMarker.from_dict(marker)
+            )
+            self.console.print(f"  [dim]✓ Marker: {marker_file}[/dim]")
+
 
 @click.command(name="generate")
 @click.option("--app", help="Generate for specific app only")
+@click.option(
+    "--write-marker",
+    is_flag=True,
+    help="Write a superdeploy marker equivalent to the app's Procfile/app.json",
+)
 @click.option("--verbose", "-v", is_flag=True, help="Show all command output")
 @click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
-def generate(project, app, verbose, json_output):
+def generate(project, app, write_marker, verbose, json_output):
     """
     Generate deployment files with GitHub Actions workflows
 
     Features:
     - Secret hierarchy (shared + app-specific)
     - GitHub self-hosted runners
-    - superdeploy marker files
+    - superdeploy marker files (or a Heroku Procfile/app.json)
     - Smart VM selection based on labels
 
     Example:
         superdeploy cheapa:generate
         superdeploy cheapa:generate --app api
+        superdeploy cheapa:generate --app api --write-marker
     """
-    cmd = GenerateCommand(project, app=app, verbose=verbose, json_output=json_output)
+    cmd = GenerateCommand(
+        project,
+        app=app,
+        write_marker=write_marker,
+        verbose=verbose,
+        json_output=json_output,
+    )
     cmd.run()
@@ -0,0 +1,358 @@
+"""SuperDeploy CLI - Lifecycle hooks
+
+Hooks run on before_build, before_deploy, after_deploy, on_rollback,
+before_down, after_backup and after_addon_change. Apps declare theirs in the
+marker's `hooks:` section; `<project>:hooks:add` adds more (for one app or
+every app) without touching app repos. See cli.core.hooks for hook types.
+"""
+
+import click
+from rich.table import Table
+from cli.base import ProjectCommand
+from cli.core.hooks import EVENTS, FAILURE_POLICIES, HTTP_METHODS
+
+
+class HooksListCommand(ProjectCommand):
+    """List a project's lifecycle hooks (markers + hooks:add)."""
+
+    def __init__(
+        self,
+        project_name: str,
+        app_name: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.app_name = app_name
+
+    def execute(self) -> None:
+        """Execute hooks command."""
+        from cli.services.hook_service import HookService
+
+        service = HookService(self.project_root, self.project_name)
+        apps = [self.app_name] if self.app_name else self.list_apps()
+        hooks = []
+        try:
+            for app_name in apps:
+                hooks += service.marker_hooks(app_name)
+        except Exception as e:
+            self.print_warning(f"Marker hooks not read: {e}")
+        hooks += [
+            hook
+            for hook in service.db_hooks()
+            if not self.app_name or hook.app in (None, self.app_name)
+        ]
+        hooks.sort(key=lambda hook: EVENTS.index(hook.event))
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "hooks": [
+                        {"event": hook.event, "source": hook.source, **hook.to_dict()}
+                        for hook in hooks
+                    ]
+                }
+            )
+            return
+
+        self.show_header(title="Lifecycle Hooks", project=self.project_name)
+        if not hooks:
+            self.print_dim("No hooks configured")
+            self.print_dim(
+                f"Add one: superdeploy {self.project_name}:hooks:add after_deploy "
+                '-a api --exec "python manage.py migrate"'
+            )
+            return
+
+        table = Table()
+        table.add_column("ID", style="cyan")
+        table.add_column("Event")
+        table.add_column("App")
+        table.add_column("Type")
+        table.add_column("Runs")
+        table.add_column("Timeout", style="dim")
+        table.add_column("On failure")
+        for hook in hooks:
+            table.add_row(
+                str(hook.id) if hook.id is not None else "[dim]marker[/dim]",
+                hook.event,
+                hook.app or "[dim]all[/dim]",
+                hook.type,
+                hook.describe(),
+                f"{hook.effective_timeout}s",
+                "[yellow]warn[/yellow]" if hook.on_failure == "warn" else "abort",
+            )
+        self.console.print(table)
+
+
+class HooksAddCommand(ProjectCommand):
+    """Add a lifecycle hook."""
+
+    def __init__(
+        self,
+        project_name: str,
+        event: str,
+        spec: dict,
+        app_name: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.event = event
+        self.spec = spec
+        self.app_name = app_name
+
+    def execute(self) -> None:
+        """Execute hooks:add command."""
+        from cli.services.hook_service import HookService
+
+        service = HookService(self.project_root, self.project_name)
+        try:
+            hook = service.add(self.event, self.spec, self.app_name)
+        except ValueError as e:
+            self._fail(str(e))
+
+        if self.json_output:
+            self.output_json({"event": hook.event, **hook.to_dict()})
+        else:
+            scope = hook.app or "every app"
+            self.print_success(
+                f"Hook #{hook.id} added: {hook.event} ({hook.type}) for {scope}"
+            )
+        push_hooks(self, service)
+
+    def _fail(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
+
+class HooksRemoveCommand(ProjectCommand):
+    """Remove a lifecycle hook added with hooks:add."""
+
+    def __init__(
+        self,
+        project_name: str,
+        hook_id: int,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.hook_id = hook_id
+
+    def execute(self) -> None:
+        """Execute hooks:remove command."""
+        from cli.services.hook_service import HookService
+
+        service = HookService(self.project_root, self.project_name)
+        try:
+            hook = service.remove(self.hook_id)
+        except ValueError as e:
+            if self.json_output:
+                self.output_json_error(str(e))
+            self.exit_with_error(str(e))
+
+        if self.json_output:
+            self.output_json({"removed": {"event": hook.event, **hook.to_dict()}})
+        else:
+            self.print_success(f"Hook #{self.hook_id} removed from {self.project_name}")
+        push_hooks(self, service)
+
+
+class HooksPushCommand(ProjectCommand):
+    """Write hooks:add hooks to the project's VMs for deploy workflows."""
+
+    def execute(self) -> None:
+        """Execute hooks:push command."""
+        from cli.services.hook_service import HookService
+
+        self.require_deployment()
+        errors = HookService(self.project_root, self.project_name).push()
+        if self.json_output:
+            self.output_json({"vms": errors})
+            return
+        for vm_name, error in errors.items():
+            if error:
+                self.print_warning(f"{vm_name}: {error}")
+            else:
+                self.print_success(f"Hooks written to {vm_name}")
+        if any(errors.values()):
+            raise SystemExit(1)
+
+
+class HooksRunCommand(ProjectCommand):
+    """Run an event's hooks now (to try them out)."""
+
+    def __init__(
+        self,
+        project_name: str,
+        event: str,
+        app_name: str = None,
+        verbose: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose)
+        self.event = event
+        self.app_name = app_name
+
+    def execute(self) -> None:
+        """Execute hooks:run command."""
+        self.show_header(
+            title="Run Hooks",
+            project=self.project_name,
+            app=self.app_name,
+            details={"Event": self.event},
+        )
+        logger = self.init_logger(self.project_name, f"hooks-{self.event}")
+        apps = [self.app_name] if self.app_name else None
+        if not self.run_hooks(self.event, apps, logger, source="manual"):
+            raise SystemExit(1)
+        self.print_success(f"{self.event} hooks completed")
+
+
+def push_hooks(cmd: ProjectCommand, service) -> None:
+    """Refresh hooks.json on deployed VMs after a change (best effort)"""
+    if not cmd.ensure_state_service().has_state():
+        return
+    for vm_name, error in service.push().items():
+        if error:
+            cmd.print_warning(
+                f"Hooks not written to {vm_name} ({error}); deploy workflows "
+                f"there use the old set until: superdeploy "
+                f"{cmd.project_name}:hooks:push"
+            )
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="hooks")
+@click.option("-a", "--app", help="Only hooks that run for this app")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def hooks_list(project, app, verbose, json_output):
+    """
+    List lifecycle hooks (app markers and hooks:add)
+
+    \b
+    Example:
+      superdeploy cheapa:hooks
+    """
+    cmd = HooksListCommand(project, app, verbose=verbose, json_output=json_output)
+    cmd.run()
+
+
+@click.command(name="hooks:add")
+@click.argument("event", type=click.Choice(EVENTS))
+@click.option("-a", "--app", help="App the hook belongs to (default: every app)")
+@click.option("--exec", "exec_command", help="Command to run in the app container")
+@click.option("--http", "url", help="URL to call with the event as JSON")
+@click.option("--script", help="Command to run from the app directory")
+@click.option("--timeout", type=int, help="Seconds (default: 300, http 30)")
+@click.option(
+    "--on-failure",
+    type=click.Choice(FAILURE_POLICIES),
+    default="abort",
+    show_default=True,
+    help="abort stops the operation, warn carries on",
+)
+@click.option(
+    "--method",
+    type=click.Choice(HTTP_METHODS, case_sensitive=False),
+    default="POST",
+    show_default=True,
+    help="HTTP method (http hooks)",
+)
+@click.option("--header", "headers", multiple=True, help="Name: value (http hooks)")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def hooks_add(
+    project,
+    event,
+    app,
+    exec_command,
+    url,
+    script,
+    timeout,
+    on_failure,
+    method,
+    headers,
+    verbose,
+    json_output,
+):
+    """
+    Add a lifecycle hook
+
+    \b
+    Examples:
+      superdeploy cheapa:hooks:add after_deploy -a api --exec "python manage.py migrate"
+      superdeploy cheapa:hooks:add after_deploy --http https://hooks.slack.com/... \\
+          --on-failure warn
+      superdeploy cheapa:hooks:add before_down --script ./scripts/drain.sh
+    """
+    spec = {"on_failure": on_failure}
+    for key, value in (("exec", exec_command), ("http", url), ("script", script)):
+        if value:
+            spec[key] = value
+    if timeout is not None:
+        spec["timeout"] = timeout
+    if url:
+        spec["method"] = method.upper()
+        spec["headers"] = dict(
+            (name.strip(), value.strip())
+            for name, _, value in (header.partition(":") for header in headers)
+        )
+    cmd = HooksAddCommand(
+        project, event, spec, app, verbose=verbose, json_output=json_output
+    )
+    cmd.run()
+
+
+@click.command(name="hooks:remove")
+@click.argument("hook_id", type=int)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def hooks_remove(project, hook_id, verbose, json_output):
+    """
+    Remove a hook added with hooks:add (marker hooks live in the app repo)
+
+    \b
+    Example:
+      superdeploy cheapa:hooks:remove 3
+    """
+    cmd = HooksRemoveCommand(project, hook_id, verbose=verbose, json_output=json_output)
+    cmd.run()
+
+
+@click.command(name="hooks:push")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def hooks_push(project, verbose, json_output):
+    """
+    Write hooks:add hooks to the project's VMs (deploy workflows read them)
+
+    hooks:add and hooks:remove do this themselves; run it for new VMs.
+
+    \b
+    Example:
+      superdeploy cheapa:hooks:push
+    """
+    cmd = HooksPushCommand(project, verbose=verbose, json_output=json_output)
+    cmd.run()
+
+
+@click.command(name="hooks:run")
+@click.argument("event", type=click.Choice(EVENTS))
+@click.option("-a", "--app", help="Only this app's hooks (and project-wide ones)")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+def hooks_run(project, event, app, verbose):
+    """
+    Run an event's hooks now, to try them out
+
+    \b
+    Example:
+      superdeploy cheapa:hooks:run after_deploy -a api
+    """
+    cmd = HooksRunCommand(project, event, app, verbose=verbose)
+    cmd.run()
@@ -0,0 +1,260 @@
+"""SuperDeploy CLI - Import a docker-compose.yml into a project
+
+Database/cache/queue images become addons, the other services become apps
+and processes, environment values become secrets (or aliases when they
+point at an addon). Whatever has no SuperDeploy equivalent is listed in a
+report instead of being dropped silently.
+"""
+
+import click
+import yaml
+from pathlib import Path
+from cli.base import ProjectCommand
+from cli.commands.manifest import ACTION_STYLES
+
+
+class ImportComposeCommand(ProjectCommand):
+    """Import services, env and addons from a docker-compose.yml."""
+
+    def __init__(
+        self,
+        project_name: str,
+        file_path: str,
+        environment: str = "production",
+        dry_run: bool = False,
+        report_path: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.file_path = Path(file_path)
+        self.environment = environment
+        self.dry_run = dry_run
+        self.report_path = report_path
+
+    def execute(self) -> None:
+        """Execute import:compose command."""
+        from cli.core.addon_schema import validate_data
+        from cli.core.compose_import import translate
+        from cli.core.manifest import rollout_commands
+        from cli.services.manifest_service import ManifestService
+
+        if not self.file_path.is_file():
+            self.exit_with_error(f"Compose file not found: {self.file_path}")
+        try:
+            compose = yaml.safe_load(self.file_path.read_text()) or {}
+        except yaml.YAMLError as e:
+            self.exit_with_error(f"Invalid YAML in {self.file_path}: {e}")
+        if not compose.get("services"):
+            self.exit_with_error(f"No services in {self.file_path}")
+
+        result = translate(compose, self.project_name, self.file_path.parent)
+
+        # Same schema apply and the reconciler enforce on project.yml
+        issues = validate_data("manifest", result.manifest)
+        if issues:
+            if self.json_output:
+                self.output_json(
+                    {
+                        "project": self.project_name,
+                        "valid": False,
+                        "issues": [str(i) for i in issues],
+                    },
+                    1,
+                )
+            self.print_error(
+                f"{self.file_path} translates to an invalid manifest "
+                "(rename the services or fix the values below)"
+            )
+            for issue in issues:
+                self.console.print(f"  [red]•[/red] {issue}")
+            raise SystemExit(1)
+
+        service = ManifestService()
+        changes, problems = service.plan(result.manifest)
+
+        if problems:
+            if self.json_output:
+                self.output_json(
+                    {"project": self.project_name, "problems": problems}, 1
+                )
+            self.print_error(f"{self.file_path} can't be imported")
+            for problem in problems:
+                self.console.print(f"  [red]•[/red] {problem}")
+            raise SystemExit(1)
+
+        if self.report_path:
+            Path(self.report_path).write_text(self.format_report(result))
+
+        secret_count = sum(len(keys) for keys in result.secrets.values())
+        if not self.json_output:
+            self.show_header(
+                title="Import docker-compose.yml",
+                project=self.project_name,
+                details={
+                    "File": str(self.file_path),
+                    "Environment": self.environment,
+                },
+            )
+            self.print_plan(changes, result)
+
+        if self.dry_run:
+            if self.json_output:
+                self.output_json(self.to_dict(changes, result, applied=False))
+                return
+            self.print_dim("Dry run - nothing imported")
+            return
+
+        # Apps have to exist before their secrets and files can be written
+        service.apply(result.manifest, changes)
+
+        from cli.secret_manager import SecretManager
+
+        secret_mgr = SecretManager(
+            self.project_root, self.project_name, self.environment
+        )
+        for app_name, secrets in result.secrets.items():
+            for key, (value, usage) in secrets.items():
+                secret_mgr.set_app_secret(app_name, key, value, usage=usage)
+        for secret_file in result.files:
+            secret_mgr.set_file(
+                secret_file.app,
+                secret_file.name,
+                secret_file.source.read_text(),
+                mount_path=secret_file.mount_path,
+            )
+
+        if self.json_output:
+            self.output_json(self.to_dict(changes, result, applied=True))
+            return
+
+        self.print_success(
+            f"Imported {len(changes)} change(s), {secret_count} secret(s) "
+            f"and {len(result.files)} file(s) into {self.project_name}"
+        )
+        if self.report_path:
+            self.print_dim(f"Report written to {self.report_path}")
+
+        # Plain secrets and files aren't manifest changes, push them too
+        steps = [f"{self.project_name}:generate"]
+        steps += rollout_commands(self.project_name, changes)
+        if secret_count or result.files:
+            steps.append(f"{self.project_name}:config:push")
+        self.console.print("\n[bold]Next steps:[/bold]")
+        for i, step in enumerate(dict.fromkeys(steps), 1):
+            self.console.print(f"  {i}. [cyan]superdeploy {step}[/cyan]")
+
+    def print_plan(self, changes, result) -> None:
+        """Planned database changes, then secrets and the report"""
+        for change in changes:
+            marker, color = ACTION_STYLES[change.action]
+            self.console.print(f"[{color}]{marker} {change.resource}[/{color}]")
+            for name, (old, new) in change.fields.items():
+                if change.action == "create":
+                    self.console.print(f"    {name}: {new}")
+                else:
+                    self.console.print(f"    {name}: [dim]{old}[/dim] → {new}")
+
+        for app_name, secrets in result.secrets.items():
+            for key, (_, usage) in secrets.items():
+                suffix = f" [dim]({usage})[/dim]" if usage != "runtime" else ""
+                self.console.print(f"[green]+ secret {app_name}.{key}[/green]{suffix}")
+        for secret_file in result.files:
+            self.console.print(
+                f"[green]+ file {secret_file.app}/{secret_file.name}[/green] "
+                f"→ {secret_file.mount_path}"
+            )
+
+        if result.report:
+            self.console.print("\n[bold]Not imported as written:[/bold]")
+            for item in result.report:
+                color = "yellow" if item.level == "skipped" else "dim"
+                self.console.print(
+                    f"  [{color}]{item.level:<8}[/{color}] "
+                    f"{item.service} {item.subject}: {item.message}"
+                )
+        self.console.print()
+
+    def format_report(self, result) -> str:
+        """Markdown report for the migration ticket"""
+        lines = [
+            f"# docker-compose import: {self.project_name}",
+            "",
+            f"Source: `{self.file_path}`",
+            "",
+        ]
+        for level, title in (("skipped", "Not imported"), ("changed", "Changed")):
+            items = [i for i in result.report if i.level == level]
+            if not items:
+                continue
+            lines += [f"## {title}", "", "| Service | Item | Note |", "|---|---|---|"]
+            lines += [f"| {i.service} | `{i.subject}` | {i.message} |" for i in items]
+            lines.append("")
+        return "\n".join(lines)
+
+    def to_dict(self, changes, result, applied: bool) -> dict:
+        return {
+            "project": self.project_name,
+            "environment": self.environment,
+            "changes": [c.to_dict() for c in changes],
+            "secrets": {
+                app_name: {key: usage for key, (_, usage) in secrets.items()}
+                for app_name, secrets in result.secrets.items()
+            },
+            "files": [
+                {"app": f.app, "name": f.name, "mount_path": f.mount_path}
+                for f in result.files
+            ],
+            "report": [item.to_dict() for item in result.report],
+            "applied": applied,
+        }
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="import:compose")
+@click.argument("file_path", type=click.Path())
+@click.option(
+    "-e", "--env", "environment", default="production", help="Secrets environment"
+)
+@click.option("--dry-run", is_flag=True, help="Show what would be imported")
+@click.option(
+    "--report", "report_path", help="Write the report as Markdown to this file"
+)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def import_compose(
+    project, file_path, environment, dry_run, report_path, verbose, json_output
+):
+    """
+    Import a docker-compose.yml
+
+    \b
+    postgres/redis/rabbitmq/mongo/elasticsearch  → addons (image tag = version)
+    services sharing a build context            → one app, one process each
+    environment, env_file, build args            → app secrets
+    values pointing at an addon service          → aliases to the addon's keys
+    read-only single-file bind mounts            → secret files
+
+    Volumes, networks, healthchecks and everything else without an
+    equivalent are listed in the report. Existing apps and addons are
+    updated, nothing is deleted.
+
+    \b
+    Examples:
+      superdeploy cheapa:import:compose docker-compose.yml --dry-run
+      superdeploy cheapa:import:compose docker-compose.yml --report import.md
+    """
+    cmd = ImportComposeCommand(
+        project,
+        file_path,
+        environment=environment,
+        dry_run=dry_run,
+        report_path=report_path,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
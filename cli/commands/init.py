@@ -1,7 +1,12 @@
 """
 Project initialization - interactive wizard with database-backed secrets
+
+With --blueprint the wizard is skipped: VMs, addons, apps and edge policy
+come from a blueprint (see cli/core/blueprint.py) and its parameters from
+--set, so init can run unattended.
 """
 
+import sys
 import click
 from pathlib import Path
 from dataclasses import dataclass
@@ -326,10 +331,17 @@ class InitCommand(BaseCommand):
     """Initialize new project with database secrets."""
 
     def __init__(
-        self, project_name: str, verbose: bool = False, json_output: bool = False
+        self,
+        project_name: str,
+        blueprint: str = None,
+        params: Dict[str, str] = None,
+        verbose: bool = False,
+        json_output: bool = False,
     ):
         super().__init__(verbose=verbose, json_output=json_output)
         self.project_name = project_name
+        self.blueprint = blueprint
+        self.params = dict(params or {})
 
     def execute(self) -> None:
         """Execute init command."""
@@ -337,8 +349,13 @@ class InitCommand(BaseCommand):
             title="Initialize Project",
             project=self.project_name,
             subtitle="Infrastructure + secrets setup",
+            details={"Blueprint": self.blueprint} if self.blueprint else None,
         )
 
+        if self.blueprint:
+            self._init_from_blueprint()
+            return
+
         project_dir = self.project_root / "projects" / self.project_name
 
         # Check if project exists in database
@@ -596,6 +613,81 @@ class InitCommand(BaseCommand):
             addons=addons,
         )
 
+    def _init_from_blueprint(self) -> None:
+        """Create the project from a blueprint, prompting only for missing params"""
+        from cli.core.addon_schema import validate_data
+        from cli.core.blueprint import BlueprintError, find, render
+        from cli.services.manifest_service import ManifestService
+
+        try:
+            blueprint = find(self.blueprint, self.project_root)
+        except BlueprintError as e:
+            self._fail(str(e))
+
+        missing = [p for p in blueprint.required_params() if p not in self.params]
+        if missing and not self.json_output and sys.stdin.isatty():
+            for name in missing:
+                description = blueprint.params[name].get("description") or name
+                self.params[name] = Prompt.ask(f"[?] {description}")
+
+        try:
+            manifest = render(blueprint, self.project_name, self.params)
+        except BlueprintError as e:
+            self._fail(f"{e} (pass --set name=value)")
+
+        issues = validate_data("manifest", manifest)
+        if issues:
+            self._fail(
+                f"Blueprint {blueprint.name} renders an invalid manifest: "
+                + "; ".join(f"{i.path}: {i.message}" for i in issues)
+            )
+
+        service = ManifestService()
+        exists, _ = service.export(self.project_name)
+        if exists:
+            if self.json_output:
+                self._fail(f"Project already exists: {self.project_name}")
+            if not self._confirm_overwrite():
+                return
+
+        # Overwriting replaces the VMs, apps and addons, like the wizard does
+        changes, problems = service.plan(manifest, prune=bool(exists))
+        if problems:
+            self._fail("; ".join(problems))
+        service.apply(manifest, changes)
+        (self.project_root / "projects" / self.project_name).mkdir(
+            parents=True, exist_ok=True
+        )
+
+        initializer = ProjectInitializer(self.project_root, self.console)
+        addons = manifest.get("addons") or {}
+        apps = list(manifest.get("apps") or {})
+        initializer.create_secrets_in_database(self.project_name, apps, addons)
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "project": self.project_name,
+                    "blueprint": blueprint.name,
+                    "source": blueprint.source,
+                    "changes": [c.to_dict() for c in changes],
+                    "applied": True,
+                }
+            )
+            return
+
+        addon_count = sum(len(instances or {}) for instances in addons.values())
+        self.console.print()
+        self.console.print(f"✓ Configuration saved from blueprint {blueprint.name}")
+        self.console.print(f"✓ Generated {addon_count} addon credentials")
+        self.console.print("⚠ Set Docker/GitHub credentials before deploying")
+        self._display_next_steps()
+
+    def _fail(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
     def _display_next_steps(self) -> None:
         """Display next steps after initialization."""
         self.console.print("\n[dim]Next steps:[/dim]")
@@ -604,9 +696,21 @@ class InitCommand(BaseCommand):
 
 
 @click.command(name="init")
+@click.option(
+    "--blueprint",
+    "-b",
+    help="Blueprint name, file or git URL (repo#name) instead of the wizard",
+)
+@click.option(
+    "--set",
+    "sets",
+    multiple=True,
+    metavar="NAME=VALUE",
+    help="Blueprint parameter (repeatable)",
+)
 @click.option("--verbose", "-v", is_flag=True, help="Show all command output")
 @click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
-def init(project, verbose, json_output):
+def init(project, blueprint, sets, verbose, json_output):
     """
     Initialize new project with database secrets
 
@@ -615,6 +719,27 @@ def init(project, verbose, json_output):
     - Secrets in PostgreSQL database
 
     No more .env files!
+
+    \b
+    From a blueprint (no prompts when every parameter is set):
+      superdeploy acme:init --blueprint saas-basic \\
+        --set domain=acme.com --set gcp_project=acme-prod
+      superdeploy blueprints    # List blueprints and their parameters
     """
-    cmd = InitCommand(project, verbose=verbose, json_output=json_output)
+    from cli.core.blueprint import BlueprintError, parse_sets
+
+    if sets and not blueprint:
+        raise click.UsageError("--set needs --blueprint")
+    try:
+        params = parse_sets(list(sets))
+    except BlueprintError as e:
+        raise click.BadParameter(str(e), param_hint="--set")
+
+    cmd = InitCommand(
+        project,
+        blueprint=blueprint,
+        params=params,
+        verbose=verbose,
+        json_output=json_output,
+    )
     cmd.run()
@@ -0,0 +1,332 @@
+"""SuperDeploy CLI - Declarative project manifests
+
+`apply -f project.yml` diffs a versioned manifest against the database and
+applies it; `<project>:export --manifest` writes one back out. Secrets are
+only carried by reference (vault://, sops://, {{ org.X }}), so the file is
+safe to commit.
+"""
+
+import click
+from pathlib import Path
+from cli.base import BaseCommand, ProjectCommand
+
+# Marker shown in front of each planned change
+ACTION_STYLES = {
+    "create": ("+", "green"),
+    "update": ("~", "yellow"),
+    "delete": ("-", "red"),
+}
+
+
+class ApplyCommand(BaseCommand):
+    """Apply a project manifest to the database."""
+
+    def __init__(
+        self,
+        file_path: str,
+        prune: bool = False,
+        dry_run: bool = False,
+        yes: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.file_path = Path(file_path)
+        self.prune = prune
+        self.dry_run = dry_run
+        self.yes = yes
+
+    def execute(self) -> None:
+        """Execute apply command."""
+        from cli.core.addon_schema import load_yaml_file, validate_file
+        from cli.services.manifest_service import ManifestService
+
+        if not self.file_path.is_file():
+            self.exit_with_error(f"Manifest not found: {self.file_path}")
+
+        issues = validate_file("manifest", self.file_path)
+        if issues:
+            if self.json_output:
+                self.output_json(
+                    {"valid": False, "issues": [str(i) for i in issues]}, 1
+                )
+            self.print_error(f"{self.file_path} is not a valid manifest")
+            for issue in issues:
+                self.console.print(f"  [red]•[/red] {issue}")
+            raise SystemExit(1)
+
+        manifest = load_yaml_file(self.file_path)
+        project_name = manifest["project"]["name"]
+        service = ManifestService()
+        changes, problems = service.plan(manifest, prune=self.prune)
+
+        if problems:
+            if self.json_output:
+                self.output_json({"project": project_name, "problems": problems}, 1)
+            self.print_error(f"{self.file_path} can't be applied")
+            for problem in problems:
+                self.console.print(f"  [red]•[/red] {problem}")
+            raise SystemExit(1)
+
+        if self.json_output and (self.dry_run or not changes):
+            self.output_json(
+                {
+                    "project": project_name,
+                    "changes": [c.to_dict() for c in changes],
+                    "applied": False,
+                },
+                self.exit_code(changes),
+            )
+            return
+
+        if not self.json_output:
+            self.show_header(
+                title="Apply Manifest",
+                project=project_name,
+                details={
+                    "File": str(self.file_path),
+                    "Prune": "yes" if self.prune else "no",
+                },
+            )
+            self.print_changes(changes)
+
+        if not changes:
+            self.print_success("Database matches the manifest")
+            return
+        if self.dry_run:
+            self.print_dim("Dry run - nothing applied")
+            if self.exit_code(changes):
+                raise SystemExit(self.exit_code(changes))
+            return
+
+        deletes = [c for c in changes if c.action == "delete"]
+        if deletes and not self.yes:
+            if self.json_output:
+                self.output_json_error("Deletes need confirmation: pass --yes")
+            if not self.confirm(f"Delete {len(deletes)} resource(s)?"):
+                self.print_warning("Aborted")
+                raise SystemExit(1)
+
+        service.apply(manifest, changes)
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "project": project_name,
+                    "changes": [c.to_dict() for c in changes],
+                    "applied": True,
+                }
+            )
+            return
+
+        self.print_success(f"{len(changes)} change(s) applied to {project_name}")
+        self.print_next_steps(project_name, changes)
+
+    def exit_code(self, changes) -> int:
+        """apply never fails on drift; diff overrides this"""
+        return 0
+
+    def print_changes(self, changes) -> None:
+        """Print the plan, one resource per line"""
+        if not changes:
+            return
+        for change in changes:
+            marker, color = ACTION_STYLES[change.action]
+            self.console.print(f"[{color}]{marker} {change.resource}[/{color}]")
+            if change.action == "delete":
+                continue
+            for name, (old, new) in change.fields.items():
+                if change.action == "create":
+                    self.console.print(f"    {name}: {new}")
+                else:
+                    self.console.print(f"    {name}: [dim]{old}[/dim] → {new}")
+        self.console.print()
+
+    def print_next_steps(self, project_name: str, changes) -> None:
+        """apply only writes the database; point at the commands that roll it out"""
+        from cli.core.manifest import rollout_commands
+
+        steps = rollout_commands(project_name, changes)
+        if not steps:
+            return
+        self.console.print("\n[bold]Next steps:[/bold]")
+        for i, step in enumerate(steps, 1):
+            self.console.print(f"  {i}. [cyan]superdeploy {step}[/cyan]")
+
+
+class DiffCommand(ApplyCommand):
+    """Show what apply would change (exit 1 on drift)."""
+
+    def __init__(self, file_path: str, prune: bool = False, **kwargs):
+        super().__init__(file_path, prune=prune, dry_run=True, **kwargs)
+
+    def exit_code(self, changes) -> int:
+        return 1 if changes else 0
+
+
+class ExportCommand(ProjectCommand):
+    """Export a project as a manifest or a JSON snapshot."""
+
+    def __init__(
+        self,
+        project_name: str,
+        manifest: bool = False,
+        output_path: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.manifest = manifest
+        self.output_path = output_path
+
+    def execute(self) -> None:
+        """Execute export command."""
+        from cli.core.manifest import dump
+        from cli.services.manifest_service import ManifestService
+
+        if not self.manifest:
+            # Full snapshot including secret values (backup/migration format)
+            from cli.commands.sync import SyncExportCommand
+
+            SyncExportCommand(
+                self.project_name,
+                output_path=self.output_path,
+                verbose=self.verbose,
+                json_output=self.json_output,
+            ).execute()
+            return
+
+        manifest, skipped = ManifestService().export(self.project_name)
+        if manifest is None:
+            self.exit_with_error(f"Project '{self.project_name}' not found")
+
+        if self.json_output:
+            self.output_json({"manifest": manifest, "plain_secrets_skipped": skipped})
+            return
+
+        header = (
+            f"SuperDeploy manifest for {self.project_name}\n"
+            f"Apply with: superdeploy apply -f <this file>"
+        )
+        if skipped:
+            header += (
+                f"\n\n{skipped} plain secret value(s) not exported; they stay in the\n"
+                f"database. Move them to vault:// or sops:// to manage them here."
+            )
+        text = dump(manifest, header=header)
+
+        if not self.output_path:
+            click.echo(text, nl=False)
+            return
+
+        Path(self.output_path).write_text(text)
+        self.print_success(f"Manifest written to {self.output_path}")
+        if skipped:
+            self.print_warning(f"{skipped} plain secret value(s) not exported")
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="apply")
+@click.option(
+    "-f",
+    "--file",
+    "file_path",
+    required=True,
+    type=click.Path(),
+    help="Project manifest (project.yml)",
+)
+@click.option(
+    "--prune",
+    is_flag=True,
+    help="Delete resources missing from collections the manifest declares",
+)
+@click.option("--dry-run", is_flag=True, help="Show the diff without applying")
+@click.option("-y", "--yes", is_flag=True, help="Don't ask before deleting")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def apply(file_path, prune, dry_run, yes, verbose, json_output):
+    """
+    Apply a declarative project manifest
+
+    Creates the project if it doesn't exist. Fields and sections left out
+    of the manifest are not touched; with --prune, entries missing from a
+    declared section (vms, apps, addons, an app's processes/aliases, an
+    environment's secrets) are deleted.
+
+    \b
+    Examples:
+      superdeploy apply -f project.yml --dry-run
+      superdeploy apply -f project.yml
+      superdeploy apply -f project.yml --prune --yes
+    """
+    cmd = ApplyCommand(
+        file_path,
+        prune=prune,
+        dry_run=dry_run,
+        yes=yes,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="diff")
+@click.option(
+    "-f",
+    "--file",
+    "file_path",
+    required=True,
+    type=click.Path(),
+    help="Project manifest (project.yml)",
+)
+@click.option("--prune", is_flag=True, help="Include deletes apply --prune would do")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def diff(file_path, prune, verbose, json_output):
+    """
+    Show drift between a manifest and the database
+
+    Exits 1 when apply would change something (for CI checks).
+
+    \b
+    Example:
+      superdeploy diff -f project.yml --prune
+    """
+    cmd = DiffCommand(file_path, prune=prune, verbose=verbose, json_output=json_output)
+    cmd.run()
+
+
+@click.command(name="export")
+@click.option(
+    "--manifest",
+    is_flag=True,
+    help="Declarative YAML manifest (secrets by reference) instead of a JSON snapshot",
+)
+@click.option("-o", "--output", "output_path", help="Write to file instead of stdout")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def export(project, manifest, output_path, verbose, json_output):
+    """
+    Export a project
+
+    With --manifest, writes a project.yml that `superdeploy apply`
+    round-trips without changes. Without it, writes the full JSON
+    snapshot (same as sync:export, includes secret values).
+
+    \b
+    Examples:
+      superdeploy cheapa:export --manifest -o project.yml
+      superdeploy cheapa:export -o backup.json
+    """
+    cmd = ExportCommand(
+        project,
+        manifest=manifest,
+        output_path=output_path,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
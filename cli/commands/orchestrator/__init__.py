@@ -1,13 +1,23 @@
-"""Orchestrator commands - init, up, down, status."""
+"""Orchestrator commands - init, up, down, status, backup."""
 
 from cli.commands.orchestrator.init import orchestrator_init
 from cli.commands.orchestrator.up import orchestrator_up
 from cli.commands.orchestrator.down import orchestrator_down
 from cli.commands.orchestrator.status import orchestrator_status
+from cli.commands.orchestrator.backup import (
+    orchestrator_backup,
+    orchestrator_restore,
+    orchestrator_backup_schedule,
+    orchestrator_backup_unschedule,
+)
 
 __all__ = [
     "orchestrator_init",
     "orchestrator_up",
     "orchestrator_down",
     "orchestrator_status",
+    "orchestrator_backup",
+    "orchestrator_restore",
+    "orchestrator_backup_schedule",
+    "orchestrator_backup_unschedule",
 ]
@@ -0,0 +1,278 @@
+"""SuperDeploy CLI - Organization secrets
+
+Org-level secrets (DOCKER_TOKEN, registry credentials, ...) are stored once
+and referenced by any project with an org.KEY alias or {{ org.KEY }}
+placeholder. Changing one re-syncs every consumer on its next vars:sync.
+"""
+
+import click
+from rich.table import Table
+from cli.base import BaseCommand
+
+
+class OrgSetCommand(BaseCommand):
+    """Create or update an org-level secret."""
+
+    def __init__(
+        self,
+        key_value: str,
+        environment: str = "production",
+        description: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.key_value = key_value
+        self.environment = environment
+        self.description = description
+
+    def execute(self) -> None:
+        """Execute org:set command."""
+        from cli.secret_manager import OrgSecretManager
+
+        if "=" not in self.key_value:
+            if self.json_output:
+                self.output_json({"error": "Invalid format. Use KEY=VALUE"}, 1)
+                return
+            self.console.print("[red]✗ Invalid format. Use: KEY=VALUE[/red]")
+            raise SystemExit(1)
+
+        key, value = self.key_value.split("=", 1)
+        key = key.strip()
+
+        org_mgr = OrgSecretManager(self.environment)
+        org_mgr.set(key, value, description=self.description)
+        consumers = org_mgr.consumers(key)
+
+        if self.json_output:
+            self.output_json(
+                {"key": key, "environment": self.environment, "consumers": consumers}
+            )
+            return
+
+        self.console.print(f"[green]✓ org.{key} saved ({self.environment})[/green]")
+        if not consumers:
+            self.console.print("[dim]No project references it yet[/dim]")
+            return
+
+        self.console.print(f"\n[bold]Consumers ({len(consumers)}):[/bold]")
+        projects = sorted({c["project"] for c in consumers if c["project"]})
+        for consumer in consumers:
+            self.console.print(
+                f"  • {consumer['project']}/{consumer['app'] or 'shared'} "
+                f"[dim]{consumer['key']}[/dim]"
+            )
+        self.console.print("\n[dim]Apply with:[/dim]")
+        for project in projects:
+            self.console.print(f"  [cyan]superdeploy {project}:vars:sync[/cyan]")
+
+
+class OrgUnsetCommand(BaseCommand):
+    """Delete an org-level secret."""
+
+    def __init__(
+        self,
+        key: str,
+        environment: str = "production",
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.key = key
+        self.environment = environment
+
+    def execute(self) -> None:
+        """Execute org:unset command."""
+        from cli.secret_manager import OrgSecretManager
+
+        org_mgr = OrgSecretManager(self.environment)
+        consumers = org_mgr.consumers(self.key)
+        removed = org_mgr.delete(self.key)
+
+        if self.json_output:
+            self.output_json(
+                {"key": self.key, "removed": removed, "consumers": consumers},
+                exit_code=0 if removed else 1,
+            )
+            return
+
+        if not removed:
+            self.console.print(f"[red]✗ org.{self.key} not found[/red]")
+            raise SystemExit(1)
+
+        self.console.print(f"[green]✓ org.{self.key} removed[/green]")
+        if consumers:
+            self.console.print(
+                f"[yellow]⚠ Still referenced by {len(consumers)} key(s); "
+                f"their next sync will fail to resolve it:[/yellow]"
+            )
+            for consumer in consumers:
+                self.console.print(
+                    f"  • {consumer['project']}/{consumer['app'] or 'shared'} "
+                    f"[dim]{consumer['key']}[/dim]"
+                )
+
+
+class OrgListCommand(BaseCommand):
+    """List org-level secrets and their consumers."""
+
+    def __init__(
+        self,
+        environment: str = "production",
+        show_values: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.environment = environment
+        self.show_values = show_values
+
+    def execute(self) -> None:
+        """Execute org:list command."""
+        from cli.secret_manager import OrgSecretManager
+
+        org_mgr = OrgSecretManager(self.environment)
+        secrets = org_mgr.list()
+        consumers = org_mgr.consumers()
+
+        usage = {}
+        for consumer in consumers:
+            for ref in consumer["source_ref"].split(","):
+                if ref.startswith("org."):
+                    usage.setdefault(ref[len("org.") :], set()).add(
+                        consumer["project"]
+                    )
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "environment": self.environment,
+                    "secrets": {
+                        key: {
+                            "description": data["description"],
+                            "projects": sorted(usage.get(key, [])),
+                            **({"value": data["value"]} if self.show_values else {}),
+                        }
+                        for key, data in secrets.items()
+                    },
+                }
+            )
+            return
+
+        self.show_header(
+            title="Organization Secrets", details={"Environment": self.environment}
+        )
+
+        if not secrets:
+            self.console.print("[dim]No org secrets[/dim]")
+            self.console.print(
+                "[dim]Add one: superdeploy org:set DOCKER_TOKEN=...[/dim]"
+            )
+            return
+
+        table = Table(show_header=True, header_style="bold cyan")
+        table.add_column("Key", style="cyan")
+        table.add_column("Value")
+        table.add_column("Used by")
+        table.add_column("Description", style="dim")
+        for key, data in secrets.items():
+            value = data["value"] if self.show_values else "***"
+            table.add_row(
+                key,
+                value,
+                ", ".join(sorted(usage.get(key, []))) or "-",
+                data["description"],
+            )
+        self.console.print(table)
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="org:set")
+@click.argument("key_value")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--description", help="What the secret is for")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def org_set(key_value, environment, description, verbose, json_output):
+    """
+    Set an organization-level secret
+
+    Projects reference it with an alias or a placeholder; it is never
+    added to an app's environment on its own.
+
+    \b
+    Examples:
+      superdeploy org:set DOCKER_TOKEN=dckr_pat_xxx
+      superdeploy cheapa:config:set DOCKER_TOKEN='{{ org.DOCKER_TOKEN }}'
+    """
+    cmd = OrgSetCommand(
+        key_value,
+        environment=environment,
+        description=description,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="org:unset")
+@click.argument("key")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def org_unset(key, environment, verbose, json_output):
+    """
+    Remove an organization-level secret
+
+    \b
+    Example:
+      superdeploy org:unset DOCKER_TOKEN
+    """
+    cmd = OrgUnsetCommand(
+        key, environment=environment, verbose=verbose, json_output=json_output
+    )
+    cmd.run()
+
+
+@click.command(name="org:list")
+@click.option(
+    "-e",
+    "--env",
+    "environment",
+    default="production",
+    help="Environment (production/staging)",
+)
+@click.option("--show-values", is_flag=True, help="Print secret values")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def org_list(environment, show_values, verbose, json_output):
+    """
+    List organization-level secrets and the projects using them
+
+    \b
+    Example:
+      superdeploy org:list
+    """
+    cmd = OrgListCommand(
+        environment=environment,
+        show_values=show_values,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
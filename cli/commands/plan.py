@@ -215,6 +215,37 @@ class PlanCommand(ProjectCommand):
 
         return changes
 
+    def _display_addon_graph(self, config: dict) -> None:
+        """Show addon deploy order (dependency graph) or why it cannot be built."""
+        from cli.core.addon_graph import AddonGraph, AddonGraphError
+        from cli.core.addon_loader import (
+            AddonLoader,
+            AddonNotFoundError,
+            CircularDependencyError,
+        )
+        from cli.ui_components import addon_dependency_tree
+
+        try:
+            graph = AddonGraph.from_project_config(
+                AddonLoader(self.project_root / "addons"), config.get("addons", {})
+            )
+        except (AddonGraphError, AddonNotFoundError, CircularDependencyError) as e:
+            self.console.print(
+                "  [red]✗ Addon dependencies not satisfied (up will fail):[/red]"
+            )
+            for error in getattr(e, "errors", [str(e)]):
+                self.console.print(f"    [red]•[/red] {error}")
+            self.console.print()
+            return
+
+        if not graph.nodes:
+            return
+
+        self.console.print(addon_dependency_tree(graph, title="Deploy order"))
+        for warning in graph.warnings:
+            self.console.print(f"  [yellow]⚠[/yellow] [dim]{warning}[/dim]")
+        self.console.print()
+
     def _vm_changed(self, config_vm: dict, state_vm: dict) -> bool:
         """Check if VM configuration changed."""
         keys_to_compare = ["machine_type", "disk_size", "services"]
@@ -378,6 +409,7 @@ class PlanCommand(ProjectCommand):
                 )
 
             self.console.print()
+            self._display_addon_graph(config)
 
         # Apps
         if (
@@ -0,0 +1,253 @@
+"""SuperDeploy CLI - Deletion protection and soft delete
+
+Protected projects and addons can't be taken down (`down`, dashboard
+teardown/delete) until the flag is lifted with a reason; every change is
+audited in the activity log. Projects deleted with `down --destroy` or from
+the dashboard stay restorable with projects:undelete for SOFT_DELETE_DAYS.
+"""
+
+import click
+from rich.table import Table
+from cli.base import BaseCommand, ProjectCommand
+
+
+class ProtectCommand(ProjectCommand):
+    """Set or lift deletion protection."""
+
+    def __init__(
+        self,
+        project_name: str,
+        enabled: bool,
+        addon: str = None,
+        reason: str = None,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(project_name, verbose=verbose, json_output=json_output)
+        self.enabled = enabled
+        self.addon = addon
+        self.reason = reason
+
+    def execute(self) -> None:
+        """Execute protect/unprotect command."""
+        from cli.services.protection_service import ProtectionService
+
+        target = self.project_name
+        if self.addon:
+            target += f":{self.addon}"
+        try:
+            changed = ProtectionService().set(
+                self.project_name, self.enabled, addon=self.addon, reason=self.reason
+            )
+        except ValueError as e:
+            self._fail(str(e))
+
+        if self.json_output:
+            self.output_json(
+                {
+                    "target": target,
+                    "deletion_protection": self.enabled,
+                    "changed": changed,
+                }
+            )
+            return
+
+        state = "on" if self.enabled else "off"
+        if changed:
+            self.print_success(f"Deletion protection {state} for {target}")
+        else:
+            self.print_dim(f"Deletion protection already {state} for {target}")
+
+    def _fail(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
+
+class ProtectionStatusCommand(ProjectCommand):
+    """Show deletion protection of a project and its addons."""
+
+    def execute(self) -> None:
+        """Execute protection command."""
+        from cli.services.protection_service import ProtectionService
+
+        status = ProtectionService().status(self.project_name)
+        if self.json_output:
+            self.output_json(status)
+            return
+
+        table = Table(title=f"Deletion protection: {self.project_name}")
+        table.add_column("Target", style="cyan")
+        table.add_column("Protected")
+        rows = [("project", status["project"])] + [
+            (f"addon {name}", protected)
+            for name, protected in status["addons"].items()
+        ]
+        for name, protected in rows:
+            table.add_row(name, "[green]yes[/green]" if protected else "[dim]no[/dim]")
+        self.console.print(table)
+
+
+class ProjectsDeletedCommand(BaseCommand):
+    """List deleted projects that can still be restored."""
+
+    def execute(self) -> None:
+        """Execute projects:deleted command."""
+        from cli.services.trash_service import TrashService, retention_days
+
+        entries = TrashService().list()
+        if self.json_output:
+            self.output_json({"deleted": entries, "retention_days": retention_days()})
+            return
+
+        if not entries:
+            self.print_dim("No deleted projects")
+            return
+
+        table = Table(title=f"Deleted projects (kept {retention_days()} days)")
+        table.add_column("Project", style="cyan")
+        table.add_column("Deleted")
+        table.add_column("By")
+        table.add_column("Restorable until")
+        table.add_column("Final backup", style="dim")
+        for entry in entries:
+            table.add_row(
+                entry["project"],
+                (entry["deleted_at"] or "")[:16],
+                entry["deleted_by"] or "",
+                entry["purge_after"][:16],
+                entry["final_backup"] or "-",
+            )
+        self.console.print(table)
+
+
+class ProjectsUndeleteCommand(BaseCommand):
+    """Restore a deleted project's database records."""
+
+    def __init__(self, name: str, verbose: bool = False, json_output: bool = False):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.name = name
+
+    def execute(self) -> None:
+        """Execute projects:undelete command."""
+        from cli.services.trash_service import TrashService
+
+        try:
+            entry = TrashService().undelete(self.name)
+        except ValueError as e:
+            if self.json_output:
+                self.output_json_error(str(e))
+            self.exit_with_error(str(e))
+
+        if self.json_output:
+            self.output_json(entry)
+            return
+
+        records = ", ".join(
+            f"{count} {table}" for table, count in entry["records"].items()
+        )
+        self.print_success(f"Restored {self.name} ({records})")
+        self.console.print("\n[bold]Next steps:[/bold]")
+        self.console.print(f"  1. [cyan]superdeploy {self.name}:up[/cyan]")
+        if entry["final_backup"]:
+            self.console.print(
+                f"  2. Load the final backup: [cyan]{entry['final_backup']}[/cyan]"
+            )
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="protect")
+@click.option("--addon", help="Protect one addon (<category>.<instance>)")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def protect(project, addon, verbose, json_output):
+    """
+    Turn on deletion protection
+
+    \b
+    Examples:
+      superdeploy cheapa:protect
+      superdeploy cheapa:protect --addon databases.primary
+    """
+    cmd = ProtectCommand(
+        project, True, addon=addon, verbose=verbose, json_output=json_output
+    )
+    cmd.run()
+
+
+@click.command(name="unprotect")
+@click.option("--addon", help="Unprotect one addon (<category>.<instance>)")
+@click.option("--reason", required=True, help="Why (recorded in the activity log)")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def unprotect(project, addon, reason, verbose, json_output):
+    """
+    Lift deletion protection
+
+    \b
+    Examples:
+      superdeploy cheapa:unprotect --reason "decommissioning, TICKET-123"
+      superdeploy cheapa:unprotect --addon databases.primary --reason "rebuild"
+    """
+    cmd = ProtectCommand(
+        project,
+        False,
+        addon=addon,
+        reason=reason,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
+
+
+@click.command(name="protection")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def protection(project, verbose, json_output):
+    """
+    Show deletion protection of a project and its addons
+
+    \b
+    Example:
+      superdeploy cheapa:protection
+    """
+    cmd = ProtectionStatusCommand(project, verbose=verbose, json_output=json_output)
+    cmd.run()
+
+
+@click.command(name="projects:deleted")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def projects_deleted(verbose, json_output, project=None):
+    """
+    List deleted projects that projects:undelete can restore
+
+    \b
+    Example:
+      superdeploy projects:deleted
+    """
+    cmd = ProjectsDeletedCommand(verbose=verbose, json_output=json_output)
+    cmd.run()
+
+
+@click.command(name="projects:undelete")
+@click.argument("name")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def projects_undelete(name, verbose, json_output, project=None):
+    """
+    Restore a deleted project's records (apps, addons, secrets, ...)
+
+    Only the database records come back; run `up` to recreate the
+    infrastructure and load the final backup `down` took.
+
+    \b
+    Example:
+      superdeploy projects:undelete cheapa
+    """
+    cmd = ProjectsUndeleteCommand(name, verbose=verbose, json_output=json_output)
+    cmd.run()
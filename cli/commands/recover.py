@@ -0,0 +1,349 @@
+"""SuperDeploy CLI - Disaster recovery into another region
+
+Rebuilds a project from its DB definition in a new region or zone: the
+lost infrastructure's Terraform state is released, `up` recreates VMs and
+addons, every addon is loaded from the backup, apps are pinned to the image
+digest the backup recorded and DNS is pointed at the new IPs. Each step is
+timed into a recovery report for DR drills.
+"""
+
+import click
+import json
+import subprocess
+from datetime import datetime
+from pathlib import Path
+from rich.table import Table
+from cli.base import BaseCommand
+
+STATUS_STYLES = {
+    "ok": ("✓", "green"),
+    "failed": ("✗", "red"),
+    "skipped": ("-", "dim"),
+    "pending": ("!", "yellow"),
+}
+
+
+class ProjectsRecoverCommand(BaseCommand):
+    """Recover a project from a backup into another region/zone."""
+
+    def __init__(
+        self,
+        name: str,
+        backup: str = "latest",
+        region: str = None,
+        zone: str = None,
+        dns_zone: str = None,
+        report_path: str = None,
+        yes: bool = False,
+        verbose: bool = False,
+        json_output: bool = False,
+    ):
+        super().__init__(verbose=verbose, json_output=json_output)
+        self.name = name
+        self.backup = backup
+        self.region = region
+        self.zone = zone or f"{region}-a"
+        self.dns_zone = dns_zone
+        self.report_path = report_path
+        self.yes = yes
+
+    def execute(self) -> None:
+        """Execute projects:recover command."""
+        from cli.services.protection_service import ProtectionService, ProtectedError
+        from cli.services.recovery_service import (
+            RecoveryReport,
+            RecoveryService,
+            RecoveryStep,
+        )
+
+        recovery = RecoveryService()
+        try:
+            backup_path = recovery.find_backup(self.name, self.backup)
+        except ValueError as e:
+            self._fail(str(e))
+        manifest = recovery.load_manifest(backup_path)
+
+        try:
+            targets = recovery.app_targets(self.name)
+        except AttributeError:
+            self._fail(
+                f"Project '{self.name}' not found\n"
+                f"Deleted? Restore its records first: "
+                f"superdeploy projects:undelete {self.name}"
+            )
+        try:
+            ProtectionService().check(self.name)
+        except ProtectedError as e:
+            self._fail(str(e))
+
+        if not self.json_output:
+            self.show_header(
+                title="Recover Project",
+                project=self.name,
+                details={
+                    "Backup": f"{backup_path} ({manifest['backup_date'][:16]})",
+                    "Region": f"{self.region} / {self.zone}",
+                    "Addons": ", ".join(a["addon"] for a in manifest["addons"])
+                    or "none",
+                    "Releases": ", ".join(manifest.get("releases", {})) or "none",
+                },
+                border_color="red",
+            )
+        if not self.yes:
+            if self.json_output:
+                self._fail("Recovery needs --yes in JSON mode")
+            self.print_warning(
+                f"{self.name}'s current infrastructure is released and rebuilt in "
+                f"{self.zone}; its databases are replaced with the backup"
+            )
+            if not self.confirm("Start recovery?"):
+                self.print_dim("Recovery cancelled")
+                return
+
+        report = RecoveryReport(
+            project=self.name,
+            backup=str(backup_path),
+            backup_date=manifest["backup_date"],
+            region=self.region,
+            zone=self.zone,
+        )
+        try:
+            self._recover(recovery, report, backup_path, manifest, targets)
+        except Exception as e:
+            # A failed step is already in the report; later steps depend on it
+            if report.ok:
+                report.steps.append(RecoveryStep("recover", "failed", detail=str(e)))
+        report.finished_at = datetime.now()
+
+        report_file = Path(
+            self.report_path
+            or f"./backups/{self.name}/recovery_"
+            f"{report.started_at.strftime('%Y%m%d_%H%M%S')}.json"
+        )
+        report_file.parent.mkdir(parents=True, exist_ok=True)
+        report_file.write_text(json.dumps(report.to_dict(), indent=2))
+
+        if self.json_output:
+            self.output_json(
+                {**report.to_dict(), "report": str(report_file)},
+                exit_code=0 if report.ok else 1,
+            )
+            return
+        self.print_report(report, report_file)
+        if not report.ok:
+            raise SystemExit(1)
+
+    def _recover(self, recovery, report, backup_path, manifest, targets) -> None:
+        """Run the recovery steps, each timed into the report"""
+        from cli.commands.backup import ProjectBackupWriter
+        from cli.commands.down import TerraformCleaner
+        from cli.services.state_service import StateService
+        from cli.services.vm_service import VMService
+        from cli.sync import clear_project_state
+
+        with report.step(f"move to {self.zone}") as step:
+            report.previous_region, report.previous_zone = recovery.set_location(
+                self.name, self.region, self.zone
+            )
+            step.detail = f"was {report.previous_zone or 'unset'}"
+        self._progress(report)
+
+        with report.step("release old infrastructure"):
+            # Best effort: resources in a lost region can't be destroyed
+            TerraformCleaner(self.name, self.project_root, self.console).cleanup()
+            clear_project_state(self.name)
+        self._progress(report)
+
+        with report.step("up"):
+            result = subprocess.run(
+                ["superdeploy", f"{self.name}:up"],
+                cwd=self.project_root,
+                capture_output=self.json_output or not self.verbose,
+                text=True,
+            )
+            if result.returncode != 0:
+                raise RuntimeError(
+                    f"up failed (exit {result.returncode}): "
+                    f"{(result.stdout or '').strip()[-500:]}"
+                )
+        self._progress(report)
+
+        state = StateService(self.project_root, self.name)
+        ssh = VMService(self.project_root, self.name).get_ssh_service()
+        addons = {
+            addon["addon"]: addon
+            for addon in ProjectBackupWriter(self.name, ssh, state).stateful_addons()
+        }
+        for entry in manifest["addons"]:
+            try:
+                with report.step(f"restore {entry['addon']}") as step:
+                    addon = addons.get(entry["addon"])
+                    if not addon or addon["type"] != entry["type"]:
+                        raise RuntimeError(
+                            f"{self.name} has no {entry['type']} addon "
+                            f"{entry['addon']}"
+                        )
+                    command, data = recovery.restore_command(
+                        entry,
+                        backup_path,
+                        manifest["project"],
+                        self.name,
+                        addon["config"],
+                    )
+                    vm_ip = state.get_vm_ip_by_role(addon["vm"], index=0)
+                    result = ssh.execute_command(
+                        vm_ip, command, timeout=3600, input_data=data
+                    )
+                    if result.returncode != 0:
+                        raise RuntimeError(result.stderr.strip()[-500:])
+                    step.detail = entry["file"]
+            except RuntimeError:
+                pass
+            self._progress(report)
+
+        releases = manifest.get("releases", {})
+        for app_name, target in targets.items():
+            try:
+                with report.step(f"deploy {app_name}") as step:
+                    release = releases.get(app_name)
+                    if not release or not target["processes"]:
+                        step.status = "skipped"
+                        step.detail = "no release recorded, deploy with git push"
+                        continue
+                    script = recovery.redeploy_script(
+                        self.name, app_name, target["processes"], release
+                    )
+                    vm_ip = state.get_vm_ip_by_role(target["vm"], index=0)
+                    result = ssh.execute_command(
+                        vm_ip, "bash -s", timeout=900, input_data=script
+                    )
+                    if result.returncode != 0:
+                        raise RuntimeError(result.stderr.strip()[-500:])
+                    step.detail = release["image"].split("@")[-1][:19]
+                    if release.get("version"):
+                        step.detail = f"v{release['version']} {step.detail}"
+            except RuntimeError:
+                pass
+            self._progress(report)
+
+        for app_name, target in targets.items():
+            if not target["domain"]:
+                continue
+            try:
+                with report.step(f"dns {target['domain']}") as step:
+                    vm_ip = state.get_vm_ip_by_role(target["vm"], index=0)
+                    step.status, step.detail = recovery.update_dns(
+                        target["domain"], vm_ip, self.dns_zone
+                    )
+            except RuntimeError:
+                pass
+            self._progress(report)
+
+    def _progress(self, report) -> None:
+        """Print the step that just finished"""
+        if self.json_output:
+            return
+        step = report.steps[-1]
+        marker, color = STATUS_STYLES[step.status]
+        detail = f" [dim]{step.detail}[/dim]" if step.detail else ""
+        self.console.print(
+            f"[{color}]{marker}[/{color}] {step.name} "
+            f"[dim]({step.seconds}s)[/dim]{detail}"
+        )
+
+    def print_report(self, report, report_file: Path) -> None:
+        """Timed summary (RTO = total, RPO = age of the restored data)"""
+        table = Table(title=f"Recovery report: {report.project}")
+        table.add_column("Step", style="cyan")
+        table.add_column("Status")
+        table.add_column("Seconds", justify="right")
+        table.add_column("Detail", style="dim")
+        for step in report.steps:
+            marker, color = STATUS_STYLES[step.status]
+            table.add_row(
+                step.name,
+                f"[{color}]{marker} {step.status}[/{color}]",
+                f"{step.seconds}",
+                step.detail,
+            )
+        self.console.print()
+        self.console.print(table)
+        self.console.print(f"Total (RTO): [bold]{report.total_seconds}s[/bold]")
+        if report.data_age_seconds is not None:
+            self.console.print(
+                f"Data age at start (RPO): [bold]{report.data_age_seconds}s[/bold]"
+            )
+        self.console.print(f"[dim]Report: {report_file}[/dim]")
+
+    def _fail(self, message: str) -> None:
+        if self.json_output:
+            self.output_json_error(message)
+        self.exit_with_error(message)
+
+
+# ============================================================================
+# Click Command Wrappers
+# ============================================================================
+
+
+@click.command(name="projects:recover")
+@click.argument("name")
+@click.option(
+    "--from-backup",
+    "backup",
+    default="latest",
+    help="Backup id (directory in ./backups/<project>/), path, or 'latest'",
+)
+@click.option("--region", required=True, help="GCP region to rebuild in")
+@click.option("--zone", help="GCP zone (default: <region>-a)")
+@click.option("--dns-zone", help="Cloud DNS managed zone to update A records in")
+@click.option("--report", "report_path", help="Report file (default: ./backups/)")
+@click.option("--yes", "-y", is_flag=True, help="Skip confirmation prompt")
+@click.option("--verbose", "-v", is_flag=True, help="Show all command output")
+@click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
+def projects_recover(
+    name,
+    backup,
+    region,
+    zone,
+    dns_zone,
+    report_path,
+    yes,
+    verbose,
+    json_output,
+    project=None,
+):
+    """
+    Recover a project into another region from a backup
+
+    \b
+    1. Point the project at --region/--zone (DB definition)
+    2. Release the old Terraform state, run up
+    3. Load every addon from the backup
+    4. Redeploy apps at the image digest the backup recorded
+    5. Update DNS (Cloud DNS with --dns-zone, otherwise checked)
+
+    Backups come from backups:create or the final backup `down` takes.
+    A timed report (RTO/RPO) is written next to the backups.
+
+    \b
+    Examples:
+      superdeploy projects:recover cheapa --region europe-west1 --yes
+      superdeploy projects:recover cheapa --from-backup cheapa_20261016_120000 \\
+          --region us-east1 --zone us-east1-c --dns-zone cheapa-io
+      # DR drill: a clone restored from production's latest backup
+      superdeploy projects:recover cheapa-drill --region europe-west4 \\
+          --from-backup ./backups/cheapa/cheapa_20261016_120000
+    """
+    cmd = ProjectsRecoverCommand(
+        name,
+        backup=backup,
+        region=region,
+        zone=zone,
+        dns_zone=dns_zone,
+        report_path=report_path,
+        yes=yes,
+        verbose=verbose,
+        json_output=json_output,
+    )
+    cmd.run()
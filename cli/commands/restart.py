@@ -8,7 +8,9 @@ import click
 from dataclasses import dataclass
 
 from cli.base import ProjectCommand
+from cli.constants import DEPLOY_QUEUE_WAIT_SECONDS
 from cli.exceptions import DeploymentError
+from cli.services.deploy_queue_service import queued_script
 
 
 @dataclass
@@ -78,6 +80,10 @@ class RestartCommand(ProjectCommand):
         vm_service = self.ensure_vm_service()
         ssh_service = vm_service.get_ssh_service()
 
+        # Secret files live on tmpfs; after a VM reboot they must be written
+        # again or the containers can't start
+        self._restore_secret_files(logger)
+
         if logger:
             logger.step("Restarting Container")
 
@@ -86,11 +92,22 @@ class RestartCommand(ProjectCommand):
         compose_dir = f"/opt/superdeploy/projects/{self.project_name}/compose"
 
         try:
-            # Restart using docker compose
+            # Restart using docker compose, holding the VM's deploy slot
+            restart_script = queued_script(
+                f"cd {compose_dir} && docker compose restart {service_name}",
+                self.project_name,
+                self.options.app_name,
+                source="restart",
+            )
             result = ssh_service.execute_command(
-                vm_ip, f"cd {compose_dir} && docker compose restart {service_name}"
+                vm_ip, restart_script, timeout=DEPLOY_QUEUE_WAIT_SECONDS + 60
             )
 
+            if result.returncode == 75:
+                raise DeploymentError(
+                    "Timed out waiting for the deploy queue",
+                    context=f"See: superdeploy {self.project_name}:deploys:queue",
+                )
             if result.is_failure:
                 raise DeploymentError(
                     f"Failed to restart service: {service_name}",
@@ -125,6 +142,25 @@ class RestartCommand(ProjectCommand):
             self.handle_error(e, "Failed to restart container")
             raise SystemExit(1)
 
+    def _restore_secret_files(self, logger) -> None:
+        """Push the app's secret files again when the VM lost them."""
+        from cli.services.app_env_service import AppEnvService
+
+        service = AppEnvService(self.project_root, self.project_name)
+        try:
+            missing = service.restore_files(self.options.app_name)
+        except Exception as e:
+            raise DeploymentError(
+                "Secret files are missing on the VM and could not be restored",
+                context=f"{e}\nRetry: superdeploy {self.project_name}:config:push "
+                f"-a {self.options.app_name}",
+            )
+        if missing and logger:
+            logger.log(
+                f"✓ Restored {len(missing)} secret file(s) lost from tmpfs "
+                "(VM rebooted?)"
+            )
+
     def _print_summary(self, logger) -> None:
         """Print restart summary."""
         if not self.verbose:
@@ -29,6 +29,23 @@ class SwitchCommand(ProjectCommand):
             details={"Target Git SHA": self.git_sha[:7] if self.git_sha else "latest"},
         )
 
+        # Freeze windows and production approvals (deploys:policy)
+        from cli.services.deploy_policy_service import (
+            DeployBlockedError,
+            DeployPolicyService,
+        )
+
+        try:
+            decision = DeployPolicyService().check(
+                self.project_name, f"{self.app_name}@{self.git_sha}"
+            )
+        except DeployBlockedError as e:
+            self.exit_with_error(str(e))
+        if decision["override"]:
+            self.print_warning(
+                f"Deploy policy overridden: {decision['override']['reason']}"
+            )
+
         # Initialize logger
         logger = self.init_logger(self.project_name, f"switch-{self.app_name}")
 
@@ -71,6 +88,9 @@ class SwitchCommand(ProjectCommand):
                 self.console.print("[yellow]⏹️  Switch cancelled[/yellow]")
                 return
 
+        if not self._run_hooks("before_deploy", logger):
+            raise SystemExit(1)
+
         self.console.print("\n[cyan]🔄 Starting zero-downtime switch...[/cyan]")
 
         # Zero-downtime switch script with health check and automatic rollback
@@ -179,6 +199,12 @@ PROJECT_DIR="/opt/superdeploy/projects/{self.project_name}"
 RELEASES_FILE="$PROJECT_DIR/releases.json"
 DEPLOYED_AT=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
 
+# Switching back to an earlier release runs on_rollback hooks
+if [ -f "$RELEASES_FILE" ] && jq -e --arg app "{self.app_name}" --arg sha "{self.git_sha}" \
+    '(.[$app] // [])[:-1] | any((.git_sha // "") | startswith($sha))' "$RELEASES_FILE" > /dev/null; then
+    echo "SWITCH_ROLLBACK"
+fi
+
 # Get current version from releases.json (last release)
 CURRENT_VERSION="0.0.0"
 if [ -f "$RELEASES_FILE" ]; then
@@ -213,9 +239,21 @@ echo "📝 Version tracking updated (releases.json)"
 docker image prune -f > /dev/null 2>&1
 """
 
+        # Wait for this VM's deploy slot (deploys:queue)
+        from cli.constants import DEPLOY_QUEUE_WAIT_SECONDS
+        from cli.services.deploy_queue_service import queued_script
+
+        switch_script = queued_script(
+            switch_script,
+            self.project_name,
+            self.app_name,
+            source="switch",
+            ref=self.git_sha,
+        )
+
         try:
             result = ssh_service.execute_command(
-                vm_ip, switch_script, check=False, timeout=120
+                vm_ip, switch_script, timeout=DEPLOY_QUEUE_WAIT_SECONDS + 120
             )
 
             if "SWITCH_SUCCESS" in result.stdout:
@@ -233,6 +271,15 @@ docker image prune -f > /dev/null 2>&1
                 self.console.print("[dim]  → New container started[/dim]")
                 self.console.print("[dim]  → Health check passed[/dim]\n")
 
+                hooks_ok = self._run_hooks("after_deploy", logger)
+                if "SWITCH_ROLLBACK" in result.stdout:
+                    hooks_ok = (
+                        self._run_hooks("on_rollback", logger, reason="switch")
+                        and hooks_ok
+                    )
+                if not hooks_ok:
+                    raise SystemExit(1)
+
                 # Show verification command
                 self.console.print("[bold]Verify deployment:[/bold]")
                 self.console.print(
@@ -255,9 +302,20 @@ docker image prune -f > /dev/null 2>&1
                 )
                 self.console.print("\n[dim]Check logs for details:[/dim]")
                 self.console.print(f"[dim]{result.stdout}[/dim]\n")
+                self._run_hooks("on_rollback", logger, reason="health_check")
                 if not self.verbose:
                     self.console.print(f"[dim]Logs saved to:[/dim] {logger.log_path}\n")
                 raise SystemExit(1)
+            elif result.returncode == 75:
+                if logger:
+                    logger.log_error("Timed out waiting for the deploy queue")
+                self.console.print(
+                    "\n[red]❌ Timed out waiting for the deploy queue[/red]"
+                )
+                self.console.print(
+                    f"[dim]See: superdeploy {self.project_name}:deploys:queue[/dim]\n"
+                )
+                raise SystemExit(1)
             else:
                 if logger:
                     logger.log_error("Switch failed")
@@ -277,6 +335,18 @@ docker image prune -f > /dev/null 2>&1
             raise SystemExit(1)
 
 
+    def _run_hooks(self, event: str, logger, **context) -> bool:
+        """Run the app's lifecycle hooks for this switch"""
+        return self.run_hooks(
+            event,
+            [self.app_name],
+            logger,
+            ref=self.git_sha,
+            source="switch",
+            **context,
+        )
+
+
 @click.command(name="releases:switch")
 @click.option("-a", "--app", required=True, help="App name (api, services, storefront)")
 @click.option(
@@ -249,6 +249,8 @@ class SyncExportCommand(ProjectCommand):
                     "project_type": project.project_type,
                     "domain": project.domain,
                     "ssl_email": project.ssl_email,
+                    "environment": project.environment,
+                    "deletion_protection": bool(project.deletion_protection),
                     "github_org": project.github_org,
                     "gcp_project": project.gcp_project,
                     "gcp_region": project.gcp_region,
@@ -303,6 +305,8 @@ class SyncExportCommand(ProjectCommand):
                         "replicas": app.replicas,
                         "type": app.type,
                         "services": app.services,
+                        "edge": app.edge,
+                        "anonymize": app.anonymize,
                         "processes": processes_data if processes_data else None,
                     }
                 )
@@ -342,6 +346,7 @@ class SyncExportCommand(ProjectCommand):
                         "version": addon.version,
                         "vm": addon.vm,
                         "plan": addon.plan,
+                        "deletion_protection": bool(addon.deletion_protection),
                     }
                 )
 
@@ -589,6 +594,17 @@ class SyncImportCommand:
 
             # If force and exists, delete existing data
             if existing_project and self.force:
+                from cli.services.protection_service import ProtectionService
+
+                blockers = ProtectionService().blockers(self.project_name)
+                if blockers:
+                    self.console.print(
+                        f"[red]✗ Deletion protection is on ({', '.join(blockers)}). "
+                        f"Run: superdeploy {self.project_name}:unprotect "
+                        f"--reason ...[/red]"
+                    )
+                    raise SystemExit(1)
+
                 self.console.print(
                     "[yellow]⚠️  Deleting existing project data...[/yellow]"
                 )
@@ -613,6 +629,8 @@ class SyncImportCommand:
                 project_type=project_data.get("project_type", "application"),
                 domain=project_data.get("domain"),
                 ssl_email=project_data.get("ssl_email"),
+                environment=project_data.get("environment") or "production",
+                deletion_protection=bool(project_data.get("deletion_protection")),
                 github_org=project_data.get("github_org"),
                 gcp_project=project_data.get("gcp_project"),
                 gcp_region=project_data.get("gcp_region"),
@@ -657,6 +675,8 @@ class SyncImportCommand:
                     replicas=app_data.get("replicas") or 1,  # Default 1 if not set
                     type=app_data["type"],  # Required: web/worker/backend/frontend
                     services=app_data.get("services"),  # Optional
+                    edge=app_data.get("edge"),  # Optional - Caddy edge policy
+                    anonymize=app_data.get("anonymize"),  # Optional - addons:copy
                 )
                 db.add(app)
                 db.flush()
@@ -726,6 +746,7 @@ class SyncImportCommand:
                     version=addon_data["version"],  # No fallback - must be explicit
                     vm=addon_data["vm"],  # No fallback - must be explicit
                     plan=addon_data["plan"],  # No fallback - must be explicit
+                    deletion_protection=bool(addon_data.get("deletion_protection")),
                 )
                 db.add(addon)
 
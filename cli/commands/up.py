@@ -60,8 +60,28 @@ class UpCommand(ProjectCommand):
 
     def execute(self) -> None:
         """Execute up command."""
+        if not self.dry_run:
+            self._check_deploy_policy()
         self._execute_deployment()
 
+    def _check_deploy_policy(self) -> None:
+        """Refuse during freeze windows or without approvals (deploys:policy)"""
+        from cli.services.deploy_policy_service import (
+            DeployBlockedError,
+            DeployPolicyService,
+        )
+
+        try:
+            decision = DeployPolicyService().check(self.project_name, "up")
+        except DeployBlockedError as e:
+            if self.json_output:
+                self.output_json_error(str(e))
+            self.exit_with_error(str(e))
+        if decision["override"]:
+            self.print_warning(
+                f"Deploy policy overridden: {decision['override']['reason']}"
+            )
+
     def _ensure_addon_secrets(self, logger) -> None:
         """
         Auto-generate missing addon secrets before deployment.
@@ -591,6 +611,30 @@ def _deploy_project_internal(
             logger.log_error(f"Invalid configuration: {e}")
         raise SystemExit(1)
 
+    # Addon dependency graph (fail before touching VMs if requires/conflicts break)
+    from cli.core.addon_graph import AddonGraph, AddonGraphError
+    from cli.core.addon_loader import (
+        AddonLoader,
+        AddonNotFoundError,
+        CircularDependencyError,
+    )
+
+    try:
+        addon_graph = AddonGraph.from_project_config(
+            AddonLoader(project_root / "addons"), project_config_obj.get_addons()
+        )
+    except (AddonGraphError, AddonNotFoundError, CircularDependencyError) as e:
+        if logger:
+            logger.log_error(
+                f"Addon dependencies not satisfied:\n{e}",
+                context=f"Inspect with: superdeploy {project}:addons:list --tree",
+            )
+        raise SystemExit(1)
+
+    if logger:
+        for warning in addon_graph.warnings:
+            logger.warning(warning)
+
     # Load orchestrator config
     try:
         orchestrator_config = orchestrator_loader.load()
@@ -1041,6 +1085,14 @@ def _deploy_project_internal(
             ansible_vars = project_config_obj.to_ansible_vars()
             ansible_vars["orchestrator_ip"] = orchestrator_ip
 
+            # Deploy jobs ask the orchestrator's deploy gate (over VPC peering)
+            deploy_gate = orchestrator_config.get_deploy_gate_config()
+            ansible_vars["deploy_gate_url"] = (
+                f"http://{orchestrator_internal_ip}:{deploy_gate['port']}"
+                if deploy_gate["enabled"]
+                else ""
+            )
+
             # Load and pass database secrets to Ansible
             from cli.secret_manager import SecretManager
 
@@ -1081,6 +1133,9 @@ def _deploy_project_internal(
 
             ansible_vars["env_aliases"] = env_aliases
 
+            # Deploy order + per-instance dependencies for the addon-deployer health gates
+            ansible_vars.update(addon_graph.to_ansible_vars())
+
             ansible_env_vars = {"superdeploy_root": str(project_root)}
 
             # Add VM IPs
@@ -74,6 +74,7 @@ class ValidateProjectCommand(ProjectCommand):
         self._validate_github(config, warnings)
         self._validate_network(config, warnings)
         errors.extend(self._validate_secrets(warnings))
+        errors.extend(self._validate_config_contracts(config))
 
         return ValidationResult(errors=errors, warnings=warnings)
 
@@ -226,6 +227,38 @@ class ValidateProjectCommand(ProjectCommand):
 
         return errors
 
+    def _validate_config_contracts(self, config: Dict[str, Any]) -> List[str]:
+        """Validate each app's rendered env against its marker config contract."""
+        from cli.exceptions import ConfigurationError, SecretError
+        from cli.services.app_env_service import AppEnvService
+
+        errors = []
+        service = AppEnvService(self.project_root, self.project_name)
+
+        for app_name in config.get("apps") or {}:
+            try:
+                marker = service.marker(app_name)
+                if not marker or not marker.has_config_contract():
+                    continue
+                violations = service.check_contract(app_name)
+            except (ConfigurationError, SecretError) as e:
+                errors.append(f"App '{app_name}' config contract: {e.message}")
+                self.console.print(f"[red]✗[/red] Config contract: {app_name}")
+                continue
+
+            if not violations:
+                self.console.print(
+                    f"[green]✓[/green] Config contract: {app_name} "
+                    f"({len(marker.config.keys)} keys)"
+                )
+                continue
+
+            self.console.print(f"[red]✗[/red] Config contract: {app_name}")
+            for violation in violations:
+                errors.append(f"App '{app_name}': {violation}")
+
+        return errors
+
     def _display_results(self, result: ValidationResult, logger) -> None:
         """Display validation results."""
         config = self.config_service.get_raw_config(self.project_name)
@@ -515,7 +548,8 @@ def validate_addons(project=None, addon=None, fix=False, json_output=False):
     \b
     This command validates:
     - Required files (addon.yml, docker-compose.yml.j2)
-    - Metadata fields (name, description, version, category)
+    - addon.yml and env.yml against cli/core/schemas/*.schema.json
+      (errors are reported as file:line, duplicate keys included)
     - Compose template structure
     - Healthcheck configuration
     - Ansible tasks (anti-patterns)
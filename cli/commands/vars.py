@@ -412,12 +412,16 @@ class VarsSyncCommand(ProjectCommand):
         project_name: str,
         environment: str = "production",
         app: str = None,
+        propagate: bool = True,
+        enforce_contract: bool = True,
         verbose: bool = False,
         json_output: bool = False,
     ):
         super().__init__(project_name, verbose=verbose, json_output=json_output)
         self.environment = environment
         self.app_filter = app
+        self.propagate = propagate
+        self.enforce_contract = enforce_contract
 
     def execute(self) -> None:
         """Execute vars:sync command."""
@@ -433,6 +437,11 @@ class VarsSyncCommand(ProjectCommand):
         from cli.secret_manager import SecretManager
         from cli.marker_manager import MarkerManager
         from cli.database import get_db_session, Project, App
+        from cli.services.app_env_service import (
+            AppEnvService,
+            BUILD_ENV_SECRET,
+            encode_build_env,
+        )
 
         if logger:
             logger.step("Loading project configuration")
@@ -482,7 +491,14 @@ class VarsSyncCommand(ProjectCommand):
             self.console.print("[red]❌ No secrets found in database![/red]")
             return
 
-        all_secrets = secret_mgr.load_secrets()
+        env_service = AppEnvService(
+            self.project_root, self.project_name, self.environment
+        )
+
+        # Expands {{ org.X }} / app. / project. references in shared secrets
+        all_secrets = secret_mgr.load_secrets(resolve=True)
+        if not self.app_filter:
+            secret_mgr.record_dependencies(None)
 
         # Get GitHub organization from config
         github_org = config.get("github", {}).get("organization")
@@ -508,6 +524,9 @@ class VarsSyncCommand(ProjectCommand):
             "[dim]These secrets are shared across all apps in the repo[/dim]\n"
         )
 
+        synced_apps = []
+        contract_failed = []
+
         # Process each app (filter by --app if specified)
         for app_name, app_config in config.get("apps", {}).items():
             # Skip if app filter is set and doesn't match
@@ -619,10 +638,53 @@ class VarsSyncCommand(ProjectCommand):
                 and "." not in k  # Filter out addon internal secrets
             }
 
+            # Build-time config goes to the image build as one secret;
+            # build-only keys never become runtime env secrets
+            usages = env_service.usages(app_name)
+            build_env = {
+                k: v
+                for k, v in env_secret_dict.items()
+                if usages.get(k, "runtime") != "runtime"
+                and k in app_secrets_dict  # same source as config:push compares
+                and v is not None
+            }
+            build_only = [k for k, u in usages.items() if u == "build"]
+            env_secret_dict = {
+                k: v for k, v in env_secret_dict.items() if k not in build_only
+            }
+
+            # Block the sync if the env violates the marker's config contract
+            if self.enforce_contract and marker and marker.has_config_contract():
+                violations = marker.config.validate(env_secret_dict)
+                if violations:
+                    self.console.print(
+                        f"  [red]✗ Config contract violated ({len(violations)}), "
+                        f"skipping {app_name}:[/red]"
+                    )
+                    for violation in violations:
+                        self.console.print(f"    [red]•[/red] {violation}")
+                    self.console.print()
+                    contract_failed.append(app_name)
+                    continue
+                self.console.print(
+                    f"  [dim]📜 Config contract satisfied "
+                    f"({len(marker.config.keys)} keys)[/dim]"
+                )
+
+            # Remember which app./org./project. sources this sync used
+            secret_mgr.record_dependencies(app_name)
+            synced_apps.append(app_name)
+
             # Set each secret individually (for easy management in GitHub UI)
             self.console.print(
                 f"  [dim]Setting {len(env_secret_dict)} individual secrets...[/dim]"
             )
+            if build_env:
+                self.console.print(
+                    f"  [dim]🔨 {len(build_env)} build-time keys → "
+                    f"{BUILD_ENV_SECRET}[/dim]"
+                )
+            env_secret_dict[BUILD_ENV_SECRET] = encode_build_env(build_env)
 
             # Get database session for timestamp tracking
             db = get_db_session()
@@ -640,9 +702,35 @@ class VarsSyncCommand(ProjectCommand):
             finally:
                 db.close()
 
+            # Keys switched to build-only must not reach the runtime .env
+            stale = set(build_only) & set(
+                list_github_env_secrets(repo, self.environment, self.console)
+            )
+            if stale:
+                remove_github_env_secrets(
+                    repo, self.environment, sorted(stale), self.console
+                )
+
             self.console.print()
 
+        if contract_failed:
+            self.console.print(
+                f"\n[red]❌ Not synced (config contract): "
+                f"{', '.join(contract_failed)}[/red]"
+            )
+            self.console.print(
+                "[dim]Set the missing keys with config:set, "
+                "or re-run with --skip-contract[/dim]"
+            )
+            if self.propagate:
+                self._propagate(secret_mgr, synced_apps, logger)
+            raise SystemExit(1)
+
         self.console.print("\n[green]✅ Sync complete![/green]")
+
+        if self.propagate:
+            self._propagate(secret_mgr, synced_apps, logger)
+
         self.console.print("\n[bold]📝 Next steps:[/bold]")
         self.console.print("\n1. Get GitHub runner token:")
         self.console.print(
@@ -653,6 +741,66 @@ class VarsSyncCommand(ProjectCommand):
             f"   [red]GITHUB_RUNNER_TOKEN=<token> superdeploy {self.project_name}:up[/red]"
         )
 
+    def _propagate(self, secret_mgr, synced_apps, logger) -> None:
+        """
+        Re-sync consumers whose referenced secret changed since their last sync.
+
+        Consumers are apps of other projects holding a grant-based reference
+        to this project, apps referencing org secrets, and apps of this
+        project skipped by --app.
+        """
+        import sys
+
+        targets = {}
+        for dep in secret_mgr.get_dependencies(stale_only=True):
+            project, app = dep["project"], dep["app"]
+            if not project:
+                continue
+            if project == self.project_name and (app in synced_apps or app is None):
+                continue
+            # Shared consumer (app None) means the whole project
+            apps = targets.setdefault(project, set())
+            apps.add(app)
+
+        if not targets:
+            return
+
+        self.console.print("\n[bold cyan]🔁 Propagating to consumers[/bold cyan]")
+        for project, apps in sorted(targets.items()):
+            app_args = [[]] if None in apps else [["-a", a] for a in sorted(apps)]
+            for args in app_args:
+                label = f"{project}/{args[1]}" if args else project
+                if logger:
+                    logger.log(f"Re-syncing {label}")
+                try:
+                    result = subprocess.run(
+                        [
+                            sys.executable,
+                            "-m",
+                            "cli.main",
+                            f"{project}:vars:sync",
+                            "-e",
+                            self.environment,
+                            *args,
+                            "--no-propagate",
+                        ],
+                        capture_output=True,
+                        text=True,
+                        timeout=600,
+                    )
+                    ok = result.returncode == 0
+                except subprocess.TimeoutExpired:
+                    ok = False
+
+                if ok:
+                    self.console.print(f"  [green]✓[/green] {label}")
+                else:
+                    self.console.print(f"  [red]✗[/red] {label}")
+                    self.console.print(
+                        f"    [dim]Retry: superdeploy {project}:vars:sync "
+                        f"{' '.join(args)}[/dim]"
+                    )
+
 
 # ============================================================================
 # Click Command Wrappers
@@ -729,15 +877,34 @@ def vars_clear(project, environment, app, keys, verbose, json_output):
     default=None,
     help="Sync only specific app (optional)",
 )
+@click.option(
+    "--no-propagate",
+    is_flag=True,
+    help="Don't re-sync apps/projects that reference changed secrets",
+)
+@click.option(
+    "--skip-contract",
+    is_flag=True,
+    help="Sync even if an app's env violates its marker config contract",
+)
 @click.option("--verbose", "-v", is_flag=True, help="Show all command output")
 @click.option("--json", "json_output", is_flag=True, help="Output in JSON format")
-def vars_sync(project, environment, app, verbose, json_output):
+def vars_sync(
+    project, environment, app, no_propagate, skip_contract, verbose, json_output
+):
     """
     Sync secrets to GitHub
 
     - Repository secrets (Docker credentials, shared build secrets)
     - Environment secrets (per-app secrets for production/staging)
     - Resolves env_templates from marker files ({{ APP_0_EXTERNAL_IP }} etc.)
+    - Resolves app./org./project. references and re-syncs their consumers
+      when the source changed (disable with --no-propagate)
+    - Skips apps whose env violates the config contract in their marker
+      and exits non-zero (override with --skip-contract)
+    - Build-time keys (config:set --usage build/both, marker build_env) go
+      to the image build as SUPERDEPLOY_BUILD_ENV; build-only keys are
+      removed from the environment secrets
 
     Requirements:
     - gh CLI installed and authenticated
@@ -760,6 +927,8 @@ def vars_sync(project, environment, app, verbose, json_output):
         project,
         environment=environment,
         app=app,
+        propagate=not no_propagate,
+        enforce_contract=not skip_contract,
         verbose=verbose,
         json_output=json_output,
     )
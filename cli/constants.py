@@ -55,6 +55,33 @@ TERRAFORM_WORKSPACE_DIR = "terraform.tfstate.d"
 ANSIBLE_PYTHON_INTERPRETER = "/usr/bin/python3"
 ANSIBLE_BECOME_METHOD = "sudo"
 
+# Project Deletion
+# Days projects:undelete can restore a deleted project
+# (SUPERDEPLOY_SOFT_DELETE_DAYS overrides)
+SOFT_DELETE_DAYS = 7
+
+# Control Plane Backups (orchestrator:backup)
+# Encryption passphrase is read from this environment variable
+CONTROL_PLANE_BACKUP_PASSPHRASE_ENV = "SUPERDEPLOY_BACKUP_PASSPHRASE"
+CONTROL_PLANE_BACKUP_DIR = "./backups/orchestrator"
+CONTROL_PLANE_BACKUP_KEEP = 14
+
+# Local Development (<project>:dev)
+# Rendered addons, generated credentials and app env per project
+DEV_ENV_DIR = "./dev"
+
+# Deploy Policies (<project>:deploys:*)
+# How long an approval stays valid, and the longest emergency override
+DEPLOY_APPROVAL_HOURS = 24
+DEPLOY_OVERRIDE_MAX_MINUTES = 240
+# Orchestrator service the self-hosted deploy jobs ask before deploying
+DEPLOY_GATE_PORT = 8585
+
+# Deploy Queue (one deploy at a time per app VM)
+# How long a deploy waits for its turn, and how long it may hold the VM
+DEPLOY_QUEUE_WAIT_SECONDS = 1800
+DEPLOY_QUEUE_LEASE_SECONDS = 900
+
 # Log Configuration
 LOG_DATE_FORMAT = "%Y-%m-%d"
 LOG_DATETIME_FORMAT = "%Y-%m-%d %H:%M:%S"
@@ -7,6 +7,8 @@ from .addon_loader import (
     AddonValidationError,
     CircularDependencyError,
 )
+from .addon_graph import AddonGraph, AddonGraphError, AddonNode
+from .addon_requirements import AddonRequirement
 from .template_merger import TemplateMerger
 from .validator import ValidationEngine, ValidationError, ValidationException
 from .config_loader import (
@@ -24,6 +26,10 @@ __all__ = [
     "AddonNotFoundError",
     "AddonValidationError",
     "CircularDependencyError",
+    "AddonGraph",
+    "AddonGraphError",
+    "AddonNode",
+    "AddonRequirement",
     "TemplateMerger",
     "ValidationEngine",
     "ValidationError",
@@ -6,6 +6,8 @@ from typing import Dict, List
 import yaml
 from jinja2 import Template
 
+from .addon_requirements import AddonRequirement
+
 
 @dataclass
 class Addon:
@@ -119,18 +121,36 @@ class Addon:
         Get list of addon dependencies.
 
         Returns:
-            List of addon names that this addon requires
+            List of addon names that this addon requires (constraints stripped)
         """
-        return self.metadata.get("requires", [])
+        return [req.name for req in self.get_requirements()]
 
     def get_conflicts(self) -> List[str]:
         """
         Get list of conflicting addons.
 
         Returns:
-            List of addon names that conflict with this addon
+            List of addon names that conflict with this addon (constraints stripped)
+        """
+        return [req.name for req in self.get_conflict_requirements()]
+
+    def get_requirements(self) -> List[AddonRequirement]:
+        """
+        Get parsed requires entries.
+
+        Returns:
+            List of requirements, e.g. postgres>=14
+        """
+        return [AddonRequirement.parse(r) for r in self.metadata.get("requires", [])]
+
+    def get_conflict_requirements(self) -> List[AddonRequirement]:
+        """
+        Get parsed conflicts entries.
+
+        Returns:
+            List of requirements; unconstrained entries conflict with any version
         """
-        return self.metadata.get("conflicts", [])
+        return [AddonRequirement.parse(c) for c in self.metadata.get("conflicts", [])]
 
     def get_version(self) -> str:
         """
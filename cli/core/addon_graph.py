@@ -0,0 +1,234 @@
+"""Instance-level addon dependency graph (deploy order and health gates)"""
+
+from dataclasses import dataclass, field
+from typing import Any, Dict, List
+
+from .addon_loader import AddonLoader, CircularDependencyError
+from .addon_requirements import AddonRequirement, version_key
+
+
+class AddonGraphError(Exception):
+    """Raised when instance dependencies or conflicts cannot be satisfied"""
+
+    def __init__(self, errors: List[str]):
+        self.errors = errors
+        super().__init__("\n".join(errors))
+
+
+@dataclass
+class AddonNode:
+    """One addon instance in the graph"""
+
+    category: str
+    name: str
+    type: str
+    version: str
+    plan: str = "standard"
+    vm: str = "core"
+    depends_on: List[str] = field(default_factory=list)
+
+    @property
+    def full_name(self) -> str:
+        return f"{self.category}.{self.name}"
+
+
+class AddonGraph:
+    """
+    Dependency graph between addon instances.
+
+    Type-level requires/conflicts from addon.yml are resolved against the
+    concrete instances of a project (databases.primary, caches.session, ...),
+    so constraints are checked against each instance's configured version.
+    """
+
+    def __init__(self, nodes: Dict[str, AddonNode], warnings: List[str] = None):
+        self.nodes = nodes
+        self.warnings = warnings or []
+
+    @classmethod
+    def from_project_config(
+        cls, loader: AddonLoader, addons_config: Dict[str, Any]
+    ) -> "AddonGraph":
+        """
+        Build the graph from the nested addons config.
+
+        Args:
+            loader: AddonLoader for the addons directory
+            addons_config: {category: {instance: {type, version, plan, vm}}}
+
+        Raises:
+            AddonGraphError: If a requirement or conflict is violated
+            CircularDependencyError: If instances depend on each other in a cycle
+        """
+        nodes: Dict[str, AddonNode] = {}
+        for category, instances in (addons_config or {}).items():
+            if not isinstance(instances, dict):
+                continue
+            for instance_name, instance_config in instances.items():
+                if not isinstance(instance_config, dict):
+                    continue
+                if not instance_config.get("type"):
+                    continue
+                node = AddonNode(
+                    category=category,
+                    name=instance_name,
+                    type=instance_config["type"],
+                    version=str(instance_config.get("version") or "latest"),
+                    plan=instance_config.get("plan") or "standard",
+                    vm=instance_config.get("vm") or "core",
+                )
+                nodes[node.full_name] = node
+
+        errors: List[str] = []
+        warnings: List[str] = []
+
+        for node in nodes.values():
+            addon = loader.load_addon(node.type)
+
+            for requirement in addon.get_requirements():
+                candidates = [n for n in nodes.values() if n.type == requirement.name]
+                warnings.extend(
+                    cls._unchecked(node, requirement, candidates, "requires")
+                )
+                satisfying = [n for n in candidates if requirement.matches(n.version)]
+
+                if not candidates:
+                    errors.append(
+                        f"{node.full_name} ({node.type}) requires {requirement} "
+                        f"but no {requirement.name} instance is configured"
+                    )
+                elif not satisfying:
+                    found = ", ".join(
+                        f"{n.full_name}={n.version}" for n in candidates
+                    )
+                    errors.append(
+                        f"{node.full_name} ({node.type}) requires {requirement} "
+                        f"but only found {found}"
+                    )
+                else:
+                    node.depends_on.extend(
+                        n.full_name for n in satisfying if n is not node
+                    )
+
+            for conflict in addon.get_conflict_requirements():
+                others = [n for n in nodes.values() if n.type == conflict.name]
+                warnings.extend(
+                    cls._unchecked(node, conflict, others, "conflicts with")
+                )
+                for other in others:
+                    if other is node or cls._unversioned(conflict, other):
+                        continue  # An unversioned tag is only warned about
+                    if conflict.matches(other.version):
+                        errors.append(
+                            f"{node.full_name} ({node.type}) conflicts with "
+                            f"{other.full_name} ({other.type}:{other.version}, "
+                            f"constraint {conflict})"
+                        )
+
+            node.depends_on = sorted(set(node.depends_on))
+
+        if errors:
+            raise AddonGraphError(errors)
+
+        graph = cls(nodes, warnings)
+        graph.deploy_waves()  # raises on cycles
+        return graph
+
+    def dependents(self, full_name: str) -> List[str]:
+        """Instances that must wait for full_name"""
+        return sorted(
+            node.full_name
+            for node in self.nodes.values()
+            if full_name in node.depends_on
+        )
+
+    def roots(self) -> List[str]:
+        """Instances without dependencies (deployed first)"""
+        return sorted(
+            node.full_name for node in self.nodes.values() if not node.depends_on
+        )
+
+    def deploy_waves(self) -> List[List[str]]:
+        """
+        Group instances into waves; every wave only depends on earlier waves.
+
+        Raises:
+            CircularDependencyError: If the graph has a cycle
+        """
+        remaining = {name: set(node.depends_on) for name, node in self.nodes.items()}
+        waves = []
+
+        while remaining:
+            ready = sorted(name for name, deps in remaining.items() if not deps)
+            if not ready:
+                cycle = " -> ".join(self._find_cycle(remaining))
+                raise CircularDependencyError(
+                    f"Circular addon instance dependency: {cycle}"
+                )
+            waves.append(ready)
+            for name in ready:
+                del remaining[name]
+            for deps in remaining.values():
+                deps.difference_update(ready)
+
+        return waves
+
+    def deploy_order(self) -> List[str]:
+        """Flat deployment order (wave by wave)"""
+        return [name for wave in self.deploy_waves() for name in wave]
+
+    def to_ansible_vars(self) -> Dict[str, Any]:
+        """Extra vars consumed by the addon-deployer role"""
+        return {
+            "addon_deploy_order": self.deploy_order(),
+            "addon_dependencies": {
+                name: node.depends_on for name, node in self.nodes.items()
+            },
+        }
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "waves": self.deploy_waves(),
+            "instances": {
+                name: {
+                    "type": node.type,
+                    "version": node.version,
+                    "depends_on": node.depends_on,
+                    "dependents": self.dependents(name),
+                }
+                for name, node in sorted(self.nodes.items())
+            },
+            "warnings": self.warnings,
+        }
+
+    @staticmethod
+    def _unchecked(
+        node: AddonNode,
+        requirement: AddonRequirement,
+        targets: List[AddonNode],
+        relation: str,
+    ) -> List[str]:
+        """Warnings for targets whose tag (e.g. latest) can't be compared"""
+        return [
+            f"{node.full_name} {relation} {requirement}: {target.full_name} uses "
+            f"'{target.version}', constraint not checked"
+            for target in targets
+            if target is not node and AddonGraph._unversioned(requirement, target)
+        ]
+
+    @staticmethod
+    def _unversioned(requirement: AddonRequirement, target: AddonNode) -> bool:
+        """A version constraint can't be compared with target's tag"""
+        return requirement.constrained and version_key(target.version) is None
+
+    @staticmethod
+    def _find_cycle(remaining: Dict[str, set]) -> List[str]:
+        """Walk unresolved dependencies until a node repeats"""
+        start = sorted(remaining)[0]
+        path = [start]
+        current = start
+        while True:
+            current = sorted(remaining[current])[0]
+            if current in path:
+                return path[path.index(current) :] + [current]
+            path.append(current)
@@ -6,6 +6,7 @@ import yaml
 from jinja2 import Template
 
 from .addon import Addon
+from .addon_schema import load_yaml_file, validate_data
 
 
 class AddonNotFoundError(Exception):
@@ -77,7 +78,7 @@ class AddonLoader:
             raise AddonValidationError(f"Addon '{addon_name}' has invalid YAML: {e}")
 
         # Validate metadata
-        self._validate_metadata(addon_name, metadata)
+        self._validate_metadata(addon_name, metadata, env_schema)
 
         # Create addon instance
         addon = Addon(
@@ -152,6 +153,9 @@ class AddonLoader:
         """
         Recursively resolve addon dependencies.
 
+        Works on addon types only; version constraints in requires/conflicts
+        are checked per instance by AddonGraph.
+
         Args:
             addons: Initial dictionary of addons
 
@@ -217,13 +221,12 @@ class AddonLoader:
 
         Raises:
             FileNotFoundError: If file doesn't exist
-            yaml.YAMLError: If YAML is invalid
+            yaml.YAMLError: If YAML is invalid or has duplicate keys
         """
         if not file_path.exists():
             raise FileNotFoundError(str(file_path))
 
-        with open(file_path, "r") as f:
-            return yaml.safe_load(f) or {}
+        return load_yaml_file(file_path) or {}
 
     def _load_template(self, file_path: Path) -> Template:
         """
@@ -246,24 +249,29 @@ class AddonLoader:
 
         return Template(template_content)
 
-    def _validate_metadata(self, addon_name: str, metadata: dict):
+    def _validate_metadata(
+        self, addon_name: str, metadata: dict, env_schema: dict = None
+    ):
         """
-        Validate addon metadata structure.
+        Validate addon metadata against the addon.yml and env.yml JSON Schemas.
 
         Args:
             addon_name: Name of the addon
             metadata: Metadata dictionary to validate
+            env_schema: Parsed env.yml (validated when provided)
 
         Raises:
             AddonValidationError: If metadata is invalid
         """
-        required_fields = ["name", "description", "version"]
+        issues = validate_data("addon", metadata)
+        if env_schema is not None:
+            issues.extend(validate_data("env", env_schema))
 
-        for field in required_fields:
-            if field not in metadata:
-                raise AddonValidationError(
-                    f"Addon '{addon_name}' metadata missing required field: {field}"
-                )
+        if issues:
+            details = "\n".join(f"  - {issue}" for issue in issues)
+            raise AddonValidationError(
+                f"Addon '{addon_name}' does not match schema:\n{details}"
+            )
 
         # Validate name matches directory
         if metadata["name"] != addon_name:
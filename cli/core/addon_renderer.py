@@ -0,0 +1,296 @@
+"""
+Addon rendering outside Ansible
+
+Mirrors the addon-deployer role (generate-env.yml, render-templates-instance.yml)
+so addon templates can be rendered and inspected without a VM.
+"""
+
+import json
+import re
+import secrets
+import string
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Dict, List, Optional
+
+from jinja2 import Environment, FileSystemLoader, Undefined
+
+from .addon import Addon
+
+
+def _regex_replace(value: Any, pattern: str = "", replacement: str = "") -> str:
+    """Ansible's regex_replace filter"""
+    return re.sub(pattern, replacement, str(value))
+
+
+def _to_bool(value: Any) -> bool:
+    """Ansible's bool filter"""
+    if isinstance(value, bool):
+        return value
+    return str(value).strip().lower() in ("yes", "on", "1", "true", "y")
+
+
+ANSIBLE_FILTERS = {
+    "regex_replace": _regex_replace,
+    "bool": _to_bool,
+    "to_json": json.dumps,
+}
+
+
+def _recording_undefined(names: List[str]) -> type:
+    """
+    Undefined that renders empty but records the variable name.
+
+    Ansible fails the task on these; we keep rendering and report them instead.
+    """
+
+    class RecordingUndefined(Undefined):
+        def __str__(self) -> str:
+            names.append(self._undefined_name or "?")
+            return ""
+
+    return RecordingUndefined
+
+
+def generate_password(length: int = 32) -> str:
+    """Generate an alphanumeric password (same alphabet as init)"""
+    alphabet = string.ascii_letters + string.digits
+    return "".join(secrets.choice(alphabet) for _ in range(length))
+
+
+@dataclass
+class AddonRenderContext:
+    """Project context an addon instance is rendered against"""
+
+    project_name: str
+    instance_name: str = "primary"
+    category: str = ""
+    plan: str = "standard"
+    version: Optional[str] = None
+    project_config: Dict[str, Any] = field(default_factory=dict)
+    # Instance credentials, same shape as secrets.addons[type][instance]
+    instance_secrets: Dict[str, str] = field(default_factory=dict)
+    apps: Dict[str, Any] = field(default_factory=dict)
+
+
+@dataclass
+class RenderedAddon:
+    """Files produced for one addon instance"""
+
+    output_dir: Path
+    compose_path: Path
+    env_path: Path
+    env_vars: Dict[str, str]
+    config_files: List[Path] = field(default_factory=list)
+    container_name: str = ""
+    service_name: str = ""
+    # Rendered file name → variables the role would reject as undefined
+    undefined: Dict[str, List[str]] = field(default_factory=dict)
+
+
+class AddonRenderer:
+    """Renders compose.yml.j2, .env and templates/*.j2 like the addon-deployer role"""
+
+    def __init__(self, addon: Addon):
+        """
+        Initialize renderer.
+
+        Args:
+            addon: Loaded addon (see AddonLoader)
+        """
+        self.addon = addon
+        self._undefined_names: List[str] = []
+        self.jinja = Environment(
+            loader=FileSystemLoader(str(addon.addon_path)),
+            undefined=_recording_undefined(self._undefined_names),
+            trim_blocks=True,  # Ansible template module default
+            keep_trailing_newline=True,
+        )
+        self.jinja.filters.update(ANSIBLE_FILTERS)
+
+    @property
+    def addon_type(self) -> str:
+        return self.addon.name
+
+    def get_plan(self, plan: str) -> Dict[str, Any]:
+        """
+        Get plan resources from addon.yml (plans.<plan>: memory, cpu, disk
+        and addon-specific settings, as the addon-deployer reads them).
+
+        Raises:
+            ValueError: If the addon defines plans and the plan is unknown
+        """
+        plans = self.addon.metadata.get("plans")
+        if not plans:
+            return {}
+        if plan not in plans:
+            raise ValueError(
+                f"Addon '{self.addon_type}' has no plan '{plan}' "
+                f"(available: {', '.join(plans.keys())})"
+            )
+        resources = dict(plans[plan] or {})
+        resources.pop("description", None)
+        return resources
+
+    def build_env(self, context: AddonRenderContext) -> Dict[str, str]:
+        """
+        Build the addon .env values (mirrors generate-env.yml).
+
+        Priority per variable: instance secrets > project config > defaults.
+
+        Raises:
+            ValueError: If a required variable resolves to nothing
+        """
+        env_vars: Dict[str, str] = {}
+        missing = []
+
+        for var_key, var_def in self.addon.env_schema.get("variables", {}).items():
+            env_name = var_def.get("env_name", var_key)
+            source = var_def.get("source")
+            # from_ansible names a dotted fact path that hostvars never resolves,
+            # so the role always lands on the default for those variables.
+            fallback = var_def.get("default", "")
+
+            if source == "secret" and "from_secrets" in var_def:
+                value = str(
+                    context.instance_secrets.get(var_def["from_secrets"], "")
+                ).strip()
+                if not value:
+                    value = fallback
+            elif source == "config" and "from_project" in var_def:
+                found = self._lookup(context.project_config, var_def["from_project"])
+                if found is not None and not isinstance(found, dict):
+                    value = str(found)
+                else:
+                    value = fallback
+            elif source == "runtime" and "from_ansible" not in var_def:
+                value = var_def.get("value", fallback)
+            else:
+                value = fallback
+
+            if var_def.get("required") and str(value) == "":
+                missing.append(var_key)
+
+            env_vars[env_name] = str(value).replace("${PROJECT}", context.project_name)
+
+        if missing:
+            raise ValueError(
+                f"Missing required environment variable(s) for {self.addon_type}: "
+                f"{', '.join(missing)}"
+            )
+
+        return env_vars
+
+    def build_template_vars(
+        self, context: AddonRenderContext, env_vars: Dict[str, str]
+    ) -> Dict[str, Any]:
+        """Template variables (mirrors render-templates-instance.yml)"""
+        metadata = self.addon.metadata
+        addon_type = self.addon_type
+        instance = context.instance_name
+
+        template_vars = {
+            "project_name": context.project_name,
+            "project": context.project_name,
+            "addon_type": addon_type,
+            "version": context.version or metadata.get("version", "latest"),
+            "healthcheck": metadata.get("healthcheck", {}),
+            "resources": self.get_plan(context.plan),
+            "compose": metadata.get("compose", {}),
+            "ports": metadata.get("ports", []),
+            "monitoring": metadata.get("monitoring", {}),
+            "addon_config": {},
+            "instance_name": instance,
+            "instance_full_name": f"{context.category}.{instance}",
+            "instance_category": context.category,
+            "container_name": f"{context.project_name}_{addon_type}_{instance}",
+            "volume_name": f"{context.project_name}-{addon_type}-{instance}-data",
+            "service_name": f"{addon_type}-{instance}",
+            "apps": context.apps,
+            "env_vars": env_vars,
+        }
+        # Role flattens env vars into facts so templates can use {{ PORT }}
+        template_vars.update(env_vars)
+        return template_vars
+
+    def render(self, context: AddonRenderContext, output_dir: Path) -> RenderedAddon:
+        """
+        Render all addon files into output_dir.
+
+        Layout matches the VM deployment path:
+            output_dir/.env
+            output_dir/docker-compose.yml
+            output_dir/config/<template without .j2>
+        """
+        output_dir = Path(output_dir)
+        output_dir.mkdir(parents=True, exist_ok=True)
+
+        env_vars = self.build_env(context)
+        template_vars = self.build_template_vars(context, env_vars)
+
+        env_path = output_dir / ".env"
+        env_lines = [
+            f"# Environment variables for {template_vars['instance_full_name']} addon",
+            "# Generated by SuperDeploy addon-deployer",
+            f"# Project: {context.project_name}",
+            "",
+        ]
+        env_lines.extend(f"{key}={value}" for key, value in env_vars.items())
+        env_path.write_text("\n".join(env_lines) + "\n")
+        env_path.chmod(0o600)
+
+        undefined: Dict[str, List[str]] = {}
+
+        compose_path = output_dir / "docker-compose.yml"
+        self._render_file("compose.yml.j2", compose_path, template_vars, undefined)
+
+        config_files = []
+        templates_dir = self.addon.addon_path / "templates"
+        if templates_dir.is_dir():
+            config_dir = output_dir / "config"
+            config_dir.mkdir(exist_ok=True)
+            for template_path in sorted(templates_dir.rglob("*.j2")):
+                relative = template_path.relative_to(self.addon.addon_path).as_posix()
+                # Keep subdirectories: same-named templates must not collide
+                target = config_dir / re.sub(
+                    r"\.j2$", "", template_path.relative_to(templates_dir).as_posix()
+                )
+                target.parent.mkdir(parents=True, exist_ok=True)
+                self._render_file(relative, target, template_vars, undefined)
+                config_files.append(target)
+
+        return RenderedAddon(
+            output_dir=output_dir,
+            compose_path=compose_path,
+            env_path=env_path,
+            env_vars=env_vars,
+            config_files=config_files,
+            container_name=template_vars["container_name"],
+            service_name=template_vars["service_name"],
+            undefined=undefined,
+        )
+
+    def _render_file(
+        self,
+        template_name: str,
+        target: Path,
+        template_vars: Dict[str, Any],
+        undefined: Dict[str, List[str]],
+    ) -> None:
+        """Render one template, collecting undefined variable names per output file"""
+        self._undefined_names.clear()
+        template = self.jinja.get_template(template_name)
+        target.write_text(template.render(**template_vars))
+        if self._undefined_names:
+            undefined[target.name] = sorted(set(self._undefined_names))
+
+    @staticmethod
+    def _lookup(data: Dict[str, Any], dotted_path: str) -> Any:
+        """Resolve a from_project path like addons.redis.port"""
+        value: Any = data
+        for part in dotted_path.split("."):
+            if isinstance(value, dict) and part in value:
+                value = value[part]
+            else:
+                return None
+        return value
@@ -0,0 +1,93 @@
+"""Version constraints for addon.yml requires/conflicts (e.g. postgres>=14,<17)"""
+
+import re
+from dataclasses import dataclass, field
+from typing import Any, List, Optional, Tuple
+
+REQUIREMENT_PATTERN = re.compile(r"^\s*([a-z][a-z0-9-]*)\s*(.*?)\s*$")
+SPECIFIER_PATTERN = re.compile(r"^(==|!=|>=|<=|>|<)\s*([0-9][0-9A-Za-z.\-]*)$")
+
+
+def version_key(version: Any) -> Optional[Tuple[int, ...]]:
+    """
+    Numeric key for an image tag: "15-alpine" → (15,), "3.13-management" → (3, 13).
+
+    Returns None for tags without a numeric prefix (e.g. "latest").
+    """
+    match = re.match(r"^v?(\d+(?:\.\d+)*)", str(version).strip())
+    if not match:
+        return None
+    return tuple(int(part) for part in match.group(1).split("."))
+
+
+@dataclass
+class VersionSpecifier:
+    """One comparison such as >=14"""
+
+    operator: str
+    version: str
+
+    def matches(self, version: str) -> bool:
+        actual = version_key(version)
+        wanted = version_key(self.version)
+        if actual is None or wanted is None:
+            return True  # Unversioned tags cannot be checked
+
+        # Compare only as many components as the constraint specifies,
+        # so postgres==15 accepts 15-alpine and 15.4
+        actual = actual[: len(wanted)] + (0,) * (len(wanted) - len(actual))
+
+        return {
+            "==": actual == wanted,
+            "!=": actual != wanted,
+            ">=": actual >= wanted,
+            "<=": actual <= wanted,
+            ">": actual > wanted,
+            "<": actual < wanted,
+        }[self.operator]
+
+    def __str__(self) -> str:
+        return f"{self.operator}{self.version}"
+
+
+@dataclass
+class AddonRequirement:
+    """Entry of addon.yml requires/conflicts, e.g. "postgres>=14,<17" """
+
+    name: str
+    specifiers: List[VersionSpecifier] = field(default_factory=list)
+
+    @classmethod
+    def parse(cls, text: str) -> "AddonRequirement":
+        """
+        Parse a requirement string.
+
+        Raises:
+            ValueError: If the string is not name[op version[, op version...]]
+        """
+        match = REQUIREMENT_PATTERN.match(str(text))
+        if not match:
+            raise ValueError(f"Invalid addon requirement: '{text}'")
+
+        name, rest = match.groups()
+        specifiers = []
+        if rest:
+            for part in rest.split(","):
+                spec = SPECIFIER_PATTERN.match(part.strip())
+                if not spec:
+                    raise ValueError(
+                        f"Invalid version constraint '{part.strip()}' in '{text}'"
+                    )
+                specifiers.append(VersionSpecifier(*spec.groups()))
+        return cls(name=name, specifiers=specifiers)
+
+    @property
+    def constrained(self) -> bool:
+        return bool(self.specifiers)
+
+    def matches(self, version: str) -> bool:
+        """Check a version against every specifier"""
+        return all(spec.matches(version) for spec in self.specifiers)
+
+    def __str__(self) -> str:
+        return self.name + ",".join(str(spec) for spec in self.specifiers)
@@ -0,0 +1,209 @@
+"""JSON Schema validation for addon.yml, env.yml and project manifests with line-level errors"""
+
+import json
+from dataclasses import dataclass
+from functools import lru_cache
+from pathlib import Path
+from typing import Any, List, Optional, Sequence, Tuple, Union
+
+import yaml
+from jsonschema import Draft7Validator
+
+SCHEMAS_DIR = Path(__file__).parent / "schemas"
+
+# Schema name → addon file it describes
+SCHEMA_FILES = {
+    "addon": "addon.yml",
+    "env": "env.yml",
+    "manifest": "project.yml",
+}
+
+
+@dataclass
+class SchemaIssue:
+    """A single schema violation, located in the source file when possible"""
+
+    file_name: str
+    path: str
+    message: str
+    line: Optional[int] = None
+
+    @property
+    def location(self) -> str:
+        """file:line prefix used in validator output"""
+        if self.line is not None:
+            return f"{self.file_name}:{self.line}"
+        return self.file_name
+
+    def __str__(self) -> str:
+        where = f" {self.path}" if self.path else ""
+        return f"{self.location}{where}: {self.message}"
+
+
+class DuplicateKeyError(yaml.constructor.ConstructorError):
+    """Raised when a YAML mapping defines the same key twice"""
+
+    pass
+
+
+class UniqueKeyLoader(yaml.SafeLoader):
+    """SafeLoader that rejects duplicate mapping keys instead of keeping the last one"""
+
+    def construct_mapping(self, node, deep=False):
+        seen = {}
+        for key_node, _ in node.value:
+            key = self.construct_object(key_node, deep=deep)
+            if key in seen:
+                raise DuplicateKeyError(
+                    "while constructing a mapping",
+                    node.start_mark,
+                    f"found duplicate key '{key}' (first defined on line {seen[key] + 1})",
+                    key_node.start_mark,
+                )
+            seen[key] = key_node.start_mark.line
+        return super().construct_mapping(node, deep=deep)
+
+
+@lru_cache(maxsize=None)
+def load_schema(schema_name: str) -> dict:
+    """
+    Load a published schema by name.
+
+    Args:
+        schema_name: "addon", "env" or "manifest"
+
+    Returns:
+        Parsed JSON Schema
+    """
+    with open(SCHEMAS_DIR / f"{schema_name}.schema.json") as f:
+        return json.load(f)
+
+
+def addon_categories() -> List[str]:
+    """Categories accepted by the addon.yml schema"""
+    return list(load_schema("addon")["properties"]["category"]["enum"])
+
+
+def load_yaml_file(file_path: Path) -> Any:
+    """
+    Load YAML with duplicate key detection.
+
+    Raises:
+        yaml.YAMLError: If YAML is invalid or contains duplicate keys
+    """
+    with open(file_path) as f:
+        return yaml.load(f, Loader=UniqueKeyLoader)
+
+
+def validate_data(schema_name: str, data: Any) -> List[SchemaIssue]:
+    """
+    Validate already-parsed data against a schema (no line information).
+
+    Args:
+        schema_name: "addon", "env" or "manifest"
+        data: Parsed YAML document
+
+    Returns:
+        List of schema issues (empty if valid)
+    """
+    return [
+        SchemaIssue(
+            file_name=SCHEMA_FILES[schema_name],
+            path=_format_path(error.absolute_path),
+            message=error.message,
+        )
+        for error in _schema_errors(schema_name, data)
+    ]
+
+
+def validate_file(schema_name: str, file_path: Path) -> List[SchemaIssue]:
+    """
+    Validate a YAML file against a schema, reporting source line numbers.
+
+    Args:
+        schema_name: "addon", "env" or "manifest"
+        file_path: Path to the YAML file
+
+    Returns:
+        List of schema issues (empty if valid)
+    """
+    file_name = file_path.name
+    with open(file_path) as f:
+        content = f.read()
+
+    loader = UniqueKeyLoader(content)
+    try:
+        root = loader.get_single_node()
+        data = loader.construct_document(root) if root is not None else None
+    except yaml.MarkedYAMLError as e:
+        mark = e.problem_mark or e.context_mark
+        return [
+            SchemaIssue(
+                file_name=file_name,
+                path="",
+                message=e.problem or str(e),
+                line=mark.line + 1 if mark else None,
+            )
+        ]
+    finally:
+        loader.dispose()
+
+    if data is None:
+        return [SchemaIssue(file_name=file_name, path="", message="file is empty")]
+
+    return [
+        SchemaIssue(
+            file_name=file_name,
+            path=_format_path(error.absolute_path),
+            message=error.message,
+            line=_line_for_path(root, list(error.absolute_path)),
+        )
+        for error in _schema_errors(schema_name, data)
+    ]
+
+
+def _schema_errors(schema_name: str, data: Any) -> list:
+    """Run the schema validator, ordering errors by document position"""
+    validator = Draft7Validator(load_schema(schema_name))
+    return sorted(
+        validator.iter_errors(data), key=lambda e: [str(p) for p in e.absolute_path]
+    )
+
+
+def _format_path(path: Sequence[Union[str, int]]) -> str:
+    """Render a JSON path as plans.small.memory / env_template[2].name"""
+    rendered = ""
+    for part in path:
+        if isinstance(part, int):
+            rendered += f"[{part}]"
+        else:
+            rendered += f".{part}" if rendered else str(part)
+    return rendered
+
+
+def _line_for_path(node: yaml.Node, path: List[Union[str, int]]) -> Optional[int]:
+    """
+    Find the 1-based line of the deepest YAML node matching a JSON path.
+
+    Missing keys (e.g. a "required" violation) resolve to the parent mapping.
+    """
+    line = node.start_mark.line + 1
+    for part in path:
+        child: Optional[Tuple[yaml.Node, yaml.Node]] = None
+        if isinstance(node, yaml.MappingNode):
+            for key_node, value_node in node.value:
+                if key_node.value == str(part):
+                    child = (key_node, value_node)
+                    break
+            if child is None:
+                return line
+            line = child[0].start_mark.line + 1
+            node = child[1]
+        elif isinstance(node, yaml.SequenceNode) and isinstance(part, int):
+            if part >= len(node.value):
+                return line
+            node = node.value[part]
+            line = node.start_mark.line + 1
+        else:
+            return line
+    return line
@@ -6,6 +6,8 @@ from typing import List, Dict, Any, Optional
 import yaml
 import re
 
+from .addon_schema import addon_categories, validate_file
+
 
 @dataclass
 class ValidationCheck:
@@ -50,8 +52,7 @@ class AddonValidator:
     
     REQUIRED_FILES = ["addon.yml"]
     COMPOSE_FILE_NAMES = ["docker-compose.yml.j2", "compose.yml.j2"]
-    REQUIRED_METADATA_FIELDS = ["name", "description", "version", "category"]
-    VALID_CATEGORIES = ["database", "cache", "queue", "proxy", "infrastructure", "monitoring", "storage"]
+    VALID_CATEGORIES = addon_categories()
     
     # Anti-patterns to detect in Ansible tasks
     ANSIBLE_ANTI_PATTERNS = [
@@ -106,7 +107,7 @@ class AddonValidator:
         # Validate addon.yml metadata
         addon_yml_path = addon_path / "addon.yml"
         if addon_yml_path.exists():
-            self._validate_metadata(addon_yml_path, result)
+            self._validate_schema("addon", addon_yml_path, result)
             
             # Validate compose template (check both possible names)
             compose_path = None
@@ -122,6 +123,11 @@ class AddonValidator:
             # Validate healthcheck configuration
             self._validate_healthcheck(addon_yml_path, result)
         
+        # Validate env.yml variable definitions
+        env_yml_path = addon_path / "env.yml"
+        if env_yml_path.exists():
+            self._validate_schema("env", env_yml_path, result)
+        
         # Validate Ansible tasks if they exist
         ansible_path = addon_path / "ansible.yml"
         if ansible_path.exists():
@@ -179,100 +185,42 @@ class AddonValidator:
             fix_suggestion=f"Create docker-compose.yml.j2 or compose.yml.j2 in {addon_path}" if not compose_exists else None
         ))
     
-    def _validate_metadata(self, addon_yml_path: Path, result: ValidationResult) -> None:
-        """Validate addon.yml metadata fields"""
+    def _validate_schema(self, schema_name: str, file_path: Path, result: ValidationResult) -> None:
+        """Validate addon.yml / env.yml against the published JSON Schema"""
         try:
-            with open(addon_yml_path) as f:
-                metadata = yaml.safe_load(f)
-            
-            if not metadata:
-                result.add_check(ValidationCheck(
-                    name="metadata_not_empty",
-                    passed=False,
-                    message="addon.yml is empty",
-                    severity="error"
-                ))
-                return
-            
-            # Check required fields
-            for field in self.REQUIRED_METADATA_FIELDS:
-                passed = field in metadata and metadata[field]
-                
-                result.add_check(ValidationCheck(
-                    name=f"metadata_field_{field}",
-                    passed=passed,
-                    message=f"Required field {'present' if passed else 'missing'}: {field}",
-                    severity="error" if not passed else "info",
-                    fixable=not passed,
-                    fix_suggestion=f"Add '{field}' field to addon.yml" if not passed else None
-                ))
-            
-            # Validate category
-            if "category" in metadata:
-                category = metadata["category"]
-                passed = category in self.VALID_CATEGORIES
-                
-                result.add_check(ValidationCheck(
-                    name="metadata_valid_category",
-                    passed=passed,
-                    message=f"Category '{category}' is {'valid' if passed else 'invalid'}",
-                    severity="warning" if not passed else "info",
-                    fixable=not passed,
-                    fix_suggestion=f"Use one of: {', '.join(self.VALID_CATEGORIES)}" if not passed else None
-                ))
-            
-            # Check env_vars structure
-            if "env_vars" in metadata:
-                self._validate_env_vars(metadata["env_vars"], result)
-            
-        except yaml.YAMLError as e:
-            result.add_check(ValidationCheck(
-                name="metadata_valid_yaml",
-                passed=False,
-                message=f"Invalid YAML in addon.yml: {e}",
-                severity="error"
-            ))
+            issues = validate_file(schema_name, file_path)
         except Exception as e:
             result.add_check(ValidationCheck(
-                name="metadata_readable",
+                name=f"{schema_name}_schema_readable",
                 passed=False,
-                message=f"Error reading addon.yml: {e}",
+                message=f"Error reading {file_path.name}: {e}",
                 severity="error"
             ))
-    
-    def _validate_env_vars(self, env_vars: List[Dict], result: ValidationResult) -> None:
-        """Validate environment variables structure"""
-        if not isinstance(env_vars, list):
+            return
+        
+        if not issues:
             result.add_check(ValidationCheck(
-                name="env_vars_is_list",
-                passed=False,
-                message="env_vars must be a list",
-                severity="error"
+                name=f"{schema_name}_schema",
+                passed=True,
+                message=f"{file_path.name} matches schema",
+                severity="info"
             ))
             return
         
-        for i, env_var in enumerate(env_vars):
-            if not isinstance(env_var, dict):
-                result.add_check(ValidationCheck(
-                    name=f"env_var_{i}_is_dict",
-                    passed=False,
-                    message=f"env_vars[{i}] must be a dictionary",
-                    severity="error"
-                ))
-                continue
+        schema_file = f"cli/core/schemas/{schema_name}.schema.json"
+        for i, issue in enumerate(issues):
+            fix_suggestion = f"See {schema_file}"
+            if issue.path == "category":
+                fix_suggestion = f"Use one of: {', '.join(self.VALID_CATEGORIES)}"
             
-            # Check required fields for env_var
-            required_env_fields = ["name", "description", "required"]
-            for field in required_env_fields:
-                if field not in env_var:
-                    result.add_check(ValidationCheck(
-                        name=f"env_var_{i}_has_{field}",
-                        passed=False,
-                        message=f"env_vars[{i}] missing required field: {field}",
-                        severity="warning",
-                        fixable=True,
-                        fix_suggestion=f"Add '{field}' to env_var definition"
-                    ))
+            result.add_check(ValidationCheck(
+                name=f"{schema_name}_schema_{i}",
+                passed=False,
+                message=str(issue),
+                severity="error",
+                fixable=True,
+                fix_suggestion=fix_suggestion
+            ))
     
     def _validate_compose_template(self, compose_path: Path, result: ValidationResult) -> None:
         """Validate docker-compose.yml.j2 template"""
@@ -0,0 +1,256 @@
+#!/usr/bin/env python3
+"""
+Anonymize a plain pg_dump stream (addons:copy)
+
+Rewrites the rows of COPY blocks column by column; everything else passes
+through untouched. Rules come from the apps' `anonymize` profiles:
+
+    anonymize:
+      users:                  # table (or schema.table, default public)
+        email: fake:email     # keep | null | hash | fake[:kind]
+        name: fake:name
+        password_hash: hash
+        phone: null
+
+    hash    salted SHA-256 (same input → same output within one copy,
+            so joins on hashed columns still match)
+    fake    deterministic fake value: email, name, first_name, last_name,
+            phone, address, ip, text (default: guessed from the column)
+    null    NULL (NOT NULL columns fail the copy, use fake instead)
+
+Unlisted columns are kept. A profile table or column missing from the
+dump, or a dump that ends early, ends the stream with a failing statement
+so `psql --single-transaction` rolls the whole copy back.
+
+Standalone (stdlib only): addons:copy ships it to the orchestrator and runs
+
+    pg_dump ... | python3 anonymizer.py profile.json | psql ...
+"""
+
+import hashlib
+import json
+import re
+import sys
+from typing import Dict, List, Optional, TextIO
+
+RULES = ("keep", "null", "hash", "fake")
+FAKE_KINDS = (
+    "email",
+    "name",
+    "first_name",
+    "last_name",
+    "phone",
+    "address",
+    "ip",
+    "text",
+)
+
+COPY_HEADER = re.compile(r"^COPY (\S+) \((.*)\) FROM stdin;$")
+DUMP_COMPLETE = "-- PostgreSQL database dump complete"
+NULL = "\\N"
+
+FIRST_NAMES = [
+    "Alex", "Sam", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie",
+    "Avery", "Quinn", "Charlie", "Drew", "Emery", "Finley", "Harper", "Kai",
+]  # fmt: skip
+LAST_NAMES = [
+    "Smith", "Jones", "Brown", "Garcia", "Miller", "Davis", "Wilson", "Moore",
+    "Clark", "Lewis", "Walker", "Young", "King", "Wright", "Scott", "Green",
+]  # fmt: skip
+STREETS = ["Main St", "Oak Ave", "Pine Rd", "Maple Dr", "Cedar Ln", "Elm St"]
+
+
+class ProfileError(ValueError):
+    """Invalid anonymization profile"""
+
+
+def parse_rule(rule: str) -> tuple:
+    """'fake:email' → ('fake', 'email'), 'hash' → ('hash', None)"""
+    name, _, kind = str(rule).partition(":")
+    if name not in RULES or (kind and (name != "fake" or kind not in FAKE_KINDS)):
+        raise ProfileError(f"unknown rule: {rule}")
+    return name, kind or None
+
+
+def merge_profiles(profiles: Dict[str, Dict]) -> Dict[str, Dict[str, str]]:
+    """
+    Merge the profiles of several apps into one {table: {column: rule}}.
+
+    Args:
+        profiles: {app name: profile}
+
+    Raises:
+        ProfileError: If a rule is invalid or two apps disagree on a column
+    """
+    merged: Dict[str, Dict[str, str]] = {}
+    owners: Dict[tuple, str] = {}
+    for app_name, profile in sorted(profiles.items()):
+        for table, columns in (profile or {}).items():
+            table = table if "." in table else f"public.{table}"
+            for column, rule in (columns or {}).items():
+                parse_rule(rule)
+                current = merged.setdefault(table, {}).get(column)
+                if current is not None and current != rule:
+                    raise ProfileError(
+                        f"{table}.{column}: {owners[(table, column)]} says "
+                        f"{current}, {app_name} says {rule}"
+                    )
+                merged[table][column] = rule
+                owners[(table, column)] = app_name
+    return merged
+
+
+class Anonymizer:
+    """Streams a dump, rewriting profiled COPY columns."""
+
+    def __init__(self, profile: Dict[str, Dict[str, str]], salt: str):
+        self.profile = {
+            _unquote(table if "." in table else f"public.{table}"): {
+                column: parse_rule(rule) for column, rule in columns.items()
+            }
+            for table, columns in profile.items()
+        }
+        self.salt = salt
+        self.seen: Dict[str, List[str]] = {}
+        self.rows = 0
+
+    def run(self, source: TextIO, target: TextIO) -> List[str]:
+        """
+        Copy source to target.
+
+        Returns:
+            Problems (the output then ends with a failing statement)
+        """
+        problems: List[str] = []
+        rules: Optional[List[tuple]] = None
+        complete = False
+
+        for line in source:
+            if rules is not None:
+                if line.rstrip("\n") == "\\.":
+                    rules = None
+                else:
+                    line = self.row(line, rules)
+                    self.rows += 1
+                target.write(line)
+                continue
+
+            match = COPY_HEADER.match(line.rstrip("\n"))
+            if match:
+                table = _unquote(match.group(1))
+                columns = [_unquote(c.strip()) for c in match.group(2).split(",")]
+                self.seen[table] = columns
+                wanted = self.profile.get(table, {})
+                missing = sorted(set(wanted) - set(columns))
+                if missing:
+                    problems.append(f"{table} has no column(s) {', '.join(missing)}")
+                    break
+                rules = [(c, wanted.get(c, ("keep", None))) for c in columns]
+            elif line.startswith(DUMP_COMPLETE):
+                complete = True
+            target.write(line)
+
+        if not problems:
+            for table in sorted(set(self.profile) - set(self.seen)):
+                problems.append(f"table {table} is not in the dump")
+            if not complete:
+                problems.append("dump ended early (pg_dump failed?)")
+        if problems:
+            if rules is not None:
+                target.write("\\.\n")
+            message = "; ".join(problems).replace("'", "''")
+            target.write(
+                f"DO $$ BEGIN RAISE EXCEPTION 'addons:copy aborted: {message}'; "
+                f"END $$;\n"
+            )
+        target.flush()
+        return problems
+
+    def row(self, line: str, rules: List[tuple]) -> str:
+        values = line.rstrip("\n").split("\t")
+        for i, (column, (rule, kind)) in enumerate(rules):
+            if rule == "keep" or i >= len(values):
+                continue
+            if rule == "null":
+                values[i] = NULL
+            elif values[i] != NULL:
+                values[i] = self.value(column, values[i], rule, kind)
+        return "\t".join(values) + "\n"
+
+    def value(self, column: str, value: str, rule: str, kind: Optional[str]) -> str:
+        digest = hashlib.sha256(f"{self.salt}:{value}".encode()).hexdigest()
+        if rule == "hash":
+            return digest
+        return fake(kind or guess_kind(column), digest)
+
+
+def guess_kind(column: str) -> str:
+    """Fake kind from a column name (email, phone, ...)"""
+    column = column.lower()
+    if column.endswith("_ip") or column in ("ip", "ip_address"):
+        return "ip"
+    for kind in ("email", "first_name", "last_name", "phone", "address"):
+        if kind.replace("_", "") in column.replace("_", ""):
+            return kind
+    if column in ("name", "full_name", "fullname", "display_name", "username"):
+        return "name"
+    return "text"
+
+
+def fake(kind: str, digest: str) -> str:
+    """Deterministic fake value of a kind from a hex digest"""
+    number = int(digest[:12], 16)
+    first = FIRST_NAMES[number % len(FIRST_NAMES)]
+    last = LAST_NAMES[(number // 16) % len(LAST_NAMES)]
+    if kind == "email":
+        return f"user-{digest[:12]}@example.invalid"
+    if kind == "first_name":
+        return first
+    if kind == "last_name":
+        return last
+    if kind == "name":
+        return f"{first} {last}"
+    if kind == "phone":
+        return f"+1555{number % 10_000_000:07d}"
+    if kind == "address":
+        return f"{number % 9000 + 100} {STREETS[number % len(STREETS)]}"
+    if kind == "ip":
+        return f"10.{(number >> 16) & 255}.{(number >> 8) & 255}.{number & 255}"
+    return f"redacted-{digest[:16]}"
+
+
+def _unquote(name: str) -> str:
+    """'"public"."Users"' → 'public.Users'"""
+    return ".".join(part.strip('"') for part in name.split("."))
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    """anonymizer.py <profile.json> [--salt SALT] < dump > anonymized dump"""
+    import argparse
+    import secrets
+
+    parser = argparse.ArgumentParser(prog="anonymizer")
+    parser.add_argument("profile")
+    parser.add_argument("--salt", default=None)
+    args = parser.parse_args(argv)
+
+    with open(args.profile) as f:
+        profile = json.load(f)
+    try:
+        anonymizer = Anonymizer(profile, args.salt or secrets.token_hex(16))
+    except ProfileError as e:
+        print(f"❌ {e}", file=sys.stderr)
+        return 1
+
+    problems = anonymizer.run(sys.stdin, sys.stdout)
+    for problem in problems:
+        print(f"❌ {problem}", file=sys.stderr)
+    print(
+        f"Anonymized {anonymizer.rows} row(s) in {len(anonymizer.seen)} table(s)",
+        file=sys.stderr,
+    )
+    return 1 if problems else 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -5,9 +5,9 @@ This module provides a registry for application types (Python, Next.js, etc.) wi
 pluggable configuration for workflow templates and auto-detection logic.
 """
 
-from dataclasses import dataclass
+from dataclasses import dataclass, field
 from pathlib import Path
-from typing import Callable, Optional
+from typing import Callable, Dict, List, Optional
 
 
 @dataclass
@@ -19,6 +19,14 @@ class AppTypeConfig:
     detector: Optional[Callable[[Path], bool]] = None
     default_port: Optional[int] = None
     description: str = ""
+    # <project>:dev: source dir in the image (overridden by the Dockerfile's
+    # WORKDIR), mounted from the checkout; None = no hot reload
+    dev_workdir: Optional[str] = None
+    # Web command with a file watcher ({port} is the web port)
+    dev_command: Optional[str] = None
+    # Paths under dev_workdir kept from the image instead of the checkout
+    dev_keep: List[str] = field(default_factory=list)
+    dev_env: Dict[str, str] = field(default_factory=dict)
 
 
 class AppTypeRegistry:
@@ -86,6 +94,9 @@ class AppTypeRegistry:
                 detector=lambda p: (p / "requirements.txt").exists(),
                 default_port=8000,
                 description="Python application (Django, FastAPI, Cara, etc.)",
+                # Reloads when the process command does (--reload, runserver)
+                dev_workdir="/app",
+                dev_env={"PYTHONDONTWRITEBYTECODE": "1", "PYTHONUNBUFFERED": "1"},
             )
         )
 
@@ -98,6 +109,11 @@ class AppTypeRegistry:
                 or (p / "next.config.ts").exists(),
                 default_port=3000,
                 description="Next.js application (React-based SSR framework)",
+                dev_workdir="/app",
+                dev_command="npx next dev --hostname 0.0.0.0 --port {port}",
+                dev_keep=["node_modules", ".next"],
+                # File events don't cross bind mounts on macOS/Windows
+                dev_env={"WATCHPACK_POLLING": "true"},
             )
         )
 
@@ -0,0 +1,398 @@
+"""
+Project blueprints for `init`
+
+A blueprint is a manifest with parameters: the same sections as project.yml
+(cloud, vms, addons, apps with processes, aliases, domain and edge policy)
+with `{{ name }}` placeholders filled in from `params` when a project is
+created from it.
+
+    name: saas-basic
+    description: API + frontend with Postgres and Redis
+    params:
+      domain:
+        description: Public domain
+        required: true
+      api_port:
+        type: integer
+        default: 8000
+    edge:                       # default edge policy for apps without one
+      max_body: 10MB
+    manifest:
+      project:
+        domain: "{{ domain }}"
+      apps:
+        api:
+          domain: "api.{{ domain }}"
+          port: "{{ api_port }}"
+
+`{{ project }}` is always available. Placeholders that don't name a param
+({{ org.X }}, {{ postgres.primary.HOST }}, {{ APP_0_EXTERNAL_IP }}) are left
+alone for the secret resolver.
+
+Blueprints are looked up by name in blueprints/ of the superdeploy checkout
+and ~/.superdeploy/blueprints, or loaded from a file or a git repository:
+
+    init --blueprint saas-basic --set domain=example.com
+    init --blueprint ./acme.yml
+    init --blueprint https://github.com/acme/blueprints.git#saas-basic
+"""
+
+import copy
+import re
+import subprocess
+import tempfile
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Dict, List, Optional
+
+import yaml
+
+from cli.core.manifest import API_VERSION
+
+BLUEPRINT_SUFFIXES = (".yml", ".yaml")
+
+PLACEHOLDER = re.compile(r"\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}")
+GIT_PREFIXES = ("git@", "git+", "https://", "http://", "ssh://", "file://")
+
+PARAM_TYPES = {"string": str, "integer": int, "boolean": bool}
+
+# Filled in under the blueprint's manifest (same defaults as the init wizard)
+PROJECT_DEFAULTS: Dict[str, Any] = {
+    "project": {"description": "{{ project }} project"},
+    "cloud": {
+        "ssh": {
+            "key_path": "~/.ssh/superdeploy_deploy",
+            "public_key_path": "~/.ssh/superdeploy_deploy.pub",
+            "user": "superdeploy",
+        }
+    },
+    "docker": {"registry": "docker.io"},
+    "network": {"vpc_subnet": "10.1.0.0/16", "docker_subnet": "172.30.0.0/24"},
+}
+
+DEFAULT_VMS = {
+    "core": {"count": 1, "machine_type": "e2-medium", "disk_size": 20},
+    "app": {"count": 1, "machine_type": "e2-medium", "disk_size": 30},
+}
+
+# Apps are routed through Caddy, so every project gets one
+DEFAULT_PROXY = {
+    "type": "caddy",
+    "version": "2-alpine",
+    "plan": "standard",
+    "vm": "core",
+}
+
+
+class BlueprintError(ValueError):
+    """Blueprint can't be found, parsed or rendered"""
+
+
+@dataclass
+class Blueprint:
+    """A parsed blueprint file"""
+
+    name: str
+    description: str = ""
+    params: Dict[str, Dict[str, Any]] = field(default_factory=dict)
+    edge: Optional[Dict[str, Any]] = None
+    manifest: Dict[str, Any] = field(default_factory=dict)
+    source: str = ""
+
+    @classmethod
+    def from_dict(cls, data: Any, source: str) -> "Blueprint":
+        if not isinstance(data, dict):
+            raise BlueprintError(f"{source}: not a blueprint (expected a mapping)")
+        name = data.get("name") or Path(source).stem
+        params = {}
+        for param, spec in (data.get("params") or {}).items():
+            if not isinstance(spec, dict):
+                spec = {"default": spec}
+            if spec.get("type", "string") not in PARAM_TYPES:
+                raise BlueprintError(
+                    f"{source}: params.{param}.type must be one of "
+                    f"{', '.join(PARAM_TYPES)}"
+                )
+            params[param] = spec
+        manifest = data.get("manifest") or {}
+        if not isinstance(manifest, dict):
+            raise BlueprintError(f"{source}: manifest must be a mapping")
+        return cls(
+            name=name,
+            description=data.get("description", ""),
+            params=params,
+            edge=data.get("edge"),
+            manifest=manifest,
+            source=source,
+        )
+
+    def required_params(self) -> List[str]:
+        """Params without a default that have to be given"""
+        return [
+            name
+            for name, spec in self.params.items()
+            if spec.get("required", "default" not in spec)
+            and spec.get("default") is None
+        ]
+
+    def to_dict(self) -> Dict[str, Any]:
+        """Summary for listings (blueprints, dashboard wizard)"""
+        addons = []
+        for category, instances in (self.manifest.get("addons") or {}).items():
+            for instance, addon in (instances or {}).items():
+                addons.append(f"{category}.{instance}: {(addon or {}).get('type')}")
+        return {
+            "name": self.name,
+            "description": self.description,
+            "source": self.source,
+            "params": {
+                name: {
+                    "description": spec.get("description", ""),
+                    "type": spec.get("type", "string"),
+                    "default": spec.get("default"),
+                    "required": name in self.required_params(),
+                }
+                for name, spec in self.params.items()
+            },
+            "vms": sorted(self.manifest.get("vms") or DEFAULT_VMS),
+            "apps": sorted(self.manifest.get("apps") or {}),
+            "addons": addons,
+        }
+
+
+def search_paths(project_root: Path) -> List[Path]:
+    """Directories blueprints are looked up in by name, first match wins"""
+    return [Path.home() / ".superdeploy" / "blueprints", project_root / "blueprints"]
+
+
+def load_file(path: Path, source: Optional[str] = None) -> Blueprint:
+    """
+    Load a blueprint file.
+
+    Raises:
+        BlueprintError: If the file isn't valid YAML or isn't a blueprint
+    """
+    try:
+        data = yaml.safe_load(Path(path).read_text())
+    except yaml.YAMLError as e:
+        raise BlueprintError(f"{path}: invalid YAML: {e}")
+    return Blueprint.from_dict(data, source or str(path))
+
+
+def list_blueprints(project_root: Path) -> List[Blueprint]:
+    """Blueprints available by name (a user blueprint hides a bundled one)"""
+    found: Dict[str, Blueprint] = {}
+    for directory in search_paths(project_root):
+        if not directory.is_dir():
+            continue
+        for path in sorted(directory.iterdir()):
+            if path.suffix not in BLUEPRINT_SUFFIXES or path.stem in found:
+                continue
+            found[path.stem] = load_file(path)
+    return sorted(found.values(), key=lambda b: b.name)
+
+
+def find(ref: str, project_root: Path) -> Blueprint:
+    """
+    Resolve --blueprint: a git URL (repo#name), a file path or a name.
+
+    Raises:
+        BlueprintError: If nothing matches
+    """
+    if ref.startswith(GIT_PREFIXES):
+        return _from_git(ref)
+
+    path = Path(ref).expanduser()
+    if path.is_file():
+        return load_file(path)
+
+    for directory in search_paths(project_root):
+        for suffix in BLUEPRINT_SUFFIXES:
+            candidate = directory / f"{ref}{suffix}"
+            if candidate.is_file():
+                return load_file(candidate)
+
+    available = ", ".join(b.name for b in list_blueprints(project_root)) or "none"
+    raise BlueprintError(f"Blueprint not found: {ref} (available: {available})")
+
+
+def parse_sets(values: List[str]) -> Dict[str, str]:
+    """--set name=value options → {name: value}"""
+    params = {}
+    for value in values:
+        name, sep, param_value = value.partition("=")
+        if not sep or not name.strip():
+            raise BlueprintError(f"--set expects name=value, got: {value}")
+        params[name.strip()] = param_value
+    return params
+
+
+def resolve_params(
+    blueprint: Blueprint, project_name: str, values: Dict[str, Any]
+) -> Dict[str, Any]:
+    """
+    Given values over defaults, converted to each param's type. Defaults
+    may use earlier params ("{{ project }}io", "admin@{{ domain }}").
+
+    Raises:
+        BlueprintError: On unknown, missing or mistyped params
+    """
+    unknown = sorted(set(values) - set(blueprint.params))
+    if unknown:
+        raise BlueprintError(
+            f"Unknown parameter(s) for {blueprint.name}: {', '.join(unknown)}"
+        )
+    missing = [name for name in blueprint.required_params() if name not in values]
+    if missing:
+        raise BlueprintError(f"Missing parameter(s): {', '.join(missing)}")
+
+    resolved: Dict[str, Any] = {"project": project_name}
+    for name, spec in blueprint.params.items():
+        if name in values:
+            value = values[name]
+        else:
+            value = _substitute(spec.get("default"), resolved, blueprint.params)
+        if value is None:
+            continue
+        resolved[name] = _convert(name, value, spec)
+    return resolved
+
+
+def render(
+    blueprint: Blueprint, project_name: str, values: Dict[str, Any]
+) -> Dict[str, Any]:
+    """
+    Manifest for a new project created from a blueprint.
+
+    Raises:
+        BlueprintError: On bad params or a param placeholder without a value
+    """
+    params = resolve_params(blueprint, project_name, values)
+    manifest = _merge(copy.deepcopy(PROJECT_DEFAULTS), blueprint.manifest)
+    manifest = _substitute(manifest, params, blueprint.params)
+
+    manifest["apiVersion"] = API_VERSION
+    # The name given to init wins over a name in the blueprint
+    manifest["project"] = {
+        "name": project_name,
+        **{k: v for k, v in manifest["project"].items() if k != "name"},
+    }
+    manifest.setdefault("vms", copy.deepcopy(DEFAULT_VMS))
+
+    gcp = manifest.get("cloud", {}).get("gcp") or {}
+    if gcp.get("region") and not gcp.get("zone"):
+        gcp["zone"] = f"{gcp['region']}-a"
+
+    addons = manifest.setdefault("addons", {})
+    if not addons.get("proxy"):
+        addons["proxy"] = {"primary": dict(DEFAULT_PROXY)}
+
+    if blueprint.edge:
+        edge = _substitute(blueprint.edge, params, blueprint.params)
+        for app in (manifest.get("apps") or {}).values():
+            if app is not None and "edge" not in app:
+                app["edge"] = copy.deepcopy(edge)
+
+    # Key order of a hand-written project.yml
+    ordered = {"apiVersion": manifest.pop("apiVersion")}
+    ordered["project"] = manifest.pop("project")
+    ordered.update(manifest)
+    return ordered
+
+
+def _from_git(ref: str) -> Blueprint:
+    """Clone repo[#name] and load blueprint.yml, <name>.yml or blueprints/<name>.yml"""
+    repo, _, name = ref.partition("#")
+    repo = repo[len("git+") :] if repo.startswith("git+") else repo
+
+    with tempfile.TemporaryDirectory(prefix="superdeploy-blueprint-") as tmp:
+        try:
+            result = subprocess.run(
+                ["git", "clone", "--depth", "1", repo, tmp],
+                capture_output=True,
+                text=True,
+                timeout=120,
+            )
+        except subprocess.TimeoutExpired:
+            raise BlueprintError(f"git clone timed out: {repo}")
+        if result.returncode != 0:
+            raise BlueprintError(f"git clone failed: {result.stderr.strip()}")
+
+        checkout = Path(tmp)
+        if name:
+            candidates = [checkout / name]
+            for suffix in BLUEPRINT_SUFFIXES:
+                candidates += [
+                    checkout / f"{name}{suffix}",
+                    checkout / "blueprints" / f"{name}{suffix}",
+                ]
+        else:
+            candidates = [checkout / f"blueprint{s}" for s in BLUEPRINT_SUFFIXES]
+
+        for candidate in candidates:
+            if candidate.is_file():
+                return load_file(candidate, source=ref)
+
+    wanted = name or "blueprint.yml"
+    raise BlueprintError(f"No blueprint {wanted} in {repo}")
+
+
+def _substitute(value: Any, params: Dict[str, Any], declared: Any = ()) -> Any:
+    """
+    Fill {{ param }} placeholders; a lone placeholder keeps the param's type.
+
+    Other placeholders ({{ APP_0_EXTERNAL_IP }} in env templates) stay for
+    the secret resolver. declared are the blueprint's params: one without a
+    value (optional, no default) is an error rather than left in place.
+    """
+    if isinstance(value, dict):
+        return {k: _substitute(v, params, declared) for k, v in value.items()}
+    if isinstance(value, list):
+        return [_substitute(v, params, declared) for v in value]
+    if not isinstance(value, str):
+        return value
+
+    def lookup(match: "re.Match") -> Any:
+        name = match.group(1)
+        if name in params:
+            return params[name]
+        if name in declared:
+            raise BlueprintError(f"Blueprint parameter '{name}' has no value")
+        return match.group(0)
+
+    whole = PLACEHOLDER.fullmatch(value.strip())
+    if whole and whole.group(1) in params:
+        return lookup(whole)
+    return PLACEHOLDER.sub(lambda m: str(lookup(m)), value)
+
+
+def _convert(name: str, value: Any, spec: Dict[str, Any]) -> Any:
+    kind = spec.get("type", "string")
+    try:
+        if kind == "integer":
+            value = int(value)
+        elif kind == "boolean" and isinstance(value, str):
+            if value.lower() not in ("true", "false", "yes", "no", "1", "0"):
+                raise ValueError(value)
+            value = value.lower() in ("true", "yes", "1")
+        elif kind == "string":
+            value = str(value)
+    except ValueError:
+        raise BlueprintError(f"Parameter {name} must be {kind}, got: {value}")
+
+    choices = spec.get("choices")
+    if choices and value not in choices:
+        raise BlueprintError(
+            f"Parameter {name} must be one of {', '.join(map(str, choices))}"
+        )
+    return value
+
+
+def _merge(base: Dict[str, Any], override: Dict[str, Any]) -> Dict[str, Any]:
+    """Deep merge, override wins"""
+    for key, value in override.items():
+        if isinstance(value, dict) and isinstance(base.get(key), dict):
+            base[key] = _merge(base[key], value)
+        else:
+            base[key] = copy.deepcopy(value)
+    return base
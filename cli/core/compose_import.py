@@ -0,0 +1,654 @@
+"""
+docker-compose.yml → SuperDeploy project
+
+Translates a compose file into the pieces SuperDeploy stores:
+
+    postgres/redis/rabbitmq/mongo/... images  → addons (version = image tag)
+    services with the same build context      → one app, one process each
+    environment / env_file / build args       → app secrets (runtime / build)
+    values pointing at an addon service       → aliases to the addon's keys
+    read-only single-file bind mounts         → secret files
+
+The result is a manifest (apps, processes, aliases, addons) that goes
+through ManifestService like `superdeploy apply`, plus plain secret values
+and files written the way config:set / files:set write them. Everything
+without an equivalent ends up in the report instead of being dropped
+silently.
+"""
+
+import json
+import re
+import shlex
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Dict, List, Optional, Tuple
+from urllib.parse import urlsplit
+
+from cli.core.manifest import API_VERSION
+
+# Addon type → category (same as addons:add), image names, credential keys
+ADDON_TYPES = {
+    "postgres": {
+        "category": "databases",
+        "images": ("postgres", "postgresql", "postgis/postgis"),
+        "keys": ("HOST", "PORT", "USER", "PASSWORD", "DATABASE"),
+    },
+    "mongodb": {
+        "category": "databases",
+        "images": ("mongo", "mongodb", "mongodb/mongodb-community-server"),
+        "keys": ("HOST", "PORT", "USER", "PASSWORD", "DATABASE"),
+    },
+    "redis": {
+        "category": "caches",
+        "images": ("redis", "redis/redis-stack-server", "valkey/valkey"),
+        "keys": ("HOST", "PORT", "PASSWORD"),
+    },
+    "rabbitmq": {
+        "category": "queues",
+        "images": ("rabbitmq",),
+        "keys": ("HOST", "PORT", "USER", "PASSWORD"),
+    },
+    "elasticsearch": {
+        "category": "search",
+        "images": ("elasticsearch", "elasticsearch/elasticsearch"),
+        "keys": ("HOST", "PORT"),
+    },
+}
+
+# Images SuperDeploy replaces with Caddy (per-project reverse proxy)
+PROXY_IMAGES = ("nginx", "traefik", "caddy", "haproxy", "jwilder/nginx-proxy")
+
+# Service keys translated (or deliberately reported) below; anything else
+# shows up in the report as unsupported
+HANDLED_KEYS = {
+    "image",
+    "build",
+    "command",
+    "entrypoint",
+    "environment",
+    "env_file",
+    "ports",
+    "expose",
+    "volumes",
+    "networks",
+    "depends_on",
+    "deploy",
+    "scale",
+    "restart",
+    "container_name",
+    "healthcheck",
+}
+
+INTERPOLATION = re.compile(r"\$\$|\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)")
+
+
+@dataclass
+class ReportItem:
+    """Something imported differently than written, or not at all"""
+
+    service: str
+    subject: str  # key or value the item is about
+    message: str
+    level: str = "skipped"  # skipped / changed
+
+    def to_dict(self) -> Dict[str, str]:
+        return {
+            "service": self.service,
+            "subject": self.subject,
+            "message": self.message,
+            "level": self.level,
+        }
+
+
+@dataclass
+class SecretFileImport:
+    app: str
+    name: str
+    source: Path  # File on disk (bind mount source)
+    mount_path: str
+
+
+@dataclass
+class ComposeImport:
+    """Translation result"""
+
+    manifest: Dict[str, Any]
+    # app → key → (value, usage)
+    secrets: Dict[str, Dict[str, Tuple[str, str]]] = field(default_factory=dict)
+    files: List[SecretFileImport] = field(default_factory=list)
+    report: List[ReportItem] = field(default_factory=list)
+
+    def skip(self, service: str, subject: str, message: str) -> None:
+        self.report.append(ReportItem(service, subject, message, "skipped"))
+
+    def note(self, service: str, subject: str, message: str) -> None:
+        self.report.append(ReportItem(service, subject, message, "changed"))
+
+
+def translate(
+    compose: Dict[str, Any], project_name: str, base_dir: Path
+) -> ComposeImport:
+    """
+    Translate a parsed compose file.
+
+    Args:
+        compose: Parsed docker-compose.yml
+        project_name: Target project
+        base_dir: Directory of the compose file (build contexts, env files)
+
+    Returns:
+        ComposeImport with manifest, secrets, files and report
+    """
+    result = ComposeImport(
+        manifest={"apiVersion": API_VERSION, "project": {"name": project_name}}
+    )
+    services = compose.get("services") or {}
+    dotenv = _read_env(base_dir / ".env")
+
+    for network in compose.get("networks") or {}:
+        result.note(
+            "-",
+            f"network {network}",
+            "all services of a project share one Docker network",
+        )
+
+    # Addons first, so app env values can be matched against their hostnames
+    addons: Dict[str, Tuple[str, str]] = {}  # service → (addon type, instance)
+    app_services: Dict[str, Dict[str, Any]] = {}
+    counts: Dict[str, int] = {}
+    for service, spec in services.items():
+        addon_type = _addon_type(spec or {})
+        if addon_type:
+            counts[addon_type] = counts.get(addon_type, 0) + 1
+    for service, spec in services.items():
+        spec = spec or {}
+        image = spec.get("image")
+        addon_type = _addon_type(spec)
+        if addon_type:
+            instance = "primary" if counts[addon_type] == 1 else _name(service)
+            addons[service] = (addon_type, instance)
+            _import_addon(result, service, spec, addon_type, instance)
+        elif image and not spec.get("build") and _image_name(image) in PROXY_IMAGES:
+            result.skip(
+                service,
+                "image",
+                f"{image} replaced by the project's Caddy; "
+                "add routes with domains:add",
+            )
+        else:
+            app_services[service] = spec
+
+    apps = result.manifest.setdefault("apps", {})
+    for app_name, members in _group_apps(app_services).items():
+        app: Dict[str, Any] = apps.setdefault(app_name, {})
+        first_service, first_spec = members[0]
+        build = _build(first_spec)
+        if build:
+            context = build.get("context", ".")
+            app["path"] = str((base_dir / context).resolve())
+        else:
+            result.skip(
+                first_service,
+                "image",
+                f"{first_spec.get('image')} is prebuilt; SuperDeploy builds apps "
+                "from a repository, set the app's repo/path",
+            )
+
+        processes: Dict[str, Any] = {}
+        for service, spec in members:
+            process_name = _process_name(service, app_name, spec, len(members))
+            # The Dockerfile's CMD is the web process; others need a command
+            dockerfile = process_name == "web" or len(members) == 1
+            process = _import_process(result, service, spec, base_dir, dockerfile)
+            if process is not None:
+                processes[process_name] = process
+            if process and process.get("port") and "port" not in app:
+                app["port"] = process["port"]
+                external = _published_port(spec.get("ports"))
+                if external and external != process["port"]:
+                    app["external_port"] = external
+
+            environment = {}
+            for env_file in _as_list(spec.get("env_file")):
+                path = env_file.get("path") if isinstance(env_file, dict) else env_file
+                environment.update(_read_env(base_dir / path))
+            environment.update(_environment(spec.get("environment")))
+            build_args = _environment((_build(spec) or {}).get("args"))
+
+            for key, value in {**environment, **build_args}.items():
+                usage = "runtime"
+                if key in build_args:
+                    usage = "both" if key in environment else "build"
+                _import_value(
+                    result, app_name, service, key, value, usage, dotenv, addons
+                )
+                if key in result.secrets.get(app_name, {}):
+                    host = _url_host(result.secrets[app_name][key][0])
+                    if host in app_services:
+                        result.note(
+                            service, key, f"'{host}' is a compose hostname, check it"
+                        )
+
+            _import_volumes(result, app_name, service, spec, base_dir)
+            _report_common(result, service, spec, addons)
+
+        if processes:
+            app["processes"] = processes
+
+    if not apps:
+        result.manifest.pop("apps")
+    return result
+
+
+def _import_addon(
+    result: ComposeImport,
+    service: str,
+    spec: Dict[str, Any],
+    addon_type: str,
+    instance: str,
+) -> None:
+    image = spec["image"]
+    if "@" in image:
+        result.note(service, "image", "digest pin dropped, using the tag")
+    tag = image.split("@", 1)[0].rpartition("/")[2].partition(":")[2]
+    category = ADDON_TYPES[addon_type]["category"]
+    result.manifest.setdefault("addons", {}).setdefault(category, {})[instance] = {
+        "type": addon_type,
+        "version": tag or "latest",
+    }
+
+    for key in _environment(spec.get("environment")):
+        result.note(
+            service,
+            f"environment.{key}",
+            f"credentials are generated by the addon ({addon_type}.{instance}.*)",
+        )
+    if spec.get("ports"):
+        result.note(
+            service, "ports", "addons aren't published; use tunnel for local access"
+        )
+    for volume in _as_list(spec.get("volumes")):
+        source = _volume_source(volume)
+        if source and source.startswith((".", "/", "~")):
+            result.skip(
+                service,
+                f"volume {source}",
+                "init scripts/config files aren't mounted into addons",
+            )
+        else:
+            result.note(
+                service,
+                "volumes",
+                "data isn't copied; restore a dump after the addon is up",
+            )
+    for key in sorted(set(spec) - HANDLED_KEYS):
+        result.skip(service, key, "not supported for addons")
+
+
+def _import_process(
+    result: ComposeImport,
+    service: str,
+    spec: Dict[str, Any],
+    base_dir: Path,
+    dockerfile: bool,
+) -> Optional[Dict[str, Any]]:
+    """{command, replicas, port} or None if there is no command to run"""
+    command = _command(spec.get("entrypoint"), spec.get("command"))
+    if not command and dockerfile:
+        command = _dockerfile_cmd(spec, base_dir)
+        if command:
+            result.note(service, "command", f"taken from the Dockerfile: {command}")
+    if not command:
+        result.skip(
+            service,
+            "command",
+            "no command to run; add the process to the app by hand",
+        )
+        return None
+
+    process: Dict[str, Any] = {"command": command}
+    replicas = (spec.get("deploy") or {}).get("replicas", spec.get("scale"))
+    if replicas is not None:
+        process["replicas"] = int(replicas)
+
+    ports = [_container_port(p) for p in _as_list(spec.get("ports"))]
+    ports += [_container_port(p) for p in _as_list(spec.get("expose"))]
+    ports = [p for p in ports if p]
+    if ports:
+        process["port"] = ports[0]
+        for extra in ports[1:]:
+            result.skip(service, f"port {extra}", "one port per process")
+    for port in _as_list(spec.get("ports")):
+        if not _container_port(port):
+            result.skip(service, f"port {port}", "port ranges aren't supported")
+    return process
+
+
+def _import_value(
+    result: ComposeImport,
+    app_name: str,
+    service: str,
+    key: str,
+    value: Optional[str],
+    usage: str,
+    dotenv: Dict[str, str],
+    addons: Dict[str, Tuple[str, str]],
+) -> None:
+    """One env var → alias to an addon key, or a plain app secret"""
+    if value is None:
+        result.skip(service, key, "no value (taken from the host environment)")
+        return
+
+    value, missing = _interpolate(str(value), dotenv)
+    if missing:
+        result.skip(service, key, f"${{{missing}}} isn't set in .env")
+        return
+
+    target = _addon_target(value, addons)
+    if target:
+        if usage != "runtime":
+            result.note(service, key, "addon aliases are runtime only")
+        app = result.manifest["apps"].setdefault(app_name, {})
+        app.setdefault("aliases", {})[key] = target
+        result.note(service, key, f"aliased to {target}")
+        return
+
+    result.secrets.setdefault(app_name, {})[key] = (value, usage)
+
+
+def _import_volumes(
+    result: ComposeImport,
+    app_name: str,
+    service: str,
+    spec: Dict[str, Any],
+    base_dir: Path,
+) -> None:
+    for volume in _as_list(spec.get("volumes")):
+        source = _volume_source(volume)
+        target = _volume_target(volume)
+        if source and source.startswith((".", "/", "~")):
+            path = (base_dir / source).expanduser().resolve()
+            if path.is_file() and target:
+                result.files.append(
+                    SecretFileImport(app_name, path.name, path, target)
+                )
+                result.note(
+                    service,
+                    f"volume {source}",
+                    f"secret file {path.name} mounted read-only at {target}",
+                )
+                continue
+            result.skip(
+                service,
+                f"volume {source}",
+                "directory bind mounts aren't supported (apps are stateless)",
+            )
+        else:
+            result.skip(
+                service,
+                f"volume {source or target}",
+                "app containers have no persistent volumes; use an addon or "
+                "object storage",
+            )
+
+
+def _report_common(
+    result: ComposeImport,
+    service: str,
+    spec: Dict[str, Any],
+    addons: Dict[str, Tuple[str, str]],
+) -> None:
+    if spec.get("healthcheck"):
+        result.skip(service, "healthcheck", "not translated")
+    if spec.get("networks"):
+        result.note(service, "networks", "joined to the project network")
+    for dependency in _as_list(spec.get("depends_on")):
+        if dependency not in addons:
+            result.note(
+                service, f"depends_on.{dependency}", "apps start independently"
+            )
+    deploy = spec.get("deploy") or {}
+    for key in sorted(set(deploy) - {"replicas"}):
+        result.skip(service, f"deploy.{key}", "not translated")
+    for key in sorted(set(spec) - HANDLED_KEYS):
+        result.skip(service, key, "not supported")
+
+
+def _addon_type(spec: Dict[str, Any]) -> Optional[str]:
+    image = spec.get("image")
+    if not image or spec.get("build"):
+        return None
+    name = _image_name(image)
+    for addon_type, meta in ADDON_TYPES.items():
+        if name in meta["images"]:
+            return addon_type
+    return None
+
+
+def _image_name(image: str) -> str:
+    """ghcr.io/library/postgres:16@sha256:… → postgres"""
+    name = image.split("@", 1)[0]
+    last = name.rsplit("/", 1)
+    if ":" in last[-1]:
+        name = name.rsplit(":", 1)[0]
+    parts = name.split("/")
+    if len(parts) > 1 and ("." in parts[0] or ":" in parts[0]):
+        parts = parts[1:]  # Registry host
+    if parts[0] == "library":
+        parts = parts[1:]
+    return "/".join(parts)
+
+
+def _group_apps(services: Dict[str, Dict[str, Any]]) -> Dict[str, List]:
+    """Services built from the same context are processes of one app"""
+    groups: Dict[str, List[Tuple[str, Dict[str, Any]]]] = {}
+    for service, spec in services.items():
+        build = _build(spec)
+        key = f"build:{build.get('context', '.')}" if build else f"svc:{service}"
+        groups.setdefault(key, []).append((service, spec))
+
+    apps = {}
+    for members in groups.values():
+        # Named after the service that serves traffic, else the first one
+        web = [s for s, spec in members if spec.get("ports") or spec.get("expose")]
+        apps[_name(web[0] if web else members[0][0])] = members
+    return apps
+
+
+def _process_name(
+    service: str, app_name: str, spec: Dict[str, Any], members: int
+) -> str:
+    if spec.get("ports") or spec.get("expose"):
+        return "web"
+    if members == 1:
+        return "worker"
+    name = _name(service)
+    for separator in ("-", "_"):
+        if name.startswith(app_name + separator):
+            return name[len(app_name) + 1 :]
+    return name
+
+
+def _build(spec: Dict[str, Any]) -> Optional[Dict[str, Any]]:
+    build = spec.get("build")
+    if build is None:
+        return None
+    return {"context": build} if isinstance(build, str) else build
+
+
+def _command(entrypoint: Any, command: Any) -> Optional[str]:
+    parts = []
+    for value in (entrypoint, command):
+        if isinstance(value, list):
+            parts.append(shlex.join(str(v) for v in value))
+        elif value:
+            parts.append(str(value))
+    return " ".join(parts) or None
+
+
+def _dockerfile_cmd(spec: Dict[str, Any], base_dir: Path) -> Optional[str]:
+    """Last CMD of the build's Dockerfile"""
+    build = _build(spec)
+    if not build:
+        return None
+    context = base_dir / build.get("context", ".")
+    dockerfile = context / build.get("dockerfile", "Dockerfile")
+    if not dockerfile.is_file():
+        return None
+    command = None
+    for line in dockerfile.read_text().splitlines():
+        line = line.strip()
+        if line.upper().startswith("CMD "):
+            value = line[4:].strip()
+            try:
+                command = shlex.join(json.loads(value))
+            except (ValueError, TypeError):
+                command = value
+    return command
+
+
+def _container_port(port: Any) -> Optional[int]:
+    """"127.0.0.1:8080:8000/tcp", 8000 or {target: 8000} → 8000"""
+    if isinstance(port, dict):
+        port = port.get("target")
+    value = str(port).split("/", 1)[0].rsplit(":", 1)[-1]
+    return int(value) if value.isdigit() else None
+
+
+def _published_port(ports: Any) -> Optional[int]:
+    for port in _as_list(ports):
+        if isinstance(port, dict):
+            published = port.get("published")
+        else:
+            parts = str(port).split("/", 1)[0].split(":")
+            published = parts[-2] if len(parts) >= 2 else None
+        if published is not None and str(published).isdigit():
+            return int(published)
+    return None
+
+
+def _environment(environment: Any) -> Dict[str, Optional[str]]:
+    """Compose accepts a mapping or a list of KEY=value"""
+    if isinstance(environment, dict):
+        return {
+            k: None if v is None else _scalar(v) for k, v in environment.items()
+        }
+    result = {}
+    for item in _as_list(environment):
+        key, sep, value = str(item).partition("=")
+        result[key] = value if sep else None
+    return result
+
+
+def _scalar(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def _interpolate(value: str, env: Dict[str, str]) -> Tuple[str, Optional[str]]:
+    """
+    Compose variable substitution against .env.
+
+    Returns:
+        (value, name of the first unset variable without a default)
+    """
+    missing = []
+
+    def substitute(match: re.Match) -> str:
+        if match.group(0) == "$$":
+            return "$"
+        expression = match.group(1) or match.group(2)
+        for operator in (":-", "-", ":?", "?"):
+            if operator in expression:
+                name, _, default = expression.partition(operator)
+                current = env.get(name)
+                if operator.startswith(":") and current == "":
+                    current = None
+                if current is not None:
+                    return current
+                if operator.endswith("-"):
+                    return default
+                missing.append(name)
+                return ""
+        if expression not in env:
+            missing.append(expression)
+            return ""
+        return env[expression]
+
+    value = INTERPOLATION.sub(substitute, value)
+    return value, (missing[0] if missing else None)
+
+
+def _addon_target(value: str, addons: Dict[str, Tuple[str, str]]) -> Optional[str]:
+    """Alias target when a value is an addon's hostname, port or URL"""
+    if value in addons:
+        addon_type, instance = addons[value]
+        return f"{addon_type}.{instance}.HOST"
+
+    host = _url_host(value)
+    if host not in addons:
+        return None
+    addon_type, instance = addons[host]
+    prefix = f"{addon_type}.{instance}"
+    keys = ADDON_TYPES[addon_type]["keys"]
+    url = urlsplit(value)
+
+    credentials = ""
+    if "USER" in keys:
+        credentials = (
+            f"{{{{ {prefix}.USER }}}}:{{{{ {prefix}.PASSWORD | urlencode }}}}@"
+        )
+    elif "PASSWORD" in keys:
+        credentials = f":{{{{ {prefix}.PASSWORD | urlencode }}}}@"
+    path = url.path
+    if "DATABASE" in keys:
+        path = f"/{{{{ {prefix}.DATABASE }}}}"
+    query = f"?{url.query}" if url.query else ""
+    return (
+        f"{url.scheme}://{credentials}{{{{ {prefix}.HOST }}}}:"
+        f"{{{{ {prefix}.PORT }}}}{path}{query}"
+    )
+
+
+def _url_host(value: str) -> Optional[str]:
+    if "://" not in value:
+        return None
+    try:
+        return urlsplit(value).hostname
+    except ValueError:
+        return None
+
+
+def _volume_source(volume: Any) -> Optional[str]:
+    if isinstance(volume, dict):
+        return volume.get("source")
+    parts = str(volume).split(":")
+    return parts[0] if len(parts) > 1 else None
+
+
+def _volume_target(volume: Any) -> Optional[str]:
+    if isinstance(volume, dict):
+        return volume.get("target")
+    parts = str(volume).split(":")
+    return parts[1] if len(parts) > 1 else parts[0]
+
+
+def _read_env(path: Path) -> Dict[str, str]:
+    from cli.core.config_contract import read_env_file
+
+    return read_env_file(str(path)) if path.is_file() else {}
+
+
+def _as_list(value: Any) -> List[Any]:
+    if value is None:
+        return []
+    if isinstance(value, dict):
+        return list(value)  # depends_on / networks in long form
+    return value if isinstance(value, list) else [value]
+
+
+def _name(service: str) -> str:
+    """Compose service names allow characters SuperDeploy names don't"""
+    name = re.sub(r"[^a-z0-9_-]", "-", service.lower()).strip("-_")
+    return name or "app"
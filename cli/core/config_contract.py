@@ -0,0 +1,290 @@
+#!/usr/bin/env python3
+"""
+Config contracts declared by apps in their superdeploy marker
+
+    config:
+      required:
+        STRIPE_KEY:
+          description: Stripe secret key
+          pattern: "^sk_(live|test)_"
+        DATABASE_URL: url
+        WEB_CONCURRENCY: {type: int, min: 1, max: 32}
+      optional:
+        LOG_LEVEL: {type: enum, values: [debug, info, warning, error]}
+        SENTRY_DSN: {type: url, description: Error reporting}
+
+Types: string (default), url, int, bool, enum, regex. pattern applies to
+any type. Empty values count as missing.
+
+Standalone (stdlib + PyYAML): the same file is installed on app VMs as
+/opt/superdeploy/bin/check-config-contract and run by the deploy workflow
+against the .env it is about to use.
+"""
+
+import re
+import sys
+from dataclasses import dataclass, field
+from typing import Any, Dict, List, Optional
+from urllib.parse import urlparse
+
+TYPES = ("string", "url", "int", "bool", "enum", "regex")
+BOOL_VALUES = ("true", "false", "1", "0", "yes", "no", "on", "off")
+
+
+@dataclass
+class ConfigKeySpec:
+    """One declared config key"""
+
+    name: str
+    required: bool = True
+    type: str = "string"
+    description: str = ""
+    pattern: Optional[str] = None
+    values: List[str] = field(default_factory=list)
+    min: Optional[int] = None
+    max: Optional[int] = None
+
+    @classmethod
+    def from_value(cls, name: str, value: Any, required: bool) -> "ConfigKeySpec":
+        """
+        Parse a key declaration: null, a type name, or a mapping.
+
+        Raises:
+            ValueError: If the declaration is invalid
+        """
+        if value is None:
+            value = {}
+        elif isinstance(value, str):
+            value = {"type": value}
+        elif not isinstance(value, dict):
+            raise ValueError(f"config key '{name}' must be a type name or a mapping")
+
+        spec = cls(
+            name=name,
+            required=required,
+            type=str(value.get("type", "string")),
+            description=str(value.get("description", "")),
+            pattern=value.get("pattern"),
+            values=[str(v) for v in value.get("values") or []],
+            min=value.get("min"),
+            max=value.get("max"),
+        )
+
+        if spec.type not in TYPES:
+            raise ValueError(
+                f"config key '{name}': unknown type '{spec.type}' "
+                f"(expected one of: {', '.join(TYPES)})"
+            )
+        if spec.type == "enum" and not spec.values:
+            raise ValueError(f"config key '{name}': enum needs values")
+        if spec.type == "regex" and not spec.pattern:
+            raise ValueError(f"config key '{name}': regex needs a pattern")
+        if spec.pattern:
+            try:
+                re.compile(spec.pattern)
+            except re.error as e:
+                raise ValueError(f"config key '{name}': invalid pattern: {e}")
+        return spec
+
+    def to_dict(self) -> Dict[str, Any]:
+        result: Dict[str, Any] = {"type": self.type}
+        if self.description:
+            result["description"] = self.description
+        if self.pattern:
+            result["pattern"] = self.pattern
+        if self.values:
+            result["values"] = self.values
+        if self.min is not None:
+            result["min"] = self.min
+        if self.max is not None:
+            result["max"] = self.max
+        return result
+
+    def check(self, value: str) -> Optional[str]:
+        """Return why value violates the spec, or None"""
+        value = str(value)
+        if "{{" in value:
+            return "contains an unresolved template placeholder"
+
+        if self.type == "url":
+            parsed = urlparse(value)
+            if not parsed.scheme or not (parsed.netloc or parsed.path):
+                return "is not a URL"
+        elif self.type == "int":
+            try:
+                number = int(value)
+            except ValueError:
+                return "is not an integer"
+            if self.min is not None and number < self.min:
+                return f"must be >= {self.min}"
+            if self.max is not None and number > self.max:
+                return f"must be <= {self.max}"
+        elif self.type == "bool":
+            if value.lower() not in BOOL_VALUES:
+                return "is not a boolean (true/false)"
+        elif self.type == "enum":
+            if value not in self.values:
+                return f"must be one of: {', '.join(self.values)}"
+
+        if self.pattern and not re.search(self.pattern, value):
+            return f"does not match {self.pattern}"
+        return None
+
+
+@dataclass
+class ContractViolation:
+    """A missing or invalid key"""
+
+    key: str
+    message: str
+    description: str = ""
+
+    def __str__(self) -> str:
+        hint = f" ({self.description})" if self.description else ""
+        return f"{self.key} {self.message}{hint}"
+
+
+@dataclass
+class ConfigContract:
+    """Required and optional keys an app declares"""
+
+    keys: Dict[str, ConfigKeySpec] = field(default_factory=dict)
+
+    @classmethod
+    def from_dict(cls, data: Any) -> "ConfigContract":
+        """
+        Parse the marker's config section.
+
+        required/optional may be mappings (key → spec) or plain key lists.
+
+        Raises:
+            ValueError: If the section is malformed
+        """
+        if not data:
+            return cls()
+        if not isinstance(data, dict):
+            raise ValueError("config must be a mapping with required/optional")
+
+        unknown = set(data) - {"required", "optional"}
+        if unknown:
+            raise ValueError(f"config has unknown sections: {', '.join(unknown)}")
+
+        keys = {}
+        for section, required in (("required", True), ("optional", False)):
+            entries = data.get(section) or {}
+            if isinstance(entries, list):
+                entries = {name: None for name in entries}
+            if not isinstance(entries, dict):
+                raise ValueError(f"config.{section} must be a mapping or a list")
+            for name, value in entries.items():
+                if name in keys:
+                    raise ValueError(f"config key '{name}' declared twice")
+                keys[name] = ConfigKeySpec.from_value(str(name), value, required)
+        return cls(keys=keys)
+
+    def to_dict(self) -> Dict[str, Any]:
+        result = {}
+        for section, required in (("required", True), ("optional", False)):
+            entries = {
+                name: spec.to_dict()
+                for name, spec in self.keys.items()
+                if spec.required == required
+            }
+            if entries:
+                result[section] = entries
+        return result
+
+    @property
+    def required(self) -> List[str]:
+        return [name for name, spec in self.keys.items() if spec.required]
+
+    @property
+    def optional(self) -> List[str]:
+        return [name for name, spec in self.keys.items() if not spec.required]
+
+    def __bool__(self) -> bool:
+        return bool(self.keys)
+
+    def missing(self, env: Dict[str, Any]) -> List[str]:
+        """Required keys absent or empty in env"""
+        return [name for name in self.required if not str(env.get(name) or "")]
+
+    def validate(self, env: Dict[str, Any]) -> List[ContractViolation]:
+        """Check env against every declared key"""
+        violations = []
+        for name, spec in self.keys.items():
+            value = env.get(name)
+            if value is None or str(value) == "":
+                if spec.required:
+                    violations.append(
+                        ContractViolation(name, "is required", spec.description)
+                    )
+                continue
+            error = spec.check(value)
+            if error:
+                violations.append(ContractViolation(name, error, spec.description))
+        return violations
+
+
+# Escapes format_env_value writes inside double quotes: \\ \" \n and $$
+_DOUBLE_QUOTED_ESCAPE = re.compile(r'\\([\\"n])|\$\$')
+
+
+def read_env_file(path: str) -> Dict[str, str]:
+    """KEY=value lines (quotes stripped), as written by config:push or the workflow"""
+    env = {}
+    with open(path) as f:
+        for line in f:
+            line = line.strip()
+            if not line or line.startswith("#") or "=" not in line:
+                continue
+            key, value = line.split("=", 1)
+            value = value.strip()
+            if len(value) >= 2 and value[0] == value[-1] == '"':
+                value = _DOUBLE_QUOTED_ESCAPE.sub(_unescape, value[1:-1])
+            elif len(value) >= 2 and value[0] == value[-1] == "'":
+                value = value[1:-1]
+            env[key.strip()] = value
+    return env
+
+
+def _unescape(match: "re.Match") -> str:
+    escaped = match.group(1)
+    if escaped is None:
+        return "$"
+    return "\n" if escaped == "n" else escaped
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    """check-config-contract <marker file> <.env file>"""
+    import yaml
+
+    argv = sys.argv[1:] if argv is None else argv
+    if len(argv) != 2:
+        print("usage: check-config-contract <marker> <env file>", file=sys.stderr)
+        return 2
+
+    with open(argv[0]) as f:
+        marker = yaml.safe_load(f) or {}
+    try:
+        contract = ConfigContract.from_dict(marker.get("config"))
+    except ValueError as e:
+        print(f"❌ Invalid config contract in {argv[0]}: {e}")
+        return 1
+    if not contract:
+        print("No config contract declared")
+        return 0
+
+    violations = contract.validate(read_env_file(argv[1]))
+    if violations:
+        print(f"❌ Config contract violated ({len(violations)}):")
+        for violation in violations:
+            print(f"   • {violation}")
+        return 1
+
+    print(f"✅ Config contract satisfied ({len(contract.keys)} keys)")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -194,6 +194,45 @@ class ProjectConfig:
         # No need to read marker files - they're already synced to database
         return self.raw_config.get("apps", {})
 
+    def get_apps_with_probes(self) -> Dict[str, Dict[str, Any]]:
+        """
+        Apps for the Ansible templates: healthcheck/readiness blocks from
+        each app's marker (local checkout) rendered for compose and Caddy.
+
+        Raises:
+            ConfigurationError: If a marker's blocks are invalid
+        """
+        from cli.core.healthcheck import render_apps
+        from cli.exceptions import ConfigurationError
+        from cli.marker_manager import MarkerManager
+
+        apps = {}
+        for app_name, app_config in self.get_apps().items():
+            app_config = dict(app_config)
+            path = app_config.get("path")
+            marker = None
+            if path and Path(path).expanduser().exists():
+                marker = MarkerManager.load_marker(
+                    Path(path).expanduser(), self.project_name, app_name
+                )
+            if marker and app_config.get("processes"):
+                processes = {}
+                for name, process in app_config["processes"].items():
+                    process = dict(process)
+                    declared = marker.get_process(name)
+                    if declared and declared.healthcheck:
+                        process["healthcheck"] = declared.healthcheck
+                    if declared and declared.readiness:
+                        process["readiness"] = declared.readiness
+                    processes[name] = process
+                app_config["processes"] = processes
+            apps[app_name] = app_config
+
+        try:
+            return render_apps(apps)
+        except ValueError as e:
+            raise ConfigurationError("Invalid healthcheck/readiness", context=str(e))
+
     def get_vms(self) -> Dict[str, Dict[str, Any]]:
         """
         Get VM definitions
@@ -379,7 +418,7 @@ class ProjectConfig:
             "addon_configs": addons,
             "vm_config": self.get_vm_config(),
             "network_config": self.get_network_config(),
-            "apps": self.get_apps(),
+            "apps": self.get_apps_with_probes(),
             "monitoring": self.get_monitoring_config(),
             "docker": docker_config,
         }
@@ -499,6 +538,10 @@ class ConfigLoader:
                     config_dict["apps"][app.name]["repo"] = app.repo
                 if app.owner:
                     config_dict["apps"][app.name]["owner"] = app.owner
+                if app.domain:
+                    config_dict["apps"][app.name]["domain"] = app.domain
+                if app.edge:
+                    config_dict["apps"][app.name]["edge"] = app.edge
 
                 # Load processes from database (Process table)
                 from cli.database import Process
@@ -0,0 +1,299 @@
+#!/usr/bin/env python3
+"""
+Per-VM deploy queue
+
+Deploys onto the same app VM share /opt/superdeploy/projects/<p>/compose
+(docker-compose.yml edited in place, docker compose up/restart), so they
+take turns: one running, the rest waiting in FIFO order.
+
+    deploy-queue acquire --id ID --project P --app A --source workflow
+    deploy-queue heartbeat --id ID --pid PID &
+    deploy-queue release --id ID
+    deploy-queue status [--json]
+
+acquire blocks until the ticket reaches the head of the queue and then holds
+the slot for --lease seconds (or until release). Waiting tickets refresh a
+heartbeat; a waiter that stops polling (killed job, dropped SSH session) is
+dropped after STALE_SECONDS so it cannot block the queue. A holder past its
+lease is dropped the same way, so a crashed deploy frees the VM on its own.
+
+Deploys can outlast one lease (release phase, readiness wait, slow pulls),
+so holders run heartbeat in the background: it renews the lease every third
+of it while --pid (the deploy's shell or job runner) is alive and stops once
+the ticket is released.
+
+Standalone (stdlib only): the same file is installed on app VMs as
+/opt/superdeploy/bin/deploy-queue and used by the deploy workflow and by
+deploy, releases:switch and restart over SSH.
+"""
+
+import argparse
+import fcntl
+import json
+import os
+import socket
+import sys
+import time
+from contextlib import contextmanager
+from datetime import datetime, timezone
+from typing import Any, Dict, Iterator, List, Optional
+
+QUEUE_DIR = os.environ.get("DEPLOY_QUEUE_DIR", "/opt/superdeploy/deploy-queue")
+DEFAULT_WAIT_SECONDS = 1800
+DEFAULT_LEASE_SECONDS = 900
+STALE_SECONDS = 30
+POLL_SECONDS = 2
+REPORT_SECONDS = 15
+
+# Exit code when the wait times out (EX_TEMPFAIL)
+EXIT_TIMEOUT = 75
+
+
+class DeployQueue:
+    """FIFO tickets in one JSON file, guarded by an flock"""
+
+    def __init__(self, queue_dir: str = QUEUE_DIR):
+        self.queue_dir = queue_dir
+        self.state_path = os.path.join(queue_dir, "queue.json")
+        self.lock_path = os.path.join(queue_dir, "queue.lock")
+
+    @contextmanager
+    def _locked(self) -> Iterator[List[Dict[str, Any]]]:
+        """Load entries under the lock; changes are written back on exit"""
+        os.makedirs(self.queue_dir, exist_ok=True)
+        with open(self.lock_path, "a") as lock:
+            fcntl.flock(lock, fcntl.LOCK_EX)
+            try:
+                entries = self._read()
+                yield entries
+                self._write(entries)
+            finally:
+                fcntl.flock(lock, fcntl.LOCK_UN)
+
+    def _read(self) -> List[Dict[str, Any]]:
+        try:
+            with open(self.state_path) as f:
+                data = json.load(f)
+        except (OSError, ValueError):
+            return []
+        return data.get("entries", []) if isinstance(data, dict) else []
+
+    def _write(self, entries: List[Dict[str, Any]]) -> None:
+        tmp_path = f"{self.state_path}.tmp"
+        with open(tmp_path, "w") as f:
+            json.dump({"entries": entries}, f, indent=2)
+        os.replace(tmp_path, self.state_path)
+
+    @staticmethod
+    def _prune(entries: List[Dict[str, Any]], now: float) -> List[str]:
+        """Drop expired holders and waiters that stopped polling"""
+        dropped = []
+        for entry in list(entries):
+            if entry.get("started_at"):
+                expired = entry.get("expires_at", 0) < now
+            else:
+                expired = entry.get("seen_at", 0) < now - STALE_SECONDS
+            if expired:
+                entries.remove(entry)
+                dropped.append(entry["id"])
+        return dropped
+
+    def acquire(
+        self,
+        ticket_id: str,
+        project: str,
+        app: str,
+        source: str,
+        ref: str = "",
+        wait: int = DEFAULT_WAIT_SECONDS,
+        lease: int = DEFAULT_LEASE_SECONDS,
+    ) -> bool:
+        """Wait for the slot. Returns False if wait seconds pass first."""
+        enqueued = time.time()
+        last_report = 0.0
+
+        while True:
+            now = time.time()
+            with self._locked() as entries:
+                for dropped in self._prune(entries, now):
+                    print(f"⚠️  Dropped stale deploy queue entry: {dropped}")
+
+                entry = next((e for e in entries if e["id"] == ticket_id), None)
+                if entry is None:
+                    entry = {
+                        "id": ticket_id,
+                        "project": project,
+                        "app": app,
+                        "source": source,
+                        "ref": ref,
+                        "enqueued_at": now,
+                    }
+                    entries.append(entry)
+                entry["seen_at"] = now
+
+                if entries[0] is entry:
+                    entry.setdefault("started_at", now)
+                    entry["expires_at"] = now + lease
+                    print(
+                        f"🔒 Deploy slot acquired for {app} "
+                        f"(waited {int(now - enqueued)}s)"
+                    )
+                    return True
+
+                if now - enqueued >= wait:
+                    entries.remove(entry)
+                    head = entries[0]
+                    print(
+                        f"❌ Timed out after {wait}s waiting for the deploy queue "
+                        f"(running: {head['app']} from {head['source']})"
+                    )
+                    return False
+
+                if now - last_report >= REPORT_SECONDS:
+                    last_report = now
+                    head = entries[0]
+                    position = entries.index(entry)
+                    print(
+                        f"⏳ Waiting for deploy queue: position {position}, "
+                        f"running {head['app']} from {head['source']}"
+                    )
+
+            time.sleep(POLL_SECONDS)
+
+    def renew(self, ticket_id: str, lease: int = DEFAULT_LEASE_SECONDS) -> bool:
+        """Extend the holder's lease. False if the ticket is gone."""
+        now = time.time()
+        with self._locked() as entries:
+            entry = next((e for e in entries if e["id"] == ticket_id), None)
+            if entry is None:
+                return False
+            entry["seen_at"] = now
+            if entry.get("started_at"):
+                entry["expires_at"] = now + lease
+            return True
+
+    def heartbeat(
+        self,
+        ticket_id: str,
+        lease: int = DEFAULT_LEASE_SECONDS,
+        pid: Optional[int] = None,
+    ) -> None:
+        """Renew the lease until the ticket is released or pid exits"""
+        interval = max(lease // 3, POLL_SECONDS)
+        while self.renew(ticket_id, lease):
+            renew_at = time.time() + interval
+            while time.time() < renew_at:
+                if pid and not _alive(pid):
+                    return  # The lease runs out on its own
+                time.sleep(POLL_SECONDS)
+
+    def release(self, ticket_id: str) -> bool:
+        """Free the slot (or leave the queue). False if the ticket is gone."""
+        with self._locked() as entries:
+            entry = next((e for e in entries if e["id"] == ticket_id), None)
+            if entry is None:
+                return False
+            entries.remove(entry)
+            return True
+
+    def status(self) -> List[Dict[str, Any]]:
+        """Current entries, head first, with state running/pending"""
+        now = time.time()
+        with self._locked() as entries:
+            self._prune(entries, now)
+            return [
+                dict(entry, state="running" if entry.get("started_at") else "pending")
+                for entry in entries
+            ]
+
+
+def _alive(pid: int) -> bool:
+    try:
+        os.kill(pid, 0)
+    except ProcessLookupError:
+        return False
+    except PermissionError:
+        pass  # Exists, owned by another user
+    return True
+
+
+def _timestamp(value: Optional[float]) -> str:
+    if not value:
+        return ""
+    return datetime.fromtimestamp(value, timezone.utc).isoformat(timespec="seconds")
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    parser = argparse.ArgumentParser(prog="deploy-queue")
+    sub = parser.add_subparsers(dest="command", required=True)
+
+    acquire = sub.add_parser("acquire", help="Wait for this VM's deploy slot")
+    acquire.add_argument("--id", required=True)
+    acquire.add_argument("--project", required=True)
+    acquire.add_argument("--app", required=True)
+    acquire.add_argument("--source", default="manual")
+    acquire.add_argument("--ref", default="")
+    acquire.add_argument("--wait", type=int, default=DEFAULT_WAIT_SECONDS)
+    acquire.add_argument("--lease", type=int, default=DEFAULT_LEASE_SECONDS)
+
+    heartbeat = sub.add_parser(
+        "heartbeat", help="Renew the lease of --id until it is released"
+    )
+    heartbeat.add_argument("--id", required=True)
+    heartbeat.add_argument("--lease", type=int, default=DEFAULT_LEASE_SECONDS)
+    heartbeat.add_argument("--pid", type=int, help="Stop when this process exits")
+
+    release = sub.add_parser("release", help="Free the slot held by --id")
+    release.add_argument("--id", required=True)
+
+    status = sub.add_parser("status", help="Show running and pending deploys")
+    status.add_argument("--json", action="store_true")
+
+    args = parser.parse_args(argv)
+    queue = DeployQueue()
+
+    if args.command == "acquire":
+        acquired = queue.acquire(
+            args.id,
+            args.project,
+            args.app,
+            args.source,
+            ref=args.ref,
+            wait=args.wait,
+            lease=args.lease,
+        )
+        return 0 if acquired else EXIT_TIMEOUT
+
+    if args.command == "heartbeat":
+        queue.heartbeat(args.id, lease=args.lease, pid=args.pid)
+        return 0
+
+    if args.command == "release":
+        if queue.release(args.id):
+            print("🔓 Deploy slot released")
+        return 0
+
+    entries = queue.status()
+    for entry in entries:
+        for key in ("enqueued_at", "started_at", "expires_at", "seen_at"):
+            entry[key] = _timestamp(entry.get(key))
+
+    if args.json:
+        print(json.dumps({"vm": socket.gethostname(), "entries": entries}))
+        return 0
+
+    if not entries:
+        print("Deploy queue is empty")
+        return 0
+    for entry in entries:
+        target = f"{entry['project']}/{entry['app']}"
+        print(
+            f"{entry['state']:<8} {target:<24} "
+            f"{entry['source']:<10} {entry.get('ref', ''):<12} "
+            f"{entry['started_at'] or entry['enqueued_at']}"
+        )
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
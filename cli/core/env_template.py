@@ -0,0 +1,195 @@
+"""
+Sandboxed template language for env_templates and alias targets
+
+    DATABASE_URL: "postgres://app:{{ postgres.primary.PASSWORD | urlencode }}@{{ postgres.primary.HOST }}/app"
+    GCP_KEY_B64: "{{ GCP_KEY_JSON | b64encode }}"
+    SENTRY_DSN: "{{ SENTRY_DSN | default('') }}"
+    API_URL: "{% if app.api.domain %}https://{{ app.api.domain }}{% else %}http://{{ APP_0_EXTERNAL_IP }}:{{ app.api.port }}{% endif %}"
+
+Templates run in Jinja's sandbox: no attribute access to internals, no
+imports, no file access. Unknown names render unchanged ({{ NAME }}), as
+the old regex substitution did, and are reported by the engine.
+"""
+
+import base64
+import hashlib
+import json
+from typing import Any, Callable, Dict, List, Optional, Set
+from urllib.parse import quote
+
+from jinja2 import ChainableUndefined, TemplateError, meta
+from jinja2.sandbox import SandboxedEnvironment
+
+from cli.exceptions import SecretError
+
+
+def _urlencode(value: Any) -> str:
+    """Percent-encode everything except unreserved characters (passwords in DSNs)"""
+    return quote(str(value), safe="")
+
+
+def _b64encode(value: Any) -> str:
+    return base64.b64encode(str(value).encode()).decode()
+
+
+def _b64decode(value: Any) -> str:
+    return base64.b64decode(str(value).encode()).decode()
+
+
+def _json(value: Any) -> str:
+    return json.dumps(value)
+
+
+def _fromjson(value: Any) -> Any:
+    return json.loads(str(value))
+
+
+def _sha256(value: Any) -> str:
+    return hashlib.sha256(str(value).encode()).hexdigest()
+
+
+TEMPLATE_FILTERS = {
+    "urlencode": _urlencode,
+    "b64encode": _b64encode,
+    "b64decode": _b64decode,
+    "json": _json,
+    "fromjson": _fromjson,
+    "sha256": _sha256,
+}
+
+
+def _keep_undefined(names: List[str]) -> type:
+    """
+    Undefined that renders as the original placeholder and records its name.
+
+    Attribute access chains (postgres.missing.HOST) so dotted names survive.
+    """
+
+    class KeepUndefined(ChainableUndefined):
+        def __getattr__(self, name: str):
+            if name[:2] == "__":
+                raise AttributeError(name)
+            return type(self)(name=f"{self._undefined_name or '?'}.{name}")
+
+        __getitem__ = __getattr__
+
+        def __str__(self) -> str:
+            name = self._undefined_name or "?"
+            names.append(name)
+            return "{{ %s }}" % name
+
+    return KeepUndefined
+
+
+class Namespace:
+    """
+    Lazily resolved dotted references: app.billing.TOKEN, org.DOCKER_TOKEN.
+
+    Segments without lowercase letters are keys and are resolved through
+    resolve(ref); other segments are names (apps, projects, addon
+    instances) and return a deeper namespace. ns['key'] always resolves,
+    for keys that aren't upper case. attributes holds plain values such as
+    an app's domain and port.
+    """
+
+    def __init__(
+        self,
+        prefix: str,
+        resolve: Callable[[str], Optional[str]],
+        undefined: type,
+        attributes: Optional[Dict[str, Any]] = None,
+        children: Optional[Dict[str, "Namespace"]] = None,
+    ):
+        self._prefix = prefix
+        self._resolve = resolve
+        self._undefined = undefined
+        self._attributes = attributes or {}
+        self._children = children or {}
+
+    def _leaf(self, name: str):
+        ref = f"{self._prefix}.{name}"
+        value = self._resolve(ref)
+        if value is None:
+            return self._undefined(name=ref)
+        return value
+
+    def __getattr__(self, name: str):
+        if name.startswith("_"):
+            raise AttributeError(name)
+        if name in self._attributes:
+            value = self._attributes[name]
+            if value is None:
+                return self._undefined(name=f"{self._prefix}.{name}")
+            return value
+        if name in self._children:
+            return self._children[name]
+        if name == name.upper():
+            return self._leaf(name)
+        return Namespace(f"{self._prefix}.{name}", self._resolve, self._undefined)
+
+    def __getitem__(self, name: str):
+        return self._leaf(str(name))
+
+    def __str__(self) -> str:
+        return "{{ %s }}" % self._prefix
+
+
+class TemplateEngine:
+    """Renders env templates in a sandboxed Jinja environment"""
+
+    def __init__(self):
+        self.undefined_names: List[str] = []
+        self.undefined = _keep_undefined(self.undefined_names)
+        self.env = SandboxedEnvironment(
+            undefined=self.undefined,
+            autoescape=False,
+            keep_trailing_newline=True,
+        )
+        self.env.filters.update(TEMPLATE_FILTERS)
+
+    @staticmethod
+    def is_template(value: Any) -> bool:
+        return isinstance(value, str) and ("{{" in value or "{%" in value)
+
+    def names(self, template: str, key: Optional[str] = None) -> Set[str]:
+        """
+        Top-level names a template uses, so only those are looked up.
+
+        Raises:
+            SecretError: If the template has a syntax error
+        """
+        try:
+            return meta.find_undeclared_variables(self.env.parse(template))
+        except TemplateError as e:
+            raise self._error(e, template, key)
+
+    def namespace(
+        self,
+        prefix: str,
+        resolve: Callable[[str], Optional[str]],
+        attributes: Optional[Dict[str, Any]] = None,
+        children: Optional[Dict[str, Namespace]] = None,
+    ) -> Namespace:
+        return Namespace(prefix, resolve, self.undefined, attributes, children)
+
+    def render(
+        self, template: str, context: Dict[str, Any], key: Optional[str] = None
+    ) -> str:
+        """
+        Render one template.
+
+        Raises:
+            SecretError: On syntax errors, sandbox violations or filter failures
+        """
+        try:
+            return self.env.from_string(template).render(context)
+        except TemplateError as e:
+            raise self._error(e, template, key)
+        except (ValueError, TypeError) as e:
+            # Filter failures: invalid base64/JSON input
+            raise self._error(e, template, key)
+
+    @staticmethod
+    def _error(error: Exception, template: str, key: Optional[str]) -> SecretError:
+        target = f"{key}: " if key else ""
+        return SecretError(f"{target}template error: {error}", context=template)
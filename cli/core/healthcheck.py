@@ -0,0 +1,543 @@
+#!/usr/bin/env python3
+"""
+Container healthchecks and readiness, declared per process in the marker
+
+    processes:
+      web:
+        command: gunicorn app:app --bind 0.0.0.0:8000
+        port: 8000
+        healthcheck:              # alive? -> compose healthcheck
+          http: /healthz
+          interval: 30s
+          start_period: 40s
+        readiness:                # ready for traffic? -> Caddy, deploy check
+          http: /ready
+          status: [200, 204]
+          interval: 5s
+      worker:
+        command: celery -A app worker
+        healthcheck:
+          command: celery -A app inspect ping
+
+Probes (exactly one per block):
+    http     path on the process port; passes on `status` (default 200)
+    tcp      port accepts connections (true: the process port)
+    command  shell command in the container exits 0
+Timing: interval, timeout, retries, start_period ("10s", "2m" or seconds).
+
+The healthcheck replaces the image's HEALTHCHECK in docker-compose.yml.
+Readiness (or the healthcheck when there is none) drives Caddy's active
+health checks (http probes only) and deploy verification: a deploy passes
+once every replica answers the probe (processes without probes only need to
+be running). HTTP probes need curl or wget in the image; tcp probes nc,
+python3 or bash.
+
+Standalone (stdlib + PyYAML): the same file is installed on app VMs as
+/opt/superdeploy/bin/healthcheck and run by deploy workflows and deploy:
+
+    healthcheck compose --marker FILE --compose FILE --app A
+    healthcheck wait --project P --app A [--timeout SECONDS]
+"""
+
+import json
+import os
+import re
+import shlex
+import subprocess
+import sys
+import time
+from dataclasses import dataclass, field
+from typing import Any, Callable, Dict, List, Optional, Tuple
+
+PROBE_TYPES = ("http", "tcp", "command")
+
+DEFAULT_INTERVAL = 10
+DEFAULT_TIMEOUT = 5
+DEFAULT_RETRIES = 3
+
+# Deploy verification for services without a declared probe: running
+DEFAULT_WAIT_SECONDS = 60
+
+# Compose labels: the probe deploy verification runs, and "healthcheck set
+# from the marker" (dropped again when the marker stops declaring one)
+READINESS_LABEL = "com.superdeploy.readiness"
+MANAGED_LABEL = "com.superdeploy.healthcheck"
+
+COMPOSE_FILE = "/opt/superdeploy/projects/{project}/compose/docker-compose.yml"
+
+_DURATION = re.compile(r"^\s*(\d+)\s*(s|m|h)?\s*$")
+_UNITS = {"s": 1, "m": 60, "h": 3600}
+
+
+def parse_duration(value: Any, what: str) -> int:
+    """Seconds of a duration ("30s", "2m", 45)"""
+    if isinstance(value, bool):
+        raise ValueError(f"{what} must be a duration like 10s or 2m")
+    if isinstance(value, int):
+        seconds = value
+    else:
+        match = _DURATION.match(str(value))
+        if not match:
+            raise ValueError(f"{what} must be a duration like 10s or 2m")
+        seconds = int(match.group(1)) * _UNITS[match.group(2) or "s"]
+    if seconds < 0:
+        raise ValueError(f"{what} can't be negative")
+    return seconds
+
+
+@dataclass
+class Probe:
+    """One healthcheck or readiness probe of a process"""
+
+    type: str  # http | tcp | command
+    target: str  # Path, port or command
+    port: Optional[int] = None
+    status: List[int] = field(default_factory=lambda: [200])
+    interval: int = DEFAULT_INTERVAL
+    timeout: int = DEFAULT_TIMEOUT
+    retries: int = DEFAULT_RETRIES
+    start_period: int = 0
+
+    @classmethod
+    def from_value(
+        cls, value: Any, port: Optional[int] = None, where: str = "healthcheck"
+    ) -> "Probe":
+        """
+        Parse a healthcheck/readiness block. port is the process port.
+
+        Raises:
+            ValueError: If the block is invalid
+        """
+        if not isinstance(value, dict):
+            raise ValueError(f"{where} must be a mapping")
+        types = [name for name in PROBE_TYPES if name in value]
+        if len(types) != 1:
+            raise ValueError(f"{where} needs exactly one of: {', '.join(PROBE_TYPES)}")
+        probe_type = types[0]
+        raw = value[probe_type]
+        port = value.get("port", port)
+        if port is not None and (
+            isinstance(port, bool) or not isinstance(port, int) or port <= 0
+        ):
+            raise ValueError(f"{where} port must be a port number")
+
+        if probe_type == "http":
+            target = str(raw or "").strip()
+            if not target.startswith("/"):
+                raise ValueError(f"{where} http must be a path like /healthz")
+            if not port:
+                raise ValueError(f"{where} http probe needs the process port")
+        elif probe_type == "tcp":
+            if raw is True:
+                raw = port
+            if isinstance(raw, bool) or not isinstance(raw, int) or raw <= 0:
+                raise ValueError(f"{where} tcp must be a port number or true")
+            port = raw
+            target = str(raw)
+        else:
+            target = str(raw or "").strip()
+            if not target:
+                raise ValueError(f"{where} command is empty")
+
+        status = value.get("status", 200)
+        status = status if isinstance(status, list) else [status]
+        if not status or not all(
+            isinstance(code, int) and not isinstance(code, bool) and 100 <= code < 600
+            for code in status
+        ):
+            raise ValueError(f"{where} status must be HTTP status codes")
+        if "status" in value and probe_type != "http":
+            raise ValueError(f"{where} status only applies to http probes")
+
+        retries = value.get("retries", DEFAULT_RETRIES)
+        if isinstance(retries, bool) or not isinstance(retries, int) or retries < 1:
+            raise ValueError(f"{where} retries must be a number of at least 1")
+
+        interval = parse_duration(
+            value.get("interval", DEFAULT_INTERVAL), f"{where} interval"
+        )
+        timeout = parse_duration(
+            value.get("timeout", DEFAULT_TIMEOUT), f"{where} timeout"
+        )
+        if not interval or not timeout:
+            raise ValueError(f"{where} interval and timeout must be at least 1s")
+
+        return cls(
+            type=probe_type,
+            target=target,
+            port=port,
+            status=status,
+            interval=interval,
+            timeout=timeout,
+            retries=retries,
+            start_period=parse_duration(
+                value.get("start_period", 0), f"{where} start_period"
+            ),
+        )
+
+    def to_dict(self) -> Dict[str, Any]:
+        """Declaration form (what from_value reads), with the port"""
+        result: Dict[str, Any] = {
+            self.type: int(self.target) if self.type == "tcp" else self.target
+        }
+        if self.port and self.type != "tcp":
+            result["port"] = self.port
+        if self.type == "http" and self.status != [200]:
+            result["status"] = self.status
+        if self.interval != DEFAULT_INTERVAL:
+            result["interval"] = f"{self.interval}s"
+        if self.timeout != DEFAULT_TIMEOUT:
+            result["timeout"] = f"{self.timeout}s"
+        if self.retries != DEFAULT_RETRIES:
+            result["retries"] = self.retries
+        if self.start_period:
+            result["start_period"] = f"{self.start_period}s"
+        return result
+
+    def describe(self) -> str:
+        if self.type == "http":
+            codes = "/".join(str(code) for code in self.status)
+            return f"GET {self.target} → {codes}"
+        if self.type == "tcp":
+            return f"tcp :{self.target}"
+        return self.target
+
+    @property
+    def wait_seconds(self) -> int:
+        """How long deploy verification waits for the probe to pass"""
+        return self.start_period + self.retries * self.interval + self.timeout
+
+    def shell(self) -> str:
+        """The probe as a shell command run inside the container"""
+        if self.type == "command":
+            return self.target
+        if self.type == "tcp":
+            port, timeout = self.target, self.timeout
+            return (
+                f"nc -z -w {timeout} localhost {port} 2>/dev/null"
+                f" || python3 -c \"import socket; socket.create_connection("
+                f"('localhost', {port}), {timeout})\" 2>/dev/null"
+                f" || bash -c 'exec 3<>/dev/tcp/localhost/{port}' 2>/dev/null"
+            )
+        url = shlex.quote(f"http://localhost:{self.port}{self.target}")
+        codes = "|".join(str(code) for code in self.status)
+        return (
+            f"code=$(curl -s -o /dev/null -w '%{{http_code}}' -m {self.timeout}"
+            f" {url} 2>/dev/null || wget -S -O /dev/null -T {self.timeout} {url}"
+            " 2>&1 | awk '/^ *HTTP\\//{c=$2} END{print c}'); "
+            f'case "$code" in {codes}) exit 0;; esac; exit 1'
+        )
+
+    def compose(self) -> Dict[str, Any]:
+        """docker-compose.yml healthcheck ($ escaped for compose)"""
+        return {
+            "test": ["CMD-SHELL", self.shell().replace("$", "$$")],
+            "interval": f"{self.interval}s",
+            "timeout": f"{self.timeout}s",
+            "retries": self.retries,
+            "start_period": f"{self.start_period}s",
+        }
+
+    def label(self) -> str:
+        """READINESS_LABEL value ($ escaped for compose)"""
+        return json.dumps(self.to_dict(), sort_keys=True).replace("$", "$$")
+
+    @classmethod
+    def from_label(cls, value: str) -> "Probe":
+        return cls.from_value(json.loads(value.replace("$$", "$")), where="label")
+
+    def caddy(self) -> Dict[str, Any]:
+        """Caddy active health check ({} for tcp/command probes)"""
+        if self.type != "http":
+            return {}
+        status = None
+        if len(self.status) == 1:
+            status = str(self.status[0])
+        elif len({code // 100 for code in self.status}) == 1:
+            status = f"{self.status[0] // 100}xx"
+        return {
+            "uri": self.target,
+            "status": status,
+            "interval": f"{self.interval}s",
+            "timeout": f"{self.timeout}s",
+        }
+
+
+def process_probes(
+    process: Dict[str, Any], where: str = "process"
+) -> Tuple[Optional[Probe], Optional[Probe]]:
+    """
+    A process's (healthcheck, readiness) probes, None where not declared.
+
+    Raises:
+        ValueError: If a block is invalid
+    """
+    port = process.get("port")
+    probes = []
+    for key in ("healthcheck", "readiness"):
+        value = process.get(key)
+        probes.append(
+            Probe.from_value(value, port, f"{where}.{key}") if value else None
+        )
+    return probes[0], probes[1]
+
+
+def render_apps(apps: Dict[str, Any]) -> Dict[str, Any]:
+    """
+    Apps for the Ansible templates: processes that declare probes get
+    compose_healthcheck, readiness_label and caddy_health.
+
+    Raises:
+        ValueError: If a block is invalid
+    """
+    rendered = {}
+    for app_name, app_config in apps.items():
+        app_config = dict(app_config)
+        processes = {}
+        for name, process in (app_config.get("processes") or {}).items():
+            process = dict(process)
+            healthcheck, readiness = process_probes(process, f"{app_name}.{name}")
+            if healthcheck:
+                process["compose_healthcheck"] = healthcheck.compose()
+            probe = readiness or healthcheck
+            if probe:
+                process["readiness_label"] = probe.label()
+                process["caddy_health"] = probe.caddy()
+            processes[name] = process
+        if processes:
+            app_config["processes"] = processes
+        rendered[app_name] = app_config
+    return rendered
+
+
+def _labels(service: Dict[str, Any]) -> Dict[str, str]:
+    labels = service.get("labels") or {}
+    if isinstance(labels, list):
+        return dict(
+            (item.split("=", 1) + [""])[:2] for item in labels if isinstance(item, str)
+        )
+    return {str(key): str(value) for key, value in labels.items()}
+
+
+def _set_labels(service: Dict[str, Any], labels: Dict[str, str]) -> None:
+    """Write labels back in the form the service uses (list or mapping)"""
+    if isinstance(service.get("labels"), list):
+        service["labels"] = [f"{key}={value}" for key, value in labels.items()]
+    elif labels or "labels" in service:
+        service["labels"] = labels
+
+
+def apply_to_compose(
+    compose: Dict[str, Any], processes: Dict[str, Any], app: str
+) -> List[str]:
+    """
+    Set the marker's probes on the app's services in a docker-compose.yml.
+    Returns one line per changed service.
+
+    Raises:
+        ValueError: If a block is invalid
+    """
+    services = compose.get("services") or {}
+    changes = []
+    for name, process in (processes or {}).items():
+        service = services.get(f"{app}-{name}")
+        if not isinstance(process, dict) or service is None:
+            continue
+        if process.get("run_on") == "deploy":
+            continue
+        healthcheck, readiness = process_probes(process, name)
+        labels = _labels(service)
+
+        if healthcheck:
+            service["healthcheck"] = healthcheck.compose()
+            labels[MANAGED_LABEL] = "marker"
+        elif labels.pop(MANAGED_LABEL, None):
+            service.pop("healthcheck", None)
+
+        probe = readiness or healthcheck
+        if probe:
+            labels[READINESS_LABEL] = probe.label()
+        else:
+            labels.pop(READINESS_LABEL, None)
+        _set_labels(service, labels)
+
+        if healthcheck or readiness:
+            parts = [f"healthcheck {healthcheck.describe()}"] if healthcheck else []
+            parts += [f"readiness {readiness.describe()}"] if readiness else []
+            changes.append(f"{app}-{name}: {', '.join(parts)}")
+    return changes
+
+
+def _docker(args: List[str], cwd: Optional[str] = None, timeout: int = 30):
+    try:
+        return subprocess.run(
+            ["docker"] + args,
+            cwd=cwd,
+            capture_output=True,
+            text=True,
+            timeout=timeout,
+        )
+    except subprocess.TimeoutExpired:
+        return subprocess.CompletedProcess(args, 124, "", f"timed out after {timeout}s")
+
+
+def _containers(compose_dir: str, service: str) -> List[str]:
+    result = _docker(["compose", "ps", "-q", service], cwd=compose_dir)
+    return result.stdout.split() if result.returncode == 0 else []
+
+
+def _wait_probe(
+    service: str,
+    probe: Probe,
+    containers: List[str],
+    timeout: Optional[int],
+    log: Callable[[str], None],
+) -> bool:
+    wait = timeout or probe.wait_seconds
+    log(
+        f"⏳ {service}: waiting for {probe.describe()} "
+        f"({len(containers)} replica(s), up to {wait}s)"
+    )
+    deadline = time.monotonic() + wait
+    pending, output = list(containers), ""
+    while True:
+        for container in list(pending):
+            result = _docker(
+                ["exec", container, "sh", "-c", probe.shell()],
+                timeout=probe.timeout + 5,
+            )
+            if result.returncode == 0:
+                pending.remove(container)
+            else:
+                output = (result.stdout + result.stderr).strip()
+        if not pending:
+            log(f"✅ {service}: ready ({len(containers)}/{len(containers)})")
+            return True
+        if time.monotonic() >= deadline:
+            ready = len(containers) - len(pending)
+            log(
+                f"❌ {service}: not ready after {wait}s "
+                f"({ready}/{len(containers)} passed {probe.describe()})"
+            )
+            for line in output.splitlines()[-10:]:
+                log(f"   │ {line}")
+            return False
+        time.sleep(probe.interval)
+
+
+def _wait_running(
+    service: str,
+    containers: List[str],
+    timeout: Optional[int],
+    log: Callable[[str], None],
+) -> bool:
+    """Services without a declared probe only need to be running"""
+    wait = timeout or DEFAULT_WAIT_SECONDS
+    deadline = time.monotonic() + wait
+    while True:
+        states = []
+        for container in containers:
+            result = _docker(["inspect", "-f", "{{.State.Status}}", container])
+            states.append(result.stdout.strip() or "missing")
+        if all(state == "running" for state in states):
+            log(f"✅ {service}: running ({len(containers)}/{len(containers)})")
+            return True
+        if time.monotonic() >= deadline:
+            log(f"❌ {service}: not running after {wait}s ({', '.join(states)})")
+            return False
+        time.sleep(5)
+
+
+def wait_ready(
+    project: str,
+    app: str,
+    compose_file: Optional[str] = None,
+    timeout: Optional[int] = None,
+    log: Callable[[str], None] = print,
+) -> bool:
+    """
+    Wait until every replica of the app's services passes its readiness
+    probe (label), or is running when the marker declares none.
+    """
+    import yaml
+
+    compose_file = compose_file or COMPOSE_FILE.format(project=project)
+    with open(compose_file) as f:
+        compose = yaml.safe_load(f) or {}
+
+    ready = True
+    for name, service in (compose.get("services") or {}).items():
+        if not name.startswith(f"{app}-") or not isinstance(service, dict):
+            continue
+        replicas = (service.get("deploy") or {}).get("replicas", 1)
+        if service.get("profiles") or replicas == 0:
+            continue
+        containers = _containers(os.path.dirname(compose_file), name)
+        if not containers:
+            log(f"❌ {name}: no containers running")
+            ready = False
+            continue
+        label = _labels(service).get(READINESS_LABEL)
+        if label:
+            probe = Probe.from_label(label)
+            ready &= _wait_probe(name, probe, containers, timeout, log)
+        else:
+            ready &= _wait_running(name, containers, timeout, log)
+    return ready
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    import argparse
+
+    import yaml
+
+    parser = argparse.ArgumentParser(prog="healthcheck")
+    commands = parser.add_subparsers(dest="command", required=True)
+
+    compose_cmd = commands.add_parser(
+        "compose", help="Set the marker's probes in docker-compose.yml"
+    )
+    compose_cmd.add_argument("--marker", required=True)
+    compose_cmd.add_argument("--compose", required=True)
+    compose_cmd.add_argument("--app", required=True)
+
+    wait_cmd = commands.add_parser("wait", help="Wait until the app is ready")
+    wait_cmd.add_argument("--project", required=True)
+    wait_cmd.add_argument("--app", required=True)
+    wait_cmd.add_argument("--compose", help="Default: " + COMPOSE_FILE)
+    wait_cmd.add_argument("--timeout", type=int, help="Seconds (default: per probe)")
+
+    args = parser.parse_args(argv)
+
+    if args.command == "compose":
+        try:
+            with open(args.marker) as f:
+                marker = yaml.safe_load(f) or {}
+            with open(args.compose) as f:
+                compose = yaml.safe_load(f) or {}
+            changes = apply_to_compose(compose, marker.get("processes"), args.app)
+        except (OSError, ValueError, yaml.YAMLError) as e:
+            print(f"❌ Invalid healthcheck/readiness: {e}")
+            return 1
+        with open(args.compose, "w") as f:
+            yaml.dump(compose, f, default_flow_style=False, sort_keys=False)
+        for line in changes:
+            print(f"🩺 {line}")
+        return 0
+
+    try:
+        ready = wait_ready(
+            args.project,
+            args.app,
+            args.compose,
+            args.timeout,
+            log=lambda line: print(line, flush=True),
+        )
+    except (OSError, ValueError, yaml.YAMLError) as e:
+        print(f"❌ Readiness check failed: {e}")
+        return 1
+    return 0 if ready else 1
+
+
+if __name__ == "__main__":
+    sys.exit(main())
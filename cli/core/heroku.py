@@ -0,0 +1,298 @@
+#!/usr/bin/env python3
+"""
+Heroku Procfile / app.json in place of a superdeploy marker
+
+    Procfile    web: gunicorn app:app --bind 0.0.0.0:$PORT
+                worker: celery -A app worker
+                release: python manage.py migrate
+
+    app.json    env                 → config contract (required unless
+                                      "required": false)
+                addons              → addon attachments (heroku-postgresql
+                                      → postgres as DATABASE)
+                formation           → replicas
+                scripts.postdeploy  → hooks.after_deploy
+
+release runs once per deploy before the other processes (run_on: deploy).
+Compose doesn't set $PORT, so it's replaced with the app's port.
+
+Standalone (stdlib + PyYAML): the same file is installed on app VMs as
+/opt/superdeploy/bin/heroku-marker and run by the deploy workflow when the
+repository has no marker:
+
+    heroku-marker <app dir> <project> <app> [--vm app] [--port 8000]
+"""
+
+import json
+import re
+import secrets
+import shlex
+import sys
+from pathlib import Path
+from typing import Any, Dict, List, Optional, Tuple
+
+PROCFILE = "Procfile"
+APP_JSON = "app.json"
+
+# Heroku add-on service → (SuperDeploy addon type, default attachment name)
+HEROKU_ADDONS = {
+    "heroku-postgresql": ("postgres", "DATABASE"),
+    "heroku-redis": ("redis", "REDIS"),
+    "rediscloud": ("redis", "REDISCLOUD"),
+    "cloudamqp": ("rabbitmq", "CLOUDAMQP"),
+    "mongolab": ("mongodb", "MONGODB"),
+    "bonsai": ("elasticsearch", "BONSAI"),
+    "searchbox": ("elasticsearch", "SEARCHBOX"),
+}
+
+# Addon keys an attachment aliases as <AS>_<KEY> (same as addons:attach)
+ATTACHMENT_KEYS = {
+    "postgres": ["HOST", "PORT", "USER", "PASSWORD", "DATABASE"],
+    "redis": ["HOST", "PORT", "PASSWORD"],
+    "rabbitmq": ["HOST", "PORT", "USER", "PASSWORD", "VHOST"],
+    "mongodb": ["HOST", "PORT", "USER", "PASSWORD", "DATABASE"],
+    "elasticsearch": ["HOST", "PORT"],
+}
+
+# <AS>_URL for an attachment, like the config var Heroku add-ons set
+ATTACHMENT_URLS = {
+    "postgres": "postgres://{{ %(t)s.USER }}:{{ %(t)s.PASSWORD | urlencode }}"
+    "@{{ %(t)s.HOST }}:{{ %(t)s.PORT }}/{{ %(t)s.DATABASE }}",
+    "redis": "redis://:{{ %(t)s.PASSWORD | urlencode }}"
+    "@{{ %(t)s.HOST }}:{{ %(t)s.PORT }}",
+    "rabbitmq": "amqp://{{ %(t)s.USER }}:{{ %(t)s.PASSWORD | urlencode }}"
+    "@{{ %(t)s.HOST }}:{{ %(t)s.PORT }}/",
+    "mongodb": "mongodb://{{ %(t)s.USER }}:{{ %(t)s.PASSWORD | urlencode }}"
+    "@{{ %(t)s.HOST }}:{{ %(t)s.PORT }}/{{ %(t)s.DATABASE }}",
+    "elasticsearch": "http://{{ %(t)s.HOST }}:{{ %(t)s.PORT }}",
+}
+
+PROCFILE_LINE = re.compile(r"^([A-Za-z0-9_-]+):\s*(.+)$")
+PORT_VARIABLE = re.compile(r"\$\{PORT\}|\$PORT\b")
+SHELL_SYNTAX = re.compile(r"[$&|;<>`*?()]")
+
+
+def has_heroku_files(app_dir: Path) -> bool:
+    return (app_dir / PROCFILE).is_file() or (app_dir / APP_JSON).is_file()
+
+
+def parse_procfile(text: str, port: Optional[int] = None) -> Dict[str, dict]:
+    """Procfile lines → marker process definitions"""
+    processes = {}
+    for line in text.splitlines():
+        line = line.strip()
+        if not line or line.startswith("#"):
+            continue
+        match = PROCFILE_LINE.match(line)
+        if not match:
+            raise ValueError(f"invalid Procfile line: {line}")
+        name, command = match.group(1), match.group(2).strip()
+
+        process: Dict[str, Any] = {"command": compose_command(command, port)}
+        process["replicas"] = 1
+        if name == "web" and port:
+            process["port"] = port
+        if name == "release":
+            process["run_on"] = "deploy"
+        processes[name] = process
+    return processes
+
+
+def compose_command(command: str, port: Optional[int] = None) -> str:
+    """
+    Procfile commands run in a shell; compose runs them without one and
+    interpolates $VARS itself. Wrap them in sh -c when they need a shell.
+    """
+    if port:
+        command = PORT_VARIABLE.sub(str(port), command)
+    if not SHELL_SYNTAX.search(command):
+        return command
+    return "sh -c " + shlex.quote(command.replace("$", "$$"))
+
+
+def from_app_json(data: Dict[str, Any]) -> Tuple[Dict[str, Any], List[str]]:
+    """
+    app.json → marker sections (config, addons, hooks, formation).
+
+    Returns:
+        (sections, warnings for what has no equivalent)
+    """
+    sections: Dict[str, Any] = {}
+    warnings = []
+
+    required, optional = {}, {}
+    for key, spec in (data.get("env") or {}).items():
+        if not isinstance(spec, dict):
+            spec = {"value": spec}
+        entry = None
+        if spec.get("description"):
+            entry = {"description": spec["description"]}
+        if spec.get("required", True):
+            required[key] = entry
+        else:
+            optional[key] = entry
+    config = {}
+    if required:
+        config["required"] = required
+    if optional:
+        config["optional"] = optional
+    if config:
+        sections["config"] = config
+
+    addons = []
+    for addon in data.get("addons") or []:
+        if isinstance(addon, str):
+            addon = {"plan": addon}
+        service = str(addon.get("plan", "")).split(":", 1)[0]
+        if service not in HEROKU_ADDONS:
+            warnings.append(f"add-on {addon.get('plan')} has no SuperDeploy addon")
+            continue
+        addon_type, default_as = HEROKU_ADDONS[service]
+        addons.append({"type": addon_type, "as": addon.get("as") or default_as})
+    if addons:
+        sections["addons"] = addons
+
+    formation = data.get("formation") or {}
+    if isinstance(formation, list):  # Older array form
+        formation = {f.get("process"): f for f in formation if f.get("process")}
+    replicas = {}
+    for name, spec in formation.items():
+        if spec.get("quantity") is not None:
+            replicas[name] = int(spec["quantity"])
+        if spec.get("size"):
+            warnings.append(f"formation.{name}.size ignored (the VM's machine type)")
+    if replicas:
+        sections["formation"] = replicas
+
+    scripts = data.get("scripts") or {}
+    postdeploy = scripts.get("postdeploy")
+    if isinstance(postdeploy, dict):
+        postdeploy = postdeploy.get("command")
+    if postdeploy:
+        sections["hooks"] = {"after_deploy": [postdeploy]}
+    for name in sorted(set(scripts) - {"postdeploy"}):
+        warnings.append(f"scripts.{name} ignored (only postdeploy is supported)")
+
+    for key in ("buildpacks", "stack", "image"):
+        if data.get(key):
+            warnings.append(f"{key} ignored (apps are built from their Dockerfile)")
+    if data.get("environments"):
+        warnings.append("environments ignored (use config:set per environment)")
+    return sections, warnings
+
+
+def build_marker(
+    app_dir: Path,
+    project: str,
+    app: str,
+    vm: str = "app",
+    port: Optional[int] = None,
+) -> Tuple[Optional[Dict[str, Any]], List[str]]:
+    """
+    Marker equivalent of an app's Procfile and app.json.
+
+    Returns:
+        (marker dict, warnings); marker is None if neither file exists
+
+    Raises:
+        ValueError: If a file can't be parsed
+    """
+    app_dir = Path(app_dir)
+    if not has_heroku_files(app_dir):
+        return None, []
+
+    marker: Dict[str, Any] = {"project": project, "app": app, "vm": vm}
+    warnings = []
+
+    if (app_dir / PROCFILE).is_file():
+        processes = parse_procfile((app_dir / PROCFILE).read_text(), port)
+        if processes:
+            marker["processes"] = processes
+    else:
+        warnings.append("no Procfile: add one (or a marker) with the processes")
+
+    if (app_dir / APP_JSON).is_file():
+        try:
+            data = json.loads((app_dir / APP_JSON).read_text())
+        except json.JSONDecodeError as e:
+            raise ValueError(f"invalid app.json: {e}")
+        sections, app_warnings = from_app_json(data)
+        warnings += app_warnings
+
+        processes = marker.get("processes", {})
+        for name, quantity in sections.pop("formation", {}).items():
+            if name in processes:
+                processes[name]["replicas"] = quantity
+            else:
+                warnings.append(f"formation.{name} has no Procfile process")
+        marker.update(sections)
+
+    return marker, warnings
+
+
+def env_defaults(app_dir: Path) -> Dict[str, str]:
+    """
+    Values app.json provides for its env: "value" defaults, and a random
+    64-character hex string for "generator": "secret" (same as Heroku).
+    """
+    path = Path(app_dir) / APP_JSON
+    if not path.is_file():
+        return {}
+    defaults = {}
+    for key, spec in (json.loads(path.read_text()).get("env") or {}).items():
+        if not isinstance(spec, dict):
+            spec = {"value": spec}
+        if spec.get("generator") == "secret":
+            defaults[key] = secrets.token_hex(32)
+        elif spec.get("value") not in (None, ""):
+            defaults[key] = str(spec["value"])
+    return defaults
+
+
+def attachment_aliases(addon_type: str, target: str, as_var: str) -> Dict[str, str]:
+    """
+    Aliases for attaching an addon instance (target, e.g. postgres.primary)
+    as as_var: <AS>_HOST → postgres.primary.HOST, ..., <AS>_URL → template
+    """
+    aliases = {
+        f"{as_var}_{key}": f"{target}.{key}"
+        for key in ATTACHMENT_KEYS.get(addon_type, ["HOST", "PORT"])
+    }
+    if addon_type in ATTACHMENT_URLS:
+        aliases[f"{as_var}_URL"] = ATTACHMENT_URLS[addon_type] % {"t": target}
+    return aliases
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    """heroku-marker <app dir> <project> <app> [--vm VM] [--port PORT]"""
+    import argparse
+
+    import yaml
+
+    parser = argparse.ArgumentParser(prog="heroku-marker")
+    parser.add_argument("app_dir")
+    parser.add_argument("project")
+    parser.add_argument("app")
+    parser.add_argument("--vm", default="app")
+    parser.add_argument("--port", type=int)
+    args = parser.parse_args(argv)
+
+    try:
+        marker, warnings = build_marker(
+            Path(args.app_dir), args.project, args.app, args.vm, args.port
+        )
+    except ValueError as e:
+        print(f"❌ {e}", file=sys.stderr)
+        return 1
+    if marker is None:
+        print(f"❌ No Procfile or app.json in {args.app_dir}", file=sys.stderr)
+        return 1
+
+    for warning in warnings:
+        print(f"⚠️  {warning}", file=sys.stderr)
+    yaml.safe_dump(marker, sys.stdout, default_flow_style=False, sort_keys=False)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
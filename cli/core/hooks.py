@@ -0,0 +1,450 @@
+#!/usr/bin/env python3
+"""
+Lifecycle hooks declared in the superdeploy marker or with <project>:hooks:add
+
+    hooks:
+      before_deploy:
+        - {http: "https://status.example.com/maintenance", method: PUT}
+      after_deploy:
+        - python manage.py migrate --noinput
+        - {exec: python manage.py collectstatic --noinput, timeout: 600}
+        - {script: ./scripts/notify.sh, on_failure: warn}
+
+A plain string is an exec hook. Hook types:
+    exec    shell command in the app's container (<app>-web, else its first
+            process) on the app's VM
+    http    request with the event as JSON body; any 2xx status passes
+    script  shell command on the machine running the event (the deploy
+            runner for workflow deploys, the operator's machine for CLI
+            commands), run from the app directory
+
+Options: timeout (seconds), on_failure (abort stops the operation and fails
+it, warn carries on), method and headers (http). Hooks see the event as
+SUPERDEPLOY_EVENT, SUPERDEPLOY_PROJECT, SUPERDEPLOY_APP and more
+SUPERDEPLOY_* variables depending on the event (SUPERDEPLOY_REF, ...).
+
+Standalone (stdlib + PyYAML): the same file is installed on app VMs as
+/opt/superdeploy/bin/run-hooks and run by the deploy workflow with the app's
+marker plus the project's hooks.json (written by <project>:hooks:push).
+"""
+
+import json
+import os
+import shlex
+import subprocess
+import sys
+import time
+import urllib.error
+import urllib.request
+from dataclasses import dataclass, field
+from typing import Any, Callable, Dict, List, Optional, Tuple
+
+EVENTS = (
+    "before_build",
+    "before_deploy",
+    "after_deploy",
+    "on_rollback",
+    "before_down",
+    "after_backup",
+    "after_addon_change",
+)
+HOOK_TYPES = ("exec", "http", "script")
+FAILURE_POLICIES = ("abort", "warn")
+HTTP_METHODS = ("GET", "POST", "PUT", "PATCH", "DELETE")
+
+DEFAULT_TIMEOUT = 300
+MAX_TIMEOUT = 3600
+DEFAULT_HTTP_TIMEOUT = 30
+
+# Lines of hook output echoed into the deploy log
+OUTPUT_TAIL_LINES = 50
+
+# Written on each VM by <project>:hooks:push
+HOOKS_FILE = "/opt/superdeploy/projects/{project}/hooks.json"
+
+
+@dataclass
+class Hook:
+    """One hook: what runs, for which event and app, and how failures count"""
+
+    event: str
+    type: str
+    target: str  # Command, URL or script
+    app: Optional[str] = None  # None: every app of the project (not exec)
+    timeout: Optional[int] = None
+    on_failure: str = "abort"
+    method: str = "POST"
+    headers: Dict[str, str] = field(default_factory=dict)
+    source: str = "marker"  # marker | db
+    id: Optional[int] = None
+
+    @property
+    def effective_timeout(self) -> int:
+        if self.timeout:
+            return self.timeout
+        return DEFAULT_HTTP_TIMEOUT if self.type == "http" else DEFAULT_TIMEOUT
+
+    @classmethod
+    def from_value(
+        cls,
+        event: str,
+        value: Any,
+        app: Optional[str] = None,
+        source: str = "marker",
+    ) -> "Hook":
+        """
+        Parse a hook declaration: a command string or a mapping.
+
+        Raises:
+            ValueError: If the declaration is invalid
+        """
+        if event not in EVENTS:
+            raise ValueError(
+                f"unknown hook event '{event}' (events: {', '.join(EVENTS)})"
+            )
+        if isinstance(value, str):
+            value = {"exec": value}
+        if not isinstance(value, dict):
+            raise ValueError(f"{event} hook must be a command or a mapping")
+
+        types = [name for name in HOOK_TYPES if name in value]
+        if len(types) != 1:
+            raise ValueError(
+                f"{event} hook needs exactly one of: {', '.join(HOOK_TYPES)}"
+            )
+        hook_type = types[0]
+        target = str(value[hook_type] or "").strip()
+        if not target:
+            raise ValueError(f"{event} {hook_type} hook is empty")
+        if hook_type == "exec" and not app:
+            raise ValueError(f"{event} exec hook needs an app to run in")
+        if hook_type == "http" and not target.startswith(("http://", "https://")):
+            raise ValueError(f"{event} http hook needs an http(s) URL")
+
+        timeout = value.get("timeout")
+        if timeout is not None:
+            if isinstance(timeout, bool) or not isinstance(timeout, int):
+                raise ValueError(f"{event} hook timeout must be seconds")
+            if not 0 < timeout <= MAX_TIMEOUT:
+                raise ValueError(
+                    f"{event} hook timeout must be between 1 and {MAX_TIMEOUT}"
+                )
+
+        on_failure = value.get("on_failure", "abort")
+        if on_failure not in FAILURE_POLICIES:
+            raise ValueError(f"{event} hook on_failure must be abort or warn")
+
+        method = str(value.get("method", "POST")).upper()
+        if method not in HTTP_METHODS:
+            raise ValueError(f"{event} hook method must be one of {HTTP_METHODS}")
+
+        headers = value.get("headers") or {}
+        if not isinstance(headers, dict):
+            raise ValueError(f"{event} hook headers must be a mapping")
+
+        return cls(
+            event=event,
+            type=hook_type,
+            target=target,
+            app=app,
+            timeout=timeout,
+            on_failure=on_failure,
+            method=method,
+            headers={str(k): str(v) for k, v in headers.items()},
+            source=source,
+            id=value.get("id"),
+        )
+
+    def to_dict(self) -> Dict[str, Any]:
+        """Declaration form (what from_value reads), plus app and id"""
+        result: Dict[str, Any] = {self.type: self.target}
+        if self.timeout:
+            result["timeout"] = self.timeout
+        if self.on_failure != "abort":
+            result["on_failure"] = self.on_failure
+        if self.type == "http":
+            if self.method != "POST":
+                result["method"] = self.method
+            if self.headers:
+                result["headers"] = self.headers
+        if self.app:
+            result["app"] = self.app
+        if self.id is not None:
+            result["id"] = self.id
+        return result
+
+    def describe(self) -> str:
+        if self.type == "http":
+            return f"{self.method} {self.target}"
+        return self.target
+
+
+def parse_hooks(
+    data: Any, app: Optional[str] = None, source: str = "marker"
+) -> List[Hook]:
+    """
+    Hooks of a marker's `hooks:` mapping (event -> list of declarations).
+
+    Raises:
+        ValueError: If an event or declaration is invalid
+    """
+    if not data:
+        return []
+    if not isinstance(data, dict):
+        raise ValueError("hooks must map events to lists of hooks")
+    hooks = []
+    for event, values in data.items():
+        if isinstance(values, (str, dict)):
+            values = [values]
+        if not isinstance(values, list):
+            raise ValueError(f"hooks.{event} must be a list")
+        hooks.extend(Hook.from_value(event, value, app, source) for value in values)
+    return hooks
+
+
+def hooks_file_hooks(data: Any, app: Optional[str]) -> List[Hook]:
+    """
+    Hooks in a hooks.json ({"hooks": [...]}) that apply to app: its own,
+    then the project-wide ones.
+    """
+    entries = data.get("hooks", []) if isinstance(data, dict) else []
+    own, shared = [], []
+    for entry in entries:
+        entry = dict(entry)
+        event = entry.pop("event", "")
+        hook_app = entry.pop("app", None)
+        if hook_app and hook_app != app:
+            continue
+        hook = Hook.from_value(event, entry, hook_app, source="db")
+        (own if hook_app else shared).append(hook)
+    return own + shared
+
+
+def compose_exec_command(
+    app: str, command: str, env: Dict[str, str], timeout: int
+) -> str:
+    """Shell line running command in the app's container (from compose/)"""
+    env_args = " ".join(f"-e {shlex.quote(f'{k}={v}')}" for k, v in env.items())
+    web = shlex.quote(f"{app}-web")
+    prefix = shlex.quote(f"^{app}-")
+    return (
+        f"SERVICE=$(docker compose config --services | grep -x {web} | head -1); "
+        f'[ -z "$SERVICE" ] && SERVICE=$(docker compose config --services '
+        f"| grep {prefix} | head -1); "
+        f'[ -z "$SERVICE" ] && {{ echo "No compose service for {app}" >&2; exit 1; }}; '
+        f'timeout {timeout} docker compose exec -T {env_args} "$SERVICE" '
+        f"sh -c {shlex.quote(command)}"
+    )
+
+
+def local_compose_exec(project: str) -> Callable[..., Tuple[int, str]]:
+    """Exec hooks against the compose project on this machine (deploy runner)"""
+    compose_dir = f"/opt/superdeploy/projects/{project}/compose"
+
+    def run(app: str, command: str, env: Dict[str, str], timeout: int):
+        result = subprocess.run(
+            compose_exec_command(app, command, env, timeout),
+            shell=True,
+            cwd=compose_dir,
+            capture_output=True,
+            text=True,
+            timeout=timeout + 10,
+        )
+        return result.returncode, result.stdout + result.stderr
+
+    return run
+
+
+@dataclass
+class HookResult:
+    """Outcome of one hook run"""
+
+    hook: Hook
+    ok: bool
+    output: str = ""
+    error: str = ""
+    duration: float = 0.0
+
+
+class HookFailed(RuntimeError):
+    """A hook with on_failure: abort failed"""
+
+    def __init__(self, result: HookResult, results: List[HookResult]):
+        self.result = result
+        self.results = results
+        super().__init__(
+            f"{result.hook.event} hook failed: {result.hook.describe()} "
+            f"({result.error})"
+        )
+
+
+class HookRunner:
+    """
+    Runs an event's hooks in order and logs their output.
+
+    exec_in_container(app, command, env, timeout) -> (returncode, output)
+    decides where exec hooks run (local compose, or over SSH from the CLI).
+    """
+
+    def __init__(
+        self,
+        exec_in_container: Optional[Callable[..., Tuple[int, str]]] = None,
+        workdirs: Optional[Dict[Optional[str], str]] = None,
+        log: Callable[[str], None] = print,
+    ):
+        self.exec_in_container = exec_in_container
+        self.workdirs = workdirs or {}
+        self.log = log
+
+    def run(
+        self,
+        event: str,
+        hooks: List[Hook],
+        project: str,
+        context: Optional[Dict[str, str]] = None,
+    ) -> List[HookResult]:
+        """
+        Run hooks for event.
+
+        Raises:
+            HookFailed: When an abort hook fails (later hooks don't run)
+        """
+        results = []
+        for hook in [h for h in hooks if h.event == event]:
+            payload = {
+                "event": event,
+                "project": project,
+                "app": hook.app or "",
+                **(context or {}),
+            }
+            label = f"{hook.app}: " if hook.app else ""
+            self.log(f"🪝 {event} ({hook.type}) {label}{hook.describe()}")
+
+            started = time.time()
+            try:
+                ok, output, error = self._run_one(hook, payload)
+            except subprocess.TimeoutExpired:
+                ok, output, error = (
+                    False,
+                    "",
+                    f"timed out after {hook.effective_timeout}s",
+                )
+            except TimeoutError as e:
+                ok, output, error = False, "", str(e).splitlines()[0]
+            result = HookResult(hook, ok, output, error, time.time() - started)
+            results.append(result)
+
+            for line in output.strip().splitlines()[-OUTPUT_TAIL_LINES:]:
+                self.log(f"   │ {line}")
+            if ok:
+                self.log(f"   ✓ done in {result.duration:.1f}s")
+            elif hook.on_failure == "warn":
+                self.log(f"   ⚠️  failed ({error}), continuing (on_failure: warn)")
+            else:
+                self.log(f"   ❌ failed ({error})")
+                raise HookFailed(result, results)
+        return results
+
+    def _run_one(self, hook: Hook, payload: Dict[str, str]) -> Tuple[bool, str, str]:
+        env = {f"SUPERDEPLOY_{key.upper()}": str(v) for key, v in payload.items()}
+        timeout = hook.effective_timeout
+
+        if hook.type == "exec":
+            if self.exec_in_container is None:
+                return False, "", "exec hooks can't run here"
+            code, output = self.exec_in_container(hook.app, hook.target, env, timeout)
+            return code == 0, output, f"exit code {code}"
+
+        if hook.type == "script":
+            workdir = self.workdirs.get(hook.app) or self.workdirs.get(None)
+            result = subprocess.run(
+                hook.target,
+                shell=True,
+                cwd=workdir or None,
+                env={**os.environ, **env},
+                capture_output=True,
+                text=True,
+                timeout=timeout,
+            )
+            return (
+                result.returncode == 0,
+                result.stdout + result.stderr,
+                f"exit code {result.returncode}",
+            )
+
+        request = urllib.request.Request(
+            hook.target,
+            data=None if hook.method == "GET" else json.dumps(payload).encode(),
+            method=hook.method,
+            headers={"Content-Type": "application/json", **hook.headers},
+        )
+        try:
+            with urllib.request.urlopen(request, timeout=timeout) as response:
+                body = response.read(2000).decode(errors="replace")
+                return True, body, ""
+        except urllib.error.HTTPError as e:
+            return False, e.read(2000).decode(errors="replace"), f"HTTP {e.code}"
+        except (urllib.error.URLError, OSError) as e:
+            reason = getattr(e, "reason", e)
+            if "timed out" in str(reason):
+                return False, "", f"timed out after {timeout}s"
+            return False, "", str(reason)
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    import argparse
+
+    import yaml
+
+    parser = argparse.ArgumentParser(prog="run-hooks")
+    parser.add_argument("event", choices=EVENTS)
+    parser.add_argument("--project", required=True)
+    parser.add_argument("--app", required=True)
+    parser.add_argument("--marker", help="App's superdeploy marker file")
+    parser.add_argument("--hooks-file", help="Default: " + HOOKS_FILE)
+    parser.add_argument("--workdir", help="Where script hooks run")
+    parser.add_argument(
+        "--set",
+        action="append",
+        default=[],
+        metavar="KEY=VALUE",
+        help="Extra event detail (SUPERDEPLOY_<KEY>)",
+    )
+    args = parser.parse_args(argv)
+
+    try:
+        hooks = []
+        if args.marker and os.path.isfile(args.marker):
+            with open(args.marker) as f:
+                marker = yaml.safe_load(f) or {}
+            hooks += parse_hooks(marker.get("hooks"), args.app)
+
+        hooks_file = args.hooks_file or HOOKS_FILE.format(project=args.project)
+        if os.path.isfile(hooks_file):
+            with open(hooks_file) as f:
+                hooks += hooks_file_hooks(json.load(f), args.app)
+    except (OSError, ValueError, yaml.YAMLError) as e:
+        print(f"❌ Invalid hooks: {e}")
+        return 1
+
+    if not any(hook.event == args.event for hook in hooks):
+        print(f"⏭ No {args.event} hooks configured")
+        return 0
+
+    context = dict(item.split("=", 1) for item in args.set if "=" in item)
+    runner = HookRunner(
+        exec_in_container=local_compose_exec(args.project),
+        workdirs={None: args.workdir or os.getcwd()},
+        log=lambda line: print(line, flush=True),
+    )
+    try:
+        runner.run(args.event, hooks, args.project, context)
+    except HookFailed as e:
+        print(f"❌ {e}")
+        return 1
+    print(f"✅ {args.event} hooks completed")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
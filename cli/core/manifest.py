@@ -0,0 +1,326 @@
+"""
+Declarative project manifests (project.yml)
+
+A manifest describes a project the way the database stores it: project
+settings, VMs, apps with their processes, aliases, domain and edge policy,
+addons, and secrets by reference. It is flattened into resources keyed by
+(kind, name) so the desired state can be diffed against the database.
+
+Anything the manifest doesn't mention is left alone. Prune only removes
+entries missing from a collection the manifest declares: leaving out
+`addons:` never deletes addons, but an `addons:` section without
+databases.primary does.
+"""
+
+from dataclasses import dataclass, field
+from typing import Any, Dict, List, Optional, Set, Tuple
+
+import yaml
+
+API_VERSION = "superdeploy/v1"
+
+# Manifest path → projects column
+PROJECT_FIELDS = {
+    ("project", "description"): "description",
+    ("project", "environment"): "environment",
+    ("project", "domain"): "domain",
+    ("project", "ssl_email"): "ssl_email",
+    ("cloud", "gcp", "project_id"): "gcp_project",
+    ("cloud", "gcp", "region"): "gcp_region",
+    ("cloud", "gcp", "zone"): "gcp_zone",
+    ("cloud", "ssh", "key_path"): "ssh_key_path",
+    ("cloud", "ssh", "public_key_path"): "ssh_public_key_path",
+    ("cloud", "ssh", "user"): "ssh_user",
+    ("docker", "registry"): "docker_registry",
+    ("docker", "organization"): "docker_organization",
+    ("github", "organization"): "github_org",
+    ("network", "vpc_subnet"): "vpc_subnet",
+    ("network", "docker_subnet"): "docker_subnet",
+}
+
+VM_FIELDS = ("count", "machine_type", "disk_size")
+APP_FIELDS = (
+    "path",
+    "repo",
+    "owner",
+    "type",
+    "vm",
+    "port",
+    "external_port",
+    "domain",
+    "edge",
+    "anonymize",
+)
+PROCESS_FIELDS = ("command", "replicas", "port")
+ADDON_FIELDS = ("type", "version", "vm", "plan")
+
+# Creation order; deletes run in reverse
+KINDS = ("project", "vm", "addon", "app", "process", "alias", "secret")
+
+# Resource key → (prune scope, fields)
+Resources = Dict[Tuple[str, str], Tuple[str, Dict[str, Any]]]
+
+
+@dataclass
+class Change:
+    """One resource to create, update or delete"""
+
+    action: str  # create / update / delete
+    kind: str
+    name: str
+    fields: Dict[str, Tuple[Any, Any]] = field(default_factory=dict)  # → (old, new)
+
+    @property
+    def resource(self) -> str:
+        return f"{self.kind}/{self.name}"
+
+    def desired(self) -> Dict[str, Any]:
+        """New field values (what create/update writes)"""
+        return {k: new for k, (_, new) in self.fields.items()}
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "action": self.action,
+            "resource": self.resource,
+            "fields": {
+                k: {"from": old, "to": new} for k, (old, new) in self.fields.items()
+            },
+        }
+
+
+def secret_name(environment: str, app: Optional[str], key: str) -> str:
+    """production/DATABASE_URL (shared) or production/api/STRIPE_KEY"""
+    return f"{environment}/{app}/{key}" if app else f"{environment}/{key}"
+
+
+def parse_secret_name(name: str) -> Tuple[str, Optional[str], str]:
+    """Inverse of secret_name → (environment, app or None, key)"""
+    parts = name.split("/")
+    if len(parts) == 2:
+        return parts[0], None, parts[1]
+    return parts[0], parts[1], parts[2]
+
+
+def secret_entry(value: Any) -> Dict[str, Any]:
+    """Normalize `KEY: ref` / `KEY: {ref, usage}` to {"ref": ..., "usage": ...}"""
+    if isinstance(value, dict):
+        return {k: value[k] for k in ("ref", "usage") if value.get(k) is not None}
+    return {"ref": value}
+
+
+def resources(manifest: Dict[str, Any]) -> Tuple[Resources, Set[str]]:
+    """
+    Flatten a manifest into resources.
+
+    Returns:
+        (resources, declared prune scopes)
+    """
+    result: Resources = {}
+    scopes: Set[str] = set()
+
+    project = {}
+    for path, column in PROJECT_FIELDS.items():
+        value = _dig(manifest, path)
+        if value is not None:
+            project[column] = value
+    result[("project", manifest["project"]["name"])] = ("project", project)
+
+    if "vms" in manifest:
+        scopes.add("vms")
+    for role, vm in (manifest.get("vms") or {}).items():
+        result[("vm", role)] = ("vms", _pick(vm, VM_FIELDS))
+
+    if "addons" in manifest:
+        scopes.add("addons")
+    for category, instances in (manifest.get("addons") or {}).items():
+        for instance, addon in (instances or {}).items():
+            result[("addon", f"{category}.{instance}")] = (
+                "addons",
+                _pick(addon, ADDON_FIELDS),
+            )
+
+    if "apps" in manifest:
+        scopes.add("apps")
+    for app_name, app in (manifest.get("apps") or {}).items():
+        app = app or {}
+        result[("app", app_name)] = ("apps", _pick(app, APP_FIELDS))
+
+        if "processes" in app:
+            scopes.add(f"processes:{app_name}")
+        for proc_name, proc in (app.get("processes") or {}).items():
+            result[("process", f"{app_name}.{proc_name}")] = (
+                f"processes:{app_name}",
+                _pick(proc, PROCESS_FIELDS),
+            )
+
+        if "aliases" in app:
+            scopes.add(f"aliases:{app_name}")
+        for alias_key, target in (app.get("aliases") or {}).items():
+            result[("alias", f"{app_name}.{alias_key}")] = (
+                f"aliases:{app_name}",
+                {"target": target},
+            )
+
+    for environment, scoped in (manifest.get("secrets") or {}).items():
+        scopes.add(f"secrets:{environment}")
+        scoped = scoped or {}
+        for key, value in (scoped.get("shared") or {}).items():
+            result[("secret", secret_name(environment, None, key))] = (
+                f"secrets:{environment}",
+                secret_entry(value),
+            )
+        for app_name, keys in (scoped.get("apps") or {}).items():
+            for key, value in (keys or {}).items():
+                result[("secret", secret_name(environment, app_name, key))] = (
+                    f"secrets:{environment}",
+                    secret_entry(value),
+                )
+
+    return result, scopes
+
+
+def diff(
+    current: Optional[Dict[str, Any]], desired: Dict[str, Any], prune: bool = False
+) -> List[Change]:
+    """
+    Compute the changes that turn current into desired.
+
+    Args:
+        current: Manifest exported from the database (None if project is new)
+        desired: Manifest from project.yml
+        prune: Delete resources missing from declared collections
+
+    Returns:
+        Changes in apply order (creates/updates by kind, then deletes)
+    """
+    have, _ = resources(current) if current else ({}, set())
+    want, scopes = resources(desired)
+    for (kind, _), (_, fields) in have.items():
+        if kind == "secret":
+            fields.setdefault("usage", "runtime")  # Export omits the default
+
+    changes: List[Change] = []
+    for (kind, name), (_, fields) in want.items():
+        if (kind, name) not in have:
+            changes.append(
+                Change("create", kind, name, {k: (None, v) for k, v in fields.items()})
+            )
+            continue
+        old = have[(kind, name)][1]
+        updated = {k: (old.get(k), v) for k, v in fields.items() if old.get(k) != v}
+        if updated:
+            changes.append(Change("update", kind, name, updated))
+
+    if prune:
+        deleted_apps = {
+            name
+            for (kind, name), (scope, _) in have.items()
+            if kind == "app" and scope in scopes and (kind, name) not in want
+        }
+        for (kind, name), (scope, fields) in have.items():
+            if scope not in scopes or (kind, name) in want:
+                continue
+            if _owner_app(kind, name) in deleted_apps:
+                continue  # Removed with the app (ON DELETE CASCADE)
+            changes.append(
+                Change("delete", kind, name, {k: (v, None) for k, v in fields.items()})
+            )
+
+    def order(change: Change) -> Tuple[int, int, str]:
+        rank = KINDS.index(change.kind)
+        if change.action == "delete":
+            return (1, len(KINDS) - rank, change.name)
+        return (0, rank, change.name)
+
+    return sorted(changes, key=order)
+
+
+def rollout_commands(project_name: str, changes: List[Change]) -> List[str]:
+    """
+    Commands that roll applied changes out (apply only writes the database).
+
+    Returns:
+        CLI invocations without the `superdeploy` prefix, in run order
+    """
+    kinds = {c.kind for c in changes}
+    steps = []
+    if kinds & {"vm", "addon"}:
+        steps.append(f"{project_name}:up")
+    elif kinds & {"app", "process"}:
+        # Re-renders app compose files and the Caddyfile (domains, edge)
+        steps.append(f"{project_name}:up --skip-terraform")
+    if any(c.kind == "app" and c.action == "create" for c in changes):
+        steps.append(f"{project_name}:generate")
+    if kinds & {"secret", "alias"}:
+        steps.append(f"{project_name}:config:push")
+    return steps
+
+
+def cross_check(manifest: Dict[str, Any]) -> List[str]:
+    """
+    References between sections the schema can't express.
+
+    Only checked when both sides are in the manifest; the database fills
+    in anything the manifest leaves out.
+    """
+    problems = []
+    vms = manifest.get("vms")
+    apps = manifest.get("apps")
+
+    if vms is not None:
+        for app_name, app in (apps or {}).items():
+            vm = (app or {}).get("vm")
+            if vm and vm not in vms:
+                problems.append(f"apps.{app_name}.vm: VM '{vm}' is not in vms")
+        for category, instances in (manifest.get("addons") or {}).items():
+            for instance, addon in (instances or {}).items():
+                vm = (addon or {}).get("vm")
+                if vm and vm not in vms:
+                    problems.append(
+                        f"addons.{category}.{instance}.vm: VM '{vm}' is not in vms"
+                    )
+
+    if apps is not None:
+        for environment, scoped in (manifest.get("secrets") or {}).items():
+            for app_name in ((scoped or {}).get("apps") or {}):
+                if app_name not in apps:
+                    problems.append(
+                        f"secrets.{environment}.apps.{app_name}: app is not in apps"
+                    )
+
+    return problems
+
+
+def dump(manifest: Dict[str, Any], header: Optional[str] = None) -> str:
+    """Render a manifest as YAML (keys in manifest order)"""
+    text = yaml.safe_dump(
+        manifest, sort_keys=False, default_flow_style=False, allow_unicode=True
+    )
+    if header:
+        comment = "".join(
+            f"# {line}\n" if line else "#\n" for line in header.split("\n")
+        )
+        text = comment + "\n" + text
+    return text
+
+
+def _dig(data: Dict[str, Any], path: Tuple[str, ...]) -> Any:
+    for part in path:
+        if not isinstance(data, dict):
+            return None
+        data = data.get(part)
+    return data
+
+
+def _pick(data: Optional[Dict[str, Any]], keys: Tuple[str, ...]) -> Dict[str, Any]:
+    data = data or {}
+    return {k: data[k] for k in keys if data.get(k) is not None}
+
+
+def _owner_app(kind: str, name: str) -> Optional[str]:
+    """App a process/alias/app secret belongs to"""
+    if kind in ("process", "alias"):
+        return name.split(".", 1)[0]
+    if kind == "secret":
+        return parse_secret_name(name)[1]
+    return None
@@ -189,6 +189,47 @@ class OrchestratorConfig:
         """Get network configuration"""
         return self.config.get("network", {})
 
+    def get_reconciler_config(self) -> Dict[str, Any]:
+        """GitOps reconciler settings (RECONCILER_* orchestrator secrets)"""
+        secrets = self.get_secrets()
+        return {
+            "enabled": secrets.get("RECONCILER_ENABLED") == "true",
+            "repo": secrets.get("RECONCILER_REPO", ""),
+            "branch": secrets.get("RECONCILER_BRANCH") or "main",
+            "path": secrets.get("RECONCILER_PATH") or ".",
+            "interval": int(secrets.get("RECONCILER_INTERVAL") or 60),
+            "prune": secrets.get("RECONCILER_PRUNE") == "true",
+            "dry_run": secrets.get("RECONCILER_DRY_RUN") == "true",
+            "db_url": secrets.get("RECONCILER_DB_URL", ""),
+            # Copied to the orchestrator for SSH to project VMs and git
+            "ssh_key_path": str(Path(self.config["ssh"]["key_path"]).expanduser()),
+        }
+
+    def get_backup_config(self) -> Dict[str, Any]:
+        """Scheduled control plane backups (BACKUP_* orchestrator secrets)"""
+        from cli.constants import CONTROL_PLANE_BACKUP_KEEP
+
+        secrets = self.get_secrets()
+        return {
+            "enabled": secrets.get("BACKUP_ENABLED") == "true",
+            "target": secrets.get("BACKUP_TARGET", ""),
+            "schedule": secrets.get("BACKUP_SCHEDULE") or "daily",
+            "keep": int(secrets.get("BACKUP_KEEP") or CONTROL_PLANE_BACKUP_KEEP),
+            "db_url": secrets.get("BACKUP_DB_URL", ""),
+            "passphrase": secrets.get("BACKUP_PASSPHRASE", ""),
+        }
+
+    def get_deploy_gate_config(self) -> Dict[str, Any]:
+        """Deploy policy gate for deploy jobs (DEPLOY_GATE_* orchestrator secrets)"""
+        from cli.constants import DEPLOY_GATE_PORT
+
+        secrets = self.get_secrets()
+        return {
+            "enabled": secrets.get("DEPLOY_GATE_ENABLED") == "true",
+            "db_url": secrets.get("DEPLOY_GATE_DB_URL", ""),
+            "port": DEPLOY_GATE_PORT,
+        }
+
     def to_terraform_vars(
         self, project_id: str, ssh_pub_key_path: str
     ) -> Dict[str, Any]:
@@ -294,6 +335,9 @@ class OrchestratorConfig:
             "addons": addons_config,  # Also pass at top-level for Ansible compatibility
             "enabled_addons": enabled_addons,
             "addon_configs": addon_configs,
+            "reconciler": self.get_reconciler_config(),
+            "control_plane_backup": self.get_backup_config(),
+            "deploy_gate": self.get_deploy_gate_config(),
         }
 
 
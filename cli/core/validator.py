@@ -753,7 +753,10 @@ class ValidationEngine:
         errors = []
 
         for addon_name, addon in addons.items():
-            conflicts = addon.get_conflicts()
+            # Version-specific conflicts depend on instance versions (AddonGraph)
+            conflicts = [
+                c.name for c in addon.get_conflict_requirements() if not c.constrained
+            ]
 
             for conflicting_addon in conflicts:
                 if conflicting_addon in addons:
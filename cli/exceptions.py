@@ -101,3 +101,22 @@ class ProjectNotDeployedError(DeploymentError):
         message = f"Project '{project_name}' is not deployed"
         context = f"Run: superdeploy {project_name}:up"
         super().__init__(message, context)
+
+
+class SecretBackendError(SecretError):
+    """Raised when an external secret reference (vault://, sops://) can't be resolved."""
+
+    def __init__(self, reference: str, message: str, context: Optional[str] = None):
+        self.reference = reference
+        super().__init__(f"{reference}: {message}", context)
+
+
+class ConfigContractError(ValidationError):
+    """Raised when an app's env violates the config contract in its marker."""
+
+    def __init__(self, app_name: str, violations: list):
+        self.app_name = app_name
+        self.violations = violations
+        message = f"App '{app_name}' config contract violated ({len(violations)} key(s))"
+        context = "\n".join(f"• {v}" for v in violations)
+        super().__init__(message, context)
@@ -86,6 +86,7 @@ from cli.commands import (
     tunnel,
 )
 from cli.commands.ps import ps
+from cli.commands.dev import dev
 
 # NOTE: up, down, plan are imported dynamically in NamespacedGroup (not registered as standalone commands)
 from cli.commands.domains import domains_add, domains_list, domains_remove
@@ -95,6 +96,53 @@ from cli.commands.config import (
     config_list,
     config_unset,
     config_show,
+    config_push,
+    config_refs,
+    config_render,
+    config_grant,
+    config_revoke,
+    config_grants,
+)
+from cli.commands.org import org_set, org_unset, org_list
+from cli.commands.files import files_set, files_unset, files_list
+from cli.commands.manifest import apply, diff, export
+from cli.commands.import_compose import import_compose
+from cli.commands.blueprints import blueprints
+from cli.commands.clone import projects_clone
+from cli.commands.protection import (
+    protect,
+    unprotect,
+    protection,
+    projects_deleted,
+    projects_undelete,
+)
+from cli.commands.recover import projects_recover
+from cli.commands.deploys import (
+    deploys_policy,
+    deploys_policy_set,
+    deploys_freeze,
+    deploys_unfreeze,
+    deploys_approve,
+    deploys_override,
+    deploys_queue,
+    deploy_gate_run,
+    orchestrator_deploy_gate_enable,
+    orchestrator_deploy_gate_disable,
+)
+from cli.commands.hooks import (
+    hooks_list,
+    hooks_add,
+    hooks_remove,
+    hooks_push,
+    hooks_run,
+)
+from cli.commands.reconciler import (
+    reconciler_run,
+    orchestrator_reconciler_enable,
+    orchestrator_reconciler_disable,
+    orchestrator_reconciler_status,
+    orchestrator_reconciler_pause,
+    orchestrator_reconciler_resume,
 )
 from cli.commands.env import env_list, env_check
 from cli.commands.releases import releases_list
@@ -105,6 +153,10 @@ from cli.commands.orchestrator import (
     orchestrator_up,
     orchestrator_down,
     orchestrator_status,
+    orchestrator_backup,
+    orchestrator_restore,
+    orchestrator_backup_schedule,
+    orchestrator_backup_unschedule,
 )
 from cli.commands.dashboard import dashboard_start
 from cli.commands.project import projects_deploy
@@ -117,6 +169,8 @@ from cli.commands.addons import (
     addons_remove,
     addons_attach,
     addons_detach,
+    addons_test,
+    addons_copy,
 )
 from cli.commands.vars import vars_clear, vars_sync
 from cli.commands.migrate import migrate
@@ -375,6 +429,10 @@ def cli(ctx: click.Context) -> None:
       superdeploy <project>:domains:add app.com # Add domain
       superdeploy <project>:ps              # View app processes & replicas
       superdeploy <project>:scale web=3     # Scale app replicas
+      superdeploy <project>:dev -a api      # Run app + addons locally
+      superdeploy <project>:deploys:policy  # Freeze windows & approvals
+      superdeploy <project>:deploys:queue   # Deploys running/waiting per VM
+      superdeploy <project>:hooks           # Lifecycle hooks
       superdeploy <project>:addons          # List addons
       superdeploy <project>:addons:add postgres --name primary # Add addon
       superdeploy <project>:addons:attach databases.primary --app api # Attach addon
@@ -388,12 +446,16 @@ def cli(ctx: click.Context) -> None:
 
 # Register commands
 cli.add_command(init.init)
+# Register blueprint listing (global; init --blueprint creates a project from one)
+cli.add_command(blueprints)
 # NOTE: Project-specific commands use namespaced syntax: <project>:command
 # Examples: cheapa:up, cheapa:down, cheapa:plan, cheapa:validate, cheapa:status, cheapa:metrics, etc.
 # NOTE: logs command moved to namespaced syntax: <project>:logs
 cli.add_command(run_cmd.run)
 cli.add_command(deploy.deploy)
 cli.add_command(restart.restart)
+# Register local development (namespaced: <project>:dev -a api)
+cli.add_command(dev)
 cli.add_command(doctor.doctor)
 # Register config commands (Heroku-style with colons)
 cli.add_command(config_set)
@@ -401,6 +463,20 @@ cli.add_command(config_get)
 cli.add_command(config_list)
 cli.add_command(config_unset)
 cli.add_command(config_show)
+cli.add_command(config_push)
+cli.add_command(config_refs)
+cli.add_command(config_render)
+cli.add_command(config_grant)
+cli.add_command(config_revoke)
+cli.add_command(config_grants)
+# Register secret file commands (Heroku-style with colons)
+cli.add_command(files_set)
+cli.add_command(files_unset)
+cli.add_command(files_list)
+# Register org secret commands (global, not namespaced)
+cli.add_command(org_set)
+cli.add_command(org_unset)
+cli.add_command(org_list)
 # Register env commands (Heroku-style with colons)
 cli.add_command(env_list)
 cli.add_command(env_check)
@@ -409,6 +485,10 @@ cli.add_command(releases_list)
 cli.add_command(releases_switch)
 # Register project commands (Heroku-style with colons)
 cli.add_command(projects_deploy)
+cli.add_command(projects_clone)
+cli.add_command(projects_deleted)
+cli.add_command(projects_undelete)
+cli.add_command(projects_recover)
 cli.add_command(promote.promote)
 # Register domains commands (Heroku-style with colons)
 cli.add_command(domains_add)
@@ -422,8 +502,28 @@ cli.add_command(addons_add)
 cli.add_command(addons_remove)
 cli.add_command(addons_attach)
 cli.add_command(addons_detach)
+cli.add_command(addons_test)
+cli.add_command(addons_copy)
 # Register backup commands (Heroku-style with colons)
 cli.add_command(backups_create)
+# Register deletion protection commands (<project>:protect)
+cli.add_command(protect)
+cli.add_command(unprotect)
+cli.add_command(protection)
+# Register deploy policy commands (<project>:deploys:freeze, ...)
+cli.add_command(deploys_policy)
+cli.add_command(deploys_policy_set)
+cli.add_command(deploys_freeze)
+cli.add_command(deploys_unfreeze)
+cli.add_command(deploys_approve)
+cli.add_command(deploys_override)
+cli.add_command(deploys_queue)
+# Register lifecycle hook commands (<project>:hooks:add, ...)
+cli.add_command(hooks_list)
+cli.add_command(hooks_add)
+cli.add_command(hooks_remove)
+cli.add_command(hooks_push)
+cli.add_command(hooks_run)
 # NOTE: validate:project moved to <project>:validate (namespaced)
 cli.add_command(validate_addons)
 # NOTE: metrics moved to <project>:metrics (namespaced)
@@ -432,6 +532,10 @@ cli.add_command(orchestrator_init)
 cli.add_command(orchestrator_up)
 cli.add_command(orchestrator_down)
 cli.add_command(orchestrator_status)
+cli.add_command(orchestrator_backup)
+cli.add_command(orchestrator_restore)
+cli.add_command(orchestrator_backup_schedule)
+cli.add_command(orchestrator_backup_unschedule)
 cli.add_command(subnets.subnets)
 cli.add_command(tunnel.tunnel)
 # Register vars commands (GitHub secrets & variables management)
@@ -447,6 +551,23 @@ from cli.commands.sync import sync_export, sync_import
 
 cli.add_command(sync_export)
 cli.add_command(sync_import)
+# Register manifest commands (apply/diff are global, export is namespaced)
+cli.add_command(apply)
+cli.add_command(diff)
+cli.add_command(export)
+# Register docker-compose import (namespaced: <project>:import:compose)
+cli.add_command(import_compose)
+# Register GitOps reconciler commands (run is what the orchestrator service executes)
+cli.add_command(reconciler_run)
+cli.add_command(orchestrator_reconciler_enable)
+cli.add_command(orchestrator_reconciler_disable)
+cli.add_command(orchestrator_reconciler_status)
+cli.add_command(orchestrator_reconciler_pause)
+cli.add_command(orchestrator_reconciler_resume)
+# Register deploy gate commands (run is what the orchestrator service executes)
+cli.add_command(deploy_gate_run)
+cli.add_command(orchestrator_deploy_gate_enable)
+cli.add_command(orchestrator_deploy_gate_disable)
 # Register GCP commands
 cli.add_command(gcp_group)
 
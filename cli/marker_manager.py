@@ -5,13 +5,19 @@ Manages superdeploy marker files for app tracking and identification.
 
 The marker file supports Heroku Procfile-like process definitions with a clean,
 minimal syntax. Processes can use Dockerfile commands by default or override them.
+
+Apps without a marker can ship a Heroku Procfile and/or app.json instead
+(see cli.core.heroku); load_marker reads them as an equivalent marker.
 """
 
 import yaml
 from pathlib import Path
 from dataclasses import dataclass, field
-from typing import Optional, Dict, Any
+from typing import Optional, Dict, Any, List
 
+from cli.core.config_contract import ConfigContract
+from cli.core.healthcheck import process_probes
+from cli.core.hooks import parse_hooks
 from cli.exceptions import ConfigurationError
 
 
@@ -28,6 +34,9 @@ class ProcessDefinition:
     replicas: int = 1
     run_on: Optional[str] = None  # e.g., "deploy" for release commands
     env: Optional[Dict[str, str]] = None  # Process-specific env vars
+    # Probes (see cli.core.healthcheck): {"http": "/healthz", "interval": "10s"}
+    healthcheck: Optional[Dict[str, Any]] = None
+    readiness: Optional[Dict[str, Any]] = None
 
     def to_dict(self) -> dict:
         """Convert to dictionary for serialization."""
@@ -43,6 +52,10 @@ class ProcessDefinition:
             result["run_on"] = self.run_on
         if self.env:
             result["env"] = self.env
+        if self.healthcheck:
+            result["healthcheck"] = self.healthcheck
+        if self.readiness:
+            result["readiness"] = self.readiness
 
         return result
 
@@ -55,6 +68,8 @@ class ProcessDefinition:
             replicas=data.get("replicas", 1),
             run_on=data.get("run_on"),
             env=data.get("env"),
+            healthcheck=data.get("healthcheck"),
+            readiness=data.get("readiness"),
         )
 
 
@@ -70,6 +85,34 @@ class AppMarker:
         env_templates:
           NEXT_PUBLIC_API_URL: "http://{{ APP_0_EXTERNAL_IP }}:8000"
           NEXT_PUBLIC_WS_URL: "ws://{{ APP_0_EXTERNAL_IP }}:8000/ws"
+
+    build_env lists env_templates keys the image build also needs (stored
+    secrets carry their own runtime/build/both usage):
+        build_env: [NEXT_PUBLIC_API_URL, NEXT_PUBLIC_WS_URL]
+
+    config declares the keys the app needs (see cli.core.config_contract):
+        config:
+          required:
+            STRIPE_KEY: {pattern: "^sk_", description: Stripe secret key}
+          optional:
+            LOG_LEVEL: {type: enum, values: [debug, info]}
+
+    processes can declare healthcheck and readiness probes (see
+    cli.core.healthcheck):
+        processes:
+          web:
+            healthcheck: {http: /healthz, interval: 30s, start_period: 40s}
+            readiness: {http: /ready, status: [200, 204]}
+
+    hooks run on lifecycle events (see cli.core.hooks):
+        hooks:
+          after_deploy:
+            - python manage.py collectstatic --noinput
+            - {http: "https://hooks.example.com/deployed", on_failure: warn}
+
+    addons are attachments generate creates (aliases <AS>_HOST, ..., <AS>_URL):
+        addons:
+          - {type: postgres, as: DATABASE}
     """
 
     project: str
@@ -82,6 +125,18 @@ class AppMarker:
     # Environment variable templates with {{ PLACEHOLDER }} syntax
     env_templates: Dict[str, str] = field(default_factory=dict)
 
+    # env_templates keys passed to the image build as well
+    build_env: List[str] = field(default_factory=list)
+
+    # Required/optional config keys, checked before deploys
+    config: ConfigContract = field(default_factory=ConfigContract)
+
+    # Hooks per lifecycle event (before_deploy, after_deploy, ...)
+    hooks: Dict[str, List[Any]] = field(default_factory=dict)
+
+    # Addon attachments: [{"type": "postgres", "as": "DATABASE"}]
+    addons: List[Dict[str, str]] = field(default_factory=list)
+
     def to_dict(self) -> dict:
         """
         Convert to dictionary with clean, minimal syntax.
@@ -95,11 +150,16 @@ class AppMarker:
                 command: python craft serve --host 0.0.0.0 --port 8000
                 port: 8000
                 replicas: 2
+                readiness:
+                  http: /health
               worker:
                 command: python craft queue:work --tries=3
                 replicas: 3
             env_templates:
               NEXT_PUBLIC_API_URL: "http://{{ APP_0_EXTERNAL_IP }}:8000"
+            config:
+              required:
+                STRIPE_KEY: {type: string}
         """
         result = {
             "project": self.project,
@@ -117,6 +177,19 @@ class AppMarker:
         if self.env_templates:
             result["env_templates"] = self.env_templates
 
+        if self.build_env:
+            result["build_env"] = self.build_env
+
+        # Add config contract if present
+        if self.config:
+            result["config"] = self.config.to_dict()
+
+        if self.hooks:
+            result["hooks"] = self.hooks
+
+        if self.addons:
+            result["addons"] = self.addons
+
         return result
 
     @classmethod
@@ -133,6 +206,8 @@ class AppMarker:
         if isinstance(processes_data, dict):
             for name, proc_config in processes_data.items():
                 if isinstance(proc_config, dict):
+                    # Healthcheck/readiness blocks (ValueError if invalid)
+                    process_probes(proc_config, name)
                     processes[name] = ProcessDefinition.from_dict(proc_config)
 
         # Parse env_templates
@@ -140,12 +215,34 @@ class AppMarker:
         if not isinstance(env_templates, dict):
             env_templates = {}
 
+        build_env = data.get("build_env", [])
+        if not isinstance(build_env, list):
+            build_env = []
+
+        # Parse config contract (ValueError on malformed declarations)
+        config = ConfigContract.from_dict(data.get("config"))
+
+        # Lifecycle hooks (ValueError on unknown events or bad declarations)
+        hooks = data.get("hooks") or {}
+        parse_hooks(hooks, app or "app")
+
+        addons = data.get("addons", [])
+        if not isinstance(addons, list):
+            addons = []
+
         return cls(
             project=project,
             app=app,
             vm=vm,
             processes=processes,
             env_templates=env_templates,
+            build_env=[str(key) for key in build_env],
+            config=config,
+            hooks={
+                event: commands if isinstance(commands, list) else [commands]
+                for event, commands in hooks.items()
+            },
+            addons=[a for a in addons if isinstance(a, dict) and a.get("type")],
         )
 
     def has_processes(self) -> bool:
@@ -156,6 +253,10 @@ class AppMarker:
         """Check if marker has env_templates."""
         return bool(self.env_templates)
 
+    def has_config_contract(self) -> bool:
+        """Check if marker declares required/optional config keys."""
+        return bool(self.config)
+
     def get_process(self, name: str) -> Optional[ProcessDefinition]:
         """Get a specific process definition."""
         return self.processes.get(name)
@@ -233,6 +334,19 @@ class MarkerManager:
             env_templates=env_templates or {},
         )
 
+        return MarkerManager.save_marker(app_path, marker)
+
+    @staticmethod
+    def save_marker(app_path: Path, marker: AppMarker) -> Path:
+        """
+        Write a marker to the app directory.
+
+        Returns:
+            Path to the marker file
+
+        Raises:
+            ConfigurationError: If the file can't be written
+        """
         marker_file = app_path / MarkerManager.MARKER_FILENAME
 
         try:
@@ -248,12 +362,25 @@ class MarkerManager:
             )
 
     @staticmethod
-    def load_marker(marker_path: Path) -> Optional[AppMarker]:
+    def load_marker(
+        marker_path: Path,
+        project: str = "",
+        app: str = "",
+        vm: str = "app",
+        port: Optional[int] = None,
+    ) -> Optional[AppMarker]:
         """
         Load superdeploy marker from path.
 
+        An app directory without a marker falls back to its Procfile and
+        app.json; project/app/vm/port fill in what those files don't say.
+
         Args:
             marker_path: Path to marker file or app directory
+            project: Project name (Procfile/app.json fallback)
+            app: App name (Procfile/app.json fallback)
+            vm: VM role (Procfile/app.json fallback)
+            port: App port, replaces $PORT (Procfile/app.json fallback)
 
         Returns:
             AppMarker object if file exists, None otherwise
@@ -264,6 +391,10 @@ class MarkerManager:
         # If path is a directory, look for marker file inside
         if marker_path.is_dir():
             marker_file = marker_path / MarkerManager.MARKER_FILENAME
+            if not marker_file.exists():
+                return MarkerManager.load_heroku_marker(
+                    marker_path, project, app, vm, port
+                )
         else:
             marker_file = marker_path
 
@@ -280,6 +411,34 @@ class MarkerManager:
                 context=f"Path: {marker_file}, Error: {str(e)}",
             )
 
+    @staticmethod
+    def load_heroku_marker(
+        app_path: Path,
+        project: str = "",
+        app: str = "",
+        vm: str = "app",
+        port: Optional[int] = None,
+    ) -> Optional[AppMarker]:
+        """
+        Marker equivalent of an app's Procfile and app.json.
+
+        Returns:
+            AppMarker, or None if the app has neither file
+
+        Raises:
+            ConfigurationError: If a file is invalid
+        """
+        from cli.core.heroku import build_marker
+
+        try:
+            data, _ = build_marker(app_path, project, app, vm, port)
+            return AppMarker.from_dict(data) if data is not None else None
+        except ValueError as e:
+            raise ConfigurationError(
+                "Failed to load Procfile/app.json",
+                context=f"Path: {app_path}, Error: {str(e)}",
+            )
+
     @staticmethod
     def has_marker(app_path: Path) -> bool:
         """
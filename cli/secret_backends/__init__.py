@@ -0,0 +1,14 @@
+"""External secret backends (Vault, SOPS) behind SecretManager"""
+
+from .base import SecretBackend, SecretReference
+from .resolver import SecretResolver
+from .sops import SopsBackend
+from .vault import VaultBackend
+
+__all__ = [
+    "SecretBackend",
+    "SecretReference",
+    "SecretResolver",
+    "SopsBackend",
+    "VaultBackend",
+]
@@ -0,0 +1,106 @@
+"""
+Secret backend interface
+
+A secret value stored as a reference (vault://kv/data/api#STRIPE_KEY) is
+fetched from an external backend at sync/render time instead of being kept
+in the SuperDeploy database.
+"""
+
+import re
+import time
+from abc import ABC, abstractmethod
+from dataclasses import dataclass
+from typing import Any, Dict, Optional, Tuple
+
+from cli.exceptions import SecretBackendError
+
+REFERENCE_PATTERN = re.compile(
+    r"^(?P<scheme>[a-z][a-z0-9+.-]*)://(?P<path>[^#\s]+)#(?P<key>\S+)$"
+)
+
+
+@dataclass(frozen=True)
+class SecretReference:
+    """Parsed <scheme>://<path>#<key> reference"""
+
+    scheme: str
+    path: str
+    key: str
+
+    @classmethod
+    def parse(cls, value: Any) -> Optional["SecretReference"]:
+        """Return a reference, or None if value is a plain secret"""
+        if not isinstance(value, str):
+            return None
+        match = REFERENCE_PATTERN.match(value.strip())
+        if not match:
+            return None
+        return cls(match["scheme"], match["path"], match["key"])
+
+    def __str__(self) -> str:
+        return f"{self.scheme}://{self.path}#{self.key}"
+
+
+class SecretBackend(ABC):
+    """
+    Base class for external secret backends.
+
+    Subclasses fetch a whole document (Vault path, SOPS file) as a dict;
+    documents are cached for cache_ttl seconds so a sync touching many keys
+    of the same path hits the backend once.
+    """
+
+    scheme: str = ""
+
+    def __init__(self, cache_ttl: float = 300):
+        self.cache_ttl = cache_ttl
+        self._cache: Dict[str, Tuple[float, Dict[str, Any]]] = {}
+
+    @abstractmethod
+    def fetch_document(self, path: str) -> Dict[str, Any]:
+        """
+        Load all keys stored at path.
+
+        Raises:
+            SecretBackendError: If the backend is unreachable or denies access
+        """
+
+    def cache_key(self, path: str) -> str:
+        return path
+
+    def document(self, path: str) -> Dict[str, Any]:
+        key = self.cache_key(path)
+        cached = self._cache.get(key)
+        if cached and cached[0] > time.monotonic():
+            return cached[1]
+
+        data = self.fetch_document(path)
+        self._cache[key] = (time.monotonic() + self.cache_ttl, data)
+        return data
+
+    def get(self, reference: SecretReference) -> str:
+        """Resolve one reference to its value"""
+        data = self.document(reference.path)
+
+        value = data.get(reference.key)
+        if value is None and "." in reference.key:
+            # Nested documents: sops://secrets/prod.yaml#database.password
+            value = data
+            for part in reference.key.split("."):
+                value = value.get(part) if isinstance(value, dict) else None
+
+        if value is None:
+            available = ", ".join(sorted(data)) or "none"
+            raise SecretBackendError(
+                str(reference),
+                f"key '{reference.key}' not found",
+                context=f"Keys at {reference.path}: {available}",
+            )
+        if isinstance(value, (dict, list)):
+            raise SecretBackendError(
+                str(reference), f"key '{reference.key}' is not a scalar value"
+            )
+        return str(value)
+
+    def clear_cache(self) -> None:
+        self._cache.clear()
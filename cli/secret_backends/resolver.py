@@ -0,0 +1,90 @@
+"""Resolves secret references through the registered backends"""
+
+import os
+from typing import Dict, List, Optional
+
+from cli.exceptions import SecretBackendError
+from .base import SecretBackend, SecretReference
+from .sops import SopsBackend
+from .vault import VaultBackend
+
+
+class SecretResolver:
+    """
+    Maps reference schemes to backends.
+
+    Plain values pass through untouched; backends are created on first use
+    so projects without references never need Vault or sops configured.
+    """
+
+    def __init__(self, backends: Optional[Dict[str, SecretBackend]] = None):
+        cache_ttl = float(os.getenv("SUPERDEPLOY_SECRET_CACHE_TTL", "300"))
+        self._factories = {
+            "vault": lambda: VaultBackend(cache_ttl=cache_ttl),
+            "sops": lambda: SopsBackend(cache_ttl=cache_ttl),
+        }
+        self.backends: Dict[str, SecretBackend] = dict(backends or {})
+
+    @property
+    def schemes(self) -> List[str]:
+        return sorted(set(self._factories) | set(self.backends))
+
+    def backend(self, scheme: str) -> Optional[SecretBackend]:
+        if scheme not in self.backends and scheme in self._factories:
+            self.backends[scheme] = self._factories[scheme]()
+        return self.backends.get(scheme)
+
+    def is_reference(self, value) -> bool:
+        reference = SecretReference.parse(value)
+        return reference is not None and reference.scheme in self.schemes
+
+    def resolve(self, value, key: Optional[str] = None):
+        """
+        Resolve a single value.
+
+        Raises:
+            SecretBackendError: If value is a reference that can't be resolved
+        """
+        reference = SecretReference.parse(value)
+        if reference is None:
+            return value
+
+        backend = self.backend(reference.scheme)
+        if backend is None:
+            # Not one of ours (e.g. a postgres:// URL with a fragment)
+            return value
+
+        try:
+            return backend.get(reference)
+        except SecretBackendError as e:
+            if key:
+                e.message = f"{key} → {e.message}"
+                e.args = (e.format_message(),)
+            raise
+
+    def resolve_dict(self, secrets: Dict[str, str]) -> Dict[str, str]:
+        """
+        Resolve every reference in a flat dict.
+
+        All failures are collected so one run reports every broken reference.
+
+        Raises:
+            SecretBackendError: Combined error listing each failed key
+        """
+        resolved = {}
+        errors = []
+        for key, value in secrets.items():
+            try:
+                resolved[key] = self.resolve(value, key=key)
+            except SecretBackendError as e:
+                errors.append(e)
+
+        if len(errors) == 1:
+            raise errors[0]
+        if errors:
+            raise SecretBackendError(
+                ", ".join(e.reference for e in errors),
+                f"{len(errors)} secret references could not be resolved",
+                context="\n".join(e.format_message() for e in errors),
+            )
+        return resolved
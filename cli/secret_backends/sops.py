@@ -0,0 +1,84 @@
+"""SOPS backend (sops://<file>#KEY)"""
+
+import json
+import os
+import subprocess
+from pathlib import Path
+from typing import Any, Dict, Optional
+
+from cli.exceptions import SecretBackendError
+from .base import SecretBackend
+
+
+class SopsBackend(SecretBackend):
+    """
+    Decrypts SOPS files with the sops binary.
+
+    Relative paths are resolved against SUPERDEPLOY_SOPS_ROOT (typically a
+    checkout of the secrets repo), falling back to the current directory.
+    Decryption keys (age, KMS, PGP) come from sops' own environment.
+    """
+
+    scheme = "sops"
+
+    def __init__(
+        self, root: Optional[Path] = None, timeout: float = 30, cache_ttl: float = 300
+    ):
+        super().__init__(cache_ttl=cache_ttl)
+        self.root = Path(root or os.getenv("SUPERDEPLOY_SOPS_ROOT") or Path.cwd())
+        self.timeout = timeout
+
+    def resolve_path(self, path: str) -> Path:
+        file_path = Path(path).expanduser()
+        if not file_path.is_absolute():
+            file_path = self.root.expanduser() / file_path
+        return file_path
+
+    def cache_key(self, path: str) -> str:
+        # Re-decrypt when the file changes (git pull in the secrets repo)
+        file_path = self.resolve_path(path)
+        mtime = file_path.stat().st_mtime if file_path.exists() else 0
+        return f"{file_path}:{mtime}"
+
+    def fetch_document(self, path: str) -> Dict[str, Any]:
+        reference = f"sops://{path}"
+        file_path = self.resolve_path(path)
+        if not file_path.exists():
+            raise SecretBackendError(
+                reference,
+                f"file not found: {file_path}",
+                context="Set SUPERDEPLOY_SOPS_ROOT to the secrets repo checkout",
+            )
+
+        try:
+            result = subprocess.run(
+                ["sops", "--decrypt", "--output-type", "json", str(file_path)],
+                capture_output=True,
+                text=True,
+                timeout=self.timeout,
+            )
+        except FileNotFoundError:
+            raise SecretBackendError(
+                reference,
+                "sops binary not found",
+                context="Install: https://github.com/getsops/sops",
+            )
+        except subprocess.TimeoutExpired:
+            raise SecretBackendError(reference, f"sops timed out after {self.timeout}s")
+
+        if result.returncode != 0:
+            stderr = result.stderr.strip().splitlines()
+            raise SecretBackendError(
+                reference,
+                "sops could not decrypt the file",
+                context=stderr[-1] if stderr else None,
+            )
+
+        try:
+            data = json.loads(result.stdout or "{}")
+        except json.JSONDecodeError:
+            raise SecretBackendError(reference, "sops output is not valid JSON")
+        if not isinstance(data, dict):
+            raise SecretBackendError(reference, "file does not contain a mapping")
+        data.pop("sops", None)
+        return data
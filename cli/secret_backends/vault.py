@@ -0,0 +1,97 @@
+"""HashiCorp Vault backend (vault://<mount>/data/<path>#KEY)"""
+
+import json
+import os
+import urllib.error
+import urllib.request
+from pathlib import Path
+from typing import Any, Dict, Optional
+
+from cli.exceptions import SecretBackendError
+from .base import SecretBackend
+
+
+class VaultBackend(SecretBackend):
+    """
+    Reads secrets through Vault's HTTP API.
+
+    Connection settings follow the vault CLI: VAULT_ADDR, VAULT_TOKEN (or
+    ~/.vault-token), VAULT_NAMESPACE. KV v2 paths include the data segment
+    (vault://kv/data/api#STRIPE_KEY); KV v1 paths are read as-is.
+    """
+
+    scheme = "vault"
+
+    def __init__(
+        self,
+        address: Optional[str] = None,
+        token: Optional[str] = None,
+        namespace: Optional[str] = None,
+        timeout: float = 10,
+        cache_ttl: float = 300,
+    ):
+        super().__init__(cache_ttl=cache_ttl)
+        self.address = (address or os.getenv("VAULT_ADDR") or "").rstrip("/")
+        self.token = token or os.getenv("VAULT_TOKEN") or self._token_file()
+        self.namespace = namespace or os.getenv("VAULT_NAMESPACE")
+        self.timeout = timeout
+
+    @staticmethod
+    def _token_file() -> Optional[str]:
+        token_file = Path.home() / ".vault-token"
+        if token_file.exists():
+            return token_file.read_text().strip() or None
+        return None
+
+    def fetch_document(self, path: str) -> Dict[str, Any]:
+        reference = f"vault://{path}"
+        if not self.address:
+            raise SecretBackendError(
+                reference, "VAULT_ADDR is not set", context="export VAULT_ADDR=..."
+            )
+        if not self.token:
+            raise SecretBackendError(
+                reference,
+                "no Vault token",
+                context="export VAULT_TOKEN=... or run 'vault login'",
+            )
+
+        request = urllib.request.Request(f"{self.address}/v1/{path.lstrip('/')}")
+        request.add_header("X-Vault-Token", self.token)
+        if self.namespace:
+            request.add_header("X-Vault-Namespace", self.namespace)
+
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                payload = json.loads(response.read().decode() or "{}")
+        except urllib.error.HTTPError as e:
+            reasons = {
+                403: "permission denied (check the token's policy)",
+                404: "path not found",
+            }
+            raise SecretBackendError(
+                reference,
+                reasons.get(e.code, f"Vault returned HTTP {e.code}"),
+                context=self._error_detail(e),
+            )
+        except (urllib.error.URLError, OSError) as e:
+            reason = getattr(e, "reason", e)
+            raise SecretBackendError(
+                reference, f"cannot reach Vault at {self.address}", context=str(reason)
+            )
+        except json.JSONDecodeError:
+            raise SecretBackendError(reference, "Vault returned invalid JSON")
+
+        data = payload.get("data") or {}
+        # KV v2 wraps the secret in data.data next to data.metadata
+        if isinstance(data.get("data"), dict) and "metadata" in data:
+            data = data["data"]
+        return data
+
+    @staticmethod
+    def _error_detail(error: urllib.error.HTTPError) -> Optional[str]:
+        try:
+            errors = json.loads(error.read().decode()).get("errors") or []
+            return "; ".join(str(e) for e in errors) or None
+        except Exception:
+            return None
@@ -3,14 +3,61 @@ Secret Management
 
 Database-backed secret manager.
 All secrets are stored in PostgreSQL with proper FK relationships.
+Values may also be references to external backends (vault://, sops://),
+resolved when secrets are synced or rendered.
+
+Aliases and {{ }} placeholders can point outside the app:
+- app.<app>.KEY                 another app's secret in the same project
+- org.KEY                       organization-level secret (org_secrets)
+- project.<project>.<key>       another project's secret (needs a grant)
+
+env_templates and alias targets containing {{ }} are rendered with the
+sandboxed template language in cli.core.env_template (filters, defaults,
+conditionals, app.<app>.domain / .port).
+
+Secret files (service-account JSON, TLS client certs, SSH keys) live in
+secret_files with the same shared/app scoping and are never part of an
+app's env; config:push mounts them read-only into the containers.
+
+Each secret has a usage: runtime (the app's .env), build (passed to the
+image build only) or both.
 """
 
+import fnmatch
+import hashlib
 import re
+from datetime import datetime
 from pathlib import Path
-from typing import Dict, Optional
-from cli.database import get_db_session, Secret, SecretAlias, Project, App, VM
+from typing import Any, Dict, List, Optional
+from cli.core.env_template import TemplateEngine
+from cli.database import (
+    get_db_session,
+    Secret,
+    SecretAlias,
+    Project,
+    App,
+    VM,
+    OrgSecret,
+    SecretGrant,
+    SecretDependency,
+    SecretFile,
+)
+from cli.exceptions import SecretError
+from cli.secret_backends import SecretResolver
+from sqlalchemy import or_
 from sqlalchemy.orm import Session
 
+SCOPED_PREFIXES = ("app.", "org.", "project.")
+
+# runtime: app .env only, build: image build only, both: both
+SECRET_USAGES = ("runtime", "build", "both")
+
+# Secret file names become file names on the VM
+SECRET_FILE_NAME = re.compile(r"^[A-Za-z0-9][A-Za-z0-9._-]*$")
+
+# {{ org.DOCKER_TOKEN }} inside a stored value
+SCOPED_PLACEHOLDER = re.compile(r"\{\{\s*((?:app|org|project)\.[A-Za-z0-9_.-]+)\s*\}\}")
+
 
 class SecretManager:
     """
@@ -34,6 +81,24 @@ class SecretManager:
         self.project_name = project_name
         self.environment = environment
         self._project_id: Optional[int] = None
+        self._resolver: Optional[SecretResolver] = None
+        self._template_engine: Optional[TemplateEngine] = None
+        # app name (None = shared) -> {consumer key: absolute source ref}
+        self.dependencies: Dict[Optional[str], Dict[str, str]] = {}
+
+    @property
+    def resolver(self) -> SecretResolver:
+        """External backend resolver (cached per manager, so per sync/render run)"""
+        if self._resolver is None:
+            self._resolver = SecretResolver()
+        return self._resolver
+
+    @property
+    def template_engine(self) -> TemplateEngine:
+        """Sandboxed renderer for env_templates and alias targets"""
+        if self._template_engine is None:
+            self._template_engine = TemplateEngine()
+        return self._template_engine
 
     def _get_db(self) -> Session:
         """Get database session."""
@@ -62,17 +127,28 @@ class SecretManager:
         )
         return app.id if app else None
 
-    def _resolve_alias(self, alias_value: str, db: Session) -> Optional[str]:
+    def _resolve_alias(
+        self, alias_value: str, db: Session, dependency: tuple = None
+    ) -> Optional[str]:
         """
         Resolve an alias value like 'postgres.primary.HOST' to actual value.
 
         Args:
-            alias_value: Alias target key (e.g. postgres.primary.HOST)
+            alias_value: Alias target key (e.g. postgres.primary.HOST) or a
+                template ({{ postgres.primary.PASSWORD | urlencode }})
             db: Database session
+            dependency: (app_name, alias_key) to record scoped references under
 
         Returns:
             Resolved value or None if not found
         """
+        if self.template_engine.is_template(alias_value):
+            return self._resolve_template(alias_value, db, dependency)
+
+        # Scoped references: app.billing.TOKEN, org.DOCKER_TOKEN, project.x.KEY
+        if alias_value.startswith(SCOPED_PREFIXES):
+            return self.resolve_reference(alias_value, db)
+
         # Alias format: addon_type.instance_name.KEY
         # e.g. postgres.primary.HOST
         parts = alias_value.split(".")
@@ -97,66 +173,266 @@ class SecretManager:
 
         return secret.value if secret else None
 
-    def _resolve_template(self, template: str, db: Session) -> str:
+    def _absolute_ref(self, ref: str) -> str:
+        """app.billing.KEY -> project.<this project>.app.billing.KEY"""
+        if ref.startswith(("org.", "project.")):
+            return ref
+        return f"project.{self.project_name}.{ref}"
+
+    def _lookup_project_key(
+        self, db: Session, project_id: int, key: str
+    ) -> Optional[str]:
+        """Raw value of app.<app>.KEY, <addon>.<instance>.KEY or KEY in a project"""
+        query = db.query(Secret).filter(
+            Secret.project_id == project_id,
+            Secret.environment == self.environment,
+        )
+        if key.startswith("app."):
+            parts = key.split(".", 2)
+            if len(parts) != 3:
+                return None
+            app = (
+                db.query(App)
+                .filter(App.project_id == project_id, App.name == parts[1])
+                .first()
+            )
+            if not app:
+                return None
+            query = query.filter(Secret.app_id == app.id, Secret.key == parts[2])
+        else:
+            query = query.filter(Secret.app_id.is_(None), Secret.key == key)
+
+        secret = query.first()
+        return secret.value if secret else None
+
+    def _check_grant(self, db: Session, source: Project, key: str) -> None:
+        """
+        Raises:
+            SecretError: If source project hasn't granted key to this project
         """
-        Resolve template placeholders like {{ APP_0_EXTERNAL_IP }}.
+        grants = (
+            db.query(SecretGrant)
+            .filter(
+                SecretGrant.source_project_id == source.id,
+                SecretGrant.consumer_project_id == self._get_project_id(db),
+            )
+            .all()
+        )
+        if not any(fnmatch.fnmatchcase(key, g.key_pattern) for g in grants):
+            raise SecretError(
+                f"Project '{source.name}' has not granted '{key}' to '{self.project_name}'",
+                context=f"Run: superdeploy {source.name}:config:grant {self.project_name} '{key}'",
+            )
+
+    def resolve_reference(self, ref: str, db: Session) -> Optional[str]:
+        """
+        Resolve a scoped reference to its stored value.
 
         Args:
-            template: Template string with {{ PLACEHOLDER }} syntax
+            ref: app.<app>.KEY, org.KEY or project.<project>.<key>
             db: Database session
 
         Returns:
-            Resolved string
+            Stored value (external vault:// refs still unresolved) or None
+
+        Raises:
+            SecretError: If a cross-project reference has no matching grant
         """
+        ref = self._absolute_ref(ref)
+
+        if ref.startswith("org."):
+            secret = (
+                db.query(OrgSecret)
+                .filter(
+                    OrgSecret.key == ref[len("org.") :],
+                    OrgSecret.environment == self.environment,
+                )
+                .first()
+            )
+            return secret.value if secret else None
+
+        parts = ref.split(".", 2)
+        if len(parts) != 3:
+            return None
+        _, source_name, key = parts
+
+        source = db.query(Project).filter(Project.name == source_name).first()
+        if not source:
+            return None
+        if source_name != self.project_name:
+            self._check_grant(db, source, key)
+
+        return self._lookup_project_key(db, source.id, key)
+
+    def _expand_references(
+        self, value, db: Session, app_name: Optional[str] = None, key: str = None
+    ):
+        """Substitute {{ org.X }} style placeholders inside a stored value"""
+        if not isinstance(value, str) or "{{" not in value:
+            return value
+
+        refs = []
+
+        def replace(match):
+            resolved = self.resolve_reference(match.group(1), db)
+            if resolved is None:
+                return match.group(0)
+            refs.append(self._absolute_ref(match.group(1)))
+            return resolved
+
+        expanded = SCOPED_PLACEHOLDER.sub(replace, value)
+        if refs and key:
+            self.dependencies.setdefault(app_name, {})[key] = ",".join(refs)
+        return expanded
+
+    def _app_namespaces(self, db: Session, resolve) -> Dict[str, Any]:
+        """app.<name> namespaces with domain/port/url attributes"""
         project_id = self._get_project_id(db)
-        if not project_id:
-            return template
+        vms = {
+            vm.role: vm
+            for vm in db.query(VM).filter(VM.project_id == project_id).all()
+        }
+        namespaces = {}
+        for app in db.query(App).filter(App.project_id == project_id).all():
+            port = app.port or next((p.port for p in app.processes if p.port), None)
+            vm = vms.get(app.vm or "app")
+            external_ip = vm.external_ip if vm else None
+            internal_ip = vm.internal_ip if vm else None
+            if app.domain:
+                url = f"https://{app.domain}"
+            elif external_ip and (app.external_port or port):
+                url = f"http://{external_ip}:{app.external_port or port}"
+            else:
+                url = None
+            namespaces[app.name] = self.template_engine.namespace(
+                f"app.{app.name}",
+                resolve,
+                attributes={
+                    "name": app.name,
+                    "domain": app.domain,
+                    "port": port,
+                    "external_port": app.external_port or port,
+                    "url": url,
+                    "internal_url": (
+                        f"http://{internal_ip}:{port}" if internal_ip and port else None
+                    ),
+                    "external_ip": external_ip,
+                    "internal_ip": internal_ip,
+                },
+            )
+        return namespaces
 
-        # Find all {{ VAR_NAME }} patterns
-        pattern = r"\{\{\s*([A-Z0-9_]+)\s*\}\}"
+    def _template_context(
+        self, names, db: Session, dependency: tuple = None
+    ) -> Dict[str, Any]:
+        """Values for the top-level names a template uses"""
+        engine = self.template_engine
+        project_id = self._get_project_id(db)
 
-        def replace_placeholder(match):
-            var_name = match.group(1)
+        def scoped(ref: str) -> Optional[str]:
+            value = self.resolve_reference(ref, db)
+            if value is None:
+                return None
+            if dependency:
+                app_name, key = dependency
+                deps = self.dependencies.setdefault(app_name, {})
+                refs = [r for r in deps.get(key, "").split(",") if r]
+                absolute = self._absolute_ref(ref)
+                if absolute not in refs:
+                    deps[key] = ",".join(refs + [absolute])
+            return self.resolver.resolve(value, key=ref)
+
+        def shared(key: str) -> Optional[str]:
+            secret = (
+                db.query(Secret)
+                .filter(
+                    Secret.project_id == project_id,
+                    Secret.app_id.is_(None),
+                    Secret.key == key,
+                    Secret.environment == self.environment,
+                )
+                .first()
+            )
+            return self.resolver.resolve(secret.value, key=key) if secret else None
 
-            # Check if it's a VM IP variable (e.g., APP_0_EXTERNAL_IP, CORE_0_INTERNAL_IP)
-            vm_pattern = r"([A-Z]+)_(\d+)_(EXTERNAL|INTERNAL)_IP"
-            vm_match = re.match(vm_pattern, var_name)
+        context = {}
+        for name in names:
+            if name == "app":
+                context[name] = engine.namespace(
+                    name, scoped, children=self._app_namespaces(db, scoped)
+                )
+                continue
+            if name in ("org", "project"):
+                context[name] = engine.namespace(name, scoped)
+                continue
+            if name == "environment":
+                context[name] = self.environment
+                continue
+            if name == "project_name":
+                context[name] = self.project_name
+                continue
+
+            # VM IP variable (e.g., APP_0_EXTERNAL_IP, CORE_0_INTERNAL_IP)
+            vm_match = re.fullmatch(r"([A-Z]+)_(\d+)_(EXTERNAL|INTERNAL)_IP", name)
             if vm_match:
                 role = vm_match.group(1).lower()  # app, core, etc.
                 ip_type = vm_match.group(3).lower()  # external or internal
-
-                # Get VM from database
                 vm = (
                     db.query(VM)
                     .filter(VM.project_id == project_id, VM.role == role)
                     .first()
                 )
+                ip = None
                 if vm:
-                    if ip_type == "external" and vm.external_ip:
-                        return vm.external_ip
-                    elif ip_type == "internal" and vm.internal_ip:
-                        return vm.internal_ip
-
-            # Check shared secrets
-            secret = (
-                db.query(Secret)
+                    ip = vm.external_ip if ip_type == "external" else vm.internal_ip
+                if ip:
+                    context[name] = ip
+                    continue
+
+            # Shared secret, or addon namespace (postgres.primary.PASSWORD)
+            value = shared(name)
+            if value is not None:
+                context[name] = value
+            elif (
+                db.query(Secret.id)
                 .filter(
                     Secret.project_id == project_id,
                     Secret.app_id.is_(None),
-                    Secret.key == var_name,
+                    Secret.key.like(f"{name}.%"),
                     Secret.environment == self.environment,
                 )
                 .first()
-            )
-            if secret:
-                return secret.value
+            ):
+                context[name] = engine.namespace(name, shared)
+        return context
+
+    def _resolve_template(
+        self, template: str, db: Session, dependency: tuple = None
+    ) -> str:
+        """
+        Render an env template ({{ APP_0_EXTERNAL_IP }}, {{ X | urlencode }}).
+
+        Args:
+            template: Template in the cli.core.env_template language
+            db: Database session
+            dependency: (app_name, key) to record scoped references under
 
-            # Return original if not found
-            return match.group(0)
+        Returns:
+            Rendered string (unknown names are left as {{ NAME }})
 
-        return re.sub(pattern, replace_placeholder, template)
+        Raises:
+            SecretError: On template syntax errors or sandbox violations
+        """
+        project_id = self._get_project_id(db)
+        if not project_id or not self.template_engine.is_template(template):
+            return template
 
-    def get_app_secrets(self, app_name: str) -> Dict[str, str]:
+        key = dependency[1] if dependency else None
+        names = self.template_engine.names(template, key=key)
+        context = self._template_context(names, db, dependency)
+        return self.template_engine.render(template, context, key=key)
+
+    def get_app_secrets(self, app_name: str, resolve: bool = True) -> Dict[str, str]:
         """
         Get merged secrets for specific app with alias resolution.
 
@@ -164,10 +440,21 @@ class SecretManager:
         - Shared secrets (app_id=NULL)
         - Addon secrets (source='addon')
         - App-specific secrets (app_id={app})
-        - Resolves aliases
+        - Resolves aliases (addon keys, app./org./project. references)
+        - Expands {{ org.KEY }} style placeholders in stored values
+        - Resolves vault:// and sops:// references
+        (the last two only with resolve=True)
+
+        Scoped references are recorded in self.dependencies[app_name];
+        record_dependencies() persists them after a sync.
 
         Args:
             app_name: Name of the application
+            resolve: Fetch references (False keeps the reference text)
+
+        Raises:
+            SecretError: If a cross-project reference isn't granted
+            SecretBackendError: If a reference can't be resolved
 
         Returns:
             Dictionary of environment variables for the app
@@ -180,6 +467,7 @@ class SecretManager:
 
             app_id = self._get_app_id(db, app_name)
             merged = {}
+            deps = self.dependencies[app_name] = {}
 
             # 1. Get shared secrets (app_id=NULL)
             shared_secrets = (
@@ -225,7 +513,11 @@ class SecretManager:
 
                 for alias in aliases:
                     # Resolve alias to actual value
-                    resolved_value = self._resolve_alias(alias.target_key, db)
+                    resolved_value = self._resolve_alias(
+                        alias.target_key, db, dependency=(app_name, alias.alias_key)
+                    )
+                    if alias.target_key.startswith(SCOPED_PREFIXES):
+                        deps[alias.alias_key] = self._absolute_ref(alias.target_key)
                     if resolved_value:
                         merged[alias.alias_key] = resolved_value
                         # Mark target key for removal
@@ -235,6 +527,12 @@ class SecretManager:
                 for target_key in target_keys_to_remove:
                     merged.pop(target_key, None)
 
+            if resolve:
+                merged = {
+                    key: self._expand_references(value, db, app_name, key)
+                    for key, value in merged.items()
+                }
+                merged = self.resolver.resolve_dict(merged)
             return merged
 
         finally:
@@ -260,7 +558,11 @@ class SecretManager:
 
             # Resolve templates and add to merged
             for key, template in env_templates.items():
-                resolved = self._resolve_template(template, db)
+                # Template replaces the stored value (and what it referenced)
+                self.dependencies.get(app_name, {}).pop(key, None)
+                resolved = self._resolve_template(
+                    template, db, dependency=(app_name, key)
+                )
                 merged[key] = resolved
 
             return merged
@@ -294,7 +596,13 @@ class SecretManager:
         finally:
             db.close()
 
-    def set_shared_secret(self, key: str, value: str, source: str = "shared") -> None:
+    def set_shared_secret(
+        self,
+        key: str,
+        value: str,
+        source: str = "shared",
+        usage: Optional[str] = None,
+    ) -> None:
         """
         Set a shared secret value.
 
@@ -302,7 +610,9 @@ class SecretManager:
             key: Secret key name
             value: Secret value
             source: Secret source (shared/addon)
+            usage: runtime/build/both (None keeps the current usage)
         """
+        self._check_usage(usage)
         db = self._get_db()
         try:
             project_id = self._get_project_id(db)
@@ -324,6 +634,8 @@ class SecretManager:
             if secret:
                 secret.value = value
                 secret.source = source
+                if usage:
+                    secret.usage = usage
             else:
                 secret = Secret(
                     project_id=project_id,
@@ -333,6 +645,7 @@ class SecretManager:
                     environment=self.environment,
                     source=source,
                     editable=True,
+                    usage=usage or "runtime",
                 )
                 db.add(secret)
 
@@ -340,7 +653,9 @@ class SecretManager:
         finally:
             db.close()
 
-    def set_app_secret(self, app_name: str, key: str, value: str) -> None:
+    def set_app_secret(
+        self, app_name: str, key: str, value: str, usage: Optional[str] = None
+    ) -> None:
         """
         Set an app-specific secret value.
 
@@ -348,7 +663,9 @@ class SecretManager:
             app_name: Name of the application
             key: Secret key name
             value: Secret value
+            usage: runtime/build/both (None keeps the current usage)
         """
+        self._check_usage(usage)
         db = self._get_db()
         try:
             project_id = self._get_project_id(db)
@@ -373,6 +690,8 @@ class SecretManager:
 
             if secret:
                 secret.value = value
+                if usage:
+                    secret.usage = usage
             else:
                 secret = Secret(
                     project_id=project_id,
@@ -382,6 +701,7 @@ class SecretManager:
                     environment=self.environment,
                     source="app",
                     editable=True,
+                    usage=usage or "runtime",
                 )
                 db.add(secret)
 
@@ -389,6 +709,51 @@ class SecretManager:
         finally:
             db.close()
 
+    @staticmethod
+    def _check_usage(usage: Optional[str]) -> None:
+        if usage and usage not in SECRET_USAGES:
+            raise ValueError(
+                f"Invalid usage '{usage}' (expected {', '.join(SECRET_USAGES)})"
+            )
+
+    def get_usages(self, app_name: str) -> Dict[str, str]:
+        """
+        Get the usage of each stored key an app sees.
+
+        App-specific secrets override shared ones, like get_app_secrets().
+        Aliases aren't listed and count as runtime.
+
+        Args:
+            app_name: Name of the application
+
+        Returns:
+            Dictionary of key -> runtime/build/both
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return {}
+
+            app_id = self._get_app_id(db, app_name)
+            secrets = (
+                db.query(Secret)
+                .filter(
+                    Secret.project_id == project_id,
+                    or_(Secret.app_id.is_(None), Secret.app_id == app_id),
+                    Secret.environment == self.environment,
+                )
+                .all()
+            )
+
+            usages = {}
+            # Shared first so app-specific rows win
+            for secret in sorted(secrets, key=lambda s: s.app_id is not None):
+                usages[secret.key] = secret.usage or "runtime"
+            return usages
+        finally:
+            db.close()
+
     def delete_secret(self, app_name: Optional[str], key: str) -> bool:
         """
         Delete a secret.
@@ -454,10 +819,13 @@ class SecretManager:
         finally:
             db.close()
 
-    def load_secrets(self):
+    def load_secrets(self, resolve: bool = False):
         """
         Load secrets from database and structure them for Ansible.
 
+        With resolve=True, vault:// and sops:// references are replaced by
+        their values (used when secrets are handed to Ansible).
+
         Returns a dict with nested addon structure:
         {
             "shared": {"DOCKER_ORG": "..."},
@@ -524,10 +892,307 @@ class SecretManager:
                             result["apps"][app_name] = {}
                         result["apps"][app_name][secret.key] = secret.value
 
+            if resolve:
+                self.dependencies[None] = {}
+                result["shared"] = {
+                    key: self._expand_references(value, db, None, key)
+                    for key, value in result["shared"].items()
+                }
+                result = self._resolve_nested(result)
             return result
         finally:
             db.close()
 
+    def _resolve_nested(self, data):
+        """Resolve references in load_secrets() output, keeping its shape"""
+        if isinstance(data, dict):
+            return {key: self._resolve_nested(value) for key, value in data.items()}
+        return self.resolver.resolve(data)
+
+    def _source_hash(self, source_ref: str, db: Session) -> Optional[str]:
+        """Fingerprint of the current source value(s) behind a dependency"""
+        try:
+            values = [self.resolve_reference(ref, db) for ref in source_ref.split(",")]
+        except SecretError:
+            return None
+        if any(v is None for v in values):
+            return None
+        return hashlib.sha256("\0".join(values).encode()).hexdigest()
+
+    def record_dependencies(self, app_name: Optional[str] = None) -> int:
+        """
+        Persist the scoped references used by the last secrets lookup.
+
+        Stores a hash of each source value so a later change can be detected
+        and propagated to this consumer.
+
+        Args:
+            app_name: Consumer app (None for shared secrets)
+
+        Returns:
+            Number of dependencies recorded
+        """
+        deps = self.dependencies.get(app_name, {})
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return 0
+            app_id = self._get_app_id(db, app_name) if app_name else None
+
+            query = db.query(SecretDependency).filter(
+                SecretDependency.consumer_project_id == project_id,
+                SecretDependency.environment == self.environment,
+            )
+            if app_id:
+                query = query.filter(SecretDependency.consumer_app_id == app_id)
+            else:
+                query = query.filter(SecretDependency.consumer_app_id.is_(None))
+            existing = {row.consumer_key: row for row in query.all()}
+
+            now = datetime.utcnow()
+            for key, source_ref in deps.items():
+                row = existing.pop(key, None)
+                if row is None:
+                    row = SecretDependency(
+                        consumer_project_id=project_id,
+                        consumer_app_id=app_id,
+                        consumer_key=key,
+                        environment=self.environment,
+                    )
+                    db.add(row)
+                row.source_ref = source_ref
+                row.value_hash = self._source_hash(source_ref, db)
+                row.resolved_at = now
+
+            # References removed since the last sync
+            for row in existing.values():
+                db.delete(row)
+
+            db.commit()
+            return len(deps)
+        finally:
+            db.close()
+
+    def get_dependencies(self, stale_only: bool = False) -> List[Dict[str, str]]:
+        """
+        Consumers of this project's secrets and of org secrets.
+
+        A dependency is stale when its source changed after the consumer's
+        last sync; the next vars:sync of this project re-syncs it.
+
+        Returns:
+            List of {project, app, key, source_ref, stale}
+        """
+        db = self._get_db()
+        try:
+            own_prefix = f"project.{self.project_name}."
+            projects = {p.id: p.name for p in db.query(Project).all()}
+            apps = {a.id: a.name for a in db.query(App).all()}
+
+            result = []
+            rows = (
+                db.query(SecretDependency)
+                .filter(SecretDependency.environment == self.environment)
+                .all()
+            )
+            for row in rows:
+                refs = row.source_ref.split(",")
+                if not any(r.startswith((own_prefix, "org.")) for r in refs):
+                    continue
+                stale = self._source_hash(row.source_ref, db) != row.value_hash
+                if stale_only and not stale:
+                    continue
+                result.append(
+                    {
+                        "project": projects.get(row.consumer_project_id),
+                        "app": apps.get(row.consumer_app_id),
+                        "key": row.consumer_key,
+                        "source_ref": row.source_ref,
+                        "stale": stale,
+                    }
+                )
+            return result
+        finally:
+            db.close()
+
+    def get_consumed_references(self) -> List[Dict[str, str]]:
+        """References this project's apps resolve from elsewhere"""
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return []
+            apps = {
+                a.id: a.name
+                for a in db.query(App).filter(App.project_id == project_id).all()
+            }
+            rows = (
+                db.query(SecretDependency)
+                .filter(
+                    SecretDependency.consumer_project_id == project_id,
+                    SecretDependency.environment == self.environment,
+                )
+                .all()
+            )
+            return [
+                {
+                    "app": apps.get(row.consumer_app_id),
+                    "key": row.consumer_key,
+                    "source_ref": row.source_ref,
+                    "stale": self._source_hash(row.source_ref, db) != row.value_hash,
+                }
+                for row in rows
+            ]
+        finally:
+            db.close()
+
+    def grant(self, consumer_project: str, key_pattern: str) -> None:
+        """
+        Allow another project to reference this project's secrets.
+
+        Args:
+            consumer_project: Project that may reference the secrets
+            key_pattern: Key within this project, glob allowed (app.api.*, DOCKER_*)
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                raise ValueError(f"Project '{self.project_name}' not found")
+            consumer = (
+                db.query(Project).filter(Project.name == consumer_project).first()
+            )
+            if not consumer:
+                raise ValueError(f"Project '{consumer_project}' not found")
+
+            exists = (
+                db.query(SecretGrant)
+                .filter(
+                    SecretGrant.source_project_id == project_id,
+                    SecretGrant.consumer_project_id == consumer.id,
+                    SecretGrant.key_pattern == key_pattern,
+                )
+                .first()
+            )
+            if not exists:
+                db.add(
+                    SecretGrant(
+                        source_project_id=project_id,
+                        consumer_project_id=consumer.id,
+                        key_pattern=key_pattern,
+                    )
+                )
+                db.commit()
+        finally:
+            db.close()
+
+    def revoke(self, consumer_project: str, key_pattern: str) -> bool:
+        """
+        Remove a grant.
+
+        Returns:
+            True if removed, False if not found
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            consumer = (
+                db.query(Project).filter(Project.name == consumer_project).first()
+            )
+            if not project_id or not consumer:
+                return False
+            grant = (
+                db.query(SecretGrant)
+                .filter(
+                    SecretGrant.source_project_id == project_id,
+                    SecretGrant.consumer_project_id == consumer.id,
+                    SecretGrant.key_pattern == key_pattern,
+                )
+                .first()
+            )
+            if not grant:
+                return False
+            db.delete(grant)
+            db.commit()
+            return True
+        finally:
+            db.close()
+
+    def list_grants(self) -> Dict[str, List[Dict[str, str]]]:
+        """
+        Grants given and received by this project.
+
+        Returns:
+            {"given": [{project, pattern}], "received": [{project, pattern}]}
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return {"given": [], "received": []}
+            projects = {p.id: p.name for p in db.query(Project).all()}
+            given = (
+                db.query(SecretGrant)
+                .filter(SecretGrant.source_project_id == project_id)
+                .all()
+            )
+            received = (
+                db.query(SecretGrant)
+                .filter(SecretGrant.consumer_project_id == project_id)
+                .all()
+            )
+            return {
+                "given": [
+                    {
+                        "project": projects.get(g.consumer_project_id),
+                        "pattern": g.key_pattern,
+                    }
+                    for g in given
+                ],
+                "received": [
+                    {
+                        "project": projects.get(g.source_project_id),
+                        "pattern": g.key_pattern,
+                    }
+                    for g in received
+                ],
+            }
+        finally:
+            db.close()
+
+    def list_references(self) -> Dict[str, str]:
+        """
+        External references stored for this project/environment.
+
+        Returns:
+            Dictionary of "<scope>:<key>" -> reference (scope is shared or app name)
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return {}
+
+            apps = db.query(App).filter(App.project_id == project_id).all()
+            app_id_to_name = {app.id: app.name for app in apps}
+            secrets = (
+                db.query(Secret)
+                .filter(
+                    Secret.project_id == project_id,
+                    Secret.environment == self.environment,
+                )
+                .all()
+            )
+
+            return {
+                f"{app_id_to_name.get(s.app_id, 'shared')}:{s.key}": s.value
+                for s in secrets
+                if self.resolver.is_reference(s.value)
+            }
+        finally:
+            db.close()
+
     def set_alias(self, app_name: str, alias_key: str, target_key: str) -> None:
         """
         Set an alias for an app.
@@ -605,3 +1270,321 @@ class SecretManager:
             return {a.alias_key: a.target_key for a in aliases}
         finally:
             db.close()
+
+    def set_file(
+        self,
+        app_name: Optional[str],
+        name: str,
+        content: str,
+        mount_path: Optional[str] = None,
+    ) -> None:
+        """
+        Create or update a secret file.
+
+        Args:
+            app_name: Name of the application (None for a shared file)
+            name: File name (letters, digits, . _ -)
+            content: File content or a vault:// / sops:// reference
+            mount_path: Absolute path inside the containers (required on create)
+
+        Raises:
+            ValueError: If the name or path is invalid, or the app doesn't exist
+        """
+        if not SECRET_FILE_NAME.match(name):
+            raise ValueError(
+                f"Invalid file name '{name}' (use letters, digits, '.', '_', '-')"
+            )
+        if mount_path is not None and not mount_path.startswith("/"):
+            raise ValueError(f"Mount path must be absolute: {mount_path}")
+
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                raise ValueError(f"Project '{self.project_name}' not found")
+
+            app_id = None
+            scope = SecretFile.app_id.is_(None)
+            if app_name:
+                app_id = self._get_app_id(db, app_name)
+                if not app_id:
+                    raise ValueError(f"App '{app_name}' not found in project")
+                scope = SecretFile.app_id == app_id
+
+            secret_file = (
+                db.query(SecretFile)
+                .filter(
+                    SecretFile.project_id == project_id,
+                    scope,
+                    SecretFile.name == name,
+                    SecretFile.environment == self.environment,
+                )
+                .first()
+            )
+
+            if secret_file:
+                secret_file.content = content
+                if mount_path:
+                    secret_file.mount_path = mount_path
+            else:
+                if not mount_path:
+                    raise ValueError(f"New secret file '{name}' needs a mount path")
+                db.add(
+                    SecretFile(
+                        project_id=project_id,
+                        app_id=app_id,
+                        name=name,
+                        mount_path=mount_path,
+                        content=content,
+                        environment=self.environment,
+                    )
+                )
+
+            db.commit()
+        finally:
+            db.close()
+
+    def delete_file(self, app_name: Optional[str], name: str) -> bool:
+        """
+        Delete a secret file.
+
+        Returns:
+            True if deleted, False if not found
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return False
+
+            query = db.query(SecretFile).filter(
+                SecretFile.project_id == project_id,
+                SecretFile.name == name,
+                SecretFile.environment == self.environment,
+            )
+            if app_name:
+                app_id = self._get_app_id(db, app_name)
+                if not app_id:
+                    return False
+                query = query.filter(SecretFile.app_id == app_id)
+            else:
+                query = query.filter(SecretFile.app_id.is_(None))
+
+            secret_file = query.first()
+            if secret_file:
+                db.delete(secret_file)
+                db.commit()
+                return True
+            return False
+        finally:
+            db.close()
+
+    def list_files(self) -> List[Dict[str, Any]]:
+        """
+        Secret files of this project/environment, without their content.
+
+        Returns:
+            [{"app": name or None, "name", "mount_path", "size", "reference",
+              "updated_at"}]
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return []
+
+            apps = db.query(App).filter(App.project_id == project_id).all()
+            app_id_to_name = {app.id: app.name for app in apps}
+            files = (
+                db.query(SecretFile)
+                .filter(
+                    SecretFile.project_id == project_id,
+                    SecretFile.environment == self.environment,
+                )
+                .order_by(SecretFile.app_id, SecretFile.name)
+                .all()
+            )
+
+            return [
+                {
+                    "app": app_id_to_name.get(f.app_id),
+                    "name": f.name,
+                    "mount_path": f.mount_path,
+                    "size": len(f.content),
+                    "reference": self.resolver.is_reference(f.content),
+                    "updated_at": f.updated_at,
+                }
+                for f in files
+            ]
+        finally:
+            db.close()
+
+    def get_app_files(
+        self, app_name: str, resolve: bool = True
+    ) -> Dict[str, Dict[str, str]]:
+        """
+        Files mounted into an app: shared files, overridden by app files of
+        the same name.
+
+        Args:
+            app_name: Name of the application
+            resolve: Fetch vault:// / sops:// content (False keeps the reference)
+
+        Raises:
+            SecretBackendError: If a reference can't be resolved
+
+        Returns:
+            Dictionary of name -> {"mount_path", "content"}
+        """
+        db = self._get_db()
+        try:
+            project_id = self._get_project_id(db)
+            if not project_id:
+                return {}
+
+            app_id = self._get_app_id(db, app_name)
+            scope = [SecretFile.app_id.is_(None)]
+            if app_id:
+                scope.append(SecretFile.app_id == app_id)
+
+            files = (
+                db.query(SecretFile)
+                .filter(
+                    SecretFile.project_id == project_id,
+                    SecretFile.environment == self.environment,
+                    or_(*scope),
+                )
+                .all()
+            )
+
+            # Shared first so app files override them
+            merged = {}
+            for f in sorted(files, key=lambda f: f.app_id is not None):
+                content = f.content
+                if resolve:
+                    content = self.resolver.resolve(content, key=f.name)
+                merged[f.name] = {"mount_path": f.mount_path, "content": content}
+            return merged
+        finally:
+            db.close()
+
+
+class OrgSecretManager:
+    """
+    Organization-level secrets shared by every project (DOCKER_TOKEN, ...).
+
+    Apps consume them through an org.KEY alias or a {{ org.KEY }} placeholder;
+    they are never merged into an app's environment implicitly.
+    """
+
+    def __init__(self, environment: str = "production"):
+        self.environment = environment
+
+    def _get_db(self) -> Session:
+        return get_db_session()
+
+    def get(self, key: str) -> Optional[str]:
+        db = self._get_db()
+        try:
+            secret = (
+                db.query(OrgSecret)
+                .filter(OrgSecret.key == key, OrgSecret.environment == self.environment)
+                .first()
+            )
+            return secret.value if secret else None
+        finally:
+            db.close()
+
+    def set(self, key: str, value: str, description: Optional[str] = None) -> None:
+        db = self._get_db()
+        try:
+            secret = (
+                db.query(OrgSecret)
+                .filter(OrgSecret.key == key, OrgSecret.environment == self.environment)
+                .first()
+            )
+            if secret:
+                secret.value = value
+                if description is not None:
+                    secret.description = description
+            else:
+                db.add(
+                    OrgSecret(
+                        key=key,
+                        value=value,
+                        environment=self.environment,
+                        description=description,
+                    )
+                )
+            db.commit()
+        finally:
+            db.close()
+
+    def delete(self, key: str) -> bool:
+        db = self._get_db()
+        try:
+            secret = (
+                db.query(OrgSecret)
+                .filter(OrgSecret.key == key, OrgSecret.environment == self.environment)
+                .first()
+            )
+            if not secret:
+                return False
+            db.delete(secret)
+            db.commit()
+            return True
+        finally:
+            db.close()
+
+    def list(self) -> Dict[str, Dict[str, str]]:
+        """All org secrets as key -> {value, description}"""
+        db = self._get_db()
+        try:
+            secrets = (
+                db.query(OrgSecret)
+                .filter(OrgSecret.environment == self.environment)
+                .order_by(OrgSecret.key)
+                .all()
+            )
+            return {
+                s.key: {"value": s.value, "description": s.description or ""}
+                for s in secrets
+            }
+        finally:
+            db.close()
+
+    def consumers(self, key: Optional[str] = None) -> List[Dict[str, str]]:
+        """
+        Projects/apps that referenced org secrets on their last sync.
+
+        Returns:
+            List of {project, app, key, source_ref}
+        """
+        db = self._get_db()
+        try:
+            projects = {p.id: p.name for p in db.query(Project).all()}
+            apps = {a.id: a.name for a in db.query(App).all()}
+            rows = (
+                db.query(SecretDependency)
+                .filter(SecretDependency.environment == self.environment)
+                .all()
+            )
+            result = []
+            for row in rows:
+                refs = row.source_ref.split(",")
+                wanted = f"org.{key}" if key else None
+                if wanted and wanted not in refs:
+                    continue
+                if not wanted and not any(r.startswith("org.") for r in refs):
+                    continue
+                result.append(
+                    {
+                        "project": projects.get(row.consumer_project_id),
+                        "app": apps.get(row.consumer_app_id),
+                        "key": row.consumer_key,
+                        "source_ref": row.source_ref,
+                    }
+                )
+            return result
+        finally:
+            db.close()
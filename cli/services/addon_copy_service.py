@@ -0,0 +1,192 @@
+"""
+Addon Copy Service
+
+Copies a database addon instance into another (addons:copy). The dump is
+streamed on the orchestrator, which reaches every project's addons over
+VPC peering: pg_dump from the source, the anonymizer (cli/core/anonymizer.py)
+with the apps' profiles, psql into the target in a single transaction.
+Data never passes through the operator's machine.
+"""
+
+import json
+import shlex
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Dict, Optional
+
+from cli.core.anonymizer import merge_profiles
+from cli.database import get_db_session, Addon, Project, Secret
+
+# Addon types addons:copy can stream (pg_dump plain format)
+COPYABLE_TYPES = ("postgres",)
+
+# Heredoc delimiter for files written by the copy script
+EOF_MARKER = "SUPERDEPLOY_COPY_EOF"
+
+
+@dataclass
+class AddonEndpoint:
+    """One side of a copy: an addon instance and its credentials"""
+
+    project: str
+    environment: str  # The project's environment (production, staging, ...)
+    category: str
+    instance: str
+    type: str
+    version: str
+    credentials: Dict[str, str] = field(default_factory=dict)
+
+    @property
+    def ref(self) -> str:
+        return f"{self.project}:{self.category}.{self.instance}"
+
+    @property
+    def image(self) -> str:
+        return f"{self.type}:{self.version or 'latest'}"
+
+    def to_dict(self) -> Dict[str, str]:
+        return {
+            "ref": self.ref,
+            "environment": self.environment,
+            "type": self.type,
+            "version": self.version,
+            "host": self.credentials.get("HOST"),
+            "database": self.credentials.get("DATABASE"),
+        }
+
+
+class AddonCopyService:
+    """Resolves addon references and builds the copy pipeline."""
+
+    def resolve(self, ref: str) -> AddonEndpoint:
+        """
+        <project>:<category>.<instance> → endpoint
+
+        Raises:
+            ValueError: If the reference is malformed or doesn't exist
+        """
+        project_name, _, addon = ref.partition(":")
+        category, _, instance = addon.partition(".")
+        if not project_name or not category or not instance:
+            raise ValueError(
+                f"Invalid addon reference: {ref} "
+                f"(expected <project>:<category>.<instance>)"
+            )
+
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            if not project:
+                raise ValueError(f"Project '{project_name}' not found")
+            row = (
+                db.query(Addon)
+                .filter(
+                    Addon.project_id == project.id,
+                    Addon.category == category,
+                    Addon.instance_name == instance,
+                )
+                .first()
+            )
+            if not row:
+                raise ValueError(f"Addon not found: {ref}")
+
+            # Credentials `up` generated (HOST is the VM's internal IP)
+            prefix = f"{row.type}.{instance}."
+            credentials = {
+                secret.key[len(prefix) :]: secret.value
+                for secret in db.query(Secret).filter(
+                    Secret.project_id == project.id,
+                    Secret.source == "addon",
+                    Secret.environment == "production",
+                    Secret.key.startswith(prefix),
+                )
+            }
+            return AddonEndpoint(
+                project=project.name,
+                environment=project.environment or "production",
+                category=category,
+                instance=instance,
+                type=row.type,
+                version=row.version,
+                credentials=credentials,
+            )
+        finally:
+            db.close()
+
+    def profile(self, project_name: str) -> Dict[str, Dict[str, str]]:
+        """
+        Anonymization profile of a project: its apps' profiles merged.
+
+        Raises:
+            ProfileError: If apps disagree on a column or a rule is invalid
+        """
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            profiles = {
+                app.name: app.anonymize for app in project.apps if app.anonymize
+            }
+        finally:
+            db.close()
+        return merge_profiles(profiles)
+
+    def build_script(
+        self,
+        source: AddonEndpoint,
+        target: AddonEndpoint,
+        profile: Dict[str, Dict[str, str]],
+    ) -> str:
+        """
+        Shell script the orchestrator runs (sent on stdin, so credentials
+        never show up in a command line).
+        """
+        anonymizer = Path(__file__).parent.parent / "core" / "anonymizer.py"
+
+        def connect(endpoint: AddonEndpoint) -> str:
+            creds = endpoint.credentials
+            return " ".join(
+                [
+                    "-h",
+                    shlex.quote(creds["HOST"]),
+                    "-p",
+                    shlex.quote(creds.get("PORT") or "5432"),
+                    "-U",
+                    shlex.quote(creds["USER"]),
+                    shlex.quote(creds["DATABASE"]),
+                ]
+            )
+
+        return "\n".join(
+            [
+                "set -euo pipefail",
+                "work=$(mktemp -d)",
+                "trap 'rm -rf \"$work\"' EXIT",
+                f"cat > \"$work/anonymizer.py\" <<'{EOF_MARKER}'",
+                anonymizer.read_text().rstrip("\n"),
+                EOF_MARKER,
+                f"cat > \"$work/profile.json\" <<'{EOF_MARKER}'",
+                json.dumps(profile, indent=2, sort_keys=True),
+                EOF_MARKER,
+                f"SOURCE_PASSWORD={shlex.quote(source.credentials['PASSWORD'])}",
+                f"TARGET_PASSWORD={shlex.quote(target.credentials['PASSWORD'])}",
+                # docker reads PGPASSWORD from its own environment (-e NAME)
+                f'PGPASSWORD="$SOURCE_PASSWORD" docker run --rm --network host '
+                f"-e PGPASSWORD {shlex.quote(source.image)} "
+                f"pg_dump --clean --if-exists --no-owner --no-privileges "
+                f"{connect(source)} \\",
+                '  | python3 "$work/anonymizer.py" "$work/profile.json" \\',
+                f'  | PGPASSWORD="$TARGET_PASSWORD" docker run --rm -i --network host '
+                f"-e PGPASSWORD {shlex.quote(target.image)} "
+                f"psql -q -v ON_ERROR_STOP=1 --single-transaction "
+                f"{connect(target)}",
+                "",
+            ]
+        )
+
+    @staticmethod
+    def missing_credentials(endpoint: AddonEndpoint) -> Optional[str]:
+        """First credential the copy needs that `up` hasn't generated yet"""
+        for key in ("HOST", "USER", "PASSWORD", "DATABASE"):
+            if not endpoint.credentials.get(key):
+                return key
+        return None
@@ -0,0 +1,331 @@
+"""
+Addon Test Service
+
+Renders an addon with a throwaway project context and boots it on local Docker
+so addon authors can check healthchecks and connectivity without a VM.
+"""
+
+import re
+import secrets
+import shutil
+import socket
+import subprocess
+import tempfile
+import time
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any, Dict, List, Optional
+
+from cli.core.addon_loader import AddonLoader
+from cli.core.addon_renderer import (
+    AddonRenderContext,
+    AddonRenderer,
+    RenderedAddon,
+    generate_password,
+)
+
+# addon.yml category → category used for instance names (databases.primary)
+INSTANCE_CATEGORIES = {
+    "database": "databases",
+    "cache": "caches",
+    "queue": "queues",
+    "search": "search",
+    "proxy": "proxy",
+}
+
+DURATION_UNITS = {"ms": 0.001, "s": 1, "m": 60, "h": 3600}
+
+
+def parse_duration(value: Any, default: float = 0) -> float:
+    """Parse compose-style durations (10s, 1m30s, 500ms) into seconds"""
+    if value is None or value == "":
+        return default
+    if isinstance(value, (int, float)):
+        return float(value)
+    parts = re.findall(r"(\d+(?:\.\d+)?)(ms|s|m|h)", str(value))
+    if not parts:
+        return default
+    return sum(float(amount) * DURATION_UNITS[unit] for amount, unit in parts)
+
+
+def find_free_port() -> int:
+    """Ask the kernel for an unused local TCP port"""
+    with socket.socket(socket.AF_INET, socket.SOCK_STREAM) as sock:
+        sock.bind(("127.0.0.1", 0))
+        return sock.getsockname()[1]
+
+
+@dataclass
+class CheckResult:
+    """Outcome of a healthcheck or smoke test"""
+
+    passed: bool
+    output: str = ""
+    duration: float = 0.0
+
+
+class AddonTestService:
+    """
+    Local addon test harness.
+
+    Responsibilities:
+    - Build a fake project context (ports, generated secrets)
+    - Render compose/env/templates the way addon-deployer does
+    - docker compose up, healthcheck, smoke test, teardown
+    """
+
+    def __init__(
+        self,
+        project_root: Path,
+        addon_name: str,
+        plan: str = "standard",
+        instance_name: str = "primary",
+        version: Optional[str] = None,
+    ):
+        """
+        Initialize addon test service.
+
+        Args:
+            project_root: Path to superdeploy root directory
+            addon_name: Addon directory name (postgres, redis, ...)
+            plan: Resource plan from addon.yml
+            instance_name: Instance name used for container/volume names
+            version: Image version override
+        """
+        self.project_root = project_root
+        self.addon = AddonLoader(project_root / "addons").load_addon(addon_name)
+        self.renderer = AddonRenderer(self.addon)
+        self.plan = plan
+        self.instance_name = instance_name
+        self.version = version
+        # Unique per run so parallel tests never share containers or volumes
+        self.project_name = f"sdtest{secrets.token_hex(3)}"
+        self.work_dir: Optional[Path] = None
+        self.rendered: Optional[RenderedAddon] = None
+
+    @property
+    def network_name(self) -> str:
+        return f"{self.project_name}-network"
+
+    @property
+    def compose_project(self) -> str:
+        return self.project_name
+
+    @property
+    def healthcheck(self) -> Dict[str, Any]:
+        return self.addon.metadata.get("healthcheck", {})
+
+    @property
+    def smoke_test(self) -> Dict[str, Any]:
+        return self.addon.metadata.get("smoke_test", {})
+
+    def build_context(self) -> AddonRenderContext:
+        """
+        Build the fake project context.
+
+        Ports get free local ports so the addon can run next to real services;
+        generate: true secrets get fresh passwords like init does.
+        """
+        self.renderer.get_plan(self.plan)  # fail early on unknown plans
+
+        addon_type = self.addon.name
+        instance_secrets: Dict[str, str] = {}
+        project_config: Dict[str, Any] = {"addons": {addon_type: {}}}
+        if self.version:
+            project_config["addons"][addon_type]["version"] = self.version
+
+        for var_key, var_def in self.addon.env_schema.get("variables", {}).items():
+            env_name = var_def.get("env_name", var_key)
+            if var_def.get("generate") and "from_secrets" in var_def:
+                instance_secrets[var_def["from_secrets"]] = generate_password()
+            elif env_name.endswith("PORT"):
+                port = str(find_free_port())
+                if "from_secrets" in var_def:
+                    instance_secrets[var_def["from_secrets"]] = port
+                if "from_project" in var_def:
+                    self._set_path(project_config, var_def["from_project"], port)
+
+        return AddonRenderContext(
+            project_name=self.project_name,
+            instance_name=self.instance_name,
+            category=INSTANCE_CATEGORIES.get(
+                self.addon.metadata.get("category", ""), addon_type
+            ),
+            plan=self.plan,
+            version=self.version,
+            project_config=project_config,
+            instance_secrets=instance_secrets,
+        )
+
+    def render(self, output_dir: Optional[Path] = None) -> RenderedAddon:
+        """Render addon files into output_dir (a temp dir by default)"""
+        self.work_dir = Path(
+            output_dir or tempfile.mkdtemp(prefix=f"superdeploy-{self.addon.name}-")
+        )
+        self.rendered = self.renderer.render(self.build_context(), self.work_dir)
+        return self.rendered
+
+    @staticmethod
+    def docker_available() -> bool:
+        """Check docker and the compose plugin are usable"""
+        if not shutil.which("docker"):
+            return False
+        result = subprocess.run(
+            ["docker", "compose", "version"], capture_output=True, text=True
+        )
+        return result.returncode == 0
+
+    def up(self) -> subprocess.CompletedProcess:
+        """Create the external project network and start the compose project"""
+        subprocess.run(
+            ["docker", "network", "create", self.network_name],
+            capture_output=True,
+            text=True,
+        )
+        return self._compose("up", "-d")
+
+    def wait_healthy(self, timeout: Optional[float] = None) -> CheckResult:
+        """
+        Wait for the addon healthcheck to pass.
+
+        Uses Docker's health status when the compose file defines one, otherwise
+        runs healthcheck.command from addon.yml inside the container.
+        """
+        check = self.healthcheck
+        interval = parse_duration(check.get("interval"), 5)
+        if timeout is None:
+            timeout = parse_duration(check.get("start_period"), 0) + interval * int(
+                check.get("retries", 5)
+            )
+
+        container = self.rendered.container_name
+        command_timeout = parse_duration(check.get("timeout"), 10)
+        status_format = (
+            "{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}}"
+        )
+        started = time.time()
+        output = ""
+
+        while time.time() - started < timeout:
+            elapsed = time.time() - started
+            state = self._inspect(container, status_format).split()
+
+            if not state:
+                output = f"container {container} not found"
+            elif state[0] in ("exited", "dead"):
+                return CheckResult(False, f"container {state[0]}", elapsed)
+            elif len(state) > 1:
+                if state[1] == "healthy":
+                    return CheckResult(True, "healthy", elapsed)
+                if state[1] == "unhealthy":
+                    return CheckResult(False, self._last_health_log(container), elapsed)
+                output = state[1]
+            elif check.get("command"):
+                result = self.exec(check["command"], timeout=command_timeout)
+                output = result.output
+                if result.passed:
+                    return CheckResult(True, output, time.time() - started)
+            else:
+                return CheckResult(state[0] == "running", state[0], elapsed)
+
+            time.sleep(min(interval, 5))
+
+        return CheckResult(False, f"timed out after {int(timeout)}s ({output})", timeout)
+
+    def run_smoke_test(self) -> CheckResult:
+        """Run smoke_test.command from addon.yml and check for smoke_test.expect"""
+        smoke = self.smoke_test
+        timeout = parse_duration(smoke.get("timeout"), 30)
+        result = self.exec(smoke["command"], timeout=timeout)
+        expect = smoke.get("expect")
+        if result.passed and expect is not None and str(expect) not in result.output:
+            return CheckResult(
+                False, f"expected '{expect}' in output: {result.output}", result.duration
+            )
+        return result
+
+    def exec(self, command: str, timeout: float = 30) -> CheckResult:
+        """Run a shell command inside the addon container"""
+        started = time.time()
+        try:
+            result = subprocess.run(
+                ["docker", "exec", self.rendered.container_name, "sh", "-c", command],
+                capture_output=True,
+                text=True,
+                timeout=timeout,
+            )
+        except subprocess.TimeoutExpired:
+            return CheckResult(False, f"timed out after {int(timeout)}s", timeout)
+
+        output = (result.stdout + result.stderr).strip()
+        return CheckResult(result.returncode == 0, output, time.time() - started)
+
+    def logs(self, tail: int = 50) -> str:
+        """Recent compose logs (for failure reports)"""
+        result = self._compose("logs", "--no-color", "--tail", str(tail))
+        return (result.stdout + result.stderr).strip()
+
+    def teardown(self, keep_files: bool = False) -> List[str]:
+        """
+        Stop containers, remove volumes and the network, delete rendered files.
+
+        Returns:
+            List of cleanup errors (empty on success)
+        """
+        errors = []
+        # Nothing was started without docker (render-only or render failures)
+        if self.rendered and shutil.which("docker"):
+            result = self._compose("down", "-v", "--remove-orphans")
+            if result.returncode != 0:
+                errors.append(result.stderr.strip())
+
+            subprocess.run(
+                ["docker", "network", "rm", self.network_name],
+                capture_output=True,
+                text=True,
+            )
+
+        if self.work_dir and not keep_files:
+            shutil.rmtree(self.work_dir, ignore_errors=True)
+
+        return errors
+
+    def _compose(self, *args: str) -> subprocess.CompletedProcess:
+        """Run docker compose against the rendered project directory"""
+        return subprocess.run(
+            [
+                "docker",
+                "compose",
+                "--project-name",
+                self.compose_project,
+                "--project-directory",
+                str(self.work_dir),
+                "-f",
+                str(self.rendered.compose_path),
+                *args,
+            ],
+            capture_output=True,
+            text=True,
+        )
+
+    def _inspect(self, container: str, fmt: str) -> str:
+        result = subprocess.run(
+            ["docker", "inspect", "-f", fmt, container],
+            capture_output=True,
+            text=True,
+        )
+        return result.stdout.strip() if result.returncode == 0 else ""
+
+    def _last_health_log(self, container: str) -> str:
+        output = self._inspect(
+            container,
+            "{{with .State.Health}}{{range .Log}}{{.Output}}{{end}}{{end}}",
+        )
+        return output.strip().splitlines()[-1] if output.strip() else "unhealthy"
+
+    @staticmethod
+    def _set_path(data: Dict[str, Any], dotted_path: str, value: Any) -> None:
+        parts = dotted_path.split(".")
+        for part in parts[:-1]:
+            data = data.setdefault(part, {})
+        data[parts[-1]] = value
@@ -0,0 +1,804 @@
+"""
+App Env Service
+
+Renders an app's runtime .env straight from the secrets database and pushes it
+to the app's VM, so config changes apply without a GitHub Actions run.
+
+Secret files are written to a tmpfs directory on the VM (never to disk) and
+bind-mounted read-only into the app's containers at their declared paths.
+
+Build-time config (secrets tagged build/both, marker build_env) is not part of
+the runtime .env. vars:sync hands it to the image build as one environment
+secret and the build labels the image with its fingerprint; when that no
+longer matches, applying config rebuilds the image instead of restarting.
+"""
+
+import base64
+import hashlib
+import json
+import shlex
+import subprocess
+import sys
+import time
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Dict, List, Optional
+
+from cli.core.config_contract import ContractViolation
+from cli.exceptions import ConfigContractError
+from cli.marker_manager import AppMarker, MarkerManager
+from cli.secret_manager import SecretManager
+from .config_service import ConfigService
+from .vm_service import VMService
+
+# Registry credentials are repo secrets for the build job, never runtime env
+DOCKER_KEYS = {"DOCKER_ORG", "DOCKER_USERNAME", "DOCKER_TOKEN", "DOCKER_REGISTRY"}
+
+# Written next to .env; deploy workflows skip their own .env step when present
+MANAGED_MARKER = ".env.managed"
+
+# Build-time config reaches the image build as one JSON environment secret
+BUILD_ENV_SECRET = "SUPERDEPLOY_BUILD_ENV"
+
+# Image label holding build_config_fingerprint() of the config it was built with
+BUILD_CONFIG_LABEL = "superdeploy.build-config"
+
+# Compose label naming the app a service belongs to (project-deployer template)
+APP_LABEL = "com.superdeploy.app"
+
+# Secret files live on tmpfs: gone on reboot, restored by config:push or restart
+SECRET_FILES_ROOT = "/run/superdeploy"
+
+# Lists the files and their mount targets; also read by the deploy workflows
+FILES_MANIFEST = ".files.json"
+
+# Runs on the VM as root with a JSON payload on stdin. Writes the files into a
+# fresh directory (0700, files 0444 so any container user can read its own
+# bind mount), swaps it in keeping .previous, and rewrites the secret mounts of
+# the app's services (exact names or the app label) in docker-compose.yml.
+# action=rollback swaps .previous back.
+FILES_SCRIPT = r"""
+import json, os, shutil, sys, yaml
+
+APP_LABEL = "com.superdeploy.app"
+payload = json.load(sys.stdin)
+root, app = payload["dir"], payload["app"]
+previous, tmp = root + ".previous", root + ".tmp"
+
+def fstype(path):
+    best, kind = "", None
+    with open("/proc/mounts") as f:
+        for line in f:
+            point, fs = line.split()[1:3]
+            if (path + "/").startswith(point.rstrip("/") + "/") and len(point) > len(best):
+                best, kind = point, fs
+    return kind
+
+def mounts():
+    manifest = os.path.join(root, ".files.json")
+    if not os.path.exists(manifest):
+        return []
+    with open(manifest) as f:
+        return json.load(f)["mounts"]
+
+if payload["action"] == "rollback":
+    if not os.path.isdir(previous):
+        sys.exit(0)
+    shutil.rmtree(root, ignore_errors=True)
+    os.rename(previous, root)
+else:
+    os.makedirs(os.path.dirname(root), mode=0o700, exist_ok=True)
+    if fstype(os.path.dirname(root)) != "tmpfs":
+        sys.exit("%s is not on tmpfs, refusing to write secret files" % root)
+    shutil.rmtree(tmp, ignore_errors=True)
+    os.mkdir(tmp, 0o700)
+    entries = []
+    for name, spec in sorted(payload["files"].items()):
+        fd = os.open(os.path.join(tmp, name), os.O_WRONLY | os.O_CREAT | os.O_EXCL, 0o444)
+        with os.fdopen(fd, "w") as f:
+            f.write(spec["content"])
+        entries.append({
+            "type": "bind",
+            "source": os.path.join(root, name),
+            "target": spec["mount_path"],
+            "read_only": True,
+            "bind": {"create_host_path": False},
+        })
+    fd = os.open(os.path.join(tmp, ".files.json"), os.O_WRONLY | os.O_CREAT, 0o400)
+    with os.fdopen(fd, "w") as f:
+        json.dump({"sha256": payload["sha256"], "mounts": entries}, f)
+    shutil.rmtree(previous, ignore_errors=True)
+    if os.path.isdir(root):
+        os.rename(root, previous)
+    elif os.path.exists(root):
+        os.remove(root)
+    os.rename(tmp, root)
+
+compose_file = payload["compose_file"]
+if os.path.exists(compose_file):
+    with open(compose_file) as f:
+        compose = yaml.safe_load(f) or {}
+    ours = root + "/"
+    for name, service in (compose.get("services") or {}).items():
+        if not isinstance(service, dict):
+            continue
+        labels = service.get("labels") or {}
+        if isinstance(labels, list):
+            labels = dict(label.partition("=")[::2] for label in labels)
+        if name not in payload["services"] and labels.get(APP_LABEL) != app:
+            continue
+        volumes = [
+            v for v in service.get("volumes") or []
+            if not (v.get("source", "") if isinstance(v, dict) else str(v)).startswith(ours)
+        ] + mounts()
+        if volumes:
+            service["volumes"] = volumes
+        else:
+            service.pop("volumes", None)
+    with open(compose_file, "w") as f:
+        yaml.dump(compose, f, default_flow_style=False, sort_keys=False)
+"""
+
+
+def format_env_value(value: str) -> str:
+    """Quote a value so docker compose reads it back verbatim"""
+    value = str(value)
+    plain = value.strip() == value and not any(c in value for c in "$#'\"\n\\ ")
+    if plain:
+        return value
+    if "'" not in value and "\n" not in value:
+        # Single quotes: no interpolation, no escapes
+        return f"'{value}'"
+    escaped = (
+        value.replace("\\", "\\\\")
+        .replace('"', '\\"')
+        .replace("\n", "\\n")
+        .replace("$", "$$")
+    )
+    return f'"{escaped}"'
+
+
+def render_env_file(env: Dict[str, str]) -> str:
+    """Render KEY=value lines (sorted, trailing newline)"""
+    lines = [f"{key}={format_env_value(env[key])}" for key in sorted(env)]
+    return "\n".join(lines) + "\n" if lines else ""
+
+
+def files_checksum(files: Dict[str, Dict[str, str]]) -> Optional[str]:
+    """sha256 over names, mount paths and contents (None if no files)"""
+    if not files:
+        return None
+    digest = hashlib.sha256()
+    for name in sorted(files):
+        spec = files[name]
+        digest.update(f"{name}\0{spec['mount_path']}\0".encode())
+        digest.update(hashlib.sha256(spec["content"].encode()).digest())
+    return digest.hexdigest()
+
+
+def canonical_env_json(env: Dict[str, str]) -> str:
+    """Compact, key-sorted JSON; the build workflows hash these exact bytes"""
+    return json.dumps(
+        {key: str(value) for key, value in env.items()},
+        sort_keys=True,
+        separators=(",", ":"),
+    )
+
+
+def build_config_fingerprint(env: Dict[str, str]) -> str:
+    """sha256 of the build-time config, as stored in the image label"""
+    return hashlib.sha256(canonical_env_json(env).encode()).hexdigest()
+
+
+def encode_build_env(env: Dict[str, str]) -> str:
+    """Value of the SUPERDEPLOY_BUILD_ENV secret (base64 keeps it one line)"""
+    return base64.b64encode(canonical_env_json(env).encode()).decode()
+
+
+@dataclass
+class EnvPushResult:
+    """Outcome of pushing one app's .env"""
+
+    app: str
+    vm_name: str
+    vm_ip: str
+    checksum: str
+    key_count: int
+    changed: bool
+    file_count: int = 0
+    env_changed: bool = False
+    files_changed: bool = False
+    # Running image was built with different build-time config
+    build_changed: bool = False
+
+
+@dataclass
+class RolloutResult:
+    """Outcome of a rolling restart"""
+
+    app: str
+    restarted: List[str] = field(default_factory=list)
+    failed: Optional[str] = None
+    output: str = ""
+    rolled_back: bool = False
+    # What apply() did: restarted, rebuild (triggered), unchanged or skipped
+    action: str = "restarted"
+
+    @property
+    def success(self) -> bool:
+        return self.failed is None
+
+
+class AppEnvService:
+    """
+    Orchestrator-side app environment management.
+
+    Responsibilities:
+    - Build an app's env from SecretManager (shared + app + aliases + templates)
+    - Write it to /opt/superdeploy/projects/<project>/data/<app>/.env atomically
+    - Write secret files to /run/superdeploy/<project>/<app> and mount them
+    - Recreate the app's compose services one by one, waiting for health
+    - Rebuild the image instead when its build-time config changed
+    """
+
+    def __init__(
+        self, project_root: Path, project_name: str, environment: str = "production"
+    ):
+        self.project_root = project_root
+        self.project_name = project_name
+        self.environment = environment
+        self.secret_manager = SecretManager(project_root, project_name, environment)
+        self.config_service = ConfigService(project_root)
+        self.vm_service = VMService(project_root, project_name)
+        self._ssh = None
+
+    @property
+    def ssh(self):
+        if self._ssh is None:
+            self._ssh = self.vm_service.get_ssh_service()
+        return self._ssh
+
+    @property
+    def compose_dir(self) -> str:
+        return f"/opt/superdeploy/projects/{self.project_name}/compose"
+
+    def env_dir(self, app_name: str) -> str:
+        return f"/opt/superdeploy/projects/{self.project_name}/data/{app_name}"
+
+    def files_dir(self, app_name: str) -> str:
+        return f"{SECRET_FILES_ROOT}/{self.project_name}/{app_name}"
+
+    def marker(self, app_name: str) -> Optional[AppMarker]:
+        """The app's superdeploy marker (None if the app has no local path)"""
+        try:
+            app_config = self.config_service.get_app_config(
+                self.project_name, app_name
+            )
+        except KeyError:
+            return None
+        app_path = app_config.get("path")
+        if not app_path:
+            return None
+        return MarkerManager.load_marker(Path(app_path).expanduser())
+
+    def env_templates(self, app_name: str) -> Dict[str, str]:
+        """env_templates from the app's superdeploy marker ({} if none)"""
+        marker = self.marker(app_name)
+        if marker and marker.has_env_templates():
+            return marker.env_templates
+        return {}
+
+    def check_contract(
+        self, app_name: str, env: Optional[Dict[str, str]] = None
+    ) -> List[ContractViolation]:
+        """
+        Validate an env (default: build_env) against the marker's config contract.
+
+        Returns:
+            Violations ([] if the app declares no contract)
+        """
+        marker = self.marker(app_name)
+        if not marker or not marker.has_config_contract():
+            return []
+        if env is None:
+            env = self.build_env(app_name)
+        return marker.config.validate(env)
+
+    def usages(self, app_name: str) -> Dict[str, str]:
+        """
+        runtime/build/both per key.
+
+        Stored secrets carry their own usage; marker build_env keys are
+        both. Keys not listed (aliases, templates) are runtime.
+        """
+        usages = self.secret_manager.get_usages(app_name)
+        marker = self.marker(app_name)
+        if marker:
+            for key in marker.build_env:
+                if usages.get(key) != "build":
+                    usages[key] = "both"
+        return usages
+
+    def _merged_env(self, app_name: str) -> Dict[str, str]:
+        env_templates = self.env_templates(app_name)
+        if env_templates:
+            secrets = self.secret_manager.get_app_secrets_with_templates(
+                app_name, env_templates
+            )
+        else:
+            secrets = self.secret_manager.get_app_secrets(app_name)
+
+        return {
+            key: value
+            for key, value in secrets.items()
+            if key not in DOCKER_KEYS and "." not in key and value is not None
+        }
+
+    def split_env(self, app_name: str) -> tuple[Dict[str, str], Dict[str, str]]:
+        """(runtime env, build-time env); keys tagged both are in each"""
+        usages = self.usages(app_name)
+        runtime, buildtime = {}, {}
+        for key, value in self._merged_env(app_name).items():
+            usage = usages.get(key, "runtime")
+            if usage != "build":
+                runtime[key] = value
+            if usage != "runtime":
+                buildtime[key] = value
+        return runtime, buildtime
+
+    def build_env(self, app_name: str) -> Dict[str, str]:
+        """
+        Runtime env for an app.
+
+        Same merge as vars:sync minus the developer's local .env: database
+        secrets with aliases, marker env_templates resolved, registry
+        credentials, dotted addon keys (postgres.primary.HOST) and
+        build-only keys dropped.
+        """
+        return self.split_env(app_name)[0]
+
+    def buildtime_env(self, app_name: str) -> Dict[str, str]:
+        """Config passed to the image build (keys tagged build or both)"""
+        return self.split_env(app_name)[1]
+
+    def image_build_fingerprint(self, vm_ip: str, app_name: str) -> Optional[str]:
+        """
+        Build-config label of the app's running image.
+
+        None if unknown: nothing running, or an image built before the
+        label existed. Unknown never triggers a rebuild.
+        """
+        services = self.services(vm_ip, app_name)
+        if not services:
+            return None
+        template = "{{ index .Config.Labels " + json.dumps(BUILD_CONFIG_LABEL) + " }}"
+        result = self.ssh.execute_command(
+            vm_ip,
+            f"cd {self.compose_dir} && docker compose ps -q "
+            f"{shlex.quote(services[0])} | head -1 | "
+            f"xargs -r docker inspect --format {shlex.quote(template)}",
+        )
+        label = result.stdout.strip() if result.is_success else ""
+        return label if label and label != "<no value>" else None
+
+    def trigger_rebuild(self, app_name: str) -> None:
+        """
+        Rebuild and redeploy an app with its current build-time config.
+
+        Syncs the app's GitHub environment secrets (SUPERDEPLOY_BUILD_ENV),
+        then pushes an empty commit to the environment branch.
+
+        Raises:
+            RuntimeError: If the sync or the push fails
+        """
+        sync = subprocess.run(
+            [
+                sys.executable,
+                "-m",
+                "cli.main",
+                f"{self.project_name}:vars:sync",
+                "-e",
+                self.environment,
+                "-a",
+                app_name,
+                "--no-propagate",
+            ],
+            capture_output=True,
+            text=True,
+            timeout=600,
+        )
+        if sync.returncode != 0:
+            raise RuntimeError(
+                f"vars:sync failed, not rebuilding {app_name}: "
+                f"{(sync.stderr or sync.stdout).strip()[-300:]}"
+            )
+
+        app_path = self.config_service.get_app_config(
+            self.project_name, app_name
+        ).get("path")
+        if not app_path:
+            raise RuntimeError(f"No local path for {app_name}, can't trigger a build")
+        app_path = Path(app_path).expanduser()
+        subprocess.run(
+            ["git", "commit", "--allow-empty", "-m", "config: build-time change"],
+            cwd=app_path,
+            capture_output=True,
+            text=True,
+        )
+        push = subprocess.run(
+            ["git", "push", "origin", self.environment],
+            cwd=app_path,
+            capture_output=True,
+            text=True,
+        )
+        if push.returncode != 0:
+            raise RuntimeError(
+                f"git push failed for {app_name}: {push.stderr.strip()[-300:]}"
+            )
+
+    def render(self, app_name: str) -> str:
+        return render_env_file(self.build_env(app_name))
+
+    def build_files(self, app_name: str) -> Dict[str, Dict[str, str]]:
+        """Secret files for an app: name -> {mount_path, content}"""
+        return self.secret_manager.get_app_files(app_name)
+
+    def remote_checksum(self, vm_ip: str, app_name: str) -> Optional[str]:
+        """sha256 of the .env currently on the VM (None if missing)"""
+        path = shlex.quote(f"{self.env_dir(app_name)}/.env")
+        result = self.ssh.execute_command(
+            vm_ip, f"sudo sha256sum {path} 2>/dev/null | cut -d' ' -f1"
+        )
+        checksum = result.stdout.strip() if result.is_success else ""
+        return checksum or None
+
+    def remote_files_checksum(self, vm_ip: str, app_name: str) -> Optional[str]:
+        """Checksum of the secret files on the VM (None if none, e.g. after reboot)"""
+        manifest = shlex.quote(f"{self.files_dir(app_name)}/{FILES_MANIFEST}")
+        result = self.ssh.execute_command(vm_ip, f"sudo cat {manifest} 2>/dev/null")
+        if result.is_failure or not result.stdout.strip():
+            return None
+        try:
+            return json.loads(result.stdout).get("sha256")
+        except ValueError:
+            return None
+
+    def missing_files(self, vm_ip: str, app_name: str) -> List[str]:
+        """
+        Secret files the app's services mount that are gone from the VM.
+
+        tmpfs is empty after a reboot, and create_host_path: false keeps
+        those containers from starting until the files are pushed again.
+        """
+        result = self.ssh.execute_command(
+            vm_ip, f"cd {self.compose_dir} && docker compose config --format json"
+        )
+        if result.is_failure:
+            return []
+        try:
+            compose = json.loads(result.stdout)
+        except ValueError:
+            return []
+
+        ours = self.files_dir(app_name) + "/"
+        services = set(self.services(vm_ip, app_name))
+        sources = sorted(
+            {
+                volume.get("source", "")
+                for name, service in (compose.get("services") or {}).items()
+                if name in services
+                for volume in (service or {}).get("volumes") or []
+                if isinstance(volume, dict)
+                and volume.get("source", "").startswith(ours)
+            }
+        )
+        if not sources:
+            return []
+
+        listing = self.ssh.execute_command(
+            vm_ip,
+            f"sudo ls -1A {shlex.quote(self.files_dir(app_name))} 2>/dev/null",
+        )
+        present = set(listing.stdout.split()) if listing.is_success else set()
+        return [
+            source for source in sources if source[len(ours) :] not in present
+        ]
+
+    def restore_files(self, app_name: str) -> List[str]:
+        """
+        Write the app's secret files again if the VM lost them (reboot).
+
+        Returns:
+            The mount sources that were missing ([] if none)
+        """
+        vm_name, vm_ip = self.vm_service.get_vm_for_app(app_name)
+        missing = self.missing_files(vm_ip, app_name)
+        if missing:
+            files = self.build_files(app_name)
+            self.push_files(app_name, vm_name, vm_ip, files, files_checksum(files))
+        return missing
+
+    def _run_files_script(self, vm_ip: str, app_name: str, payload: dict):
+        payload = {
+            "app": app_name,
+            "services": self.service_names(app_name),
+            "dir": self.files_dir(app_name),
+            "compose_file": f"{self.compose_dir}/docker-compose.yml",
+            **payload,
+        }
+        return self.ssh.execute_command(
+            vm_ip,
+            f"sudo python3 -c {shlex.quote(FILES_SCRIPT)}",
+            input_data=json.dumps(payload),
+        )
+
+    def push_files(
+        self,
+        app_name: str,
+        vm_name: str,
+        vm_ip: str,
+        files: Dict[str, Dict[str, str]],
+        checksum: Optional[str],
+    ) -> None:
+        """
+        Write secret files to tmpfs on the VM and point the compose mounts at them.
+
+        Raises:
+            RuntimeError: If the remote write fails
+        """
+        result = self._run_files_script(
+            vm_ip,
+            app_name,
+            {"action": "write", "files": files, "sha256": checksum},
+        )
+        if result.is_failure:
+            raise RuntimeError(
+                f"Failed to write secret files for {app_name} on {vm_name}: "
+                f"{result.output}"
+            )
+
+    def push(
+        self, app_name: str, force: bool = False, enforce_contract: bool = True
+    ) -> EnvPushResult:
+        """
+        Write the rendered .env and the app's secret files to its VM.
+
+        The file is staged next to the target and moved into place, so
+        containers never see a half-written .env. The previous file is kept
+        as .env.previous for rollback. Secret files are only rewritten when
+        their checksum differs from the VM's (or the VM lost them on reboot).
+
+        Raises:
+            ConfigContractError: If the env violates the app's config contract
+            RuntimeError: If the remote write fails
+        """
+        env, buildtime = self.split_env(app_name)
+        if enforce_contract:
+            violations = self.check_contract(app_name, env)
+            if violations:
+                raise ConfigContractError(app_name, violations)
+        content = render_env_file(env)
+        checksum = hashlib.sha256(content.encode()).hexdigest()
+        files = self.build_files(app_name)
+        files_sha = files_checksum(files)
+        vm_name, vm_ip = self.vm_service.get_vm_for_app(app_name)
+
+        image_sha = self.image_build_fingerprint(vm_ip, app_name)
+        build_changed = image_sha is not None and image_sha != (
+            build_config_fingerprint(buildtime)
+        )
+
+        env_changed = force or self.remote_checksum(vm_ip, app_name) != checksum
+        files_changed = (
+            self.remote_files_checksum(vm_ip, app_name) != files_sha
+            or (force and files_sha is not None)
+        )
+        result = EnvPushResult(
+            app_name,
+            vm_name,
+            vm_ip,
+            checksum,
+            len(env),
+            env_changed or files_changed or build_changed,
+            file_count=len(files),
+            env_changed=env_changed,
+            files_changed=files_changed,
+            build_changed=build_changed,
+        )
+
+        if files_changed:
+            self.push_files(app_name, vm_name, vm_ip, files, files_sha)
+
+        if not env_changed:
+            self.secret_manager.record_dependencies(app_name)
+            return result
+
+        env_dir = shlex.quote(self.env_dir(app_name))
+        managed = json.dumps(
+            {
+                "source": "superdeploy",
+                "environment": self.environment,
+                "sha256": checksum,
+                "keys": len(env),
+                "rendered_at": datetime.now(timezone.utc).isoformat(),
+            }
+        )
+        script = " && ".join(
+            [
+                f"sudo mkdir -p {env_dir}",
+                f"TMP={env_dir}/.env.tmp.$$",
+                'sudo tee "$TMP" > /dev/null',
+                'sudo chmod 600 "$TMP"',
+                'sudo chown superdeploy:superdeploy "$TMP"',
+                f"{{ [ ! -f {env_dir}/.env ] || sudo cp -p {env_dir}/.env {env_dir}/.env.previous; }}",
+                f'sudo mv -f "$TMP" {env_dir}/.env',
+                f"echo {shlex.quote(managed)} | sudo tee {env_dir}/{MANAGED_MARKER} > /dev/null",
+            ]
+        )
+
+        written = self.ssh.execute_command(vm_ip, script, input_data=content)
+        if written.is_failure:
+            raise RuntimeError(
+                f"Failed to write .env for {app_name} on {vm_name}: {written.output}"
+            )
+
+        # The VM now has the current app./org./project. source values
+        self.secret_manager.record_dependencies(app_name)
+        return result
+
+    def service_names(self, app_name: str) -> List[str]:
+        """
+        Compose service names of an app's processes (<app>-<process>).
+
+        Exact names, not a prefix: app api must not pick up api-gateway-web.
+        """
+        try:
+            app_config = self.config_service.get_app_config(
+                self.project_name, app_name
+            )
+        except KeyError:
+            app_config = {}
+        processes = set(app_config.get("processes") or {})
+        marker = self.marker(app_name)
+        if marker:
+            processes.update(marker.processes)
+        return sorted(f"{app_name}-{process}" for process in processes)
+
+    def services(self, vm_ip: str, app_name: str) -> List[str]:
+        """
+        Compose services of an app on its VM: its processes' services, plus
+        any service labelled com.superdeploy.app=<app>
+        """
+        result = self.ssh.execute_command(
+            vm_ip, f"cd {self.compose_dir} && docker compose config --format json"
+        )
+        if result.is_failure:
+            return []
+        try:
+            compose = json.loads(result.stdout)
+        except ValueError:
+            return []
+        names = set(self.service_names(app_name))
+        return sorted(
+            name
+            for name, service in (compose.get("services") or {}).items()
+            if name in names
+            or ((service or {}).get("labels") or {}).get(APP_LABEL) == app_name
+        )
+
+    def wait_healthy(self, vm_ip: str, service: str, timeout: int = 120) -> bool:
+        """Wait until every container of a service is running (and healthy)"""
+        deadline = time.time() + timeout
+        command = (
+            f"cd {self.compose_dir} && docker compose ps {shlex.quote(service)} "
+            "--format '{{.State}} {{.Health}}'"
+        )
+        while time.time() < deadline:
+            result = self.ssh.execute_command(vm_ip, command)
+            states = [line.split() for line in result.stdout.splitlines() if line]
+            if states and all(
+                state[0] == "running" and (len(state) == 1 or state[1] == "healthy")
+                for state in states
+            ):
+                return True
+            time.sleep(3)
+        return False
+
+    def rolling_restart(
+        self,
+        app_name: str,
+        vm_ip: str,
+        timeout: int = 120,
+        restore_env: bool = True,
+        restore_files: bool = False,
+    ) -> RolloutResult:
+        """
+        Recreate the app's services one at a time so they re-read .env and
+        remount secret files.
+
+        If a service doesn't come back healthy, .env.previous (and the
+        previous secret files, with restore_files) are restored and the
+        services touched so far are recreated with them.
+        """
+        rollout = RolloutResult(app=app_name)
+
+        for service in self.services(vm_ip, app_name):
+            result = self.ssh.execute_command(
+                vm_ip,
+                f"cd {self.compose_dir} && docker compose up -d --no-deps "
+                f"--force-recreate {shlex.quote(service)}",
+                timeout=timeout,
+            )
+            if result.is_failure or not self.wait_healthy(vm_ip, service, timeout):
+                rollout.failed = service
+                rollout.output = result.output
+                self.rollback(
+                    app_name,
+                    vm_ip,
+                    rollout.restarted + [service],
+                    restore_env=restore_env,
+                    restore_files=restore_files,
+                )
+                rollout.rolled_back = True
+                return rollout
+            rollout.restarted.append(service)
+
+        return rollout
+
+    def rollback(
+        self,
+        app_name: str,
+        vm_ip: str,
+        services: List[str],
+        restore_env: bool = True,
+        restore_files: bool = False,
+    ) -> None:
+        """Restore .env.previous / previous secret files and recreate services"""
+        env_dir = shlex.quote(self.env_dir(app_name))
+        if restore_env:
+            self.ssh.execute_command(
+                vm_ip,
+                f"[ -f {env_dir}/.env.previous ] && "
+                f"sudo cp -p {env_dir}/.env.previous {env_dir}/.env",
+            )
+        if restore_files:
+            self._run_files_script(vm_ip, app_name, {"action": "rollback"})
+        if services:
+            names = " ".join(shlex.quote(s) for s in services)
+            self.ssh.execute_command(
+                vm_ip,
+                f"cd {self.compose_dir} && docker compose up -d --no-deps "
+                f"--force-recreate {names}",
+                timeout=300,
+            )
+
+    def apply(
+        self,
+        app_name: str,
+        restart: bool = True,
+        force: bool = False,
+        enforce_contract: bool = True,
+    ) -> tuple[EnvPushResult, RolloutResult]:
+        """
+        Push the .env and secret files and, if they changed, roll the services.
+
+        If the build-time config changed, a rebuild is triggered instead:
+        restarting would keep running an image built with the old values.
+        The rollout's action says which happened (restarted, rebuild,
+        unchanged, or skipped with restart=False).
+        """
+        pushed = self.push(app_name, force=force, enforce_contract=enforce_contract)
+        if restart and pushed.build_changed:
+            self.trigger_rebuild(app_name)
+            return pushed, RolloutResult(app=app_name, action="rebuild")
+        if not pushed.changed:
+            return pushed, RolloutResult(app=app_name, action="unchanged")
+        if not restart:
+            return pushed, RolloutResult(app=app_name, action="skipped")
+        return pushed, self.rolling_restart(
+            app_name,
+            pushed.vm_ip,
+            restore_env=pushed.env_changed,
+            restore_files=pushed.files_changed,
+        )
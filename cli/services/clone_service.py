@@ -0,0 +1,363 @@
+"""
+Clone Service
+
+Copies a project into a new project (staging copy) or copies its secrets
+into another environment. Definitions go through the manifest path
+(ManifestService), so a clone is what `export --manifest` + `apply` of a
+renamed project would create; plain secrets and secret files are copied
+row by row since manifests only carry references.
+
+Addon credentials are never copied: the clone's `up` generates its own,
+so staging can't reach production's databases with a copied password.
+"""
+
+import copy
+import os
+import re
+import subprocess
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Dict, List, Optional, Tuple
+
+from cli.database import get_db_session, Addon, Project, Secret, SecretFile
+
+# Hostnames inside secret values (https://api.acme.com/callback, ALLOWED_HOSTS)
+HOSTNAME_BOUNDARY = r"(?<![A-Za-z0-9.-]){}(?![A-Za-z0-9-])"
+
+# Databases a backup dump can be loaded into (see BackupService)
+SEEDABLE_ADDONS = ("postgres", "mongodb")
+
+
+@dataclass
+class CopyResult:
+    """What copy_secrets did"""
+
+    secrets: int = 0
+    files: int = 0
+    rewritten: int = 0  # Values with a domain rewritten
+    regenerated: int = 0  # Addon credentials left for `up` to generate
+    skipped: List[str] = field(default_factory=list)
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "secrets": self.secrets,
+            "files": self.files,
+            "rewritten": self.rewritten,
+            "regenerated": self.regenerated,
+            "skipped": self.skipped,
+        }
+
+
+def parse_domain_patterns(values: List[str]) -> List[Tuple[str, str]]:
+    """
+    --domain-pattern FROM=TO options. FROM may contain one `*`, which TO
+    repeats: '*.acme.com=*.staging.acme.com', 'acme.com=staging.acme.com'
+
+    Raises:
+        ValueError: If a pattern is malformed
+    """
+    patterns = []
+    for value in values:
+        source, sep, target = value.partition("=")
+        source, target = source.strip().lower(), target.strip().lower()
+        if not sep or not source or not target:
+            raise ValueError(f"expected FROM=TO, got: {value}")
+        if source.count("*") > 1 or target.count("*") > source.count("*"):
+            raise ValueError(f"FROM may have one *, TO only if FROM has: {value}")
+        patterns.append((source, target))
+    return patterns
+
+
+def rewrite_domain(hostname: str, patterns: List[Tuple[str, str]]) -> Optional[str]:
+    """New hostname from the first matching pattern (None if none match)"""
+    hostname = hostname.lower()
+    for source, target in patterns:
+        if "*" not in source:
+            if hostname == source:
+                return target
+            continue
+        prefix, suffix = source.split("*")
+        if (
+            hostname.startswith(prefix)
+            and hostname.endswith(suffix)
+            and len(hostname) > len(prefix) + len(suffix)
+        ):
+            matched = hostname[len(prefix) : len(hostname) - len(suffix)]
+            return target.replace("*", matched)
+    return None
+
+
+class ProjectCloneService:
+    """Project → project (or environment → environment) copies."""
+
+    def clone_manifest(
+        self,
+        manifest: Dict[str, Any],
+        target: str,
+        patterns: List[Tuple[str, str]],
+        subnets: Optional[Tuple[str, str]] = None,
+        environment: str = "staging",
+    ) -> Tuple[Dict[str, Any], Dict[str, Optional[str]]]:
+        """
+        Source project's exported manifest → manifest of the clone.
+
+        Args:
+            manifest: ManifestService.export() of the source
+            target: Clone's project name
+            patterns: Domain patterns (unmatched domains are dropped)
+            subnets: (vpc_subnet, docker_subnet) allocated for the clone
+            environment: Clone's project environment (never production by
+                accident: addons:copy can only write into non-production)
+
+        Returns:
+            (manifest, {old domain: new domain or None if dropped})
+        """
+        clone = copy.deepcopy(manifest)
+        source = clone["project"]["name"]
+        clone["project"]["name"] = target
+        if clone["project"].get("description") == f"{source} project":
+            clone["project"]["description"] = f"{target} project"
+        clone["project"]["environment"] = environment
+
+        # Secrets are copied separately (plain values aren't in manifests)
+        clone.pop("secrets", None)
+
+        network = clone.setdefault("network", {})
+        network.pop("vpc_subnet", None)
+        network.pop("docker_subnet", None)
+        if subnets:
+            network["vpc_subnet"], network["docker_subnet"] = subnets
+        if not network:
+            clone.pop("network")
+
+        domains: Dict[str, Optional[str]] = {}
+
+        def move(entry: Dict[str, Any]) -> None:
+            domain = entry.get("domain")
+            if not domain:
+                return
+            domains[domain] = rewrite_domain(domain, patterns)
+            if domains[domain]:
+                entry["domain"] = domains[domain]
+            else:
+                # Two projects can't serve (or get certificates for) one host
+                del entry["domain"]
+
+        move(clone["project"])
+        for app in (clone.get("apps") or {}).values():
+            move(app)
+        return clone, domains
+
+    def copy_secrets(
+        self,
+        source: str,
+        target: str,
+        from_env: str = "production",
+        to_env: str = "production",
+        domains: Optional[Dict[str, Optional[str]]] = None,
+    ) -> CopyResult:
+        """
+        Copy secrets and secret files of one environment, matching apps by
+        name. Keys the target already has are kept.
+
+        Args:
+            domains: Old → new hostnames to rewrite inside values
+        """
+        result = CopyResult()
+        rewrite = _value_rewriter(domains or {})
+
+        db = get_db_session()
+        try:
+            src = db.query(Project).filter(Project.name == source).first()
+            dst = db.query(Project).filter(Project.name == target).first()
+            src_apps = {app.id: app.name for app in src.apps}
+            dst_apps = {app.name: app.id for app in dst.apps}
+
+            def target_app(app_id: Optional[int], label: str) -> Tuple[bool, Any]:
+                if app_id is None:
+                    return True, None
+                name = src_apps.get(app_id)
+                if name not in dst_apps:
+                    result.skipped.append(f"{label} (app {name} not in {target})")
+                    return False, None
+                return True, dst_apps[name]
+
+            existing = {
+                (row.app_id, row.key)
+                for row in db.query(Secret).filter(
+                    Secret.project_id == dst.id, Secret.environment == to_env
+                )
+            }
+            rows = (
+                db.query(Secret)
+                .filter(Secret.project_id == src.id, Secret.environment == from_env)
+                .order_by(Secret.key)
+                .all()
+            )
+            for row in rows:
+                if row.source == "addon":
+                    result.regenerated += 1
+                    continue
+                label = f"{src_apps[row.app_id]}.{row.key}" if row.app_id else row.key
+                ok, app_id = target_app(row.app_id, label)
+                if not ok:
+                    continue
+                if (app_id, row.key) in existing:
+                    result.skipped.append(f"{label} (already set)")
+                    continue
+                value = rewrite(row.value)
+                if value != row.value:
+                    result.rewritten += 1
+                db.add(
+                    Secret(
+                        project_id=dst.id,
+                        app_id=app_id,
+                        key=row.key,
+                        value=value,
+                        environment=to_env,
+                        source=row.source,
+                        editable=row.editable,
+                        usage=row.usage,
+                    )
+                )
+                result.secrets += 1
+
+            existing_files = {
+                (row.app_id, row.name)
+                for row in db.query(SecretFile).filter(
+                    SecretFile.project_id == dst.id, SecretFile.environment == to_env
+                )
+            }
+            files = db.query(SecretFile).filter(
+                SecretFile.project_id == src.id, SecretFile.environment == from_env
+            )
+            for row in files.order_by(SecretFile.name).all():
+                label = f"{src_apps.get(row.app_id)}/{row.name}"
+                ok, app_id = target_app(row.app_id, label)
+                if not ok:
+                    continue
+                if (app_id, row.name) in existing_files:
+                    result.skipped.append(f"{label} (already set)")
+                    continue
+                db.add(
+                    SecretFile(
+                        project_id=dst.id,
+                        app_id=app_id,
+                        name=row.name,
+                        mount_path=row.mount_path,
+                        content=row.content,
+                        environment=to_env,
+                    )
+                )
+                result.files += 1
+
+            db.commit()
+            return result
+        except Exception:
+            db.rollback()
+            raise
+        finally:
+            db.close()
+
+    @staticmethod
+    def latest_backup(source: str, backups_dir: Path) -> Optional[Path]:
+        """Newest database dump written by `<source>:backups:create`"""
+        dumps = sorted(Path(backups_dir).glob(f"{source}_*/database.sql"))
+        return dumps[-1] if dumps else None
+
+    @staticmethod
+    def seed_target(project: str) -> Optional[Tuple[str, Dict[str, str]]]:
+        """
+        Database addon a dump is loaded into, as (addon type, config for
+        BackupService) with the credentials `up` generated for it.
+        """
+        db = get_db_session()
+        try:
+            dst = db.query(Project).filter(Project.name == project).first()
+            addon = (
+                db.query(Addon)
+                .filter(
+                    Addon.project_id == dst.id,
+                    Addon.category == "databases",
+                    Addon.type.in_(SEEDABLE_ADDONS),
+                )
+                .order_by(Addon.id)
+                .first()
+            )
+            if not addon:
+                return None
+            prefix = f"{addon.type}.{addon.instance_name}."
+            credentials = {
+                row.key[len(prefix) :]: row.value
+                for row in db.query(Secret).filter(
+                    Secret.project_id == dst.id,
+                    Secret.source == "addon",
+                    Secret.key.startswith(prefix),
+                )
+            }
+        finally:
+            db.close()
+
+        config = {"instance": addon.instance_name}
+        if credentials.get("USER"):
+            config["user"] = credentials["USER"]
+        if credentials.get("DATABASE"):
+            config["database"] = credentials["DATABASE"]
+        return addon.type, config
+
+    @staticmethod
+    def retarget_dump(dump: str, source: str, target: str) -> str:
+        """
+        pg_dump output grants to and sets owners to the source's role,
+        which doesn't exist in the clone's database.
+        """
+        role = re.compile(rf"\b{re.escape(source)}_user\b")
+        lines = []
+        for line in dump.splitlines(keepends=True):
+            if line.startswith(("ALTER ", "GRANT ", "REVOKE ")):
+                line = role.sub(f"{target}_user", line)
+            lines.append(line)
+        return "".join(lines)
+
+    @staticmethod
+    def anonymize(dump: str, command: str, source: str, target: str) -> str:
+        """
+        Pipe a dump through an anonymization hook (stdin → stdout).
+
+        Raises:
+            RuntimeError: If the hook fails or prints nothing
+        """
+        env = {
+            **os.environ,
+            "SUPERDEPLOY_SOURCE_PROJECT": source,
+            "SUPERDEPLOY_TARGET_PROJECT": target,
+        }
+        result = subprocess.run(
+            command,
+            shell=True,
+            input=dump,
+            capture_output=True,
+            text=True,
+            env=env,
+            timeout=3600,
+        )
+        if result.returncode != 0:
+            raise RuntimeError(
+                f"Anonymization hook failed ({result.returncode}): "
+                f"{result.stderr.strip()[-1000:]}"
+            )
+        if not result.stdout.strip():
+            raise RuntimeError("Anonymization hook printed nothing")
+        return result.stdout
+
+
+def _value_rewriter(domains: Dict[str, Optional[str]]):
+    """Replace old hostnames in a value in one pass (longest first)"""
+    moved = {old: new for old, new in domains.items() if new}
+    if not moved:
+        return lambda value: value
+    alternatives = "|".join(
+        re.escape(old) for old in sorted(moved, key=len, reverse=True)
+    )
+    pattern = re.compile(HOSTNAME_BOUNDARY.format(f"({alternatives})"), re.IGNORECASE)
+    return lambda value: pattern.sub(lambda m: moved[m.group(1).lower()], value)
@@ -0,0 +1,654 @@
+"""
+Deploy Policy Service
+
+Per-project deploy policies: change-freeze windows (recurring cron + duration
+or one-off), approvals production deploys need from N deployers, and
+time-boxed emergency overrides. The CLI, the dashboard and the deploy gate
+the self-hosted deploy jobs query all decide through evaluate(); policy
+changes, approvals, overrides and blocked deploys land in activity_logs.
+
+Targets name what is deployed: "<app>@<git sha>" for app releases, "up" for
+infrastructure deploys. Approvals of a short SHA (7+ hex characters) cover
+the full one. Approvers are identified by their gh CLI login, never by
+SUPERDEPLOY_ACTOR.
+"""
+
+import re
+from datetime import datetime, timedelta
+from typing import Dict, List, Optional, Set
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+from cli.constants import DEPLOY_APPROVAL_HOURS, DEPLOY_OVERRIDE_MAX_MINUTES
+from cli.database import (
+    get_db_session,
+    DeployApproval,
+    DeployFreeze,
+    DeployOverride,
+    DeployPolicy,
+    Project,
+)
+from cli.services.protection_service import audit, current_actor, github_login
+
+# Environments whose deploys need approvals
+APPROVAL_ENVIRONMENTS = ("production",)
+
+# Longest recurring freeze window (one week)
+MAX_FREEZE_MINUTES = 7 * 24 * 60
+
+# Approved SHAs: at least 7 hex characters, so a prefix can't cover
+# unrelated commits
+SHA_PATTERN = re.compile(r"^[0-9a-fA-F]{7,40}$")
+
+# (lowest, highest) per cron field: minute hour day-of-month month day-of-week
+CRON_FIELDS = [(0, 59), (0, 23), (1, 31), (1, 12), (0, 7)]
+
+
+class DeployBlockedError(ValueError):
+    """A freeze window or missing approvals block the deploy"""
+
+    def __init__(self, project_name: str, reasons: List[str]):
+        self.reasons = reasons
+        super().__init__(
+            f"Deploy to {project_name} blocked: {'; '.join(reasons)}. Emergency "
+            f"override: superdeploy {project_name}:deploys:override --reason '...'"
+        )
+
+
+def parse_duration(text: str) -> int:
+    """Minutes in "90m", "62h", "2d" or "1h30m" """
+    parts = re.findall(r"(\d+)\s*([dhm])", (text or "").lower())
+    if not parts or re.sub(r"[\d\sdhm]", "", text.lower()):
+        raise ValueError(f"Invalid duration '{text}' (use e.g. 90m, 62h, 2d)")
+    factors = {"d": 1440, "h": 60, "m": 1}
+    return sum(int(value) * factors[unit] for value, unit in parts)
+
+
+class CronSchedule:
+    """Five-field cron expression (minute hour day month weekday)."""
+
+    def __init__(self, expression: str):
+        fields = expression.split()
+        if len(fields) != 5:
+            raise ValueError(
+                f"Invalid cron '{expression}': expected 5 fields "
+                f"(minute hour day month weekday)"
+            )
+        self.expression = expression
+        self.values = [
+            self._parse(field, low, high)
+            for field, (low, high) in zip(fields, CRON_FIELDS)
+        ]
+        # Sunday is 0 and 7
+        if 7 in self.values[4]:
+            self.values[4].add(0)
+        # Day-of-month and weekday match either when both are restricted
+        self.any_day = fields[2] == "*"
+        self.any_weekday = fields[4] == "*"
+
+    def _parse(self, field: str, low: int, high: int) -> Set[int]:
+        values = set()
+        for part in field.split(","):
+            base, _, step = part.partition("/")
+            if base == "*":
+                start, end = low, high
+            elif "-" in base:
+                start, end = (int(v) for v in base.split("-", 1))
+            elif base.isdigit():
+                start = end = int(base)
+                if step:
+                    end = high
+            else:
+                raise ValueError(f"Invalid cron field '{field}' in {self.expression}")
+            if not (low <= start <= end <= high):
+                raise ValueError(f"Cron field '{field}' out of range {low}-{high}")
+            if step and not step.isdigit():
+                raise ValueError(f"Invalid cron step '{part}'")
+            values.update(range(start, end + 1, int(step or 1)))
+        return values
+
+    def matches(self, moment: datetime) -> bool:
+        minute, hour, day, month, weekday = self.values
+        if moment.minute not in minute or moment.hour not in hour:
+            return False
+        if moment.month not in month:
+            return False
+        day_ok = moment.day in day
+        weekday_ok = (moment.isoweekday() % 7) in weekday
+        if self.any_day or self.any_weekday:
+            return day_ok and weekday_ok
+        return day_ok or weekday_ok
+
+    def last_start(self, moment: datetime, within_minutes: int) -> Optional[datetime]:
+        """Latest matching minute in (moment - within_minutes, moment]"""
+        candidate = moment.replace(second=0, microsecond=0)
+        for _ in range(within_minutes):
+            if self.matches(candidate):
+                return candidate
+            candidate -= timedelta(minutes=1)
+        return None
+
+
+def same_target(approved: str, requested: str) -> bool:
+    """api@abc1234 approves api@abc1234def...; "up" approves "up" """
+    approved_app, _, approved_sha = approved.partition("@")
+    requested_app, _, requested_sha = requested.partition("@")
+    if approved_app != requested_app:
+        return False
+    if not approved_sha or not requested_sha:
+        return approved_sha == requested_sha
+    if not SHA_PATTERN.match(approved_sha):
+        return False
+    return requested_sha.lower().startswith(approved_sha.lower())
+
+
+def _iso(value: Optional[datetime]) -> Optional[str]:
+    return value.isoformat() if value else None
+
+
+def _timezone(name: str) -> ZoneInfo:
+    try:
+        return ZoneInfo(name)
+    except (ZoneInfoNotFoundError, ValueError):
+        raise ValueError(f"Unknown timezone '{name}'")
+
+
+def freeze_window(freeze: DeployFreeze, now: datetime) -> Optional[Dict]:
+    """{"start", "end"} (naive UTC) if the freeze covers now, else None"""
+    if not freeze.cron:
+        if freeze.starts_at <= now < freeze.ends_at:
+            return {"start": freeze.starts_at, "end": freeze.ends_at}
+        return None
+
+    zone = _timezone(freeze.timezone or "UTC")
+    local_now = now.replace(tzinfo=ZoneInfo("UTC")).astimezone(zone)
+    start = CronSchedule(freeze.cron).last_start(local_now, freeze.duration_minutes)
+    if not start:
+        return None
+    start = start.astimezone(ZoneInfo("UTC")).replace(tzinfo=None)
+    return {"start": start, "end": start + timedelta(minutes=freeze.duration_minutes)}
+
+
+class DeployPolicyService:
+    """Reads, changes and enforces deploy policies."""
+
+    def policy(self, project_name: str) -> Dict:
+        """Policy, freezes, pending approvals and the active override"""
+        now = datetime.utcnow()
+        db = get_db_session()
+        try:
+            policy = self._policy(db, project_name)
+            freezes = self._freezes(db, project_name)
+            approvals = self._approvals(db, project_name, now)
+            override = self._override(db, project_name, now)
+            return {
+                "project": project_name,
+                "environment": self._environment(db, project_name),
+                "approvals_required": policy.approvals_required if policy else 0,
+                "deployers": list(policy.deployers or []) if policy else [],
+                "freezes": [self._freeze_dict(f, now) for f in freezes],
+                "approvals": [
+                    {
+                        "target": a.target,
+                        "approver": a.approver,
+                        "expires_at": _iso(a.expires_at),
+                    }
+                    for a in approvals
+                ],
+                "override": self._override_dict(override),
+            }
+        finally:
+            db.close()
+
+    def set_policy(
+        self,
+        project_name: str,
+        approvals: Optional[int] = None,
+        deployers: Optional[List[str]] = None,
+        actor: Optional[str] = None,
+    ) -> Dict:
+        """
+        Change required approvals and/or the deployer list.
+
+        Raises:
+            ValueError: Unknown project, or fewer deployers than approvals
+        """
+        db = get_db_session()
+        try:
+            self._require_project(db, project_name)
+            policy = self._policy(db, project_name)
+            if not policy:
+                policy = DeployPolicy(
+                    project_name=project_name, approvals_required=0, deployers=[]
+                )
+                db.add(policy)
+            if approvals is not None:
+                if approvals < 0:
+                    raise ValueError("Approvals can't be negative")
+                policy.approvals_required = approvals
+            if deployers is not None:
+                policy.deployers = sorted({d.strip() for d in deployers if d.strip()})
+            # The deployer who asks can't approve their own deploy
+            if policy.approvals_required and (
+                len(policy.deployers or []) <= policy.approvals_required
+            ):
+                raise ValueError(
+                    f"{policy.approvals_required} approval(s) need at least "
+                    f"{policy.approvals_required + 1} deployers "
+                    f"(the requester can't approve)"
+                )
+            actor = actor or current_actor()
+            policy.updated_by = actor
+            audit(
+                db,
+                project_name,
+                "deploy-policy:set",
+                actor,
+                {
+                    "approvals_required": policy.approvals_required,
+                    "deployers": policy.deployers,
+                },
+            )
+            db.commit()
+            return {
+                "approvals_required": policy.approvals_required,
+                "deployers": list(policy.deployers),
+            }
+        finally:
+            db.close()
+
+    def add_freeze(
+        self,
+        project_name: str,
+        cron: Optional[str] = None,
+        duration: Optional[str] = None,
+        timezone: str = "UTC",
+        starts_at: Optional[datetime] = None,
+        ends_at: Optional[datetime] = None,
+        reason: Optional[str] = None,
+        actor: Optional[str] = None,
+    ) -> Dict:
+        """
+        Add a recurring (cron + duration, in timezone) or one-off freeze.
+
+        One-off start/end are interpreted in timezone.
+
+        Raises:
+            ValueError: Unknown project or invalid window
+        """
+        if bool(cron) == bool(starts_at or ends_at):
+            raise ValueError("Give either --cron with --duration or --from/--until")
+        zone = _timezone(timezone)
+
+        freeze = DeployFreeze(
+            project_name=project_name, timezone=timezone, reason=reason
+        )
+        if cron:
+            CronSchedule(cron)
+            if not duration:
+                raise ValueError("A recurring freeze needs --duration")
+            minutes = parse_duration(duration)
+            if not 0 < minutes <= MAX_FREEZE_MINUTES:
+                raise ValueError("Freeze duration must be between 1m and 7d")
+            freeze.cron = cron
+            freeze.duration_minutes = minutes
+        else:
+            if not (starts_at and ends_at):
+                raise ValueError("A one-off freeze needs --from and --until")
+            freeze.starts_at, freeze.ends_at = (
+                moment.replace(tzinfo=zone)
+                .astimezone(ZoneInfo("UTC"))
+                .replace(tzinfo=None)
+                for moment in (starts_at, ends_at)
+            )
+            if freeze.ends_at <= freeze.starts_at:
+                raise ValueError("Freeze must end after it starts")
+
+        db = get_db_session()
+        try:
+            self._require_project(db, project_name)
+            freeze.created_by = actor or current_actor()
+            db.add(freeze)
+            db.flush()
+            entry = self._freeze_dict(freeze, datetime.utcnow())
+            audit(db, project_name, "deploy-freeze:add", freeze.created_by, entry)
+            db.commit()
+            return entry
+        finally:
+            db.close()
+
+    def remove_freeze(
+        self, project_name: str, freeze_id: int, actor: Optional[str] = None
+    ) -> Dict:
+        """
+        Raises:
+            ValueError: No such freeze in the project
+        """
+        db = get_db_session()
+        try:
+            freeze = (
+                db.query(DeployFreeze)
+                .filter(
+                    DeployFreeze.project_name == project_name,
+                    DeployFreeze.id == freeze_id,
+                )
+                .first()
+            )
+            if not freeze:
+                raise ValueError(f"Freeze {freeze_id} not found in {project_name}")
+            entry = self._freeze_dict(freeze, datetime.utcnow())
+            db.delete(freeze)
+            audit(
+                db,
+                project_name,
+                "deploy-freeze:remove",
+                actor or current_actor(),
+                entry,
+            )
+            db.commit()
+            return entry
+        finally:
+            db.close()
+
+    def approve(
+        self, project_name: str, target: str, actor: Optional[str] = None
+    ) -> Dict:
+        """
+        Approve a deploy target for DEPLOY_APPROVAL_HOURS.
+
+        The approver is the gh CLI login unless `actor` is given by a caller
+        that authenticated it.
+
+        Raises:
+            ValueError: Unknown project, a SHA shorter than 7 hex characters,
+                no GitHub login, or the approver isn't a deployer
+        """
+        _, _, sha = target.partition("@")
+        if target != "up" and not SHA_PATTERN.match(sha):
+            raise ValueError(
+                f"Approve a commit SHA of at least 7 hex characters, not '{sha}'"
+            )
+        actor = actor or github_login()
+        if not actor:
+            raise ValueError(
+                "Approvals are tied to your GitHub login; run: gh auth login"
+            )
+        db = get_db_session()
+        try:
+            self._require_project(db, project_name)
+            policy = self._policy(db, project_name)
+            if not policy or actor not in (policy.deployers or []):
+                raise ValueError(
+                    f"{actor} is not a deployer of {project_name} "
+                    f"(see superdeploy {project_name}:deploys:policy)"
+                )
+            approval = DeployApproval(
+                project_name=project_name,
+                target=target,
+                approver=actor,
+                expires_at=datetime.utcnow() + timedelta(hours=DEPLOY_APPROVAL_HOURS),
+            )
+            db.add(approval)
+            audit(
+                db,
+                project_name,
+                "deploy:approve",
+                actor,
+                {"target": target, "expires_at": _iso(approval.expires_at)},
+            )
+            db.commit()
+            return {
+                "target": target,
+                "approver": actor,
+                "expires_at": _iso(approval.expires_at),
+            }
+        finally:
+            db.close()
+
+    def override(
+        self,
+        project_name: str,
+        reason: str,
+        minutes: int,
+        actor: Optional[str] = None,
+    ) -> Dict:
+        """
+        Let deploys skip freezes and approvals for the next `minutes`.
+
+        Raises:
+            ValueError: No reason, too long, or the actor isn't a deployer
+        """
+        if not (reason or "").strip():
+            raise ValueError("An emergency override needs a reason")
+        if not 0 < minutes <= DEPLOY_OVERRIDE_MAX_MINUTES:
+            raise ValueError(
+                f"Overrides last 1-{DEPLOY_OVERRIDE_MAX_MINUTES} minutes"
+            )
+        actor = actor or current_actor()
+        db = get_db_session()
+        try:
+            self._require_project(db, project_name)
+            policy = self._policy(db, project_name)
+            if policy and policy.deployers and actor not in policy.deployers:
+                raise ValueError(f"{actor} is not a deployer of {project_name}")
+            override = DeployOverride(
+                project_name=project_name,
+                reason=reason,
+                actor=actor,
+                expires_at=datetime.utcnow() + timedelta(minutes=minutes),
+            )
+            db.add(override)
+            audit(
+                db,
+                project_name,
+                "deploy:override",
+                actor,
+                {"reason": reason, "expires_at": _iso(override.expires_at)},
+            )
+            db.commit()
+            return self._override_dict(override)
+        finally:
+            db.close()
+
+    def end_override(self, project_name: str, actor: Optional[str] = None) -> bool:
+        """End the active override early (False if there was none)"""
+        db = get_db_session()
+        try:
+            override = self._override(db, project_name, datetime.utcnow())
+            if not override:
+                return False
+            override.revoked_at = datetime.utcnow()
+            audit(
+                db,
+                project_name,
+                "deploy:override-end",
+                actor or current_actor(),
+                {"reason": override.reason, "by": override.actor},
+            )
+            db.commit()
+            return True
+        finally:
+            db.close()
+
+    def evaluate(
+        self,
+        project_name: str,
+        target: str,
+        environment: Optional[str] = None,
+        actor: Optional[str] = None,
+        source: str = "cli",
+    ) -> Dict:
+        """
+        Decide whether a deploy may run now.
+
+        Args:
+            target: "<app>@<sha>" or "up"
+            environment: Defaults to the project's environment
+            actor: Who deploys (can't approve their own deploy); defaults to
+                the gh CLI login, then current_actor()
+            source: cli, dashboard or gate (for the audit log)
+
+        Returns:
+            {"allowed", "reasons", "override", "approvals", "environment"}
+        """
+        actor = actor or github_login() or current_actor()
+        now = datetime.utcnow()
+        db = get_db_session()
+        try:
+            environment = environment or self._environment(db, project_name)
+            reasons = []
+
+            for freeze in self._freezes(db, project_name):
+                window = freeze_window(freeze, now)
+                if window:
+                    reasons.append(
+                        f"change freeze #{freeze.id} until "
+                        f"{window['end']:%Y-%m-%d %H:%M} UTC"
+                        + (f" ({freeze.reason})" if freeze.reason else "")
+                    )
+
+            policy = self._policy(db, project_name)
+            approvers = []
+            required = policy.approvals_required if policy else 0
+            if required and environment in APPROVAL_ENVIRONMENTS:
+                approvers = sorted(
+                    {
+                        a.approver
+                        for a in self._approvals(db, project_name, now)
+                        if same_target(a.target, target)
+                        and a.approver != actor
+                        and a.approver in (policy.deployers or [])
+                    }
+                )
+                if len(approvers) < required:
+                    reasons.append(
+                        f"{target} has {len(approvers)}/{required} approvals "
+                        f"(superdeploy {project_name}:deploys:approve)"
+                    )
+
+            override = self._override(db, project_name, now) if reasons else None
+            decision = {
+                "project": project_name,
+                "target": target,
+                "environment": environment,
+                "actor": actor,
+                "allowed": not reasons or override is not None,
+                "reasons": reasons,
+                "approvals": approvers,
+                "override": self._override_dict(override),
+            }
+            if reasons:
+                audit(
+                    db,
+                    project_name,
+                    "deploy:overridden" if override else "deploy:blocked",
+                    actor,
+                    {
+                        "target": target,
+                        "environment": environment,
+                        "source": source,
+                        "reasons": reasons,
+                        "override": override.reason if override else None,
+                    },
+                )
+                db.commit()
+            return decision
+        finally:
+            db.close()
+
+    def check(
+        self,
+        project_name: str,
+        target: str,
+        environment: Optional[str] = None,
+        actor: Optional[str] = None,
+        source: str = "cli",
+    ) -> Dict:
+        """
+        evaluate(), raising when the deploy is blocked.
+
+        Raises:
+            DeployBlockedError: Frozen or missing approvals, without override
+        """
+        decision = self.evaluate(
+            project_name, target, environment=environment, actor=actor, source=source
+        )
+        if not decision["allowed"]:
+            raise DeployBlockedError(project_name, decision["reasons"])
+        return decision
+
+    @staticmethod
+    def _policy(db, project_name: str) -> Optional[DeployPolicy]:
+        return (
+            db.query(DeployPolicy)
+            .filter(DeployPolicy.project_name == project_name)
+            .first()
+        )
+
+    @staticmethod
+    def _freezes(db, project_name: str) -> List[DeployFreeze]:
+        return (
+            db.query(DeployFreeze)
+            .filter(DeployFreeze.project_name == project_name)
+            .order_by(DeployFreeze.id)
+            .all()
+        )
+
+    @staticmethod
+    def _approvals(db, project_name: str, now: datetime) -> List[DeployApproval]:
+        return (
+            db.query(DeployApproval)
+            .filter(
+                DeployApproval.project_name == project_name,
+                DeployApproval.expires_at > now,
+            )
+            .order_by(DeployApproval.created_at)
+            .all()
+        )
+
+    @staticmethod
+    def _override(db, project_name: str, now: datetime) -> Optional[DeployOverride]:
+        return (
+            db.query(DeployOverride)
+            .filter(
+                DeployOverride.project_name == project_name,
+                DeployOverride.expires_at > now,
+                DeployOverride.revoked_at.is_(None),
+            )
+            .order_by(DeployOverride.expires_at.desc())
+            .first()
+        )
+
+    @staticmethod
+    def _environment(db, project_name: str) -> str:
+        project = db.query(Project).filter(Project.name == project_name).first()
+        return (project.environment if project else None) or "production"
+
+    @staticmethod
+    def _require_project(db, project_name: str) -> None:
+        if not db.query(Project).filter(Project.name == project_name).first():
+            raise ValueError(f"Project '{project_name}' not found")
+
+    @staticmethod
+    def _freeze_dict(freeze: DeployFreeze, now: datetime) -> Dict:
+        window = freeze_window(freeze, now)
+        return {
+            "id": freeze.id,
+            "cron": freeze.cron,
+            "duration_minutes": freeze.duration_minutes,
+            "timezone": freeze.timezone,
+            "starts_at": _iso(freeze.starts_at),
+            "ends_at": _iso(freeze.ends_at),
+            "reason": freeze.reason,
+            "created_by": freeze.created_by,
+            "active_until": _iso(window["end"]) if window else None,
+        }
+
+    @staticmethod
+    def _override_dict(override: Optional[DeployOverride]) -> Optional[Dict]:
+        if not override:
+            return None
+        return {
+            "reason": override.reason,
+            "actor": override.actor,
+            "expires_at": _iso(override.expires_at),
+        }
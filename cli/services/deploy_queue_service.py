@@ -0,0 +1,89 @@
+"""
+Deploy Queue Service
+
+Deploys onto one app VM take turns through /opt/superdeploy/bin/deploy-queue
+(cli/core/deploy_queue.py, installed by the github-runner role). The deploy
+workflow calls it from its own steps; deploy, releases:switch and restart wrap
+their remote scripts with queued_script(), and deploys:queue reads status.
+"""
+
+import json
+import shlex
+from typing import Any, Dict, List
+
+from cli.constants import DEPLOY_QUEUE_LEASE_SECONDS, DEPLOY_QUEUE_WAIT_SECONDS
+
+DEPLOY_QUEUE_BIN = "/opt/superdeploy/bin/deploy-queue"
+
+
+def queued_script(
+    script: str,
+    project: str,
+    app: str,
+    source: str,
+    ref: str = "",
+    wait: int = DEPLOY_QUEUE_WAIT_SECONDS,
+) -> str:
+    """
+    Wrap a remote shell script so it runs holding the VM's deploy slot.
+
+    The lease is renewed while the script runs and the slot is released when
+    it exits, however it exits. VMs provisioned before the queue existed run
+    the script unqueued.
+    """
+    acquire = " ".join(
+        [
+            DEPLOY_QUEUE_BIN,
+            "acquire",
+            '--id "$QUEUE_ID"',
+            f"--project {shlex.quote(project)}",
+            f"--app {shlex.quote(app)}",
+            f"--source {shlex.quote(source)}",
+            f"--ref {shlex.quote(ref)}",
+            f"--wait {wait}",
+            f"--lease {DEPLOY_QUEUE_LEASE_SECONDS}",
+        ]
+    )
+    # heartbeat keeps the lease while the script runs longer than it
+    heartbeat = (
+        f'{DEPLOY_QUEUE_BIN} heartbeat --id "$QUEUE_ID" '
+        f"--lease {DEPLOY_QUEUE_LEASE_SECONDS} --pid $$"
+    )
+    prefix = f"""
+if [ -x {DEPLOY_QUEUE_BIN} ]; then
+  QUEUE_ID="{source}-{app}-$(date +%s)-$$"
+  {acquire} || exit 75
+  {heartbeat} < /dev/null > /dev/null 2>&1 &
+  QUEUE_HEARTBEAT=$!
+  trap 'kill $QUEUE_HEARTBEAT 2>/dev/null; {DEPLOY_QUEUE_BIN} release --id "$QUEUE_ID"' EXIT
+fi
+"""
+    return prefix + script
+
+
+class DeployQueueService:
+    """Reads the deploy queue on project VMs"""
+
+    def __init__(self, ssh_service):
+        self.ssh_service = ssh_service
+
+    def status(self, vm_ip: str) -> List[Dict[str, Any]]:
+        """
+        Running and pending entries on one VM, head first.
+
+        Raises:
+            RuntimeError: If the VM can't be queried
+        """
+        result = self.ssh_service.execute_command(
+            vm_ip,
+            f"[ -x {DEPLOY_QUEUE_BIN} ] || exit 3; {DEPLOY_QUEUE_BIN} status --json",
+            timeout=30,
+        )
+        if result.returncode == 3:
+            raise RuntimeError("deploy queue not installed (run <project>:up)")
+        if result.is_failure:
+            raise RuntimeError(result.stderr.strip() or "deploy-queue status failed")
+        try:
+            return json.loads(result.stdout).get("entries", [])
+        except ValueError:
+            raise RuntimeError("unreadable deploy-queue output")
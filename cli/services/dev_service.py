@@ -0,0 +1,562 @@
+"""
+Dev Service
+
+Runs an app and the addons it attaches on local Docker for <project>:dev.
+Addons are rendered from the templates addon-deployer uses, the app is built
+from its Dockerfile and runs its marker processes with the code mounted.
+
+The app sees the env var names it has in production with local values:
+addon keys point at the local instances, everything else is generated once
+(app.json default, or a value fitting the config contract). Production
+secret values are never read.
+
+    ./dev/<project>/state.json                addon credentials, host ports
+    ./dev/<project>/addons/<type>.<instance>/  rendered addon files
+    ./dev/<project>/<app>.env                 generated values (edit to override)
+    ./dev/<project>/<app>/                    compose file and rendered .env
+"""
+
+import json
+import re
+import secrets
+import shutil
+import subprocess
+from pathlib import Path
+from typing import Any, Dict, List, Optional, Tuple
+
+import yaml
+
+from cli.constants import DEV_ENV_DIR
+from cli.core.app_type_registry import AppTypeConfig, app_type_registry
+from cli.core.config_contract import ConfigKeySpec, ContractViolation, read_env_file
+from cli.core.env_template import TemplateEngine
+from cli.core.heroku import attachment_aliases, env_defaults
+from cli.marker_manager import MarkerManager
+from cli.secret_manager import SecretManager
+from .addon_test_service import AddonTestService
+from .app_env_service import DOCKER_KEYS, render_env_file
+from .config_service import ConfigService
+
+# Alias target naming an addon key: postgres.primary.PASSWORD
+ADDON_KEY = re.compile(r"^([a-z][a-z0-9-]*)\.([a-z0-9_-]+)\.([A-Z][A-Z0-9_]*)$")
+
+# Addon instances a template uses: {{ postgres.primary.HOST }}
+ADDON_REF = re.compile(r"\b([a-z][a-z0-9-]*)\.([a-z0-9_-]+)\.[A-Z]")
+
+# VM IP variables (APP_0_EXTERNAL_IP): everything runs on this machine
+VM_IP = re.compile(r"[A-Z]+_\d+_(EXTERNAL|INTERNAL)_IP")
+
+# Processes with run_on: deploy only run when asked (docker compose run)
+RELEASE_PROFILE = "release"
+
+
+def local_value(key: str, spec: Optional[ConfigKeySpec] = None) -> str:
+    """A stand-in value for a config key, fitting its contract where declared"""
+    if spec:
+        if spec.type == "int":
+            return str(spec.min if spec.min is not None else 0)
+        if spec.type == "bool":
+            return "false"
+        if spec.type == "enum":
+            return spec.values[0]
+        if spec.type == "url":
+            return "http://localhost"
+    if key.endswith("_URL"):
+        return "http://localhost"
+    return f"dev-{secrets.token_hex(16)}"
+
+
+class DevAddon(AddonTestService):
+    """An attached addon instance on local Docker, named like production"""
+
+    def __init__(
+        self,
+        project_root: Path,
+        project_name: str,
+        addon_type: str,
+        instance_name: str,
+        category: str,
+        plan: Optional[str],
+        version: Optional[str],
+        saved: Dict[str, str],
+        work_dir: Path,
+    ):
+        """
+        Initialize a dev addon.
+
+        Args:
+            saved: Credentials and ports of earlier runs (updated in place)
+            work_dir: Directory the addon files are rendered into
+        """
+        super().__init__(
+            project_root,
+            addon_type,
+            plan=plan or "standard",
+            instance_name=instance_name,
+            version=version,
+        )
+        self.project_name = project_name
+        self.category = category
+        self.saved = saved
+        self.work_dir = work_dir
+
+    @property
+    def ref(self) -> str:
+        return f"{self.addon.name}.{self.instance_name}"
+
+    @property
+    def compose_project(self) -> str:
+        return f"{self.project_name}-dev-{self.addon.name}-{self.instance_name}"
+
+    @property
+    def host_port(self) -> Optional[str]:
+        return self.rendered.env_vars.get("PORT") if self.rendered else None
+
+    def build_context(self):
+        """
+        Test context with the credentials and host ports of earlier runs
+        (a volume keeps the password it was created with).
+        """
+        context = super().build_context()
+        context.category = self.category
+        for key, value in context.instance_secrets.items():
+            context.instance_secrets[key] = self.saved.setdefault(key, value)
+
+        for var_key, var_def in self.addon.env_schema.get("variables", {}).items():
+            env_name = var_def.get("env_name", var_key)
+            path = var_def.get("from_project")
+            if path and env_name.endswith("PORT") and "from_secrets" not in var_def:
+                port = self.renderer._lookup(context.project_config, path)
+                self._set_path(
+                    context.project_config, path, self.saved.setdefault(env_name, port)
+                )
+        return context
+
+    def attachment_values(self) -> Dict[str, str]:
+        """
+        The instance's addon keys (postgres.primary.PASSWORD) as app
+        containers see them: HOST is the compose service on the project
+        network, PORT the container port.
+        """
+        values = {}
+        for var_key, var_def in self.addon.env_schema.get("variables", {}).items():
+            env_name = var_def.get("env_name", var_key)
+            if env_name in self.rendered.env_vars:
+                key = var_def.get("from_secrets") or var_key.split("_", 1)[-1]
+                values[key] = self.rendered.env_vars[env_name]
+        values["HOST"] = self.rendered.service_name
+        values["PORT"] = self.container_port() or values.get("PORT", "")
+        return values
+
+    def container_port(self) -> Optional[str]:
+        """Container side of the port mapping the instance's PORT publishes"""
+        compose = yaml.safe_load(self.rendered.compose_path.read_text()) or {}
+        service = compose.get("services", {}).get(self.rendered.service_name, {})
+        for mapping in service.get("ports") or []:
+            parts = str(mapping).split("/")[0].split(":")
+            if len(parts) > 1 and parts[-2] in ("${PORT}", self.host_port):
+                return parts[-1]
+        return None
+
+    def down(self, volumes: bool = False) -> subprocess.CompletedProcess:
+        """Stop the instance, keeping its data unless volumes"""
+        return self._compose("down", "--remove-orphans", *(["-v"] if volumes else []))
+
+
+class DevService:
+    """
+    Local development environment for one app.
+
+    Responsibilities:
+    - Render and start the addons the app attaches
+    - Build the app's env: production key names, local values
+    - Write a compose file for the marker processes with the code mounted
+    """
+
+    def __init__(
+        self,
+        project_root: Path,
+        project_name: str,
+        app_name: str,
+        app_path: Optional[str] = None,
+        port: Optional[int] = None,
+    ):
+        """
+        Initialize dev service.
+
+        Args:
+            project_root: Path to superdeploy root directory
+            project_name: Project name
+            app_name: App name
+            app_path: App checkout (default: the app's path in the project)
+            port: Host port for the web process (default: its own port)
+
+        Raises:
+            KeyError: If the app isn't part of the project
+            ValueError: If the app has no checkout or no processes
+        """
+        self.project_root = project_root
+        self.project_name = project_name
+        self.app_name = app_name
+        self.config = ConfigService(project_root).get_raw_config(project_name)
+
+        app_config = self.config.get("apps", {}).get(app_name)
+        if app_config is None:
+            raise KeyError(f"App '{app_name}' not found in project '{project_name}'")
+        path = app_path or app_config.get("path")
+        if not path:
+            raise ValueError(f"No local path for {app_name}, pass --path")
+        self.app_path = Path(path).expanduser().resolve()
+        if not self.app_path.is_dir():
+            raise ValueError(f"App path does not exist: {self.app_path}")
+
+        self.marker = MarkerManager.load_marker(
+            self.app_path,
+            project_name,
+            app_name,
+            app_config.get("vm", "app"),
+            app_config.get("port"),
+        )
+        if not self.marker or not self.marker.has_processes():
+            raise ValueError(
+                f"{app_name} has no processes: add a superdeploy marker or a Procfile"
+            )
+        web = self.marker.get_process("web")
+        self.web_port = port or (web and web.port) or app_config.get("port")
+
+        app_type = app_type_registry.detect(self.app_path)
+        self.app_type: Optional[AppTypeConfig] = (
+            app_type_registry.get(app_type) if app_type != "unknown" else None
+        )
+
+        # Key names only: production values stay in the database
+        self.secret_manager = SecretManager(project_root, project_name)
+        self.dev_dir = Path(DEV_ENV_DIR).resolve() / project_name
+        self.state_path = self.dev_dir / "state.json"
+        self.values_path = self.dev_dir / f"{app_name}.env"
+        self.app_dir = self.dev_dir / app_name
+        self.state: Dict[str, Any] = {"addons": {}}
+        if self.state_path.exists():
+            self.state = json.loads(self.state_path.read_text())
+        # Keys whose template kept a placeholder ({{ app.billing.TOKEN }})
+        self.unresolved: List[str] = []
+        self._aliases: Optional[Dict[str, str]] = None
+
+    @property
+    def network_name(self) -> str:
+        return f"{self.project_name}-network"
+
+    @property
+    def compose_project(self) -> str:
+        return f"{self.project_name}-dev-{self.app_name}"
+
+    @property
+    def compose_path(self) -> Path:
+        return self.app_dir / "docker-compose.yml"
+
+    @property
+    def url(self) -> Optional[str]:
+        return f"http://localhost:{self.web_port}" if self.web_port else None
+
+    def project_addons(self) -> Dict[str, Dict[str, Any]]:
+        """type.instance → {type, instance, category, plan, version}"""
+        addons = {}
+        for category, instances in (self.config.get("addons") or {}).items():
+            for instance, spec in instances.items():
+                addons[f"{spec['type']}.{instance}"] = {
+                    **spec,
+                    "instance": instance,
+                    "category": category,
+                }
+        return addons
+
+    @property
+    def aliases(self) -> Dict[str, str]:
+        """
+        The app's aliases; marker attachments generate hasn't stored yet
+        alias the project's first instance of their type.
+        """
+        if self._aliases is None:
+            aliases = {}
+            project_addons = self.project_addons()
+            for attachment in self.marker.addons:
+                addon_type = attachment["type"]
+                ref = next(
+                    (r for r, s in project_addons.items() if s["type"] == addon_type),
+                    None,
+                )
+                if ref:
+                    as_var = attachment.get("as") or addon_type.upper()
+                    aliases.update(attachment_aliases(addon_type, ref, as_var))
+            aliases.update(self.secret_manager.get_aliases(self.app_name))
+            self._aliases = aliases
+        return self._aliases
+
+    def addons(self) -> List[DevAddon]:
+        """The project's addon instances the app's aliases use"""
+        project_addons = self.project_addons()
+        refs = []
+        for target in self.aliases.values():
+            for match in ADDON_REF.finditer(target):
+                ref = f"{match.group(1)}.{match.group(2)}"
+                if ref in project_addons and ref not in refs:
+                    refs.append(ref)
+
+        addons = []
+        for ref in refs:
+            spec = project_addons[ref]
+            addons.append(
+                DevAddon(
+                    self.project_root,
+                    self.project_name,
+                    spec["type"],
+                    spec["instance"],
+                    spec["category"],
+                    spec.get("plan"),
+                    spec.get("version"),
+                    self.state["addons"].setdefault(ref, {}),
+                    self.dev_dir / "addons" / ref,
+                )
+            )
+        return addons
+
+    def save_state(self) -> None:
+        self.dev_dir.mkdir(parents=True, exist_ok=True)
+        self.state_path.write_text(json.dumps(self.state, indent=2) + "\n")
+        self.state_path.chmod(0o600)
+
+    def app_env(self, addons: List[DevAddon]) -> Tuple[Dict[str, str], Dict[str, str]]:
+        """
+        (runtime env, build-time env) with production's key names.
+
+        Addon aliases point at the local instances; template aliases and
+        env_templates render against them. Other keys get their value from
+        <app>.env, generated there on first use. <app>.env wins overall.
+        """
+        saved = {}
+        if self.values_path.exists():
+            saved = read_env_file(str(self.values_path))
+        generated = False
+        defaults = env_defaults(self.app_path)
+        local = {addon.ref: addon.attachment_values() for addon in addons}
+
+        def value(key: str) -> str:
+            nonlocal generated
+            if key not in saved:
+                spec = self.marker.config.keys.get(key)
+                saved[key] = defaults.get(key) or local_value(key, spec)
+                generated = True
+            return saved[key]
+
+        usages = self.secret_manager.get_usages(self.app_name)
+        env = {}
+        for key in usages:
+            if "." not in key and key not in DOCKER_KEYS:
+                env[key] = value(key)
+
+        for key, target in self.aliases.items():
+            match = ADDON_KEY.match(target)
+            ref = match and f"{match.group(1)}.{match.group(2)}"
+            if ref in local:
+                env[key] = local[ref].get(match.group(3), "")
+            elif TemplateEngine.is_template(target):
+                env[key] = self._render(key, target, local, env)
+            else:
+                env[key] = value(key)
+
+        for key, template in self.marker.env_templates.items():
+            env[key] = self._render(key, template, local, env)
+        for key in self.marker.config.keys:
+            if key not in env:
+                env[key] = value(key)
+        env.update(saved)
+
+        if generated:
+            self.dev_dir.mkdir(parents=True, exist_ok=True)
+            self.values_path.write_text(
+                f"# Local values for {self.app_name} ({self.project_name}:dev)\n"
+                "# Generated once; edit or add lines to override any key\n"
+                + render_env_file(saved)
+            )
+            self.values_path.chmod(0o600)
+
+        for key in self.marker.build_env:
+            if usages.get(key) != "build":
+                usages[key] = "both"
+        runtime, buildtime = {}, {}
+        for key, value in env.items():
+            usage = usages.get(key, "runtime")
+            if usage != "build":
+                runtime[key] = value
+            if usage != "runtime":
+                buildtime[key] = value
+        return runtime, buildtime
+
+    def _render(
+        self, key: str, template: str, local: Dict[str, Dict[str, str]], env: Dict
+    ) -> str:
+        """Render a template against local addons, env and localhost"""
+        engine = TemplateEngine()
+        addon_types: Dict[str, Dict[str, Dict[str, str]]] = {}
+        for ref, values in local.items():
+            addon_type, instance = ref.split(".", 1)
+            addon_types.setdefault(addon_type, {})[instance] = values
+
+        context: Dict[str, Any] = {}
+        for name in engine.names(template, key=key):
+            if name in env:
+                context[name] = env[name]
+            elif name in addon_types:
+                context[name] = addon_types[name]
+            elif VM_IP.fullmatch(name):
+                context[name] = "localhost"
+            elif name == "project_name":
+                context[name] = self.project_name
+            elif name == "environment":
+                context[name] = "development"
+            elif name == "app":
+                # No domains locally: templates fall back to host:port
+                context[name] = {
+                    app: {"domain": "", "port": config.get("port")}
+                    for app, config in self.config.get("apps", {}).items()
+                }
+
+        rendered = engine.render(template, context, key=key)
+        if engine.undefined_names:
+            self.unresolved.append(key)
+        return rendered
+
+    def check_contract(self, env: Dict[str, str]) -> List[ContractViolation]:
+        """Config contract violations of the local env (set them in <app>.env)"""
+        return self.marker.config.validate(env)
+
+    def source_dir(self) -> Optional[str]:
+        """Where the image keeps the code (the Dockerfile's last WORKDIR)"""
+        if not self.app_type or not self.app_type.dev_workdir:
+            return None
+        dockerfile = self.app_path / "Dockerfile"
+        if dockerfile.is_file():
+            workdirs = re.findall(
+                r"^\s*WORKDIR\s+(\S+)", dockerfile.read_text(), re.MULTILINE | re.I
+            )
+            if workdirs and workdirs[-1].startswith("/"):
+                return workdirs[-1]
+        return self.app_type.dev_workdir
+
+    def write_compose(self, runtime: Dict[str, str], buildtime: Dict[str, str]) -> Path:
+        """
+        Write the app's compose file: one service per marker process, on
+        the project network, with the code mounted where the app type
+        reloads. Release processes (run_on: deploy) sit in a profile.
+        """
+        self.app_dir.mkdir(parents=True, exist_ok=True)
+        env_path = self.app_dir / ".env"
+        env_path.write_text(render_env_file(runtime))
+        env_path.chmod(0o600)
+
+        source_dir = self.source_dir()
+        volumes = []
+        if source_dir:
+            volumes.append(f"{self.app_path}:{source_dir}")
+            volumes += [f"{source_dir}/{path}" for path in self.app_type.dev_keep]
+        dev_env = self.app_type.dev_env if source_dir else {}
+
+        services = {}
+        for name, process in self.marker.processes.items():
+            command = process.command
+            if name == "web" and source_dir and self.app_type.dev_command:
+                command = self.app_type.dev_command.format(
+                    port=process.port or self.web_port
+                )
+            service: Dict[str, Any] = {
+                "image": f"{self.project_name}-dev-{self.app_name}",
+                "build": {"context": str(self.app_path), "args": buildtime},
+                "command": command,
+                "env_file": [str(env_path)],
+                "networks": [self.network_name],
+            }
+            environment = {**dev_env, **(process.env or {})}
+            if environment:
+                service["environment"] = environment
+            if volumes:
+                service["volumes"] = volumes
+            if process.port:
+                host_port = self.web_port if name == "web" else process.port
+                service["ports"] = [f"{host_port}:{process.port}"]
+            if process.run_on == "deploy":
+                service["profiles"] = [RELEASE_PROFILE]
+            services[f"{self.app_name}-{name}"] = service
+
+        compose = {
+            "services": services,
+            "networks": {
+                self.network_name: {"name": self.network_name, "external": True}
+            },
+        }
+        self.compose_path.write_text(yaml.safe_dump(compose, sort_keys=False))
+        return self.compose_path
+
+    def release_services(self) -> List[str]:
+        return [
+            f"{self.app_name}-{name}"
+            for name, process in self.marker.processes.items()
+            if process.run_on == "deploy"
+        ]
+
+    def compose(self, *args: str, capture: bool = True) -> subprocess.CompletedProcess:
+        """Run docker compose against the app's dev compose file"""
+        return subprocess.run(
+            [
+                "docker",
+                "compose",
+                "--project-name",
+                self.compose_project,
+                "--project-directory",
+                str(self.app_dir),
+                "-f",
+                str(self.compose_path),
+                *args,
+            ],
+            capture_output=capture,
+            text=True,
+        )
+
+    def down(self, addons: List[DevAddon], reset: bool = False) -> List[str]:
+        """
+        Stop the app and its addons.
+
+        reset also removes addon volumes, their credentials and <app>.env,
+        so the next run starts from scratch.
+
+        Returns:
+            List of errors (empty on success)
+        """
+        errors = []
+        if self.compose_path.exists():
+            result = self.compose(
+                "--profile", RELEASE_PROFILE, "down", "--remove-orphans"
+            )
+            if result.returncode != 0:
+                errors.append(result.stderr.strip())
+
+        for addon in addons:
+            addon.render(addon.work_dir)
+            result = addon.down(volumes=reset)
+            if result.returncode != 0:
+                errors.append(result.stderr.strip())
+            if reset:
+                self.state["addons"].pop(addon.ref, None)
+                shutil.rmtree(addon.work_dir, ignore_errors=True)
+
+        # Still in use while other apps of the project run
+        subprocess.run(
+            ["docker", "network", "rm", self.network_name],
+            capture_output=True,
+            text=True,
+        )
+
+        if reset:
+            self.values_path.unlink(missing_ok=True)
+            shutil.rmtree(self.app_dir, ignore_errors=True)
+            self.save_state()
+        return errors
@@ -0,0 +1,276 @@
+"""
+Hook Service
+
+Lifecycle hooks (cli.core.hooks) from two places: the `hooks:` section of an
+app's superdeploy marker, and hooks set with <project>:hooks:add (stored in
+lifecycle_hooks, optionally for every app of the project). CLI commands run
+them through run(); the deploy workflow runs the marker's and the pushed
+hooks.json with /opt/superdeploy/bin/run-hooks on the app VM.
+"""
+
+import json
+import os
+import shlex
+from pathlib import Path
+from typing import Callable, Dict, List, Optional
+
+from cli.core.hooks import (
+    HOOKS_FILE,
+    Hook,
+    HookResult,
+    HookRunner,
+    compose_exec_command,
+    parse_hooks,
+)
+from cli.database import get_db_session, LifecycleHook, Project
+from cli.marker_manager import MarkerManager
+from cli.services.protection_service import audit, current_actor
+from .config_service import ConfigService
+from .vm_service import VMService
+
+
+class HookService:
+    """Lists, changes, pushes and runs a project's lifecycle hooks."""
+
+    def __init__(self, project_root: Path, project_name: str):
+        self.project_root = project_root
+        self.project_name = project_name
+        self.config_service = ConfigService(project_root)
+        self.vm_service = VMService(project_root, project_name)
+        self._ssh = None
+
+    @property
+    def ssh(self):
+        if self._ssh is None:
+            self._ssh = self.vm_service.get_ssh_service()
+        return self._ssh
+
+    @property
+    def compose_dir(self) -> str:
+        return f"/opt/superdeploy/projects/{self.project_name}/compose"
+
+    def db_hooks(self) -> List[Hook]:
+        """Hooks set with hooks:add, in the order they were added"""
+        db = get_db_session()
+        try:
+            rows = (
+                db.query(LifecycleHook)
+                .filter(LifecycleHook.project_name == self.project_name)
+                .order_by(LifecycleHook.id)
+                .all()
+            )
+            return [self._to_hook(row) for row in rows]
+        finally:
+            db.close()
+
+    def app_path(self, app_name: str) -> Optional[Path]:
+        """Local checkout of an app (None if config has no path)"""
+        try:
+            app_config = self.config_service.get_app_config(
+                self.project_name, app_name
+            )
+        except KeyError:
+            return None
+        path = app_config.get("path")
+        return Path(path).expanduser() if path else None
+
+    def marker_hooks(self, app_name: str) -> List[Hook]:
+        """
+        Hooks in the app's marker ([] without a local checkout or marker).
+
+        Raises:
+            ConfigurationError: If the marker is invalid
+        """
+        app_path = self.app_path(app_name)
+        if not app_path or not app_path.exists():
+            return []
+        marker = MarkerManager.load_marker(app_path, self.project_name, app_name)
+        if not marker:
+            return []
+        return parse_hooks(marker.hooks, app_name)
+
+    def hooks_for(self, event: str, apps: List[str]) -> List[Hook]:
+        """
+        Hooks an event runs for apps: each app's marker hooks and hooks:add
+        hooks, then the project-wide ones (once).
+        """
+        db_hooks = [hook for hook in self.db_hooks() if hook.event == event]
+        hooks = []
+        for app_name in apps:
+            hooks += [h for h in self.marker_hooks(app_name) if h.event == event]
+            hooks += [h for h in db_hooks if h.app == app_name]
+        return hooks + [h for h in db_hooks if not h.app]
+
+    def add(
+        self,
+        event: str,
+        spec: Dict,
+        app_name: Optional[str] = None,
+        actor: Optional[str] = None,
+    ) -> Hook:
+        """
+        Store a hook. spec is a marker declaration ({"exec": ..., ...}).
+
+        Raises:
+            ValueError: Unknown project/app or invalid hook
+        """
+        if app_name and app_name not in self.config_service.list_apps(
+            self.project_name
+        ):
+            raise ValueError(f"App '{app_name}' not found in {self.project_name}")
+        hook = Hook.from_value(event, spec, app_name, source="db")
+
+        db = get_db_session()
+        try:
+            if not db.query(Project).filter(Project.name == self.project_name).first():
+                raise ValueError(f"Project '{self.project_name}' not found")
+            row = LifecycleHook(
+                project_name=self.project_name,
+                app_name=app_name,
+                event=event,
+                type=hook.type,
+                target=hook.target,
+                timeout=hook.timeout,
+                on_failure=hook.on_failure,
+                method=hook.method if hook.type == "http" else None,
+                headers=hook.headers or None,
+                created_by=actor or current_actor(),
+            )
+            db.add(row)
+            db.flush()
+            hook.id = row.id
+            audit(
+                db,
+                self.project_name,
+                "hook:add",
+                row.created_by,
+                {"event": event, **hook.to_dict()},
+            )
+            db.commit()
+            return hook
+        finally:
+            db.close()
+
+    def remove(self, hook_id: int, actor: Optional[str] = None) -> Hook:
+        """
+        Raises:
+            ValueError: No such hook in the project
+        """
+        db = get_db_session()
+        try:
+            row = (
+                db.query(LifecycleHook)
+                .filter(
+                    LifecycleHook.project_name == self.project_name,
+                    LifecycleHook.id == hook_id,
+                )
+                .first()
+            )
+            if not row:
+                raise ValueError(f"Hook {hook_id} not found in {self.project_name}")
+            hook = self._to_hook(row)
+            db.delete(row)
+            audit(
+                db,
+                self.project_name,
+                "hook:remove",
+                actor or current_actor(),
+                {"event": hook.event, **hook.to_dict()},
+            )
+            db.commit()
+            return hook
+        finally:
+            db.close()
+
+    def push(self) -> Dict[str, Optional[str]]:
+        """
+        Write hooks:add hooks to every VM as hooks.json (read by the deploy
+        workflow's run-hooks). Returns {vm_name: error or None}.
+        """
+        payload = json.dumps(
+            {
+                "hooks": [
+                    {"event": hook.event, **hook.to_dict()}
+                    for hook in self.db_hooks()
+                ]
+            },
+            indent=2,
+        )
+        path = HOOKS_FILE.format(project=self.project_name)
+        command = (
+            f"sudo mkdir -p {shlex.quote(os.path.dirname(path))} && "
+            f"sudo tee {shlex.quote(path)} > /dev/null && "
+            f"sudo chown superdeploy:superdeploy {shlex.quote(path)}"
+        )
+        errors = {}
+        for vm_name, vm_ip in sorted(self.vm_service.get_all_vm_ips().items()):
+            try:
+                result = self.ssh.execute_command(vm_ip, command, input_data=payload)
+                errors[vm_name] = (
+                    None if result.is_success else result.stderr.strip() or "failed"
+                )
+            except (RuntimeError, TimeoutError) as e:
+                errors[vm_name] = str(e).splitlines()[0]
+        return errors
+
+    def run(
+        self,
+        event: str,
+        apps: Optional[List[str]] = None,
+        log: Callable[[str], None] = print,
+        **context: str,
+    ) -> List[HookResult]:
+        """
+        Run an event's hooks for apps (default: every app). Exec hooks run in
+        the app's container over SSH; script hooks from the app's checkout.
+
+        Raises:
+            HookFailed: When an on_failure: abort hook fails
+            ConfigurationError: If a marker is invalid
+        """
+        if apps is None:
+            apps = self.config_service.list_apps(self.project_name)
+        hooks = self.hooks_for(event, apps)
+        if not hooks:
+            return []
+
+        workdirs = {None: os.getcwd()}
+        for app_name in apps:
+            app_path = self.app_path(app_name)
+            if app_path and app_path.exists():
+                workdirs[app_name] = str(app_path)
+
+        runner = HookRunner(
+            exec_in_container=self._remote_exec, workdirs=workdirs, log=log
+        )
+        return runner.run(
+            event,
+            hooks,
+            self.project_name,
+            {key: str(value) for key, value in context.items() if value is not None},
+        )
+
+    def _remote_exec(self, app_name: str, command: str, env: Dict, timeout: int):
+        _, vm_ip = self.vm_service.get_vm_for_app(app_name)
+        result = self.ssh.execute_command(
+            vm_ip,
+            f"cd {self.compose_dir} && "
+            + compose_exec_command(app_name, command, env, timeout),
+            timeout=timeout + 30,
+        )
+        return result.returncode, result.stdout + result.stderr
+
+    @staticmethod
+    def _to_hook(row: LifecycleHook) -> Hook:
+        return Hook(
+            event=row.event,
+            type=row.type,
+            target=row.target,
+            app=row.app_name,
+            timeout=row.timeout,
+            on_failure=row.on_failure,
+            method=row.method or "POST",
+            headers=row.headers or {},
+            source="db",
+            id=row.id,
+        )
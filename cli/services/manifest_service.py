@@ -0,0 +1,402 @@
+"""
+Manifest Service
+
+Exports a project from the database as a declarative manifest and applies
+manifest changes back in a single transaction.
+"""
+
+from typing import Any, Dict, List, Optional, Set, Tuple
+
+from cli.core.manifest import (
+    API_VERSION,
+    PROJECT_FIELDS,
+    Change,
+    cross_check,
+    diff,
+    parse_secret_name,
+)
+from cli.database import (
+    get_db_session,
+    Project,
+    App,
+    Process,
+    Addon,
+    VM,
+    Secret,
+    SecretAlias,
+)
+from cli.secret_backends import SecretResolver
+
+
+class ManifestService:
+    """
+    Database ↔ manifest.
+
+    Only secrets stored as references (vault://, sops://, {{ org.X }}) are
+    managed by manifests; plain values never leave the database and are
+    neither exported nor pruned. Addon-generated credentials are skipped.
+    """
+
+    def __init__(self):
+        self.resolver = SecretResolver()
+
+    def is_reference(self, value: Any) -> bool:
+        """Whether a stored secret value is a reference (safe to version)"""
+        from cli.secret_manager import SCOPED_PLACEHOLDER
+
+        if not isinstance(value, str):
+            return False
+        return self.resolver.is_reference(value) or bool(
+            SCOPED_PLACEHOLDER.fullmatch(value.strip())
+        )
+
+    def export(self, project_name: str) -> Tuple[Optional[Dict[str, Any]], int]:
+        """
+        Export a project as a manifest.
+
+        Returns:
+            (manifest or None if the project doesn't exist, plain secrets skipped)
+        """
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            if not project:
+                return None, 0
+
+            manifest: Dict[str, Any] = {
+                "apiVersion": API_VERSION,
+                "project": {"name": project.name},
+            }
+            for path, column in PROJECT_FIELDS.items():
+                value = getattr(project, column)
+                if value not in (None, ""):
+                    _put(manifest, path, value)
+
+            manifest["vms"] = {}
+            for vm in sorted(project.vms, key=lambda v: v.role):
+                manifest["vms"][vm.role] = _clean(
+                    {
+                        "count": vm.count,
+                        "machine_type": vm.machine_type,
+                        "disk_size": vm.disk_size,
+                    }
+                )
+
+            manifest["apps"] = {}
+            for app in sorted(project.apps, key=lambda a: a.name):
+                entry = _clean(
+                    {
+                        "path": app.path,
+                        "repo": app.repo,
+                        "owner": app.owner,
+                        "type": app.type,
+                        "vm": app.vm,
+                        "port": app.port,
+                        "external_port": app.external_port,
+                        "domain": app.domain,
+                        "edge": app.edge or None,
+                        "anonymize": app.anonymize or None,
+                    }
+                )
+                entry["processes"] = {
+                    proc.name: _clean(
+                        {
+                            "command": proc.command,
+                            "replicas": proc.replicas,
+                            "port": proc.port,
+                        }
+                    )
+                    for proc in sorted(app.processes, key=lambda p: p.name)
+                }
+                entry["aliases"] = {
+                    alias.alias_key: alias.target_key
+                    for alias in sorted(app.secret_aliases, key=lambda a: a.alias_key)
+                }
+                manifest["apps"][app.name] = entry
+
+            manifest["addons"] = {}
+            for addon in sorted(
+                project.addons, key=lambda a: (a.category, a.instance_name)
+            ):
+                manifest["addons"].setdefault(addon.category, {})[
+                    addon.instance_name
+                ] = _clean(
+                    {
+                        "type": addon.type,
+                        "version": addon.version,
+                        "vm": addon.vm,
+                        "plan": addon.plan,
+                    }
+                )
+
+            skipped = 0
+            secrets: Dict[str, Any] = {}
+            rows = (
+                db.query(Secret)
+                .filter(Secret.project_id == project.id, Secret.source != "addon")
+                .order_by(Secret.environment, Secret.key)
+                .all()
+            )
+            for row in rows:
+                if not self.is_reference(row.value):
+                    skipped += 1
+                    continue
+                value: Any = row.value
+                if row.usage and row.usage != "runtime":
+                    value = {"ref": row.value, "usage": row.usage}
+                scoped = secrets.setdefault(row.environment, {})
+                if row.app:
+                    scoped.setdefault("apps", {}).setdefault(row.app.name, {})[
+                        row.key
+                    ] = value
+                else:
+                    scoped.setdefault("shared", {})[row.key] = value
+            if secrets:
+                manifest["secrets"] = secrets
+
+            return manifest, skipped
+        finally:
+            db.close()
+
+    def plan(
+        self, manifest: Dict[str, Any], prune: bool = False
+    ) -> Tuple[List[Change], List[str]]:
+        """
+        Diff a manifest against the database.
+
+        Returns:
+            (changes, problems) - problems block apply
+        """
+        project_name = manifest["project"]["name"]
+        current, _ = self.export(project_name)
+
+        merged = {**(current or {}), **manifest}
+        problems = cross_check(merged)
+        if project_name == "orchestrator":
+            problems.append("project.name: the orchestrator isn't managed by manifests")
+
+        app_names = set(merged.get("apps") or {})
+        changes = diff(current, manifest, prune)
+        plain = self._plain_secrets(project_name)
+        for change in changes:
+            if change.kind != "secret":
+                continue
+            _, app_name, _ = parse_secret_name(change.name)
+            if app_name and app_name not in app_names:
+                problems.append(f"secret/{change.name}: app '{app_name}' not found")
+            if change.action == "create" and change.name in plain:
+                # A plain value already stored under this key gets replaced
+                change.action = "update"
+                change.fields["ref"] = ("(plain value)", change.fields["ref"][1])
+
+        return changes, problems
+
+    def apply(self, manifest: Dict[str, Any], changes: List[Change]) -> None:
+        """Apply planned changes in one transaction"""
+        project_name = manifest["project"]["name"]
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            apps: Dict[str, App] = {}
+
+            def app_row(name: str) -> App:
+                if name not in apps:
+                    apps[name] = (
+                        db.query(App)
+                        .filter(App.project_id == project.id, App.name == name)
+                        .first()
+                    )
+                return apps[name]
+
+            for change in changes:
+                values = change.desired()
+
+                if change.kind == "project":
+                    if project is None:
+                        project = Project(
+                            name=project_name, project_type="application", **values
+                        )
+                        db.add(project)
+                        db.flush()
+                    else:
+                        _assign(project, values)
+
+                elif change.kind == "vm":
+                    row = (
+                        db.query(VM)
+                        .filter(VM.project_id == project.id, VM.role == change.name)
+                        .first()
+                    )
+                    self._write(
+                        db,
+                        row,
+                        change,
+                        VM,
+                        values,
+                        project_id=project.id,
+                        role=change.name,
+                    )
+
+                elif change.kind == "addon":
+                    category, instance = change.name.split(".", 1)
+                    row = (
+                        db.query(Addon)
+                        .filter(
+                            Addon.project_id == project.id,
+                            Addon.category == category,
+                            Addon.instance_name == instance,
+                        )
+                        .first()
+                    )
+                    if row is None:
+                        values.setdefault("version", "latest")
+                        values.setdefault("vm", "core")
+                    self._write(
+                        db,
+                        row,
+                        change,
+                        Addon,
+                        values,
+                        project_id=project.id,
+                        category=category,
+                        instance_name=instance,
+                    )
+
+                elif change.kind == "app":
+                    row = app_row(change.name)
+                    apps[change.name] = self._write(
+                        db,
+                        row,
+                        change,
+                        App,
+                        values,
+                        project_id=project.id,
+                        name=change.name,
+                    )
+
+                elif change.kind == "process":
+                    app_name, proc_name = change.name.split(".", 1)
+                    app = app_row(app_name)
+                    row = (
+                        db.query(Process)
+                        .filter(Process.app_id == app.id, Process.name == proc_name)
+                        .first()
+                    )
+                    self._write(
+                        db, row, change, Process, values, app_id=app.id, name=proc_name
+                    )
+
+                elif change.kind == "alias":
+                    app_name, alias_key = change.name.split(".", 1)
+                    app = app_row(app_name)
+                    row = (
+                        db.query(SecretAlias)
+                        .filter(
+                            SecretAlias.app_id == app.id,
+                            SecretAlias.alias_key == alias_key,
+                        )
+                        .first()
+                    )
+                    if "target" in values:
+                        values["target_key"] = values.pop("target")
+                    self._write(
+                        db,
+                        row,
+                        change,
+                        SecretAlias,
+                        values,
+                        project_id=project.id,
+                        app_id=app.id,
+                        alias_key=alias_key,
+                    )
+
+                elif change.kind == "secret":
+                    environment, app_name, key = parse_secret_name(change.name)
+                    app_id = app_row(app_name).id if app_name else None
+                    row = (
+                        db.query(Secret)
+                        .filter(
+                            Secret.project_id == project.id,
+                            Secret.app_id == app_id,
+                            Secret.key == key,
+                            Secret.environment == environment,
+                        )
+                        .first()
+                    )
+                    if "ref" in values:
+                        values["value"] = values.pop("ref")
+                    if row is None and change.action != "delete":
+                        values.setdefault("usage", "runtime")
+                    self._write(
+                        db,
+                        row,
+                        change,
+                        Secret,
+                        values,
+                        project_id=project.id,
+                        app_id=app_id,
+                        key=key,
+                        environment=environment,
+                        source="app" if app_name else "shared",
+                        editable=True,
+                    )
+
+                # Later changes look rows up by foreign key
+                db.flush()
+
+            db.commit()
+        except Exception:
+            db.rollback()
+            raise
+        finally:
+            db.close()
+
+    def _write(
+        self, db, row, change: Change, model, values: Dict[str, Any], **identity
+    ):
+        """Create, update or delete one row for a change"""
+        if change.action == "delete":
+            if row is not None:
+                db.delete(row)
+            return None
+        if row is None:
+            row = model(**identity, **values)
+            db.add(row)
+            return row
+        _assign(row, values)
+        return row
+
+    def _plain_secrets(self, project_name: str) -> Set[str]:
+        """secret_name() of every plain (non-reference) value in the project"""
+        from cli.core.manifest import secret_name
+
+        db = get_db_session()
+        try:
+            rows = (
+                db.query(Secret)
+                .join(Project, Secret.project_id == Project.id)
+                .filter(Project.name == project_name, Secret.source != "addon")
+                .all()
+            )
+            return {
+                secret_name(row.environment, row.app.name if row.app else None, row.key)
+                for row in rows
+                if not self.is_reference(row.value)
+            }
+        finally:
+            db.close()
+
+
+def _put(data: Dict[str, Any], path: Tuple[str, ...], value: Any) -> None:
+    for part in path[:-1]:
+        data = data.setdefault(part, {})
+    data[path[-1]] = value
+
+
+def _clean(data: Dict[str, Any]) -> Dict[str, Any]:
+    return {k: v for k, v in data.items() if v is not None}
+
+
+def _assign(row, values: Dict[str, Any]) -> None:
+    for column, value in values.items():
+        setattr(row, column, value)
@@ -0,0 +1,388 @@
+"""
+Orchestrator Backup Service
+
+Backups of the SuperDeploy control plane: the state database (projects,
+secrets, VMs), Grafana (provisioning and the data volume holding dashboards
+and users) and Caddy's certificates. Everything goes into one tarball
+encrypted with openssl (AES-256, passphrase from
+SUPERDEPLOY_BACKUP_PASSPHRASE), written to a directory or a gs:// bucket.
+
+Prometheus and Loki data are not included; they are rebuilt by scraping.
+"""
+
+import base64
+import gzip
+import io
+import json
+import os
+import re
+import shlex
+import shutil
+import subprocess
+import tarfile
+import tempfile
+from datetime import datetime
+from pathlib import Path
+from typing import Callable, Dict, List, Optional
+
+from cli.constants import CONTROL_PLANE_BACKUP_PASSPHRASE_ENV
+
+# Orchestrator containers and volumes (addons/monitoring, addons/caddy)
+GRAFANA_CONTAINER = "superdeploy-grafana"
+GRAFANA_PROVISIONING = "/etc/grafana/provisioning"
+GRAFANA_VOLUME = "superdeploy-grafana-data"
+PROVISIONING_MOUNT = (
+    "{{range .Mounts}}{{if eq .Destination "
+    f'"{GRAFANA_PROVISIONING}"'
+    "}}{{.Source}}{{end}}{{end}}"
+)
+CADDY_CONTAINER = "orchestrator-caddy"
+CADDY_VOLUME = "orchestrator-caddy-data"
+
+# Restore order: the database first, it knows where the orchestrator is
+COMPONENTS = ["db", "grafana", "caddy"]
+
+ARCHIVE_PATTERN = re.compile(r"^superdeploy_\d{8}_\d{6}\.tar\.gz\.enc$")
+
+
+def is_remote(target: str) -> bool:
+    return str(target).startswith("gs://")
+
+
+def pg_url(url: str) -> str:
+    """libpq URL from an SQLAlchemy one (postgresql+psycopg2:// -> postgresql://)"""
+    return re.sub(r"^postgresql\+\w+://", "postgresql://", url)
+
+
+class OrchestratorBackupService:
+    """Creates, uploads, prunes and restores control plane backups."""
+
+    def __init__(
+        self,
+        db_url: str,
+        orchestrator_ip: Optional[str] = None,
+        ssh_service=None,
+        on_host: bool = False,
+        log: Optional[Callable[[str], None]] = None,
+    ):
+        """
+        Args:
+            orchestrator_ip: None = no orchestrator, only the database
+            on_host: Running on the orchestrator itself (scheduled runs)
+        """
+        self.db_url = pg_url(db_url)
+        self.orchestrator_ip = orchestrator_ip
+        self.ssh_service = ssh_service
+        self.on_host = on_host
+        self.log = log or (lambda message: None)
+
+    @property
+    def has_orchestrator(self) -> bool:
+        return self.on_host or bool(self.orchestrator_ip)
+
+    # ------------------------------------------------------------------
+    # Backup
+    # ------------------------------------------------------------------
+
+    def create(self, target: str, keep: Optional[int] = None) -> Dict:
+        """
+        Back up every component, encrypt and store under target.
+
+        Returns:
+            {"archive", "components", "pruned"}
+
+        Raises:
+            RuntimeError: If a component can't be dumped or stored
+        """
+        passphrase()
+        timestamp = datetime.now().strftime("%Y%m%d_%H%M%S")
+        name = f"superdeploy_{timestamp}.tar.gz.enc"
+
+        with tempfile.TemporaryDirectory(prefix="superdeploy-backup-") as tmp:
+            files = {"db": {"state.sql.gz": self._dump_database()}}
+            if self.has_orchestrator:
+                files["grafana"] = {
+                    "grafana-provisioning.tar.gz": self._remote_bytes(
+                        f"docker cp {GRAFANA_CONTAINER}:{GRAFANA_PROVISIONING} - "
+                        f"| gzip"
+                    ),
+                    "grafana-data.tar.gz": self._remote_bytes(
+                        self._volume_dump(GRAFANA_VOLUME)
+                    ),
+                }
+                files["caddy"] = {
+                    "caddy-data.tar.gz": self._remote_bytes(
+                        self._volume_dump(CADDY_VOLUME)
+                    )
+                }
+            else:
+                self.log("Orchestrator not deployed, backing up the database only")
+
+            manifest = {
+                "kind": "control-plane",
+                "created_at": datetime.now().isoformat(),
+                "orchestrator_ip": self.orchestrator_ip,
+                "components": {
+                    component: sorted(entries) for component, entries in files.items()
+                },
+            }
+            bundle = io.BytesIO()
+            with tarfile.open(fileobj=bundle, mode="w:gz") as tar:
+                entries = {"manifest.json": json.dumps(manifest, indent=2).encode()}
+                for component_files in files.values():
+                    entries.update(component_files)
+                for filename, data in entries.items():
+                    info = tarfile.TarInfo(filename)
+                    info.size = len(data)
+                    info.mtime = int(datetime.now().timestamp())
+                    tar.addfile(info, io.BytesIO(data))
+
+            archive = Path(tmp) / name
+            self._openssl(["-e"], bundle.getvalue(), archive)
+            location = self._store(archive, target)
+
+        pruned = self.prune(target, keep) if keep else []
+        return {
+            "archive": location,
+            "components": manifest["components"],
+            "pruned": pruned,
+        }
+
+    def _dump_database(self) -> bytes:
+        if not shutil.which("pg_dump"):
+            raise RuntimeError("pg_dump not found (install the PostgreSQL client)")
+        result = subprocess.run(
+            ["pg_dump", "--clean", "--if-exists", "--no-owner", self.db_url],
+            capture_output=True,
+        )
+        if result.returncode != 0:
+            raise RuntimeError(f"pg_dump failed: {result.stderr.decode().strip()}")
+        self.log("State database dumped")
+        return gzip.compress(result.stdout)
+
+    @staticmethod
+    def _volume_dump(volume: str) -> str:
+        return (
+            f"docker run --rm -v {volume}:/volume:ro alpine "
+            f"tar -czf - -C /volume ."
+        )
+
+    def _remote_bytes(self, command: str) -> bytes:
+        result = self._run(f"set -o pipefail; {command} | base64 -w0")
+        if result.returncode != 0:
+            raise RuntimeError(f"{command.split(' | ')[0]}: {result.stderr.strip()}")
+        return base64.b64decode(result.stdout.strip())
+
+    def _store(self, archive: Path, target: str) -> str:
+        if is_remote(target):
+            location = f"{target.rstrip('/')}/{archive.name}"
+            self._gcloud(["cp", str(archive), location])
+        else:
+            directory = Path(target)
+            directory.mkdir(parents=True, exist_ok=True)
+            location = str(directory / archive.name)
+            shutil.copyfile(archive, location)
+            os.chmod(location, 0o600)
+        self.log(f"Stored {location}")
+        return location
+
+    # ------------------------------------------------------------------
+    # Listing and retention
+    # ------------------------------------------------------------------
+
+    def list(self, target: str) -> List[str]:
+        """Archives under target, oldest first"""
+        if is_remote(target):
+            result = subprocess.run(
+                ["gcloud", "storage", "ls", f"{target.rstrip('/')}/"],
+                capture_output=True,
+                text=True,
+            )
+            names = result.stdout.split() if result.returncode == 0 else []
+        else:
+            directory = Path(target)
+            names = (
+                [str(path) for path in directory.iterdir()]
+                if directory.is_dir()
+                else []
+            )
+        return sorted(
+            name for name in names if ARCHIVE_PATTERN.match(name.rsplit("/", 1)[-1])
+        )
+
+    def prune(self, target: str, keep: int) -> List[str]:
+        """Delete all but the newest `keep` archives"""
+        pruned = self.list(target)[:-keep] if keep > 0 else []
+        for location in pruned:
+            if is_remote(target):
+                self._gcloud(["rm", location])
+            else:
+                Path(location).unlink()
+        return pruned
+
+    # ------------------------------------------------------------------
+    # Restore
+    # ------------------------------------------------------------------
+
+    def fetch(self, source: str, workdir: Path) -> Dict:
+        """
+        Fetch, decrypt and unpack an archive into workdir.
+
+        Args:
+            source: Archive path or gs:// URL; a directory or bucket
+                prefix means its newest archive
+
+        Returns:
+            The archive's manifest (plus "archive": where it came from)
+        """
+        passphrase()
+        if not ARCHIVE_PATTERN.match(source.rstrip("/").rsplit("/", 1)[-1]):
+            archives = self.list(source)
+            if not archives:
+                raise RuntimeError(f"No backups found in {source}")
+            source = archives[-1]
+
+        encrypted = workdir / "archive.enc"
+        if is_remote(source):
+            self._gcloud(["cp", source, str(encrypted)])
+        else:
+            if not Path(source).is_file():
+                raise RuntimeError(f"Backup not found: {source}")
+            shutil.copyfile(source, encrypted)
+
+        bundle = workdir / "bundle.tar.gz"
+        self._openssl(["-d"], encrypted.read_bytes(), bundle)
+        with tarfile.open(bundle, "r:gz") as tar:
+            for member in tar.getmembers():
+                if not member.isfile() or "/" in member.name:
+                    raise RuntimeError(f"Unexpected entry in archive: {member.name}")
+            tar.extractall(workdir / "bundle")
+        manifest = json.loads((workdir / "bundle" / "manifest.json").read_text())
+        manifest["archive"] = source
+        return manifest
+
+    def restore(self, component: str, bundle_dir: Path) -> None:
+        """
+        Restore one component from an unpacked archive.
+
+        Raises:
+            RuntimeError: If the restore fails
+        """
+        if component == "db":
+            self._restore_database(bundle_dir / "state.sql.gz")
+            return
+        if not self.has_orchestrator:
+            raise RuntimeError(
+                f"Restoring {component} needs a deployed orchestrator "
+                f"(superdeploy orchestrator:up)"
+            )
+        if component == "grafana":
+            # Provisioning is a bind mount; extract into its host directory
+            self._remote_load(
+                f"dir=$(docker inspect -f {shlex.quote(PROVISIONING_MOUNT)} "
+                f'{GRAFANA_CONTAINER}) && tar -xzf - --strip-components=1 -C "$dir"',
+                bundle_dir / "grafana-provisioning.tar.gz",
+            )
+            self._restore_volume(
+                GRAFANA_CONTAINER, GRAFANA_VOLUME, bundle_dir / "grafana-data.tar.gz"
+            )
+        elif component == "caddy":
+            self._restore_volume(
+                CADDY_CONTAINER, CADDY_VOLUME, bundle_dir / "caddy-data.tar.gz"
+            )
+        else:
+            raise RuntimeError(f"Unknown component: {component}")
+
+    def _restore_database(self, dump: Path) -> None:
+        if not shutil.which("psql"):
+            raise RuntimeError("psql not found (install the PostgreSQL client)")
+        result = subprocess.run(
+            [
+                "psql",
+                "--quiet",
+                "--single-transaction",
+                "-v",
+                "ON_ERROR_STOP=1",
+                self.db_url,
+            ],
+            input=gzip.decompress(dump.read_bytes()),
+            capture_output=True,
+        )
+        if result.returncode != 0:
+            raise RuntimeError(f"psql failed: {result.stderr.decode().strip()}")
+
+    def _restore_volume(self, container: str, volume: str, data: Path) -> None:
+        """Stop the container, replace the volume's contents, start it again"""
+        load = (
+            f"docker run --rm -i -v {volume}:/volume alpine "
+            f"sh -c 'find /volume -mindepth 1 -delete && tar -xzf - -C /volume'"
+        )
+        self._remote_load(
+            f"docker stop {container} >/dev/null; {load}; status=$?; "
+            f"docker start {container} >/dev/null; exit $status",
+            data,
+        )
+
+    def _remote_load(self, command: str, data: Path) -> None:
+        result = self._run(
+            f"set -o pipefail; base64 -d | {{ {command}; }}",
+            input_data=base64.b64encode(data.read_bytes()).decode(),
+        )
+        if result.returncode != 0:
+            raise RuntimeError(result.stderr.strip() or f"{data.name}: restore failed")
+
+    # ------------------------------------------------------------------
+    # Helpers
+    # ------------------------------------------------------------------
+
+    def _run(self, command: str, input_data: Optional[str] = None):
+        """Run a shell command on the orchestrator"""
+        if self.on_host:
+            return subprocess.run(
+                ["bash", "-c", command],
+                input=input_data,
+                capture_output=True,
+                text=True,
+                timeout=1800,
+            )
+        return self.ssh_service.execute_command(
+            self.orchestrator_ip,
+            f"bash -c {shlex.quote(command)}",
+            timeout=1800,
+            input_data=input_data,
+        )
+
+    @staticmethod
+    def _openssl(mode: List[str], data: bytes, output: Path) -> None:
+        result = subprocess.run(
+            ["openssl", "enc", *mode, "-aes-256-cbc", "-pbkdf2", "-iter", "200000"]
+            + ["-salt", "-pass", f"env:{CONTROL_PLANE_BACKUP_PASSPHRASE_ENV}"]
+            + ["-out", str(output)],
+            input=data,
+            capture_output=True,
+        )
+        if result.returncode != 0:
+            action = "decrypt (wrong passphrase?)" if "-d" in mode else "encrypt"
+            raise RuntimeError(f"Could not {action}: {result.stderr.decode().strip()}")
+
+    @staticmethod
+    def _gcloud(args: List[str]) -> None:
+        result = subprocess.run(
+            ["gcloud", "storage", *args], capture_output=True, text=True
+        )
+        if result.returncode != 0:
+            raise RuntimeError(f"gcloud storage {args[0]}: {result.stderr.strip()}")
+
+
+def passphrase() -> str:
+    """
+    Raises:
+        RuntimeError: If no passphrase is set (backups are never unencrypted)
+    """
+    value = os.getenv(CONTROL_PLANE_BACKUP_PASSPHRASE_ENV)
+    if not value:
+        raise RuntimeError(
+            f"Set {CONTROL_PLANE_BACKUP_PASSPHRASE_ENV}; control plane backups "
+            f"are always encrypted"
+        )
+    return value
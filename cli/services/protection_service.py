@@ -0,0 +1,184 @@
+"""
+Protection Service
+
+Deletion protection for projects and their addons. While a flag is set,
+`down` and the dashboard's delete/teardown refuse to run. Setting a flag is
+free, lifting one needs a reason; every change lands in activity_logs.
+"""
+
+import getpass
+import os
+import shutil
+import subprocess
+from typing import Dict, List, Optional
+
+from cli.database import get_db_session, ActivityLog, Addon, Project
+
+
+class ProtectedError(ValueError):
+    """The project or one of its addons has deletion protection"""
+
+
+def current_actor() -> str:
+    """Who runs the command, for the audit log (SUPERDEPLOY_ACTOR overrides)"""
+    actor = os.getenv("SUPERDEPLOY_ACTOR")
+    if actor:
+        return actor
+    try:
+        return getpass.getuser()
+    except Exception:
+        return "cli"
+
+
+def github_login() -> Optional[str]:
+    """
+    GitHub login of the gh CLI session, or None without one.
+
+    Unlike current_actor() this can't be set from the environment, so
+    deploy approvals bind to it.
+    """
+    if not shutil.which("gh"):
+        return None
+    try:
+        result = subprocess.run(
+            ["gh", "api", "user", "--jq", ".login"],
+            capture_output=True,
+            text=True,
+            timeout=15,
+        )
+    except Exception:
+        return None
+    login = result.stdout.strip()
+    return login if result.returncode == 0 and login else None
+
+
+def audit(db, project_name: str, action: str, actor: str, details: Dict) -> None:
+    """Add an activity log entry (committed with the caller's session)"""
+    db.add(
+        ActivityLog(
+            project_name=project_name, action=action, actor=actor, details=details
+        )
+    )
+
+
+class ProtectionService:
+    """Reads and changes deletion protection flags."""
+
+    def status(self, project_name: str) -> Dict:
+        """
+        {"project": bool, "addons": {"databases.primary": bool, ...}}
+
+        Raises:
+            ValueError: If the project doesn't exist
+        """
+        db = get_db_session()
+        try:
+            project = self._project(db, project_name)
+            return {
+                "project": bool(project.deletion_protection),
+                "addons": {
+                    f"{addon.category}.{addon.instance_name}": bool(
+                        addon.deletion_protection
+                    )
+                    for addon in sorted(
+                        project.addons, key=lambda a: (a.category, a.instance_name)
+                    )
+                },
+            }
+        finally:
+            db.close()
+
+    def set(
+        self,
+        project_name: str,
+        enabled: bool,
+        addon: Optional[str] = None,
+        reason: Optional[str] = None,
+        actor: Optional[str] = None,
+    ) -> bool:
+        """
+        Set or lift protection on the project, or on one addon.
+
+        Args:
+            addon: <category>.<instance> (None = the project itself)
+            reason: Why; required to lift protection
+
+        Returns:
+            False if the flag already had that value
+
+        Raises:
+            ValueError: Unknown project/addon, or lifting without a reason
+        """
+        if not enabled and not (reason or "").strip():
+            raise ValueError("A reason is required to lift deletion protection")
+
+        db = get_db_session()
+        try:
+            project = self._project(db, project_name)
+            target = project
+            if addon:
+                category, _, instance = addon.partition(".")
+                target = (
+                    db.query(Addon)
+                    .filter(
+                        Addon.project_id == project.id,
+                        Addon.category == category,
+                        Addon.instance_name == instance,
+                    )
+                    .first()
+                )
+                if not target:
+                    raise ValueError(f"Addon not found: {project_name}:{addon}")
+
+            if bool(target.deletion_protection) == enabled:
+                return False
+            target.deletion_protection = enabled
+            audit(
+                db,
+                project_name,
+                "protection:enable" if enabled else "protection:disable",
+                actor or current_actor(),
+                {"target": addon or "project", "reason": reason},
+            )
+            db.commit()
+            return True
+        finally:
+            db.close()
+
+    def blockers(self, project_name: str) -> List[str]:
+        """What keeps the project from being deleted (empty = nothing)"""
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            if not project:
+                return []
+            blockers = ["project"] if project.deletion_protection else []
+            blockers += sorted(
+                f"addon {addon.category}.{addon.instance_name}"
+                for addon in project.addons
+                if addon.deletion_protection
+            )
+            return blockers
+        finally:
+            db.close()
+
+    def check(self, project_name: str) -> None:
+        """
+        Raises:
+            ProtectedError: If anything in the project is protected
+        """
+        blockers = self.blockers(project_name)
+        if blockers:
+            raise ProtectedError(
+                f"Deletion protection is on for {project_name} "
+                f"({', '.join(blockers)}). Lift it with: superdeploy "
+                f"{project_name}:unprotect [--addon <category>.<instance>] "
+                f"--reason '...'"
+            )
+
+    @staticmethod
+    def _project(db, project_name: str) -> Project:
+        project = db.query(Project).filter(Project.name == project_name).first()
+        if not project:
+            raise ValueError(f"Project '{project_name}' not found")
+        return project
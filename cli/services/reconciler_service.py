@@ -0,0 +1,325 @@
+"""
+Reconciler Service
+
+GitOps loop for the orchestrator: polls a git repository of project
+manifests, applies what changed through ManifestService (the code path
+behind `superdeploy apply`) and runs the same rollout commands apply
+suggests. Sync state per project lives in reconciler_status so the
+dashboard can show it; a failure pauses the project until resumed.
+"""
+
+import shlex
+import shutil
+import subprocess
+import sys
+from datetime import datetime
+from pathlib import Path
+from typing import Callable, Dict, List, Optional, Tuple
+
+import yaml
+
+from cli.core.manifest import API_VERSION, rollout_commands
+from cli.database import get_db_session, ReconcilerStatus
+from cli.services.manifest_service import ManifestService
+
+# Rollout steps the reconciler can't run: generate writes workflow files
+# into local app repositories, which don't exist on the orchestrator
+SKIPPED_STEPS = (":generate",)
+
+# Characters of command output kept in last_error
+ERROR_TAIL = 4000
+
+
+class ReconcilerService:
+    """
+    Sync manifests from a git repository into the database.
+
+    Every *.yml/*.yaml under `path` with `apiVersion: superdeploy/v1` is a
+    project manifest. Projects are reconciled independently; one failing
+    never blocks the others.
+    """
+
+    def __init__(
+        self,
+        repo: str,
+        branch: str = "main",
+        path: str = ".",
+        prune: bool = False,
+        dry_run: bool = False,
+        workdir: Optional[Path] = None,
+        log: Optional[Callable[[str], None]] = None,
+    ):
+        self.repo = repo
+        self.branch = branch
+        self.path = path
+        self.prune = prune
+        self.dry_run = dry_run
+        self.workdir = Path(workdir or Path.home() / ".superdeploy-reconciler")
+        self.checkout = self.workdir / "repo"
+        self.log = log or (lambda message: None)
+        self.manifests = ManifestService()
+
+    def sync_once(self) -> List[Dict[str, str]]:
+        """
+        Fetch the repository and reconcile every manifest in it.
+
+        Returns:
+            [{"project", "status", "error"}] for each manifest found
+        """
+        revision = self.fetch()
+        found, conflicts = self.discover()
+        results = []
+
+        for project_name, manifest_path in sorted(found.items()):
+            relative = str(manifest_path.relative_to(self.checkout))
+            if project_name in conflicts:
+                status = self._fail(
+                    project_name,
+                    relative,
+                    revision,
+                    f"Declared in more than one manifest: {conflicts[project_name]}",
+                )
+            else:
+                status = self.reconcile(project_name, manifest_path, revision)
+            results.append({"project": project_name, **status})
+
+        self._forget(set(found))
+        return results
+
+    def fetch(self) -> str:
+        """Bring the local checkout to the tip of the branch, return its commit"""
+        if (self.checkout / ".git").exists():
+            origin = self._git("remote", "get-url", "origin", cwd=self.checkout)
+            if origin != self.repo:
+                shutil.rmtree(self.checkout)
+
+        if not (self.checkout / ".git").exists():
+            self.workdir.mkdir(parents=True, exist_ok=True)
+            self._git("clone", "--no-checkout", self.repo, str(self.checkout))
+
+        self._git("fetch", "--depth", "1", "origin", self.branch, cwd=self.checkout)
+        self._git("checkout", "--force", "FETCH_HEAD", cwd=self.checkout)
+        return self._git("rev-parse", "HEAD", cwd=self.checkout)
+
+    def discover(self) -> Tuple[Dict[str, Path], Dict[str, str]]:
+        """
+        Find project manifests under the configured path.
+
+        Returns:
+            ({project: manifest file}, {project: "a.yml, b.yml"} declared twice)
+        """
+        root = (self.checkout / self.path).resolve()
+        found: Dict[str, Path] = {}
+        conflicts: Dict[str, str] = {}
+
+        files = sorted([*root.rglob("*.yml"), *root.rglob("*.yaml")])
+        for manifest_path in files:
+            if ".git" in manifest_path.relative_to(self.checkout).parts:
+                continue
+            try:
+                data = yaml.safe_load(manifest_path.read_text())
+            except (yaml.YAMLError, UnicodeDecodeError):
+                continue  # Not ours to judge; could be any YAML in the repo
+            if not isinstance(data, dict) or data.get("apiVersion") != API_VERSION:
+                continue
+            project_name = (data.get("project") or {}).get("name")
+            if not isinstance(project_name, str):
+                continue
+            if project_name in found:
+                paths = [found[project_name], manifest_path]
+                conflicts[project_name] = ", ".join(
+                    str(p.relative_to(self.checkout)) for p in paths
+                )
+                continue
+            found[project_name] = manifest_path
+
+        return found, conflicts
+
+    def reconcile(
+        self, project_name: str, manifest_path: Path, revision: str
+    ) -> Dict[str, str]:
+        """Plan one manifest and apply it unless the project is paused"""
+        from cli.core.addon_schema import load_yaml_file, validate_file
+
+        relative = str(manifest_path.relative_to(self.checkout))
+
+        issues = validate_file("manifest", manifest_path)
+        if issues:
+            return self._fail(
+                project_name, relative, revision, "\n".join(str(i) for i in issues)
+            )
+
+        manifest = load_yaml_file(manifest_path)
+        changes, problems = self.manifests.plan(manifest, prune=self.prune)
+        if problems:
+            return self._fail(project_name, relative, revision, "\n".join(problems))
+
+        row = self._record(
+            project_name,
+            manifest_path=relative,
+            revision=revision,
+            drift=[c.to_dict() for c in changes] or None,
+            last_checked_at=datetime.utcnow(),
+        )
+        pending = list(row.pending_rollout or [])
+
+        if row.paused:
+            # Keep reporting drift, but leave a failed status visible until resumed
+            if row.status != "failed":
+                self._record(project_name, status="drifted" if changes else "synced")
+            return {"status": "paused", "error": row.last_error or ""}
+
+        if not changes and not pending:
+            self._record(
+                project_name,
+                status="synced",
+                applied_revision=revision,
+                last_error=None,
+                last_synced_at=(
+                    row.last_synced_at if row.status == "synced" else datetime.utcnow()
+                ),
+            )
+            return {"status": "synced", "error": ""}
+
+        if self.dry_run:
+            self._record(project_name, status="drifted")
+            return {"status": "drifted", "error": ""}
+
+        self._record(project_name, status="applying")
+        if changes:
+            self.log(f"{project_name}: applying {len(changes)} change(s) @ {revision}")
+            try:
+                self.manifests.apply(manifest, changes)
+            except Exception as e:
+                return self._fail(project_name, relative, revision, str(e))
+
+            for step in rollout_commands(project_name, changes):
+                if step.endswith(SKIPPED_STEPS):
+                    self.log(f"{project_name}: skipping '{step}' (run it locally)")
+                elif step not in pending:
+                    pending.append(step)
+            self._record(project_name, drift=None, pending_rollout=pending or None)
+
+        while pending:
+            step = pending[0]
+            self.log(f"{project_name}: superdeploy {step}")
+            ok, output = self._superdeploy(step)
+            if not ok:
+                return self._fail(
+                    project_name,
+                    relative,
+                    revision,
+                    f"superdeploy {step} failed:\n{output[-ERROR_TAIL:]}",
+                )
+            pending.pop(0)
+            self._record(project_name, pending_rollout=pending or None)
+
+        self._record(
+            project_name,
+            status="synced",
+            applied_revision=revision,
+            last_error=None,
+            last_synced_at=datetime.utcnow(),
+        )
+        return {"status": "synced", "error": ""}
+
+    @staticmethod
+    def set_paused(project_name: str, paused: bool) -> bool:
+        """
+        Pause or resume a project. Resuming clears the failure so the next
+        cycle retries, including rollout commands that didn't finish.
+
+        Returns:
+            False if the reconciler has never seen the project
+        """
+        db = get_db_session()
+        try:
+            row = (
+                db.query(ReconcilerStatus)
+                .filter(ReconcilerStatus.project_name == project_name)
+                .first()
+            )
+            if not row:
+                return False
+            row.paused = paused
+            if not paused:
+                row.last_error = None
+                if row.status == "failed":
+                    row.status = "pending"
+            db.commit()
+            return True
+        finally:
+            db.close()
+
+    def _fail(
+        self, project_name: str, manifest_path: str, revision: str, error: str
+    ) -> Dict[str, str]:
+        """Record a failure and pause the project"""
+        self.log(f"{project_name}: failed, pausing\n{error}")
+        self._record(
+            project_name,
+            manifest_path=manifest_path,
+            revision=revision,
+            status="failed",
+            paused=True,
+            last_error=error,
+            last_checked_at=datetime.utcnow(),
+        )
+        return {"status": "failed", "error": error}
+
+    def _record(self, project_name: str, **values) -> ReconcilerStatus:
+        """Upsert the status row (committed immediately, the dashboard polls it)"""
+        db = get_db_session()
+        try:
+            row = (
+                db.query(ReconcilerStatus)
+                .filter(ReconcilerStatus.project_name == project_name)
+                .first()
+            )
+            if row is None:
+                row = ReconcilerStatus(project_name=project_name, status="pending")
+                db.add(row)
+            for column, value in values.items():
+                setattr(row, column, value)
+            db.commit()
+            db.refresh(row)
+            db.expunge(row)
+            return row
+        finally:
+            db.close()
+
+    def _forget(self, project_names: set) -> None:
+        """Drop status rows for manifests removed from the repo (projects stay)"""
+        db = get_db_session()
+        try:
+            query = db.query(ReconcilerStatus)
+            if project_names:
+                query = query.filter(~ReconcilerStatus.project_name.in_(project_names))
+            query.delete(synchronize_session=False)
+            db.commit()
+        finally:
+            db.close()
+
+    def _superdeploy(self, step: str) -> Tuple[bool, str]:
+        """Run a CLI command in a subprocess, like an operator would"""
+        from cli.common_utils import get_project_root
+
+        try:
+            result = subprocess.run(
+                [sys.executable, "-m", "cli.main", *shlex.split(step)],
+                cwd=get_project_root(),
+                capture_output=True,
+                text=True,
+                timeout=3600,
+            )
+        except subprocess.TimeoutExpired:
+            return False, "Timed out after 1 hour"
+        return result.returncode == 0, result.stdout + result.stderr
+
+    def _git(self, *args: str, cwd: Optional[Path] = None) -> str:
+        result = subprocess.run(
+            ["git", *args], cwd=cwd, capture_output=True, text=True, timeout=300
+        )
+        if result.returncode != 0:
+            raise RuntimeError(f"git {args[0]} failed: {result.stderr.strip()}")
+        return result.stdout.strip()
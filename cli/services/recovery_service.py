@@ -0,0 +1,317 @@
+"""
+Recovery Service
+
+Building blocks of projects:recover: finding a backup (backups:create or
+the final backup `down` takes), moving the project to another region,
+loading addon dumps into the rebuilt VMs, pinning apps to the image digest
+recorded in the backup, pointing DNS at the new IPs, and the timed report
+DR drills are judged by.
+"""
+
+import base64
+import gzip
+import json
+import shlex
+import socket
+import subprocess
+import time
+from contextlib import contextmanager
+from dataclasses import dataclass, field
+from datetime import datetime
+from pathlib import Path
+from typing import Dict, List, Optional, Tuple
+
+from cli.database import get_db_session, Project
+from cli.services.protection_service import audit, current_actor
+
+
+@dataclass
+class RecoveryStep:
+    """One timed step of a recovery"""
+
+    name: str
+    status: str = "ok"  # ok, failed, skipped, pending
+    seconds: float = 0.0
+    detail: str = ""
+
+    def to_dict(self) -> Dict:
+        return {
+            "name": self.name,
+            "status": self.status,
+            "seconds": self.seconds,
+            "detail": self.detail,
+        }
+
+
+@dataclass
+class RecoveryReport:
+    """What projects:recover did, how long each step took"""
+
+    project: str
+    backup: str
+    backup_date: Optional[str] = None
+    region: Optional[str] = None
+    zone: Optional[str] = None
+    previous_region: Optional[str] = None
+    previous_zone: Optional[str] = None
+    started_at: datetime = field(default_factory=datetime.now)
+    finished_at: Optional[datetime] = None
+    steps: List[RecoveryStep] = field(default_factory=list)
+
+    @contextmanager
+    def step(self, name: str):
+        """Time a step; an exception marks it failed and propagates"""
+        step = RecoveryStep(name)
+        self.steps.append(step)
+        start = time.monotonic()
+        try:
+            yield step
+        except Exception as e:
+            step.status = "failed"
+            step.detail = str(e)
+            raise
+        finally:
+            step.seconds = round(time.monotonic() - start, 1)
+
+    @property
+    def ok(self) -> bool:
+        return not any(step.status == "failed" for step in self.steps)
+
+    @property
+    def total_seconds(self) -> float:
+        end = self.finished_at or datetime.now()
+        return round((end - self.started_at).total_seconds(), 1)
+
+    @property
+    def data_age_seconds(self) -> Optional[float]:
+        """How old the restored data was when recovery started (RPO)"""
+        if not self.backup_date:
+            return None
+        taken = datetime.fromisoformat(self.backup_date)
+        return round((self.started_at - taken).total_seconds(), 1)
+
+    def to_dict(self) -> Dict:
+        return {
+            "project": self.project,
+            "ok": self.ok,
+            "backup": self.backup,
+            "backup_date": self.backup_date,
+            "region": self.region,
+            "zone": self.zone,
+            "previous_region": self.previous_region,
+            "previous_zone": self.previous_zone,
+            "started_at": self.started_at.isoformat(),
+            "finished_at": self.finished_at.isoformat() if self.finished_at else None,
+            "total_seconds": self.total_seconds,
+            "data_age_seconds": self.data_age_seconds,
+            "steps": [step.to_dict() for step in self.steps],
+        }
+
+
+class RecoveryService:
+    """Backups, region moves, restores, pinned deploys and DNS."""
+
+    def __init__(self, backups_dir: Path = Path("./backups")):
+        self.backups_dir = Path(backups_dir)
+
+    def find_backup(self, project_name: str, ref: str) -> Path:
+        """
+        Backup directory for a reference: a path, a backup id (directory
+        name under ./backups/<project>/) or "latest".
+
+        Raises:
+            ValueError: If there is no such backup or it has no addon dumps
+        """
+        if ref == "latest":
+            candidates = [
+                path.parent
+                for path in (self.backups_dir / project_name).glob("*/manifest.json")
+                if "addons" in self.load_manifest(path.parent)
+            ]
+            if not candidates:
+                raise ValueError(
+                    f"No backup of {project_name} with addon dumps in "
+                    f"{self.backups_dir / project_name}\n"
+                    f"Take one with: superdeploy {project_name}:backups:create"
+                )
+            return max(
+                candidates, key=lambda path: self.load_manifest(path)["backup_date"]
+            )
+
+        path = Path(ref)
+        if not path.is_dir():
+            path = self.backups_dir / project_name / ref
+        if not (path / "manifest.json").is_file():
+            raise ValueError(f"Backup not found: {ref}")
+        manifest = self.load_manifest(path)
+        if "addons" not in manifest:
+            raise ValueError(
+                f"{path} predates addon dumps; take a new one with "
+                f"superdeploy {manifest.get('project', project_name)}:backups:create"
+            )
+        for entry in manifest["addons"]:
+            if not (path / entry["file"]).is_file():
+                raise ValueError(f"{path}: {entry['file']} is missing")
+        return path
+
+    @staticmethod
+    def load_manifest(path: Path) -> Dict:
+        return json.loads((Path(path) / "manifest.json").read_text())
+
+    @staticmethod
+    def set_location(
+        project_name: str, region: str, zone: str
+    ) -> Tuple[Optional[str], Optional[str]]:
+        """
+        Move the project's infrastructure definition to region/zone.
+
+        Returns:
+            The previous (region, zone)
+        """
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            previous = (project.gcp_region, project.gcp_zone)
+            project.gcp_region = region
+            project.gcp_zone = zone
+            audit(
+                db,
+                project_name,
+                "project:recover",
+                current_actor(),
+                {
+                    "region": region,
+                    "zone": zone,
+                    "previous_region": previous[0],
+                    "previous_zone": previous[1],
+                },
+            )
+            db.commit()
+            return previous
+        finally:
+            db.close()
+
+    @staticmethod
+    def app_targets(project_name: str) -> Dict[str, Dict]:
+        """{app: {"vm", "processes", "domain"}} from the DB definition"""
+        db = get_db_session()
+        try:
+            project = db.query(Project).filter(Project.name == project_name).first()
+            return {
+                app.name: {
+                    "vm": app.vm or "app",
+                    "processes": sorted(p.name for p in app.processes),
+                    "domain": app.domain,
+                }
+                for app in project.apps
+            }
+        finally:
+            db.close()
+
+    @staticmethod
+    def restore_command(
+        entry: Dict,
+        backup_path: Path,
+        source: str,
+        target: str,
+        config: Dict[str, str],
+    ) -> Tuple[str, str]:
+        """
+        (remote command, stdin) loading one addon dump. Dumps of another
+        project (DR drills) are retargeted like projects:clone seeds.
+        """
+        from cli.commands.backup import BackupService
+        from cli.services.clone_service import ProjectCloneService
+
+        data = (backup_path / entry["file"]).read_bytes()
+        config = dict(config)
+        if source != target:
+            if entry["type"] == "postgres":
+                dump = gzip.decompress(data).decode()
+                dump = ProjectCloneService.retarget_dump(dump, source, target)
+                data = gzip.compress(dump.encode())
+            else:
+                config["source_database"] = entry.get("database") or f"{source}_db"
+
+        restore = BackupService.get_database_restore_command(
+            entry["type"], config, target
+        )
+        remote = f"set -o pipefail; base64 -d | gunzip | {restore}"
+        return f"bash -c {shlex.quote(remote)}", base64.b64encode(data).decode()
+
+    @staticmethod
+    def redeploy_script(
+        project_name: str, app_name: str, processes: List[str], release: Dict
+    ) -> str:
+        """
+        Pin an app to the recorded digest: the digest is tagged as the
+        :latest the compose file references, then the services recreated.
+        """
+        image = release["image"]
+        latest = image.split("@")[0] + ":latest"
+        services = " ".join(f"{app_name}-{process}" for process in processes)
+        entry = {
+            **{k: v for k, v in release.items() if k != "image"},
+            "deployed_at": datetime.utcnow().strftime("%Y-%m-%dT%H:%M:%SZ"),
+            "deployed_by": "projects:recover",
+            "image": image,
+        }
+        project_dir = f"/opt/superdeploy/projects/{project_name}"
+        return "\n".join(
+            [
+                "set -e",
+                f"docker pull {shlex.quote(image)}",
+                f"docker tag {shlex.quote(image)} {shlex.quote(latest)}",
+                f"cd {project_dir}/compose",
+                f"docker compose up -d --no-deps --force-recreate {services}",
+                f'RELEASES_FILE="{project_dir}/releases.json"',
+                '[ -f "$RELEASES_FILE" ] || echo \'{}\' > "$RELEASES_FILE"',
+                f"jq --arg app {shlex.quote(app_name)} "
+                f"--argjson release {shlex.quote(json.dumps(entry))} "
+                "'.[$app] = ((.[$app] // []) + [$release])' "
+                '"$RELEASES_FILE" > "$RELEASES_FILE.tmp"',
+                'mv "$RELEASES_FILE.tmp" "$RELEASES_FILE"',
+                "",
+            ]
+        )
+
+    @staticmethod
+    def update_dns(domain: str, ip: str, dns_zone: Optional[str]) -> Tuple[str, str]:
+        """
+        Point domain's A record at ip.
+
+        With a Cloud DNS managed zone the record is updated (or created)
+        through gcloud; without one the current resolution is checked.
+
+        Returns:
+            (status, detail) for the report
+        """
+        if dns_zone:
+            for verb in ("update", "create"):
+                result = subprocess.run(
+                    [
+                        "gcloud",
+                        "dns",
+                        "record-sets",
+                        verb,
+                        f"{domain}.",
+                        "--type=A",
+                        "--ttl=300",
+                        f"--rrdatas={ip}",
+                        f"--zone={dns_zone}",
+                    ],
+                    capture_output=True,
+                    text=True,
+                )
+                if result.returncode == 0:
+                    return "ok", f"A {domain} → {ip} ({verb}d in {dns_zone})"
+            raise RuntimeError(result.stderr.strip()[-300:] or "gcloud dns failed")
+
+        try:
+            current = socket.gethostbyname(domain)
+        except OSError:
+            current = None
+        if current == ip:
+            return "ok", f"A {domain} → {ip}"
+        resolved = current or "nothing"
+        return "pending", f"set A {domain} → {ip} (resolves to {resolved})"
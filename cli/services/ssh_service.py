@@ -42,6 +42,7 @@ class SSHService:
         command: str,
         timeout: Optional[int] = 30,
         capture_output: bool = True,
+        input_data: Optional[str] = None,
     ) -> SSHResult:
         """
         Execute command on remote host via SSH.
@@ -51,6 +52,7 @@ class SSHService:
             command: Command to execute
             timeout: Command timeout in seconds
             capture_output: Whether to capture stdout/stderr
+            input_data: Text piped to the remote command's stdin
 
         Returns:
             SSHResult with execution details
@@ -75,6 +77,7 @@ class SSHService:
                 capture_output=capture_output,
                 text=True,
                 timeout=timeout,
+                input=input_data,
             )
             duration = time.time() - start_time
 
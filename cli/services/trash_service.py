@@ -0,0 +1,273 @@
+"""
+Trash Service
+
+Soft delete for projects. Deleting a project moves its records (apps,
+processes, VMs, addons, secrets, aliases, files, grants, deploy policies
+and lifecycle hooks) into deleted_projects as one JSON snapshot; projects:undelete inserts them
+back with their original ids. Snapshots older than the retention period
+(SOFT_DELETE_DAYS) are purged.
+"""
+
+import os
+from datetime import datetime, timedelta
+from typing import Dict, List, Optional
+
+from sqlalchemy import DateTime, or_
+
+from cli.constants import SOFT_DELETE_DAYS
+from cli.database import (
+    get_db_session,
+    Addon,
+    App,
+    DeletedProject,
+    DeployApproval,
+    DeployFreeze,
+    DeployOverride,
+    DeployPolicy,
+    LifecycleHook,
+    Process,
+    Project,
+    Secret,
+    SecretAlias,
+    SecretDependency,
+    SecretFile,
+    SecretGrant,
+    VM,
+)
+from cli.services.protection_service import ProtectionService, audit, current_actor
+
+# Snapshot tables in insert order (parents first)
+MODELS = [
+    Project,
+    App,
+    Process,
+    VM,
+    Addon,
+    Secret,
+    SecretAlias,
+    SecretFile,
+    SecretGrant,
+    SecretDependency,
+    DeployPolicy,
+    DeployFreeze,
+    DeployApproval,
+    DeployOverride,
+    LifecycleHook,
+]
+
+
+def retention_days() -> int:
+    """Days a deleted project stays restorable"""
+    return int(os.getenv("SUPERDEPLOY_SOFT_DELETE_DAYS") or SOFT_DELETE_DAYS)
+
+
+def _dump(row) -> Dict:
+    values = {}
+    for column in row.__table__.columns:
+        value = getattr(row, column.key)
+        values[column.key] = (
+            value.isoformat() if isinstance(value, datetime) else value
+        )
+    return values
+
+
+def _load(model, values: Dict):
+    values = dict(values)
+    for column in model.__table__.columns:
+        if isinstance(column.type, DateTime) and values.get(column.key):
+            values[column.key] = datetime.fromisoformat(values[column.key])
+    return model(**values)
+
+
+def _entry(entry: DeletedProject) -> Dict:
+    return {
+        "id": entry.id,
+        "project": entry.project_name,
+        "deleted_at": entry.deleted_at.isoformat() if entry.deleted_at else None,
+        "deleted_by": entry.deleted_by,
+        "purge_after": entry.purge_after.isoformat(),
+        "final_backup": entry.final_backup,
+        "records": {
+            table: len(rows) for table, rows in entry.snapshot.items() if rows
+        },
+    }
+
+
+class TrashService:
+    """Soft-deletes, lists and restores projects."""
+
+    def delete(
+        self,
+        project_name: str,
+        actor: Optional[str] = None,
+        final_backup: Optional[str] = None,
+    ) -> Dict:
+        """
+        Move a project's records into the trash.
+
+        Args:
+            final_backup: Where `down` wrote the addons' final dumps
+
+        Raises:
+            ProtectedError: If the project or an addon is protected
+            ValueError: If the project doesn't exist
+        """
+        ProtectionService().check(project_name)
+
+        db = get_db_session()
+        try:
+            self._purge(db)
+            project = db.query(Project).filter(Project.name == project_name).first()
+            if not project:
+                raise ValueError(f"Project '{project_name}' not found")
+
+            records = self._records(db, project)
+            now = datetime.utcnow()
+            entry = DeletedProject(
+                project_name=project_name,
+                snapshot={
+                    model.__tablename__: [_dump(row) for row in rows]
+                    for model, rows in records
+                },
+                final_backup=final_backup,
+                deleted_by=actor or current_actor(),
+                deleted_at=now,
+                purge_after=now + timedelta(days=retention_days()),
+            )
+            db.add(entry)
+
+            # Children first, one flush per table (grants have no relationship)
+            for _, rows in reversed(records):
+                for row in rows:
+                    db.delete(row)
+                db.flush()
+
+            audit(
+                db,
+                project_name,
+                "project:delete",
+                entry.deleted_by,
+                {
+                    "purge_after": entry.purge_after.isoformat(),
+                    "final_backup": final_backup,
+                },
+            )
+            db.commit()
+            return _entry(entry)
+        except Exception:
+            db.rollback()
+            raise
+        finally:
+            db.close()
+
+    def undelete(self, project_name: str, actor: Optional[str] = None) -> Dict:
+        """
+        Restore the most recently deleted project with that name.
+
+        Raises:
+            ValueError: Nothing restorable, or the name is taken again
+        """
+        db = get_db_session()
+        try:
+            self._purge(db)
+            entry = (
+                db.query(DeletedProject)
+                .filter(
+                    DeletedProject.project_name == project_name,
+                    DeletedProject.restored_at.is_(None),
+                )
+                .order_by(DeletedProject.deleted_at.desc())
+                .first()
+            )
+            if not entry:
+                raise ValueError(
+                    f"No deleted project '{project_name}' to restore "
+                    f"(deleted projects are purged after {retention_days()} days)"
+                )
+            if db.query(Project).filter(Project.name == project_name).first():
+                raise ValueError(
+                    f"A project named '{project_name}' exists again; "
+                    f"remove it before restoring"
+                )
+
+            live = {row.id for row in db.query(Project.id)}
+            live.add(entry.snapshot["projects"][0]["id"])
+            for model in MODELS:
+                for values in entry.snapshot.get(model.__tablename__, []):
+                    # Grants to projects deleted since then are dropped
+                    if model is SecretGrant and not {
+                        values["source_project_id"],
+                        values["consumer_project_id"],
+                    } <= live:
+                        continue
+                    db.add(_load(model, values))
+                db.flush()
+
+            entry.restored_at = datetime.utcnow()
+            audit(
+                db,
+                project_name,
+                "project:undelete",
+                actor or current_actor(),
+                {"deleted_at": entry.deleted_at.isoformat()},
+            )
+            db.commit()
+            return _entry(entry)
+        except Exception:
+            db.rollback()
+            raise
+        finally:
+            db.close()
+
+    def list(self) -> List[Dict]:
+        """Deleted projects that can still be restored, newest first"""
+        db = get_db_session()
+        try:
+            self._purge(db)
+            db.commit()
+            entries = (
+                db.query(DeletedProject)
+                .filter(DeletedProject.restored_at.is_(None))
+                .order_by(DeletedProject.deleted_at.desc())
+                .all()
+            )
+            return [_entry(entry) for entry in entries]
+        finally:
+            db.close()
+
+    @staticmethod
+    def _records(db, project: Project) -> List[tuple]:
+        """(model, rows) for everything that belongs to the project"""
+        app_ids = [app.id for app in project.apps]
+        by_project = {
+            App: App.project_id == project.id,
+            Process: Process.app_id.in_(app_ids),
+            VM: VM.project_id == project.id,
+            Addon: Addon.project_id == project.id,
+            Secret: Secret.project_id == project.id,
+            SecretAlias: SecretAlias.project_id == project.id,
+            SecretFile: SecretFile.project_id == project.id,
+            SecretGrant: or_(
+                SecretGrant.source_project_id == project.id,
+                SecretGrant.consumer_project_id == project.id,
+            ),
+            SecretDependency: SecretDependency.consumer_project_id == project.id,
+            # Keyed by name: left behind they'd apply to a new project with it
+            DeployPolicy: DeployPolicy.project_name == project.name,
+            DeployFreeze: DeployFreeze.project_name == project.name,
+            DeployApproval: DeployApproval.project_name == project.name,
+            DeployOverride: DeployOverride.project_name == project.name,
+            LifecycleHook: LifecycleHook.project_name == project.name,
+        }
+        records = [(Project, [project])]
+        for model in MODELS[1:]:
+            rows = db.query(model).filter(by_project[model]).order_by(model.id).all()
+            records.append((model, rows))
+        return records
+
+    @staticmethod
+    def _purge(db) -> None:
+        """Drop snapshots past their retention period"""
+        db.query(DeletedProject).filter(
+            DeletedProject.purge_after < datetime.utcnow()
+        ).delete(synchronize_session=False)
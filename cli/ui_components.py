@@ -4,6 +4,7 @@ Standardized headers, logos, and UI elements
 """
 
 from rich.console import Console
+from rich.tree import Tree
 
 LOGO = "superdeploy"
 
@@ -82,3 +83,43 @@ def show_header(
 
     # Single blank line after header
     console.print()
+
+
+def addon_dependency_tree(graph, title: str = "Addon deploy order") -> Tree:
+    """
+    Render an AddonGraph as a tree: roots deploy first, children wait for
+    their parent to be healthy.
+
+    Args:
+        graph: cli.core.addon_graph.AddonGraph
+        title: Tree root label
+
+    Returns:
+        Rich Tree (instances with several dependencies appear under each)
+    """
+    tree = Tree(f"[bold]{title}[/bold]")
+    wave_of = {
+        name: index
+        for index, wave in enumerate(graph.deploy_waves(), start=1)
+        for name in wave
+    }
+    shown = set()
+
+    def add(branch: Tree, name: str) -> None:
+        node = graph.nodes[name]
+        label = (
+            f"[cyan]{name}[/cyan] [dim]{node.type}:{node.version} "
+            f"• {node.plan} • wave {wave_of[name]}[/dim]"
+        )
+        if name in shown:
+            branch.add(f"{label} [dim](see above)[/dim]")
+            return
+        shown.add(name)
+        child = branch.add(label)
+        for dependent in graph.dependents(name):
+            add(child, dependent)
+
+    for root in graph.roots():
+        add(tree, root)
+
+    return tree
@@ -0,0 +1,131 @@
+"""Create org_secrets, secret_grants and secret_dependencies tables
+
+Revision ID: 20261016120000
+Revises: 20251121194643
+Create Date: 2026-10-16 12:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016120000"
+down_revision = "20251121194643"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Create tables for org-level secrets and cross-project references."""
+    op.create_table(
+        "org_secrets",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("key", sa.String(length=255), nullable=False),
+        sa.Column("value", sa.Text(), nullable=False),
+        sa.Column(
+            "environment",
+            sa.String(length=50),
+            nullable=False,
+            server_default="production",
+        ),
+        sa.Column("description", sa.String(length=500), nullable=True),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.Column("updated_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+        sa.UniqueConstraint("key", "environment", name="uix_org_secret"),
+    )
+    op.create_index(op.f("ix_org_secrets_id"), "org_secrets", ["id"], unique=False)
+    op.create_index(op.f("ix_org_secrets_key"), "org_secrets", ["key"], unique=False)
+
+    op.create_table(
+        "secret_grants",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("source_project_id", sa.Integer(), nullable=False),
+        sa.Column("consumer_project_id", sa.Integer(), nullable=False),
+        sa.Column("key_pattern", sa.String(length=255), nullable=False),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.ForeignKeyConstraint(
+            ["source_project_id"], ["projects.id"], ondelete="CASCADE"
+        ),
+        sa.ForeignKeyConstraint(
+            ["consumer_project_id"], ["projects.id"], ondelete="CASCADE"
+        ),
+        sa.PrimaryKeyConstraint("id"),
+        sa.UniqueConstraint(
+            "source_project_id",
+            "consumer_project_id",
+            "key_pattern",
+            name="uix_secret_grant",
+        ),
+    )
+    op.create_index(op.f("ix_secret_grants_id"), "secret_grants", ["id"], unique=False)
+    op.create_index(
+        op.f("ix_secret_grants_source_project_id"),
+        "secret_grants",
+        ["source_project_id"],
+        unique=False,
+    )
+    op.create_index(
+        op.f("ix_secret_grants_consumer_project_id"),
+        "secret_grants",
+        ["consumer_project_id"],
+        unique=False,
+    )
+
+    op.create_table(
+        "secret_dependencies",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("consumer_project_id", sa.Integer(), nullable=False),
+        sa.Column("consumer_app_id", sa.Integer(), nullable=True),
+        sa.Column("consumer_key", sa.String(length=255), nullable=False),
+        sa.Column("source_ref", sa.String(length=500), nullable=False),
+        sa.Column(
+            "environment",
+            sa.String(length=50),
+            nullable=False,
+            server_default="production",
+        ),
+        sa.Column("value_hash", sa.String(length=64), nullable=True),
+        sa.Column("resolved_at", sa.DateTime(), nullable=True),
+        sa.ForeignKeyConstraint(
+            ["consumer_project_id"], ["projects.id"], ondelete="CASCADE"
+        ),
+        sa.ForeignKeyConstraint(["consumer_app_id"], ["apps.id"], ondelete="CASCADE"),
+        sa.PrimaryKeyConstraint("id"),
+        sa.UniqueConstraint(
+            "consumer_project_id",
+            "consumer_app_id",
+            "consumer_key",
+            "environment",
+            name="uix_secret_dependency",
+        ),
+    )
+    op.create_index(
+        op.f("ix_secret_dependencies_id"), "secret_dependencies", ["id"], unique=False
+    )
+    op.create_index(
+        op.f("ix_secret_dependencies_consumer_project_id"),
+        "secret_dependencies",
+        ["consumer_project_id"],
+        unique=False,
+    )
+    op.create_index(
+        op.f("ix_secret_dependencies_consumer_app_id"),
+        "secret_dependencies",
+        ["consumer_app_id"],
+        unique=False,
+    )
+    op.create_index(
+        op.f("ix_secret_dependencies_source_ref"),
+        "secret_dependencies",
+        ["source_ref"],
+        unique=False,
+    )
+
+
+def downgrade():
+    """Drop secret sharing tables."""
+    op.drop_table("secret_dependencies")
+    op.drop_table("secret_grants")
+    op.drop_table("org_secrets")
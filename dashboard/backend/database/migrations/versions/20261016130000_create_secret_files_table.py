@@ -0,0 +1,55 @@
+"""Create secret_files table
+
+Revision ID: 20261016130000
+Revises: 20261016120000
+Create Date: 2026-10-16 13:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016130000"
+down_revision = "20261016120000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Create table for secrets mounted into containers as files."""
+    op.create_table(
+        "secret_files",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_id", sa.Integer(), nullable=False),
+        sa.Column("app_id", sa.Integer(), nullable=True),
+        sa.Column("name", sa.String(length=255), nullable=False),
+        sa.Column("mount_path", sa.String(length=500), nullable=False),
+        sa.Column("content", sa.Text(), nullable=False),
+        sa.Column(
+            "environment",
+            sa.String(length=50),
+            nullable=False,
+            server_default="production",
+        ),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.Column("updated_at", sa.DateTime(), nullable=True),
+        sa.ForeignKeyConstraint(["project_id"], ["projects.id"], ondelete="CASCADE"),
+        sa.ForeignKeyConstraint(["app_id"], ["apps.id"], ondelete="CASCADE"),
+        sa.PrimaryKeyConstraint("id"),
+        sa.UniqueConstraint(
+            "project_id", "app_id", "name", "environment", name="uix_secret_file"
+        ),
+    )
+    op.create_index(op.f("ix_secret_files_id"), "secret_files", ["id"], unique=False)
+    op.create_index(
+        op.f("ix_secret_files_project_id"), "secret_files", ["project_id"], unique=False
+    )
+    op.create_index(
+        op.f("ix_secret_files_app_id"), "secret_files", ["app_id"], unique=False
+    )
+
+
+def downgrade():
+    """Drop secret_files table."""
+    op.drop_table("secret_files")
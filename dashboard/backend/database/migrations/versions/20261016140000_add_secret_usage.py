@@ -0,0 +1,36 @@
+"""Add usage (runtime/build/both) to secrets
+
+Revision ID: 20261016140000
+Revises: 20261016130000
+Create Date: 2026-10-16 14:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016140000"
+down_revision = "20261016130000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Tag secrets as runtime, build-time or both."""
+    op.add_column(
+        "secrets",
+        sa.Column(
+            "usage",
+            sa.String(length=20),
+            nullable=False,
+            server_default="runtime",
+        ),
+    )
+    # Next.js public vars were already passed as build args by the workflows
+    op.execute("UPDATE secrets SET usage = 'both' WHERE key LIKE 'NEXT\\_PUBLIC\\_%'")
+
+
+def downgrade():
+    """Drop usage column."""
+    op.drop_column("secrets", "usage")
@@ -0,0 +1,26 @@
+"""Add edge policy to apps
+
+Revision ID: 20261016150000
+Revises: 20261016140000
+Create Date: 2026-10-16 15:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016150000"
+down_revision = "20261016140000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Store per-app edge policy (IP allow/deny, headers, body limit) for Caddy."""
+    op.add_column("apps", sa.Column("edge", sa.JSON(), nullable=True))
+
+
+def downgrade():
+    """Drop edge column."""
+    op.drop_column("apps", "edge")
@@ -0,0 +1,55 @@
+"""Create reconciler_status table
+
+Revision ID: 20261016160000
+Revises: 20261016150000
+Create Date: 2026-10-16 16:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016160000"
+down_revision = "20261016150000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Create table for GitOps reconciler sync state per project."""
+    op.create_table(
+        "reconciler_status",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column("manifest_path", sa.String(length=500), nullable=True),
+        sa.Column("revision", sa.String(length=64), nullable=True),
+        sa.Column("applied_revision", sa.String(length=64), nullable=True),
+        sa.Column(
+            "status", sa.String(length=20), nullable=False, server_default="pending"
+        ),
+        sa.Column(
+            "paused", sa.Boolean(), nullable=False, server_default=sa.text("false")
+        ),
+        sa.Column("drift", sa.JSON(), nullable=True),
+        sa.Column("pending_rollout", sa.JSON(), nullable=True),
+        sa.Column("last_error", sa.Text(), nullable=True),
+        sa.Column("last_checked_at", sa.DateTime(), nullable=True),
+        sa.Column("last_synced_at", sa.DateTime(), nullable=True),
+        sa.Column("updated_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+    )
+    op.create_index(
+        op.f("ix_reconciler_status_id"), "reconciler_status", ["id"], unique=False
+    )
+    op.create_index(
+        op.f("ix_reconciler_status_project_name"),
+        "reconciler_status",
+        ["project_name"],
+        unique=True,
+    )
+
+
+def downgrade():
+    """Drop reconciler_status table."""
+    op.drop_table("reconciler_status")
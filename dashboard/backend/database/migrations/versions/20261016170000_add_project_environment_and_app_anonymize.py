@@ -0,0 +1,36 @@
+"""Add project environment and app anonymization profile
+
+Revision ID: 20261016170000
+Revises: 20261016160000
+Create Date: 2026-10-16 17:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016170000"
+down_revision = "20261016160000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Label projects by environment and store per-app addons:copy profiles."""
+    op.add_column(
+        "projects",
+        sa.Column(
+            "environment",
+            sa.String(length=50),
+            nullable=False,
+            server_default="production",
+        ),
+    )
+    op.add_column("apps", sa.Column("anonymize", sa.JSON(), nullable=True))
+
+
+def downgrade():
+    """Drop environment and anonymize columns."""
+    op.drop_column("apps", "anonymize")
+    op.drop_column("projects", "environment")
@@ -0,0 +1,72 @@
+"""Add deletion protection flags and deleted_projects table
+
+Revision ID: 20261016180000
+Revises: 20261016170000
+Create Date: 2026-10-16 18:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016180000"
+down_revision = "20261016170000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Protect projects/addons from down and keep deleted projects restorable."""
+    for table in ("projects", "addons"):
+        op.add_column(
+            table,
+            sa.Column(
+                "deletion_protection",
+                sa.Boolean(),
+                nullable=False,
+                server_default=sa.false(),
+            ),
+        )
+
+    op.create_table(
+        "deleted_projects",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column("snapshot", sa.JSON(), nullable=False),
+        sa.Column("final_backup", sa.String(length=500), nullable=True),
+        sa.Column("deleted_by", sa.String(length=100), nullable=True),
+        sa.Column("deleted_at", sa.DateTime(), nullable=True),
+        sa.Column("purge_after", sa.DateTime(), nullable=False),
+        sa.Column("restored_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+    )
+    op.create_index(
+        op.f("ix_deleted_projects_id"), "deleted_projects", ["id"], unique=False
+    )
+    op.create_index(
+        op.f("ix_deleted_projects_project_name"),
+        "deleted_projects",
+        ["project_name"],
+        unique=False,
+    )
+    op.create_index(
+        op.f("ix_deleted_projects_purge_after"),
+        "deleted_projects",
+        ["purge_after"],
+        unique=False,
+    )
+
+
+def downgrade():
+    """Drop deleted_projects and the protection flags."""
+    op.drop_index(
+        op.f("ix_deleted_projects_purge_after"), table_name="deleted_projects"
+    )
+    op.drop_index(
+        op.f("ix_deleted_projects_project_name"), table_name="deleted_projects"
+    )
+    op.drop_index(op.f("ix_deleted_projects_id"), table_name="deleted_projects")
+    op.drop_table("deleted_projects")
+    op.drop_column("addons", "deletion_protection")
+    op.drop_column("projects", "deletion_protection")
@@ -0,0 +1,101 @@
+"""Add deploy policy, freeze window, approval and override tables
+
+Revision ID: 20261016190000
+Revises: 20261016180000
+Create Date: 2026-10-16 19:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016190000"
+down_revision = "20261016180000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Deploy approvals, change freezes and audited emergency overrides."""
+    op.create_table(
+        "deploy_policies",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column(
+            "approvals_required", sa.Integer(), nullable=False, server_default="0"
+        ),
+        sa.Column("deployers", sa.JSON(), nullable=False),
+        sa.Column("updated_by", sa.String(length=100), nullable=True),
+        sa.Column("updated_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+    )
+    op.create_index(
+        op.f("ix_deploy_policies_id"), "deploy_policies", ["id"], unique=False
+    )
+    op.create_index(
+        op.f("ix_deploy_policies_project_name"),
+        "deploy_policies",
+        ["project_name"],
+        unique=True,
+    )
+
+    op.create_table(
+        "deploy_freezes",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column("cron", sa.String(length=100), nullable=True),
+        sa.Column("duration_minutes", sa.Integer(), nullable=True),
+        sa.Column(
+            "timezone", sa.String(length=50), nullable=False, server_default="UTC"
+        ),
+        sa.Column("starts_at", sa.DateTime(), nullable=True),
+        sa.Column("ends_at", sa.DateTime(), nullable=True),
+        sa.Column("reason", sa.String(length=500), nullable=True),
+        sa.Column("created_by", sa.String(length=100), nullable=True),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+    )
+
+    op.create_table(
+        "deploy_approvals",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column("target", sa.String(length=200), nullable=False),
+        sa.Column("approver", sa.String(length=100), nullable=False),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.Column("expires_at", sa.DateTime(), nullable=False),
+        sa.PrimaryKeyConstraint("id"),
+    )
+
+    op.create_table(
+        "deploy_overrides",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column("reason", sa.String(length=500), nullable=False),
+        sa.Column("actor", sa.String(length=100), nullable=True),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.Column("expires_at", sa.DateTime(), nullable=False),
+        sa.Column("revoked_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+    )
+
+    for table in ("deploy_freezes", "deploy_approvals", "deploy_overrides"):
+        op.create_index(op.f(f"ix_{table}_id"), table, ["id"], unique=False)
+        op.create_index(
+            op.f(f"ix_{table}_project_name"), table, ["project_name"], unique=False
+        )
+
+
+def downgrade():
+    """Drop the deploy policy tables."""
+    for table in ("deploy_overrides", "deploy_approvals", "deploy_freezes"):
+        op.drop_index(op.f(f"ix_{table}_project_name"), table_name=table)
+        op.drop_index(op.f(f"ix_{table}_id"), table_name=table)
+        op.drop_table(table)
+
+    op.drop_index(
+        op.f("ix_deploy_policies_project_name"), table_name="deploy_policies"
+    )
+    op.drop_index(op.f("ix_deploy_policies_id"), table_name="deploy_policies")
+    op.drop_table("deploy_policies")
@@ -0,0 +1,56 @@
+"""Add lifecycle hooks table
+
+Revision ID: 20261016200000
+Revises: 20261016190000
+Create Date: 2026-10-16 20:00:00
+
+"""
+from alembic import op
+import sqlalchemy as sa
+
+
+# revision identifiers, used by Alembic.
+revision = "20261016200000"
+down_revision = "20261016190000"
+branch_labels = None
+depends_on = None
+
+
+def upgrade():
+    """Hooks set with <project>:hooks:add, next to the ones in app markers."""
+    op.create_table(
+        "lifecycle_hooks",
+        sa.Column("id", sa.Integer(), nullable=False),
+        sa.Column("project_name", sa.String(length=100), nullable=False),
+        sa.Column("app_name", sa.String(length=100), nullable=True),
+        sa.Column("event", sa.String(length=50), nullable=False),
+        sa.Column("type", sa.String(length=20), nullable=False),
+        sa.Column("target", sa.Text(), nullable=False),
+        sa.Column("timeout", sa.Integer(), nullable=True),
+        sa.Column(
+            "on_failure", sa.String(length=10), nullable=False, server_default="abort"
+        ),
+        sa.Column("method", sa.String(length=10), nullable=True),
+        sa.Column("headers", sa.JSON(), nullable=True),
+        sa.Column("created_by", sa.String(length=100), nullable=True),
+        sa.Column("created_at", sa.DateTime(), nullable=True),
+        sa.PrimaryKeyConstraint("id"),
+    )
+    op.create_index(
+        op.f("ix_lifecycle_hooks_id"), "lifecycle_hooks", ["id"], unique=False
+    )
+    op.create_index(
+        op.f("ix_lifecycle_hooks_project_name"),
+        "lifecycle_hooks",
+        ["project_name"],
+        unique=False,
+    )
+
+
+def downgrade():
+    """Drop the lifecycle hooks table."""
+    op.drop_index(
+        op.f("ix_lifecycle_hooks_project_name"), table_name="lifecycle_hooks"
+    )
+    op.drop_index(op.f("ix_lifecycle_hooks_id"), table_name="lifecycle_hooks")
+    op.drop_table("lifecycle_hooks")
@@ -33,6 +33,8 @@ from routes import (
     metrics,
     resources,
     config,
+    reconciler,
+    blueprints,
 )
 
 app.include_router(projects.router, prefix="/api/projects", tags=["projects"])
@@ -49,6 +51,8 @@ app.include_router(logs.router, prefix="/api/logs", tags=["logs"])
 app.include_router(metrics.router, prefix="/api/metrics", tags=["metrics"])
 app.include_router(resources.router, prefix="/api/resources", tags=["resources"])
 app.include_router(config.router, prefix="/api/config", tags=["config"])
+app.include_router(reconciler.router, prefix="/api/reconciler", tags=["reconciler"])
+app.include_router(blueprints.router, prefix="/api/blueprints", tags=["blueprints"])
 
 
 @app.get("/")
@@ -25,6 +25,11 @@ class Project(Base):
     id = Column(Integer, primary_key=True, index=True)
     name = Column(String(100), unique=True, nullable=False, index=True)
     description = Column(String(500), nullable=True)
+    # What the project is for (production, staging, ...); addons:copy never
+    # overwrites data of a production project
+    environment = Column(String(50), nullable=False, default="production")
+    # down/delete refuse while set; lifted with <project>:unprotect --reason
+    deletion_protection = Column(Boolean, nullable=False, default=False)
     # project_type: 'application' (default) for app projects, 'orchestrator' for global infra
     project_type = Column(String(50), nullable=False, default="application", index=True)
     domain = Column(String(200), nullable=True)
@@ -113,6 +118,8 @@ class App(Base):
     replicas = Column(Integer, default=1)
     type = Column(String(50), nullable=True)
     services = Column(JSON, nullable=True)  # ["web", "worker", "scheduler", "beat"]
+    edge = Column(JSON, nullable=True)  # Caddy edge policy (allow/deny/headers/max_body)
+    anonymize = Column(JSON, nullable=True)  # addons:copy profile {table: {column: rule}}
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
 
@@ -200,6 +207,9 @@ class Secret(Base):
     environment = Column(String(50), default="production", nullable=False)
     source = Column(String(50), default="app", nullable=False)  # app/shared/addon
     editable = Column(Boolean, default=True, nullable=False)
+    usage = Column(
+        String(20), default="runtime", nullable=False
+    )  # runtime/build/both (build: image build only, never in the runtime .env)
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
     last_synced_at = Column(DateTime, nullable=True)
@@ -238,6 +248,122 @@ class SecretAlias(Base):
     app = relationship("App", back_populates="secret_aliases")
 
 
+class OrgSecret(Base):
+    """Organization-level secrets shared by many projects (e.g. DOCKER_TOKEN)."""
+
+    __tablename__ = "org_secrets"
+
+    id = Column(Integer, primary_key=True, index=True)
+    key = Column(String(255), nullable=False, index=True)
+    value = Column(Text, nullable=False)
+    environment = Column(String(50), default="production", nullable=False)
+    description = Column(String(500), nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    __table_args__ = (
+        UniqueConstraint("key", "environment", name="uix_org_secret"),
+        {"extend_existing": True},
+    )
+
+
+class SecretGrant(Base):
+    """Permission for another project to reference this project's secrets."""
+
+    __tablename__ = "secret_grants"
+
+    id = Column(Integer, primary_key=True, index=True)
+    source_project_id = Column(
+        Integer,
+        ForeignKey("projects.id", ondelete="CASCADE"),
+        nullable=False,
+        index=True,
+    )
+    consumer_project_id = Column(
+        Integer,
+        ForeignKey("projects.id", ondelete="CASCADE"),
+        nullable=False,
+        index=True,
+    )
+    key_pattern = Column(String(255), nullable=False)  # e.g. app.api.*, DOCKER_TOKEN
+    created_at = Column(DateTime, default=datetime.utcnow)
+
+    __table_args__ = (
+        UniqueConstraint(
+            "source_project_id",
+            "consumer_project_id",
+            "key_pattern",
+            name="uix_secret_grant",
+        ),
+        {"extend_existing": True},
+    )
+
+
+class SecretDependency(Base):
+    """Which source a consumer's secret was resolved from (for propagation)."""
+
+    __tablename__ = "secret_dependencies"
+
+    id = Column(Integer, primary_key=True, index=True)
+    consumer_project_id = Column(
+        Integer,
+        ForeignKey("projects.id", ondelete="CASCADE"),
+        nullable=False,
+        index=True,
+    )
+    consumer_app_id = Column(
+        Integer, ForeignKey("apps.id", ondelete="CASCADE"), nullable=True, index=True
+    )
+    consumer_key = Column(String(255), nullable=False)  # e.g. BILLING_TOKEN
+    source_ref = Column(
+        String(500), nullable=False, index=True
+    )  # e.g. project.shop.app.billing.INTERNAL_TOKEN, org.DOCKER_TOKEN
+    environment = Column(String(50), default="production", nullable=False)
+    value_hash = Column(String(64), nullable=True)  # sha256 at last sync
+    resolved_at = Column(DateTime, nullable=True)
+
+    __table_args__ = (
+        UniqueConstraint(
+            "consumer_project_id",
+            "consumer_app_id",
+            "consumer_key",
+            "environment",
+            name="uix_secret_dependency",
+        ),
+        {"extend_existing": True},
+    )
+
+
+class SecretFile(Base):
+    """Secret stored as a file and mounted read-only into app containers."""
+
+    __tablename__ = "secret_files"
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_id = Column(
+        Integer,
+        ForeignKey("projects.id", ondelete="CASCADE"),
+        nullable=False,
+        index=True,
+    )
+    app_id = Column(
+        Integer, ForeignKey("apps.id", ondelete="CASCADE"), nullable=True, index=True
+    )  # NULL = shared/project-level file (mounted into every app)
+    name = Column(String(255), nullable=False)  # e.g. gcp-service-account.json
+    mount_path = Column(String(500), nullable=False)  # e.g. /app/secrets/gcp.json
+    content = Column(Text, nullable=False)  # File content or vault:// reference
+    environment = Column(String(50), default="production", nullable=False)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    __table_args__ = (
+        UniqueConstraint(
+            "project_id", "app_id", "name", "environment", name="uix_secret_file"
+        ),
+        {"extend_existing": True},
+    )
+
+
 class Addon(Base):
     """Addon model - databases, queues, caches, proxy."""
 
@@ -266,6 +392,7 @@ class Addon(Base):
     version = Column(String(50), nullable=False)
     vm = Column(String(50), nullable=False, default="core")
     plan = Column(String(50), nullable=True)  # "small", "standard", "large"
+    deletion_protection = Column(Boolean, nullable=False, default=False)
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
 
@@ -312,3 +439,123 @@ class Setting(Base):
     value = Column(Text, nullable=False)
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+
+class ReconcilerStatus(Base):
+    """GitOps reconciler state per project (written by the orchestrator)."""
+
+    __tablename__ = "reconciler_status"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, unique=True, index=True)
+    manifest_path = Column(String(500), nullable=True)  # Relative to the repo root
+    revision = Column(String(64), nullable=True)  # Last commit seen
+    applied_revision = Column(String(64), nullable=True)  # Last commit synced
+    status = Column(
+        String(20), nullable=False, default="pending"
+    )  # pending, synced, drifted, applying, failed
+    paused = Column(Boolean, default=False, nullable=False)
+    drift = Column(JSON, nullable=True)  # Pending changes (Change.to_dict list)
+    pending_rollout = Column(JSON, nullable=True)  # Rollout commands not yet run
+    last_error = Column(Text, nullable=True)
+    last_checked_at = Column(DateTime, nullable=True)
+    last_synced_at = Column(DateTime, nullable=True)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+
+class DeletedProject(Base):
+    """Soft-deleted project (restorable with projects:undelete until purge)."""
+
+    __tablename__ = "deleted_projects"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, index=True)
+    snapshot = Column(JSON, nullable=False)  # {table: [row, ...]} as deleted
+    final_backup = Column(String(500), nullable=True)  # Dumps taken by down
+    deleted_by = Column(String(100), nullable=True)
+    deleted_at = Column(DateTime, default=datetime.utcnow)
+    purge_after = Column(DateTime, nullable=False, index=True)
+    restored_at = Column(DateTime, nullable=True)
+
+
+class DeployPolicy(Base):
+    """Deploy policy - approvals production deploys need, and who gives them."""
+
+    __tablename__ = "deploy_policies"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, unique=True, index=True)
+    approvals_required = Column(Integer, nullable=False, default=0)
+    deployers = Column(JSON, nullable=False, default=list)  # Actors who may approve
+    updated_by = Column(String(100), nullable=True)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+
+class DeployFreeze(Base):
+    """Change freeze - recurring (cron + duration) or one-off window."""
+
+    __tablename__ = "deploy_freezes"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, index=True)
+    cron = Column(String(100), nullable=True)  # Window starts, e.g. "0 18 * * 5"
+    duration_minutes = Column(Integer, nullable=True)
+    timezone = Column(String(50), nullable=False, default="UTC")
+    starts_at = Column(DateTime, nullable=True)  # One-off window (UTC)
+    ends_at = Column(DateTime, nullable=True)
+    reason = Column(String(500), nullable=True)
+    created_by = Column(String(100), nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
+
+
+class DeployApproval(Base):
+    """Approval of one deploy target (api@<sha>, up) by a deployer."""
+
+    __tablename__ = "deploy_approvals"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, index=True)
+    target = Column(String(200), nullable=False)
+    approver = Column(String(100), nullable=False)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    expires_at = Column(DateTime, nullable=False)
+
+
+class DeployOverride(Base):
+    """Emergency override - deploys skip freezes and approvals until expiry."""
+
+    __tablename__ = "deploy_overrides"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, index=True)
+    reason = Column(String(500), nullable=False)
+    actor = Column(String(100), nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    expires_at = Column(DateTime, nullable=False)
+    revoked_at = Column(DateTime, nullable=True)
+
+
+class LifecycleHook(Base):
+    """Lifecycle hook set with <project>:hooks:add (marker hooks live in apps)."""
+
+    __tablename__ = "lifecycle_hooks"
+    __table_args__ = {"extend_existing": True}
+
+    id = Column(Integer, primary_key=True, index=True)
+    project_name = Column(String(100), nullable=False, index=True)
+    app_name = Column(String(100), nullable=True)  # None: every app of the project
+    event = Column(String(50), nullable=False)  # before_deploy, after_deploy, ...
+    type = Column(String(20), nullable=False)  # exec, http, script
+    target = Column(Text, nullable=False)  # Command, URL or script
+    timeout = Column(Integer, nullable=True)
+    on_failure = Column(String(10), nullable=False, default="abort")
+    method = Column(String(10), nullable=True)  # http hooks
+    headers = Column(JSON, nullable=True)  # http hooks
+    created_by = Column(String(100), nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
@@ -254,6 +254,11 @@ async def switch_version(
         if not app:
             raise HTTPException(status_code=404, detail="App not found")
 
+        # Freeze windows and production approvals (423 if blocked)
+        from routes.projects import ensure_deployable
+
+        ensure_deployable(project_name, f"{app_name}@{request.git_sha}")
+
         # Use centralized CLI executor
         cli = get_cli()
 
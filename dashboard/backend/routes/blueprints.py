@@ -0,0 +1,23 @@
+from fastapi import APIRouter, HTTPException
+
+router = APIRouter(tags=["blueprints"])
+
+
+@router.get("/")
+async def list_blueprints():
+    """
+    Blueprints the setup wizard can create a project from.
+
+    Same list as `superdeploy blueprints` (bundled + ~/.superdeploy/blueprints),
+    with each blueprint's parameters, VMs, apps and addons.
+    """
+    try:
+        from utils.cli import get_cli
+
+        cli = get_cli()
+        data = await cli.execute_json("blueprints")
+
+        return data["blueprints"]
+
+    except RuntimeError as e:
+        raise HTTPException(status_code=500, detail=str(e))
@@ -71,6 +71,9 @@ async def generate_deployment_files(request: CommandRequest):
 async def deploy_project(request: CommandRequest):
     """Deploy project infrastructure (superdeploy project:up)."""
     from services.cli_executor import CLIExecutor
+    from routes.projects import ensure_deployable
+
+    ensure_deployable(request.project_name, "up")
 
     executor = CLIExecutor(PROJECT_ROOT)
 
@@ -99,6 +102,9 @@ async def sync_secrets(request: CommandRequest):
 async def deploy_project_legacy(request: CommandRequest):
     """Deploy a project (legacy endpoint, use /up instead)."""
     from services.cli_executor import CLIExecutor
+    from routes.projects import ensure_deployable
+
+    ensure_deployable(request.project_name, "up")
 
     executor = CLIExecutor(PROJECT_ROOT)
 
@@ -1,7 +1,7 @@
 from fastapi import APIRouter, HTTPException, Depends
 from pydantic import BaseModel
 from sqlalchemy.orm import Session
-from typing import List, Optional
+from typing import Dict, List, Optional
 from pathlib import Path
 from database import get_db
 from models import Project, App, VM, Addon, Secret
@@ -51,6 +51,13 @@ class WizardProjectCreate(BaseModel):
     secrets: SecretsCreate
 
 
+class BlueprintProjectCreate(BaseModel):
+    project_name: str
+    blueprint: str
+    params: Dict[str, str] = {}
+    secrets: SecretsCreate
+
+
 class AppResponse(BaseModel):
     id: int
     name: str
@@ -76,6 +83,7 @@ class AddonResponse(BaseModel):
     version: Optional[str] = None
     vm: Optional[str] = None
     plan: Optional[str] = None
+    deletion_protection: bool = False
 
     class Config:
         from_attributes = True
@@ -110,6 +118,7 @@ class ProjectResponse(BaseModel):
     docker_organization: Optional[str] = None
     vpc_subnet: Optional[str] = None
     docker_subnet: Optional[str] = None
+    deletion_protection: bool = False
     apps: List[AppResponse] = []
     addons: List[AddonResponse] = []
     vms: List[VMResponse] = []
@@ -392,17 +401,54 @@ def get_project_apps(project_name: str, db: Session = Depends(get_db)):
     return apps
 
 
+def ensure_deletable(project_name: str) -> None:
+    """409 while the project or one of its addons has deletion protection."""
+    from cli.services.protection_service import ProtectionService, ProtectedError
+
+    try:
+        ProtectionService().check(project_name)
+    except ProtectedError as e:
+        raise HTTPException(status_code=409, detail=str(e))
+
+
+def ensure_deployable(project_name: str, target: str) -> None:
+    """423 during a freeze window or without the approvals production needs."""
+    from cli.services.deploy_policy_service import (
+        DeployBlockedError,
+        DeployPolicyService,
+    )
+
+    try:
+        DeployPolicyService().check(
+            project_name, target, actor="dashboard", source="dashboard"
+        )
+    except DeployBlockedError as e:
+        raise HTTPException(status_code=423, detail=str(e))
+
+
+@router.get("/{project_name}/deploy-policy")
+async def get_deploy_policy(project_name: str, db: Session = Depends(get_db)):
+    """Freeze windows, required approvals and the active emergency override."""
+    from cli.services.deploy_policy_service import DeployPolicyService
+
+    project = db.query(Project).filter(Project.name == project_name).first()
+    if not project:
+        raise HTTPException(status_code=404, detail="Project not found")
+    return DeployPolicyService().policy(project_name)
+
+
 @router.post("/{project_name}/down")
 async def teardown_project(project_name: str, db: Session = Depends(get_db)):
-    """Teardown project infrastructure and delete from database."""
+    """Teardown project infrastructure and move it to the trash."""
     from fastapi.responses import StreamingResponse
     import asyncio
+    import os
     import re
-    from models import Secret, App
 
     project = db.query(Project).filter(Project.name == project_name).first()
     if not project:
         raise HTTPException(status_code=404, detail="Project not found")
+    ensure_deletable(project_name)
 
     def strip_ansi_codes(text: str) -> str:
         """Remove ANSI color codes from text."""
@@ -413,14 +459,16 @@ async def teardown_project(project_name: str, db: Session = Depends(get_db)):
         current_line_buffer = ""
 
         try:
-            # Run CLI down command with --yes flag
+            # Run CLI down command: final backup, teardown, soft delete
             process = await asyncio.create_subprocess_exec(
                 "superdeploy",
                 f"{project_name}:down",
                 "--yes",
+                "--destroy",
                 stdout=asyncio.subprocess.PIPE,
                 stderr=asyncio.subprocess.STDOUT,
                 cwd=str(SUPERDEPLOY_ROOT),
+                env={**os.environ, "SUPERDEPLOY_ACTOR": "dashboard"},
             )
 
             if process.stdout:
@@ -443,13 +491,7 @@ async def teardown_project(project_name: str, db: Session = Depends(get_db)):
             await process.wait()
 
             if process.returncode == 0:
-                # Delete from database after successful teardown
-                db.query(Secret).filter(Secret.project_id == project.id).delete()
-                db.query(App).filter(App.project_id == project.id).delete()
-                db.delete(project)
-                db.commit()
-
-                yield f"data: ✓ Project '{project_name}' deleted successfully from database\n\n"
+                yield f"data: ✓ Project '{project_name}' torn down (restore with projects:undelete)\n\n"
             else:
                 yield f"data: ✗ Failed to teardown project (exit code: {process.returncode})\n\n"
 
@@ -461,24 +503,21 @@ async def teardown_project(project_name: str, db: Session = Depends(get_db)):
 
 @router.delete("/{project_name}")
 def delete_project(project_name: str, db: Session = Depends(get_db)):
-    """Delete a project and all its associated data (without infrastructure teardown)."""
-    from models import Secret, App
+    """Soft-delete a project and its data (without infrastructure teardown)."""
+    from cli.services.trash_service import TrashService
 
     project = db.query(Project).filter(Project.name == project_name).first()
     if not project:
         raise HTTPException(status_code=404, detail="Project not found")
+    ensure_deletable(project_name)
 
-    # Delete associated secrets
-    db.query(Secret).filter(Secret.project_id == project.id).delete()
-
-    # Delete associated apps (cascade will handle this, but explicit is better)
-    db.query(App).filter(App.project_id == project.id).delete()
-
-    # Delete project
-    db.delete(project)
-    db.commit()
+    # Records stay restorable with projects:undelete until purge_after
+    entry = TrashService().delete(project_name, actor="dashboard")
 
-    return {"message": f"Project '{project_name}' deleted successfully"}
+    return {
+        "message": f"Project '{project_name}' deleted successfully",
+        "restorable_until": entry["purge_after"],
+    }
 
 
 # Addon version mapping
@@ -720,6 +759,78 @@ def create_project_from_wizard(
     return db_project
 
 
+@router.post("/wizard/blueprint", response_model=ProjectResponse)
+async def create_project_from_blueprint(
+    payload: BlueprintProjectCreate, db: Session = Depends(get_db)
+):
+    """
+    Create a new project from a blueprint (init --blueprint), then store the
+    credentials entered in the wizard.
+    """
+    from utils.cli import get_cli
+
+    args = ["--blueprint", payload.blueprint]
+    for name, value in payload.params.items():
+        args += ["--set", f"{name}={value}"]
+
+    try:
+        await get_cli().execute_json(
+            f"{payload.project_name}:init", args=args, timeout=180
+        )
+    except RuntimeError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+
+    db.expire_all()
+    project = db.query(Project).filter(Project.name == payload.project_name).first()
+    if not project:
+        raise HTTPException(status_code=500, detail="Project was not created")
+    project.docker_organization = payload.secrets.docker_org
+
+    # init stores CHANGE_ME placeholders for these
+    shared_secrets = {
+        "DOCKER_ORG": payload.secrets.docker_org,
+        "DOCKER_USERNAME": payload.secrets.docker_username,
+        "DOCKER_TOKEN": payload.secrets.docker_token,
+        "REPOSITORY_TOKEN": payload.secrets.github_token,
+    }
+    if payload.secrets.smtp_host:
+        shared_secrets["SMTP_HOST"] = payload.secrets.smtp_host
+        shared_secrets["SMTP_PORT"] = payload.secrets.smtp_port
+        shared_secrets["SMTP_USER"] = payload.secrets.smtp_user
+        shared_secrets["SMTP_PASSWORD"] = payload.secrets.smtp_password
+
+    for key, value in shared_secrets.items():
+        secret = (
+            db.query(Secret)
+            .filter(
+                Secret.project_id == project.id,
+                Secret.app_id.is_(None),
+                Secret.key == key,
+                Secret.environment == "production",
+            )
+            .first()
+        )
+        if secret:
+            secret.value = value
+        else:
+            db.add(
+                Secret(
+                    project_id=project.id,
+                    app_id=None,
+                    key=key,
+                    value=value,
+                    environment="production",
+                    source="shared",
+                    editable=True,
+                )
+            )
+
+    db.commit()
+    db.refresh(project)
+
+    return project
+
+
 @router.post("/{project_name}/deploy")
 async def deploy_project_wizard(project_name: str, db: Session = Depends(get_db)):
     """Deploy a project from wizard."""
@@ -730,6 +841,7 @@ async def deploy_project_wizard(project_name: str, db: Session = Depends(get_db)
     project = db.query(Project).filter(Project.name == project_name).first()
     if not project:
         raise HTTPException(status_code=404, detail="Project not found")
+    ensure_deployable(project_name, "up")
 
     def strip_ansi_codes(text: str) -> str:
         """Remove ANSI color codes from text."""
@@ -0,0 +1,68 @@
+from fastapi import APIRouter, HTTPException, Depends
+from pydantic import BaseModel
+from sqlalchemy.orm import Session
+from typing import List, Optional
+from datetime import datetime
+from database import get_db
+from models import ReconcilerStatus
+
+router = APIRouter(tags=["reconciler"])
+
+
+class ReconcilerStatusResponse(BaseModel):
+    project_name: str
+    manifest_path: Optional[str] = None
+    revision: Optional[str] = None
+    applied_revision: Optional[str] = None
+    status: str
+    paused: bool
+    drift: Optional[list] = None
+    pending_rollout: Optional[List[str]] = None
+    last_error: Optional[str] = None
+    last_checked_at: Optional[datetime] = None
+    last_synced_at: Optional[datetime] = None
+
+    class Config:
+        from_attributes = True
+
+
+@router.get("/", response_model=List[ReconcilerStatusResponse])
+def list_status(db: Session = Depends(get_db)):
+    """Sync status and drift per project, as last reported by the reconciler."""
+    return db.query(ReconcilerStatus).order_by(ReconcilerStatus.project_name).all()
+
+
+@router.post("/{project_name}/pause", response_model=ReconcilerStatusResponse)
+def pause_project(project_name: str, db: Session = Depends(get_db)):
+    """Stop applying this project's manifest (drift is still reported)."""
+    row = _get_status(db, project_name)
+    row.paused = True
+    db.commit()
+    db.refresh(row)
+    return row
+
+
+@router.post("/{project_name}/resume", response_model=ReconcilerStatusResponse)
+def resume_project(project_name: str, db: Session = Depends(get_db)):
+    """Resume a paused project; the next cycle retries a failed sync."""
+    row = _get_status(db, project_name)
+    row.paused = False
+    row.last_error = None
+    if row.status == "failed":
+        row.status = "pending"
+    db.commit()
+    db.refresh(row)
+    return row
+
+
+def _get_status(db: Session, project_name: str) -> ReconcilerStatus:
+    row = (
+        db.query(ReconcilerStatus)
+        .filter(ReconcilerStatus.project_name == project_name)
+        .first()
+    )
+    if not row:
+        raise HTTPException(
+            status_code=404, detail="Project not tracked by the reconciler"
+        )
+    return row
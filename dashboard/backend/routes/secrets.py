@@ -4,6 +4,7 @@ from fastapi import APIRouter, HTTPException, Depends
 from pydantic import BaseModel
 from sqlalchemy.orm import Session
 from pathlib import Path
+from typing import Optional
 from database import get_db
 from models import Secret, SecretAlias, Project, App
 
@@ -17,6 +18,7 @@ router = APIRouter(tags=["secrets"])
 class SecretUpdateRequest(BaseModel):
     key: str
     value: str
+    usage: Optional[str] = None  # runtime/build/both, None keeps the current one
 
 
 class AliasUpdateRequest(BaseModel):
@@ -80,6 +82,7 @@ async def get_app_secrets(
                     "value": secret.value,
                     "source": secret.source,
                     "editable": secret.editable,
+                    "usage": secret.usage,
                 }
             )
 
@@ -101,6 +104,7 @@ async def get_app_secrets(
                     "value": secret.value,
                     "source": secret.source,
                     "editable": secret.editable,
+                    "usage": secret.usage,
                 }
             )
 
@@ -145,6 +149,77 @@ async def get_app_secrets(
         raise HTTPException(status_code=500, detail=str(e))
 
 
+@router.get("/secrets/{project_name}/{app_name}/contract")
+async def get_app_config_contract(
+    project_name: str,
+    app_name: str,
+    environment: str = "production",
+    db: Session = Depends(get_db),
+):
+    """
+    Check an app's secrets against the config contract in its marker.
+
+    Returns one entry per declared key with status ok, missing (required
+    and not set), unset (optional and not set) or invalid.
+    """
+    from cli.marker_manager import MarkerManager
+    from cli.secret_manager import SecretManager
+
+    project_id = get_project_id(db, project_name)
+    app = (
+        db.query(App).filter(App.project_id == project_id, App.name == app_name).first()
+    )
+    if not app:
+        raise HTTPException(status_code=404, detail=f"App '{app_name}' not found")
+
+    try:
+        marker = (
+            MarkerManager.load_marker(Path(app.path).expanduser()) if app.path else None
+        )
+        if not marker or not marker.has_config_contract():
+            return {"app_name": app_name, "declared": False, "keys": [], "valid": True}
+
+        secret_mgr = SecretManager(SUPERDEPLOY_ROOT, project_name, environment)
+        if marker.has_env_templates():
+            env = secret_mgr.get_app_secrets_with_templates(
+                app_name, marker.env_templates
+            )
+        else:
+            env = secret_mgr.get_app_secrets(app_name)
+
+        keys = []
+        for name, spec in marker.config.keys.items():
+            value = env.get(name)
+            message = None
+            if value is None or str(value) == "":
+                status = "missing" if spec.required else "unset"
+            else:
+                message = spec.check(value)
+                status = "invalid" if message else "ok"
+            keys.append(
+                {
+                    "key": name,
+                    "required": spec.required,
+                    "type": spec.type,
+                    "description": spec.description,
+                    "status": status,
+                    "message": message,
+                }
+            )
+
+        return {
+            "app_name": app_name,
+            "declared": True,
+            "keys": keys,
+            "valid": all(k["status"] in ("ok", "unset") for k in keys),
+        }
+
+    except HTTPException:
+        raise
+    except Exception as e:
+        raise HTTPException(status_code=500, detail=str(e))
+
+
 @router.post("/secrets/{project_name}/{app_name}")
 async def set_app_secret(
     project_name: str,
@@ -156,6 +231,11 @@ async def set_app_secret(
     """
     Set or update a secret for an app in database.
     """
+    if secret_data.usage and secret_data.usage not in ("runtime", "build", "both"):
+        raise HTTPException(
+            status_code=400, detail="usage must be runtime, build or both"
+        )
+
     try:
         project_id = get_project_id(db, project_name)
         app_id = get_app_id(db, project_id, app_name)
@@ -174,6 +254,8 @@ async def set_app_secret(
 
         if secret:
             secret.value = secret_data.value
+            if secret_data.usage:
+                secret.usage = secret_data.usage
         else:
             secret = Secret(
                 project_id=project_id,
@@ -183,6 +265,7 @@ async def set_app_secret(
                 environment=environment,
                 source="app",
                 editable=True,
+                usage=secret_data.usage or "runtime",
             )
             db.add(secret)
 
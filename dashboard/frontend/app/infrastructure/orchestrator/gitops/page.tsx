@@ -0,0 +1,261 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { PageHeader } from "@/components";
+import OrchestratorHeader from "@/components/OrchestratorHeader";
+import { GitBranch, Pause, Play } from "lucide-react";
+
+interface DriftChange {
+  action: "create" | "update" | "delete";
+  resource: string;
+  fields: Record<string, { from: unknown; to: unknown }>;
+}
+
+interface ReconcilerStatus {
+  project_name: string;
+  manifest_path?: string;
+  revision?: string;
+  applied_revision?: string;
+  status: "pending" | "synced" | "drifted" | "applying" | "failed";
+  paused: boolean;
+  drift?: DriftChange[];
+  pending_rollout?: string[];
+  last_error?: string;
+  last_checked_at?: string;
+  last_synced_at?: string;
+}
+
+const STATUS_STYLES: Record<ReconcilerStatus["status"], string> = {
+  synced: "bg-green-50 text-green-700",
+  drifted: "bg-yellow-50 text-yellow-700",
+  applying: "bg-blue-50 text-blue-700",
+  pending: "bg-gray-100 text-gray-600",
+  failed: "bg-red-50 text-red-700",
+};
+
+const ACTION_MARKERS: Record<DriftChange["action"], string> = {
+  create: "+",
+  update: "~",
+  delete: "-",
+};
+
+// The reconciler polls git on the orchestrator; refresh at a similar pace
+const REFRESH_MS = 15000;
+
+const formatTime = (value?: string) =>
+  value ? new Date(value + "Z").toLocaleString() : "-";
+
+export default function OrchestratorGitOpsPage() {
+  const [projects, setProjects] = useState<ReconcilerStatus[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [busy, setBusy] = useState<string | null>(null);
+
+  const fetchStatus = async () => {
+    try {
+      const response = await fetch("http://localhost:8401/api/reconciler/");
+      if (!response.ok) {
+        throw new Error("Failed to fetch reconciler status");
+      }
+      setProjects(await response.json());
+      setError(null);
+    } catch (err) {
+      console.error("Failed to fetch reconciler status:", err);
+      setError(err instanceof Error ? err.message : "Unknown error");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    fetchStatus();
+    const timer = setInterval(fetchStatus, REFRESH_MS);
+    return () => clearInterval(timer);
+  }, []);
+
+  const togglePause = async (project: ReconcilerStatus) => {
+    const action = project.paused ? "resume" : "pause";
+    setBusy(project.project_name);
+    try {
+      const response = await fetch(
+        `http://localhost:8401/api/reconciler/${project.project_name}/${action}`,
+        { method: "POST" }
+      );
+      if (!response.ok) {
+        throw new Error(`Failed to ${action} ${project.project_name}`);
+      }
+      const updated: ReconcilerStatus = await response.json();
+      setProjects((prev) =>
+        prev.map((p) => (p.project_name === updated.project_name ? updated : p))
+      );
+    } catch (err) {
+      alert(err instanceof Error ? err.message : "Unknown error");
+    } finally {
+      setBusy(null);
+    }
+  };
+
+  return (
+    <div>
+      <OrchestratorHeader />
+
+      <div className="bg-white rounded-[16px] p-[32px] shadow-[0px_0px_2px_0px_rgba(41,41,51,.04),0px_8px_24px_0px_rgba(41,41,51,.12)]">
+        <PageHeader
+          breadcrumbs={[
+            { label: "Infrastructure", href: "/" },
+            { label: "Orchestrator", href: "/infrastructure/orchestrator" },
+          ]}
+          menuLabel="GitOps"
+          title="Manifest Sync"
+        />
+
+        {loading ? (
+          <div className="text-center py-12 text-[#8b8b8b]">
+            <p className="text-[11px] tracking-[0.03em] font-light">Loading...</p>
+          </div>
+        ) : error ? (
+          <div className="text-center py-12 text-[#8b8b8b]">
+            <p className="text-[11px] tracking-[0.03em] font-light">{error}</p>
+          </div>
+        ) : projects.length === 0 ? (
+          <div className="text-center py-12 text-[#8b8b8b]">
+            <p className="text-[11px] tracking-[0.03em] font-light">
+              The reconciler hasn&apos;t reported any project. Enable it with{" "}
+              <code className="bg-[#f6f8fa] px-2 py-0.5 rounded">
+                superdeploy orchestrator:reconciler:enable --repo &lt;url&gt;
+              </code>
+            </p>
+          </div>
+        ) : (
+          <div className="space-y-4">
+            {projects.map((project) => (
+              <div
+                key={project.project_name}
+                className="p-5 border border-[#e3e8ee] hover:border-[#b9c1c6] rounded-lg"
+              >
+                {/* Header */}
+                <div className="flex items-start justify-between mb-3">
+                  <div className="flex items-baseline gap-2">
+                    <span className="text-[21px] text-[#0a0a0a]">
+                      {project.project_name}
+                    </span>
+                    <span
+                      className={`px-2 py-0.5 text-[10px] font-medium rounded-full ${STATUS_STYLES[project.status]}`}
+                    >
+                      ● {project.status}
+                    </span>
+                    {project.paused && (
+                      <span className="px-2 py-0.5 bg-yellow-50 text-yellow-700 text-[10px] font-medium rounded-full">
+                        Paused
+                      </span>
+                    )}
+                  </div>
+                  <button
+                    onClick={() => togglePause(project)}
+                    disabled={busy === project.project_name}
+                    className="flex items-center gap-2 px-3 py-1.5 border border-[#e3e8ee] hover:border-[#b9c1c6] text-[12px] text-[#0a0a0a] rounded-lg transition-colors disabled:opacity-50"
+                  >
+                    {project.paused ? (
+                      <>
+                        <Play className="w-3.5 h-3.5" />
+                        Resume
+                      </>
+                    ) : (
+                      <>
+                        <Pause className="w-3.5 h-3.5" />
+                        Pause
+                      </>
+                    )}
+                  </button>
+                </div>
+
+                {/* Details */}
+                <div className="grid grid-cols-2 md:grid-cols-4 gap-4 pt-3 border-t border-[#e3e8ee]">
+                  <div>
+                    <p className="text-[11px] text-[#8b8b8b] tracking-[0.03em] font-light mb-1">
+                      Manifest
+                    </p>
+                    <p className="flex items-center gap-1 text-[11px] text-[#0a0a0a] tracking-[0.03em] font-light">
+                      <GitBranch className="w-3 h-3" />
+                      {project.manifest_path || "-"}
+                    </p>
+                  </div>
+                  <div>
+                    <p className="text-[11px] text-[#8b8b8b] tracking-[0.03em] font-light mb-1">
+                      Revision
+                    </p>
+                    <p className="text-[11px] text-[#0a0a0a] tracking-[0.03em] font-mono">
+                      {project.revision?.slice(0, 8) || "-"}
+                      {project.applied_revision &&
+                        project.applied_revision !== project.revision && (
+                          <span className="text-[#8b8b8b]">
+                            {" "}
+                            (applied {project.applied_revision.slice(0, 8)})
+                          </span>
+                        )}
+                    </p>
+                  </div>
+                  <div>
+                    <p className="text-[11px] text-[#8b8b8b] tracking-[0.03em] font-light mb-1">
+                      Last check
+                    </p>
+                    <p className="text-[11px] text-[#0a0a0a] tracking-[0.03em] font-light">
+                      {formatTime(project.last_checked_at)}
+                    </p>
+                  </div>
+                  <div>
+                    <p className="text-[11px] text-[#8b8b8b] tracking-[0.03em] font-light mb-1">
+                      Last sync
+                    </p>
+                    <p className="text-[11px] text-[#0a0a0a] tracking-[0.03em] font-light">
+                      {formatTime(project.last_synced_at)}
+                    </p>
+                  </div>
+                </div>
+
+                {/* Drift */}
+                {project.drift && project.drift.length > 0 && (
+                  <div className="mt-4">
+                    <p className="text-[11px] text-[#8b8b8b] tracking-[0.03em] font-light mb-1">
+                      Drift ({project.drift.length})
+                    </p>
+                    <div className="bg-[#f6f8fa] rounded p-3 font-mono text-[11px] text-[#0a0a0a] space-y-0.5">
+                      {project.drift.map((change) => (
+                        <div key={change.resource}>
+                          {ACTION_MARKERS[change.action]} {change.resource}
+                        </div>
+                      ))}
+                    </div>
+                  </div>
+                )}
+
+                {/* Rollout left over from a failed sync */}
+                {project.pending_rollout && project.pending_rollout.length > 0 && (
+                  <div className="mt-4">
+                    <p className="text-[11px] text-[#8b8b8b] tracking-[0.03em] font-light mb-1">
+                      Not rolled out yet
+                    </p>
+                    <div className="bg-[#f6f8fa] rounded p-3 font-mono text-[11px] text-[#0a0a0a] space-y-0.5">
+                      {project.pending_rollout.map((step) => (
+                        <div key={step}>superdeploy {step}</div>
+                      ))}
+                    </div>
+                  </div>
+                )}
+
+                {/* Error */}
+                {project.last_error && (
+                  <div className="mt-4 bg-red-50 border border-red-200 rounded-lg p-3">
+                    <pre className="text-[11px] text-red-700 font-mono whitespace-pre-wrap max-h-48 overflow-auto">
+                      {project.last_error}
+                    </pre>
+                  </div>
+                )}
+              </div>
+            ))}
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
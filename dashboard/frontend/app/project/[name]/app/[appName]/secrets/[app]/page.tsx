@@ -3,7 +3,7 @@
 import { useEffect, useState, useRef } from "react";
 import { useParams, useRouter } from "next/navigation";
 import Link from "next/link";
-import { ArrowLeft, Lock, ChevronDown, X, Trash2, Plus, Loader2, Check, RefreshCw, ArrowUpRight, Copy, CheckCircle2 } from "lucide-react";
+import { ArrowLeft, Lock, ChevronDown, X, Trash2, Plus, Loader2, Check, RefreshCw, ArrowUpRight, Copy, CheckCircle2, AlertTriangle } from "lucide-react";
 import * as DropdownMenu from "@radix-ui/react-dropdown-menu";
 import { AppHeader, PageHeader, Button, Input, Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter, Table, ToastContainer } from "@/components";
 import type { Item, TableColumn } from "@/components";
@@ -15,10 +15,21 @@ interface Secret {
   value: string;
   source: "app" | "shared" | "addon" | "alias";
   editable: boolean;
+  usage?: "runtime" | "build" | "both"; // build: image build only, never in .env
   id?: number;
   target_key?: string; // For aliases - points to actual secret
 }
 
+// Key declared in the app marker's config contract
+interface ContractKey {
+  key: string;
+  required: boolean;
+  type: string;
+  description: string;
+  status: "ok" | "missing" | "unset" | "invalid";
+  message: string | null;
+}
+
 // Full Page Skeleton
 const SecretsPageSkeleton = () => {
   const shimmerStyles = `
@@ -158,6 +169,7 @@ export default function SecretsPage() {
   const [importText, setImportText] = useState("");
   const [importing, setImporting] = useState(false);
   const [copiedField, setCopiedField] = useState<string | null>(null);
+  const [contractKeys, setContractKeys] = useState<ContractKey[]>([]);
 
 
   // Auto-scroll to bottom when logs update
@@ -219,6 +231,7 @@ export default function SecretsPage() {
         });
       
       setSecrets(filteredAndSorted);
+      fetchContract();
     } catch (err) {
       setError(err instanceof Error ? err.message : "Unknown error");
     } finally {
@@ -227,6 +240,27 @@ export default function SecretsPage() {
     }
   };
 
+  // Keys the app marker requires, checked against current secrets
+  const fetchContract = async () => {
+    try {
+      const response = await fetch(
+        `http://localhost:8401/api/secrets/secrets/${projectName}/${appName}/contract?environment=${selectedEnvironment}`
+      );
+      if (!response.ok) {
+        setContractKeys([]);
+        return;
+      }
+      const data = await response.json();
+      setContractKeys(data.keys || []);
+    } catch (err) {
+      setContractKeys([]);
+    }
+  };
+
+  const contractProblems = contractKeys.filter(
+    (k) => k.status === "missing" || k.status === "invalid"
+  );
+
   useEffect(() => {
     if (projectName && appName) {
       fetchSecrets(loading === false); // Show spinner only after initial load
@@ -606,6 +640,27 @@ export default function SecretsPage() {
             </Button>
           </div>
 
+          {/* Config Contract Problems */}
+          {!environmentLoading && contractProblems.length > 0 && (
+            <div className="mb-4 rounded-[10px] border border-[#fecaca] bg-[#fef2f2] px-4 py-3">
+              <div className="flex items-center gap-2 mb-2">
+                <AlertTriangle className="w-4 h-4 text-[#b91c1c]" />
+                <span className="text-[13px] text-[#b91c1c] font-normal tracking-[0.03em]">
+                  Config contract: {contractProblems.length} key{contractProblems.length === 1 ? "" : "s"} missing or invalid. Deploys are blocked until fixed.
+                </span>
+              </div>
+              <ul className="space-y-1">
+                {contractProblems.map((k) => (
+                  <li key={k.key} className="text-[13px] text-[#7f1d1d] font-light tracking-[0.03em]">
+                    <span className="font-mono">{k.key}</span>{" "}
+                    {k.status === "missing" ? "is required" : k.message}
+                    {k.description && <span className="text-[#8b8b8b]"> ({k.description})</span>}
+                  </li>
+                ))}
+              </ul>
+            </div>
+          )}
+
           {/* Secrets Table */}
           {environmentLoading ? (
             <div className="rounded-[16px] border border-[#e3e8ee] bg-white p-16 flex items-center justify-center">
@@ -666,10 +721,18 @@ export default function SecretsPage() {
                         alias: "Alias",
                       };
                       const source = item.data.source || "app";
+                      const usage = item.data.usage || "runtime";
                       return (
-                        <span className={`inline-flex items-center px-1.5 py-0.5 rounded text-[11px] tracking-[0.03em] font-light ${sourceColors[source as keyof typeof sourceColors]}`}>
-                          {sourceLabels[source as keyof typeof sourceLabels]}
-                        </span>
+                        <div className="flex items-center gap-1.5">
+                          <span className={`inline-flex items-center px-1.5 py-0.5 rounded text-[11px] tracking-[0.03em] font-light ${sourceColors[source as keyof typeof sourceColors]}`}>
+                            {sourceLabels[source as keyof typeof sourceLabels]}
+                          </span>
+                          {usage !== "runtime" && (
+                            <span className="inline-flex items-center px-1.5 py-0.5 rounded text-[11px] tracking-[0.03em] font-light bg-[#f3e8ff] text-[#7e22ce]">
+                              {usage === "build" ? "Build only" : "Build + Runtime"}
+                            </span>
+                          )}
+                        </div>
                       );
                     },
                   },
@@ -31,6 +31,8 @@ interface ProjectConfig {
   domain: string;
   gcp_project: string;
   gcp_region: string;
+  blueprint: string;
+  blueprint_params: Record<string, string>;
   apps: Array<{
     name: string;
     repo: string;
@@ -59,6 +61,8 @@ const INITIAL_CONFIG: ProjectConfig = {
   domain: "",
   gcp_project: "",
   gcp_region: "us-central1",
+  blueprint: "",
+  blueprint_params: {},
   apps: [],
   addons: {
     databases: [],
@@ -105,6 +109,27 @@ const AVAILABLE_ADDONS = {
   ],
 };
 
+interface Blueprint {
+  name: string;
+  description: string;
+  params: Record<string, {
+    description: string;
+    type: string;
+    default: string | number | boolean | null;
+    required: boolean;
+  }>;
+  vms: string[];
+  apps: string[];
+  addons: string[];
+}
+
+// Blueprint params filled from the step 1 fields instead of their own input
+const WIZARD_PARAMS: Record<string, keyof ProjectConfig> = {
+  domain: "domain",
+  gcp_project: "gcp_project",
+  gcp_region: "gcp_region",
+};
+
 interface GroupedRepos {
   [org: string]: Array<{ full_name: string; name: string; owner: string }>;
 }
@@ -119,8 +144,25 @@ export default function NewProjectSetup() {
   const [loadingRepos, setLoadingRepos] = useState(false);
   const [repoSearch, setRepoSearch] = useState("");
   const [showSecrets, setShowSecrets] = useState<Record<string, boolean>>({});
+  const [blueprints, setBlueprints] = useState<Blueprint[]>([]);
 
   const totalSteps = 4;
+  const selectedBlueprint = blueprints.find(b => b.name === config.blueprint);
+
+  useEffect(() => {
+    const fetchBlueprints = async () => {
+      try {
+        const response = await fetch("http://localhost:8401/api/blueprints/");
+        if (response.ok) {
+          setBlueprints(await response.json());
+        }
+      } catch (error) {
+        console.error("Failed to fetch blueprints:", error);
+      }
+    };
+
+    fetchBlueprints();
+  }, []);
 
   // Fetch GitHub repos (only after token is entered)
   useEffect(() => {
@@ -209,6 +251,35 @@ export default function NewProjectSetup() {
     if (step > 1) setStep(step - 1);
   };
 
+  const blueprintParams = () => {
+    const params: Record<string, string> = {};
+    Object.keys(selectedBlueprint?.params || {}).forEach(name => {
+      const value = WIZARD_PARAMS[name]
+        ? String(config[WIZARD_PARAMS[name]] || "")
+        : config.blueprint_params[name];
+      if (value) params[name] = value;
+    });
+    return params;
+  };
+
+  const createFromBlueprint = async () => {
+    const response = await fetch("http://localhost:8401/api/projects/wizard/blueprint", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        project_name: config.project_name,
+        blueprint: config.blueprint,
+        params: blueprintParams(),
+        secrets: config.secrets,
+      }),
+    });
+    if (!response.ok) {
+      const error = await response.json();
+      throw new Error(error.detail || "Failed to create project from blueprint");
+    }
+    return response.json();
+  };
+
   const handleDeploy = async () => {
     setDeploying(true);
     setGlobalDeploying(true);
@@ -221,35 +292,41 @@ export default function NewProjectSetup() {
 
     try {
       log("💾 Saving configuration...");
-      
-      const github_org = config.apps.length > 0 && config.apps[0].repo 
-        ? config.apps[0].repo.split('/')[0] 
-        : "";
-      
-      const payload = {
-        project_name: config.project_name,
-        domain: config.domain,
-        gcp_project: config.gcp_project,
-        gcp_region: config.gcp_region,
-        github_org,
-        apps: config.apps,
-        addons: config.addons,
-        secrets: config.secrets
-      };
-
-      const createResponse = await fetch("http://localhost:8401/api/projects/wizard", {
-        method: "POST",
-        headers: { "Content-Type": "application/json" },
-        body: JSON.stringify(payload),
-      });
 
-      if (!createResponse.ok) {
-        const error = await createResponse.json();
-        throw new Error(error.detail || "Failed to save configuration");
+      if (selectedBlueprint) {
+        const project = await createFromBlueprint();
+        log(`✓ Project "${project.name}" created from blueprint ${selectedBlueprint.name}`);
+      } else {
+        const github_org = config.apps.length > 0 && config.apps[0].repo 
+          ? config.apps[0].repo.split('/')[0] 
+          : "";
+
+        const payload = {
+          project_name: config.project_name,
+          domain: config.domain,
+          gcp_project: config.gcp_project,
+          gcp_region: config.gcp_region,
+          github_org,
+          apps: config.apps,
+          addons: config.addons,
+          secrets: config.secrets
+        };
+
+        const createResponse = await fetch("http://localhost:8401/api/projects/wizard", {
+          method: "POST",
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify(payload),
+        });
+
+        if (!createResponse.ok) {
+          const error = await createResponse.json();
+          throw new Error(error.detail || "Failed to save configuration");
+        }
+
+        const project = await createResponse.json();
+        log(`✓ Project "${project.name}" saved`);
       }
 
-      const project = await createResponse.json();
-      log(`✓ Project "${project.name}" saved`);
       log("🚀 Starting deployment...");
       
       const deployResponse = await fetch(`http://localhost:8401/api/projects/${config.project_name}/deploy`, {
@@ -308,8 +385,12 @@ export default function NewProjectSetup() {
   const canProceed = () => {
     switch (step) {
       case 1:
-        return config.project_name && config.gcp_project && config.gcp_region;
+        return config.project_name && config.gcp_project && config.gcp_region &&
+          Object.entries(selectedBlueprint?.params || {}).every(
+            ([name, param]) => !param.required || blueprintParams()[name]
+          );
       case 2:
+        if (selectedBlueprint) return true; // Apps come from the blueprint
         return config.apps.length > 0 && config.apps.every(app => app.name && app.repo);
       case 3:
         return true; // Optional
@@ -496,6 +577,68 @@ export default function NewProjectSetup() {
                 </div>
               </div>
 
+              {blueprints.length > 0 && (
+                <div className="border-t border-[#e3e8ee] pt-6">
+                  <h3 className="text-[14px] text-[#0a0a0a] mb-4">Blueprint</h3>
+                  <DropdownMenu.Root>
+                    <DropdownMenu.Trigger className="bg-white user-select-none border border-[#0000001f] shadow-x1 relative flex h-8 w-full items-center justify-between px-2 pr-[22px] py-2 rounded-[10px] cursor-pointer outline-none group">
+                      <span className="text-[11px] tracking-[0.03em] font-light text-[#141414] user-select-none">
+                        {selectedBlueprint ? selectedBlueprint.name : "None (choose apps and add-ons yourself)"}
+                      </span>
+                      <ChevronRight className="top-[10px] right-[9px] absolute w-3 h-3 text-black transition-transform duration-200 group-data-[state=open]:rotate-90" />
+                    </DropdownMenu.Trigger>
+
+                    <DropdownMenu.Portal>
+                      <DropdownMenu.Content
+                        align="start"
+                        className="min-w-[400px] bg-white rounded-lg shadow-[0_4px_12px_rgba(0,0,0,0.15)] p-1 animate-[slide-fade-in-vertical_150ms_ease-out_forwards] distance--8 data-[state=closed]:animate-[slide-fade-out-vertical_150ms_ease-out_forwards]"
+                        sideOffset={5}
+                      >
+                        {[null, ...blueprints].map(blueprint => (
+                          <DropdownMenu.Item
+                            key={blueprint?.name || "none"}
+                            onClick={() => updateConfig({ blueprint: blueprint?.name || "", blueprint_params: {} })}
+                            className="flex items-center justify-between px-3 py-2 rounded hover:bg-[#f6f8fa] outline-none cursor-pointer"
+                          >
+                            <div>
+                              <span className="block text-[11px] text-[#111] font-light tracking-[0.03em]">
+                                {blueprint ? blueprint.name : "None"}
+                              </span>
+                              <span className="block text-[11px] text-[#8b8b8b] font-light tracking-[0.03em]">
+                                {blueprint ? blueprint.description : "Choose apps and add-ons yourself"}
+                              </span>
+                            </div>
+                            {config.blueprint === (blueprint?.name || "") && (
+                              <Check className="w-3.5 h-3.5 text-[#374046]" strokeWidth={2.5} />
+                            )}
+                          </DropdownMenu.Item>
+                        ))}
+                      </DropdownMenu.Content>
+                    </DropdownMenu.Portal>
+                  </DropdownMenu.Root>
+
+                  {selectedBlueprint && (
+                    <div className="grid grid-cols-2 gap-3 mt-4">
+                      {Object.entries(selectedBlueprint.params)
+                        .filter(([name]) => !WIZARD_PARAMS[name])
+                        .map(([name, param]) => (
+                          <Input
+                            key={name}
+                            label={<>{name} {param.required && <span className="text-red-500">*</span>}</>}
+                            type={param.type === "integer" ? "number" : "text"}
+                            value={config.blueprint_params[name] || ""}
+                            onChange={(e) => updateConfig({
+                              blueprint_params: { ...config.blueprint_params, [name]: e.target.value }
+                            })}
+                            placeholder={param.default !== null ? String(param.default) : ""}
+                            hint={param.description}
+                          />
+                        ))}
+                    </div>
+                  )}
+                </div>
+              )}
+
               <div className="border-t border-[#e3e8ee] pt-6">
                 <h3 className="text-[14px] text-[#0a0a0a] mb-4">GitHub Access</h3>
                 <div>
@@ -523,17 +666,28 @@ export default function NewProjectSetup() {
                 <div>
                   <h2 className="text-[18px] text-[#0a0a0a] mb-1">Applications</h2>
                 </div>
-                <Button
-                  variant="secondary"
-                  size="sm"
-                  onClick={addApp}
-                  icon={<Plus className="w-4 h-4" />}
-                >
-                  Add App
-                </Button>
+                {!selectedBlueprint && (
+                  <Button
+                    variant="secondary"
+                    size="sm"
+                    onClick={addApp}
+                    icon={<Plus className="w-4 h-4" />}
+                  >
+                    Add App
+                  </Button>
+                )}
               </div>
 
-              {config.apps.length === 0 ? (
+              {selectedBlueprint ? (
+                <div className="border border-[#e3e8ee] rounded-lg p-4">
+                  <p className="text-[11px] tracking-[0.03em] font-light text-[#8b8b8b] mb-2">
+                    Defined by blueprint <strong>{selectedBlueprint.name}</strong>
+                  </p>
+                  <code className="block text-[11px] text-[#0a0a0a] font-mono tracking-[0.03em] font-light">
+                    {selectedBlueprint.apps.join(", ") || "none"}
+                  </code>
+                </div>
+              ) : config.apps.length === 0 ? (
                 <div className="border border-[#e3e8ee] rounded-lg p-12 text-center">
                   <Code className="w-5 h-5 text-[#8b8b8b] mx-auto mb-1" />
                   <p className="text-[11px] tracking-[0.03em] font-light text-[#8b8b8b] mb-3">No applications added</p>
@@ -685,7 +839,16 @@ export default function NewProjectSetup() {
                 <h2 className="text-[18px] text-[#0a0a0a] mb-1">Add-ons</h2>
               </div>
 
-              {Object.entries(AVAILABLE_ADDONS).map(([category, addons]) => (
+              {selectedBlueprint ? (
+                <div className="border border-[#e3e8ee] rounded-lg p-4">
+                  <p className="text-[11px] tracking-[0.03em] font-light text-[#8b8b8b] mb-2">
+                    Defined by blueprint <strong>{selectedBlueprint.name}</strong>
+                  </p>
+                  <code className="block text-[11px] text-[#0a0a0a] font-mono tracking-[0.03em] font-light">
+                    {selectedBlueprint.addons.join(", ") || "none"}
+                  </code>
+                </div>
+              ) : Object.entries(AVAILABLE_ADDONS).map(([category, addons]) => (
                 <div key={category}>
                   <h2 className="flex items-center gap-2 text-[11px] text-[#777] leading-tight tracking-[0.03em] mb-[8px] font-light capitalize">
                     <Database className="w-4 h-4" />
@@ -11,6 +11,7 @@ export default function OrchestratorHeader() {
     { label: "Configuration", href: "/infrastructure/orchestrator" },
     { label: "Addons", href: "/infrastructure/orchestrator/addons" },
     { label: "Logs", href: "/infrastructure/orchestrator/logs" },
+    { label: "GitOps", href: "/infrastructure/orchestrator/gitops" },
     { label: "Settings", href: "/infrastructure/orchestrator/settings" },
   ];
 
@@ -12,6 +12,7 @@ setup(
     description="Heroku-like PaaS for self-hosted infrastructure",
     author="SuperDeploy Team",
     packages=find_packages(),
+    package_data={"cli.core": ["schemas/*.json"]},
     install_requires=requirements,
     entry_points={
         "console_scripts": [
@@ -295,6 +295,28 @@ resource "google_compute_firewall" "allow_loki_ingestion" {
   description = "Allow Loki log ingestion (3100) from Promtail agents via VPC peering"
 }
 
+# Firewall: Allow deploy gate checks (port 8585)
+# Allows self-hosted deploy jobs on project VMs to ask the orchestrator's
+# deploy gate about freeze windows and approvals
+resource "google_compute_firewall" "allow_deploy_gate" {
+  name    = "${var.network_name}-allow-deploy-gate"
+  network = google_compute_network.vpc.name
+  project = var.project_id
+
+  allow {
+    protocol = "tcp"
+    ports    = ["8585"]  # Deploy gate
+  }
+
+  # Only the orchestrator runs the gate; project subnets reach it via VPC peering
+  source_ranges = length(var.allowed_client_subnets) > 0 ? var.allowed_client_subnets : (
+    var.orchestrator_subnet != "" ? [var.orchestrator_subnet, var.subnet_cidr] : ["0.0.0.0/0"]
+  )
+  target_tags   = ["orchestrator"]
+
+  description = "Allow deploy gate checks (8585) from deploy jobs via VPC peering"
+}
+
 # VPC Peering: Project VPC <-> Orchestrator VPC
 # This allows Prometheus (orchestrator) to reach project VMs via internal IPs
 resource "google_compute_network_peering" "project_to_orchestrator" {